@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server"
+	"github.com/runatlantis/atlantis/server/core/db"
+	"github.com/runatlantis/atlantis/server/core/redis"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// DBFileFlag is the flag for the file that the db subcommands read and write.
+const DBFileFlag = "file"
+
+// DBCmd exports and imports the locks, pull statuses and drift statuses
+// stored in the locking database. It's used to move a server to a new data
+// dir or to a different --locking-db-type without losing the locks held by
+// open pull requests.
+type DBCmd struct {
+	Viper *viper.Viper
+	// SilenceOutput set to true means nothing gets printed.
+	SilenceOutput bool
+}
+
+// Init returns the runnable cobra command.
+func (d *DBCmd) Init() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "db",
+		Short: "Export or import the contents of the locking database",
+		Long: `Export or import the project locks, command locks, pull request statuses
+and drift detection statuses stored in the locking database. The database is selected with the same
+--config, --locking-db-type, --data-dir and --redis-* flags as the server.
+
+BoltDB can only be opened by one process at a time so the server must be
+stopped while exporting from or importing into a BoltDB database. A running
+server exports and imports through GET /api/db/export and POST /api/db/import
+instead, authenticated with its --api-secret.`,
+	}
+
+	d.Viper.SetEnvPrefix("ATLANTIS")
+	d.Viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	d.Viper.AutomaticEnv()
+	d.Viper.SetTypeByDefaultValue(true)
+
+	for _, name := range []string{ConfigFlag, DataDirFlag, LockingDBType, RedisHost, RedisPassword} {
+		f := stringFlags[name]
+		c.PersistentFlags().String(name, f.defaultValue, f.description)
+		d.Viper.BindPFlag(name, c.PersistentFlags().Lookup(name)) // nolint: errcheck
+	}
+	for _, name := range []string{RedisPort, RedisDB} {
+		f := intFlags[name]
+		c.PersistentFlags().Int(name, f.defaultValue, f.description)
+		d.Viper.BindPFlag(name, c.PersistentFlags().Lookup(name)) // nolint: errcheck
+	}
+	for _, name := range []string{RedisTLSEnabled, RedisInsecureSkipVerify} {
+		f := boolFlags[name]
+		c.PersistentFlags().Bool(name, f.defaultValue, f.description)
+		d.Viper.BindPFlag(name, c.PersistentFlags().Lookup(name)) // nolint: errcheck
+	}
+
+	export := &cobra.Command{
+		Use:          "export",
+		Short:        "Write the contents of the locking database to a JSON file",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return d.export(cmd.Flag(DBFileFlag).Value.String())
+		},
+	}
+	export.Flags().String(DBFileFlag, "", "Path of the JSON file to write.")
+	export.MarkFlagRequired(DBFileFlag) // nolint: errcheck
+
+	load := &cobra.Command{
+		Use:          "import",
+		Short:        "Load a JSON file written by export into the locking database",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return d.load(cmd.Flag(DBFileFlag).Value.String())
+		},
+	}
+	load.Flags().String(DBFileFlag, "", "Path of the JSON file to read.")
+	load.MarkFlagRequired(DBFileFlag) // nolint: errcheck
+
+	c.AddCommand(export, load)
+	return c
+}
+
+func (d *DBCmd) export(file string) error {
+	database, err := d.openDatabase()
+	if err != nil {
+		return err
+	}
+	defer database.Close() // nolint: errcheck
+	e, err := db.Dump(database)
+	if err != nil {
+		return errors.Wrap(err, "exporting database")
+	}
+	serialized, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "serializing export")
+	}
+	if err := os.WriteFile(file, serialized, 0600); err != nil {
+		return errors.Wrapf(err, "writing %s", file)
+	}
+	d.printf("exported %d project locks, %d command locks, %d pull statuses and %d drift statuses to %s\n",
+		len(e.ProjectLocks), len(e.CommandLocks), len(e.PullStatuses), len(e.DriftStatuses), file)
+	return nil
+}
+
+func (d *DBCmd) load(file string) error {
+	serialized, err := os.ReadFile(file) // nolint: gosec
+	if err != nil {
+		return errors.Wrapf(err, "reading %s", file)
+	}
+	var e db.Export
+	if err := json.Unmarshal(serialized, &e); err != nil {
+		return errors.Wrapf(err, "parsing %s", file)
+	}
+	database, err := d.openDatabase()
+	if err != nil {
+		return err
+	}
+	defer database.Close() // nolint: errcheck
+	if err := db.Load(database, e); err != nil {
+		return errors.Wrap(err, "importing database")
+	}
+	d.printf("imported %d project locks, %d command locks, %d pull statuses and %d drift statuses from %s\n",
+		len(e.ProjectLocks), len(e.CommandLocks), len(e.PullStatuses), len(e.DriftStatuses), file)
+	return nil
+}
+
+// openDatabase opens the database selected by the flags and config file the
+// same way the server does.
+func (d *DBCmd) openDatabase() (db.Database, error) {
+	// If passed a config file then try and load it.
+	configFile := d.Viper.GetString(ConfigFlag)
+	if configFile != "" {
+		d.Viper.SetConfigFile(configFile)
+		if err := d.Viper.ReadInConfig(); err != nil {
+			return nil, errors.Wrapf(err, "invalid config: reading %s", configFile)
+		}
+	}
+
+	var userConfig server.UserConfig
+	if err := d.Viper.Unmarshal(&userConfig); err != nil {
+		return nil, err
+	}
+
+	switch userConfig.LockingDBType {
+	case "redis":
+		if userConfig.RedisHost == "" {
+			return nil, fmt.Errorf("--%s must be set when --%s is redis", RedisHost, LockingDBType)
+		}
+		return redis.New(userConfig.RedisHost, userConfig.RedisPort, userConfig.RedisPassword, userConfig.RedisTLSEnabled, userConfig.RedisInsecureSkipVerify, userConfig.RedisDB)
+	case "boltdb":
+		dataDir, err := homedir.Expand(userConfig.DataDir)
+		if err != nil {
+			return nil, errors.Wrap(err, "determining home directory")
+		}
+		dataDir, err = filepath.Abs(dataDir)
+		if err != nil {
+			return nil, errors.Wrap(err, "making data-dir absolute")
+		}
+		return db.New(dataDir)
+	}
+	return nil, errors.New("invalid locking db type: not one of boltdb or redis")
+}
+
+func (d *DBCmd) printf(format string, a ...interface{}) {
+	if !d.SilenceOutput {
+		fmt.Printf(format, a...)
+	}
+}
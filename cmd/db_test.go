@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/runatlantis/atlantis/server/core/db"
+	"github.com/runatlantis/atlantis/server/events/models"
+	. "github.com/runatlantis/atlantis/testing"
+	"github.com/spf13/viper"
+)
+
+func TestDBCmd_BoltDBToRedis(t *testing.T) {
+	dataDir, cleanup := TempDir(t)
+	defer cleanup()
+	exportFile := filepath.Join(dataDir, "export.json")
+
+	boltdb, err := db.New(dataDir)
+	Ok(t, err)
+	lock := models.ProjectLock{
+		Project:   models.NewProject("owner/repo", "."),
+		Workspace: "default",
+		Pull:      models.PullRequest{Num: 1},
+		User:      models.User{Username: "lkysow"},
+		Time:      time.Now(),
+	}
+	_, _, err = boltdb.TryLock(lock)
+	Ok(t, err)
+	Ok(t, boltdb.Close())
+
+	runDBCmd(t, "export", "--data-dir", dataDir, "--file", exportFile)
+	_, err = os.Stat(exportFile)
+	Ok(t, err)
+
+	s := miniredis.RunT(t)
+	runDBCmd(t, "import", "--locking-db-type", "redis", "--redis-host", s.Host(), "--redis-port", s.Port(), "--file", exportFile)
+
+	keys := s.Keys()
+	Equals(t, []string{"lock/owner/repo/./default"}, keys)
+}
+
+func TestDBCmd_ConfigFile(t *testing.T) {
+	dataDir, cleanup := TempDir(t)
+	defer cleanup()
+	exportFile := filepath.Join(dataDir, "export.json")
+
+	s := miniredis.RunT(t)
+	Ok(t, s.Set("lock/owner/repo/./default", `{"Project":{"RepoFullName":"owner/repo","Path":"."},"Workspace":"default"}`))
+	configFile := filepath.Join(dataDir, "config.yaml")
+	Ok(t, os.WriteFile(configFile, []byte(fmt.Sprintf("locking-db-type: redis\nredis-host: %s\nredis-port: %s\n", s.Host(), s.Port())), 0600))
+
+	runDBCmd(t, "export", "--config", configFile, "--file", exportFile)
+	var e db.Export
+	serialized, err := os.ReadFile(exportFile)
+	Ok(t, err)
+	Ok(t, json.Unmarshal(serialized, &e))
+	Equals(t, 1, len(e.ProjectLocks))
+	Equals(t, "owner/repo", e.ProjectLocks[0].Project.RepoFullName)
+}
+
+func TestDBCmd_InvalidLockingDBType(t *testing.T) {
+	c := (&DBCmd{Viper: viper.New(), SilenceOutput: true}).Init()
+	c.SetArgs([]string{"export", "--locking-db-type", "invalid", "--file", "export.json"})
+	c.SilenceErrors = true
+	ErrEquals(t, "invalid locking db type: not one of boltdb or redis", c.Execute())
+}
+
+func runDBCmd(t *testing.T, args ...string) {
+	t.Helper()
+	c := (&DBCmd{Viper: viper.New(), SilenceOutput: true}).Init()
+	c.SetArgs(args)
+	Ok(t, c.Execute())
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/runatlantis/atlantis/server/core/runtime/sandbox"
+	"github.com/spf13/cobra"
+)
+
+// SandboxExecCmd runs a command in a sandbox. It's run by the server for
+// repos that configure a sandbox and isn't meant to be run by users.
+type SandboxExecCmd struct{}
+
+// Init returns the runnable cobra command.
+func (s *SandboxExecCmd) Init() *cobra.Command {
+	return &cobra.Command{
+		Use:    sandbox.ExecCommandName,
+		Short:  "Run a command in a sandbox",
+		Hidden: true,
+		// The flags are parsed by sandbox.Main so the command's own flags
+		// don't have to come after --.
+		DisableFlagParsing: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			os.Exit(sandbox.Main(args))
+		},
+	}
+}
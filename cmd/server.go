@@ -18,6 +18,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/docker/docker/pkg/fileutils"
@@ -26,6 +27,7 @@ import (
 	"github.com/runatlantis/atlantis/server"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/events/vcs/bitbucketcloud"
+	"github.com/runatlantis/atlantis/server/events/vcs/gitea"
 	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -44,15 +46,18 @@ const (
 	ADHostnameFlag             = "azuredevops-hostname"
 	AllowForkPRsFlag           = "allow-fork-prs"
 	AllowRepoConfigFlag        = "allow-repo-config"
+	APISecretFlag              = "api-secret" // nolint: gosec
 	AtlantisURLFlag            = "atlantis-url"
 	AutomergeFlag              = "automerge"
 	AutoplanFileListFlag       = "autoplan-file-list"
+	AutoplanModulesFlag        = "autoplan-modules"
 	BitbucketBaseURLFlag       = "bitbucket-base-url"
 	BitbucketTokenFlag         = "bitbucket-token"
 	BitbucketUserFlag          = "bitbucket-user"
 	BitbucketWebhookSecretFlag = "bitbucket-webhook-secret"
 	ConfigFlag                 = "config"
 	CheckoutStrategyFlag       = "checkout-strategy"
+	ConcurrencyLimitFlag       = "concurrency-limit"
 	DataDirFlag                = "data-dir"
 	DefaultTFVersionFlag       = "default-tf-version"
 	DisableApplyAllFlag        = "disable-apply-all"
@@ -63,6 +68,9 @@ const (
 	EnablePolicyChecksFlag     = "enable-policy-checks"
 	EnableRegExpCmdFlag        = "enable-regexp-cmd"
 	EnableDiffMarkdownFormat   = "enable-diff-markdown-format"
+	EnablePlanSummaryFlag      = "enable-plan-summary"
+	EnableRemoteWorkersFlag    = "enable-remote-workers"
+	TerragruntDiscoveryFlag    = "enable-terragrunt-discovery"
 	GHHostnameFlag             = "gh-hostname"
 	GHTeamAllowlistFlag        = "gh-team-allowlist"
 	GHTokenFlag                = "gh-token"
@@ -73,21 +81,41 @@ const (
 	GHAppSlugFlag              = "gh-app-slug"
 	GHOrganizationFlag         = "gh-org"
 	GHWebhookSecretFlag        = "gh-webhook-secret" // nolint: gosec
+	GiteaHostnameFlag          = "gitea-hostname"
+	GiteaTokenFlag             = "gitea-token" // nolint: gosec
+	GiteaUserFlag              = "gitea-user"
+	GiteaWebhookSecretFlag     = "gitea-webhook-secret" // nolint: gosec
 	GitlabHostnameFlag         = "gitlab-hostname"
 	GitlabTokenFlag            = "gitlab-token"
 	GitlabUserFlag             = "gitlab-user"
 	GitlabWebhookSecretFlag    = "gitlab-webhook-secret" // nolint: gosec
 	HidePrevPlanComments       = "hide-prev-plan-comments"
+	JobStoreFlag               = "job-store"
+	JobStoreDirFlag            = "job-store-dir"
+	JobStoreRetentionDaysFlag  = "job-store-retention-days"
+	JobStoreS3BucketFlag       = "job-store-s3-bucket"
+	JobStoreS3EndpointFlag     = "job-store-s3-endpoint"
+	JobStoreS3PrefixFlag       = "job-store-s3-prefix"
+	JobStoreS3RegionFlag       = "job-store-s3-region"
+	LockingDBType              = "locking-db-type"
 	LogLevelFlag               = "log-level"
 	ParallelPoolSize           = "parallel-pool-size"
+	PolicyRefreshIntervalFlag  = "policy-refresh-interval"
 	StatsNamespace             = "stats-namespace"
 	AllowDraftPRs              = "allow-draft-prs"
 	PortFlag                   = "port"
+	RedisDB                    = "redis-db"
+	RedisHost                  = "redis-host"
+	RedisInsecureSkipVerify    = "redis-insecure-skip-verify"
+	RedisPassword              = "redis-password"
+	RedisPort                  = "redis-port"
+	RedisTLSEnabled            = "redis-tls-enabled"
 	RepoConfigFlag             = "repo-config"
 	RepoConfigJSONFlag         = "repo-config-json"
 	// RepoWhitelistFlag is deprecated for RepoAllowlistFlag.
 	RepoWhitelistFlag          = "repo-whitelist"
 	RepoAllowlistFlag          = "repo-allowlist"
+	RepoConcurrencyLimitFlag   = "repo-concurrency-limit"
 	RequireApprovalFlag        = "require-approval"
 	RequireMergeableFlag       = "require-mergeable"
 	SilenceNoProjectsFlag      = "silence-no-projects"
@@ -108,32 +136,44 @@ const (
 	WebBasicAuthFlag           = "web-basic-auth"
 	WebUsernameFlag            = "web-username"
 	WebPasswordFlag            = "web-password"
+	WorkerLeaseTimeoutFlag     = "worker-lease-timeout"
 
 	// NOTE: Must manually set these as defaults in the setDefaults function.
-	DefaultADBasicUser      = ""
-	DefaultADBasicPassword  = ""
-	DefaultADHostname       = "dev.azure.com"
-	DefaultAutoplanFileList = "**/*.tf,**/*.tfvars,**/*.tfvars.json,**/terragrunt.hcl,**/.terraform.lock.hcl"
-	DefaultCheckoutStrategy = "branch"
-	DefaultBitbucketBaseURL = bitbucketcloud.BaseURL
-	DefaultDataDir          = "~/.atlantis"
-	DefaultGHHostname       = "github.com"
-	DefaultGitlabHostname   = "gitlab.com"
-	DefaultLogLevel         = "info"
-	DefaultParallelPoolSize = 15
-	DefaultStatsNamespace   = "atlantis"
-	DefaultPort             = 4141
-	DefaultTFDownloadURL    = "https://releases.hashicorp.com"
-	DefaultTFEHostname      = "app.terraform.io"
-	DefaultVCSStatusName    = "atlantis"
-	DefaultWebBasicAuth     = false
-	DefaultWebUsername      = "atlantis"
-	DefaultWebPassword      = "atlantis"
+	DefaultADBasicUser             = ""
+	DefaultADBasicPassword         = ""
+	DefaultADHostname              = "dev.azure.com"
+	DefaultAutoplanFileList        = "**/*.tf,**/*.tfvars,**/*.tfvars.json,**/terragrunt.hcl,**/.terraform.lock.hcl"
+	DefaultCheckoutStrategy        = "branch"
+	DefaultBitbucketBaseURL        = bitbucketcloud.BaseURL
+	DefaultDataDir                 = "~/.atlantis"
+	DefaultGHHostname              = "github.com"
+	DefaultGiteaHostname           = gitea.DefaultHostname
+	DefaultGitlabHostname          = "gitlab.com"
+	DefaultJobStore                = "memory"
+	DefaultJobStoreRetentionDays   = 30
+	DefaultLockingDBType           = "boltdb"
+	DefaultLogLevel                = "info"
+	DefaultParallelPoolSize        = 15
+	DefaultPolicyRefreshInterval   = 60
+	DefaultStatsNamespace          = "atlantis"
+	DefaultPort                    = 4141
+	DefaultRedisDB                 = 0
+	DefaultRedisPort               = 6379
+	DefaultRedisTLSEnabled         = false
+	DefaultRedisInsecureSkipVerify = false
+	DefaultTFDownloadURL           = "https://releases.hashicorp.com"
+	DefaultTFEHostname             = "app.terraform.io"
+	DefaultVCSStatusName           = "atlantis"
+	DefaultWebBasicAuth            = false
+	DefaultWebUsername             = "atlantis"
+	DefaultWebPassword             = "atlantis"
+	DefaultWorkerLeaseTimeout      = 60
 )
 
 var stringFlags = map[string]stringFlag{
 	ADTokenFlag: {
 		description: "Azure DevOps token of API user. Can also be specified via the ATLANTIS_AZUREDEVOPS_TOKEN environment variable.",
+		secret:      true,
 	},
 	ADUserFlag: {
 		description: "Azure DevOps username of API user.",
@@ -145,6 +185,7 @@ var stringFlags = map[string]stringFlag{
 			"This means that an attacker could spoof calls to Atlantis and cause it to perform malicious actions. " +
 			"Should be specified via the ATLANTIS_AZUREDEVOPS_WEBHOOK_PASSWORD environment variable.",
 		defaultValue: "",
+		secret:       true,
 	},
 	ADWebhookUserFlag: {
 		description:  "Azure DevOps basic HTTP authentication username for inbound webhooks.",
@@ -154,6 +195,13 @@ var stringFlags = map[string]stringFlag{
 		description:  "Azure DevOps hostname to support cloud and self hosted instances.",
 		defaultValue: "dev.azure.com",
 	},
+	APISecretFlag: {
+		description: "Secret used to authenticate requests to the /api endpoints." +
+			" Requests must set the X-Atlantis-Token header to this value." +
+			" If not specified, the API is disabled." +
+			" Should be specified via the ATLANTIS_API_SECRET environment variable.",
+		secret: true,
+	},
 	AtlantisURLFlag: {
 		description: "URL that Atlantis can be reached at. Defaults to http://$(hostname):$port where $port is from --" + PortFlag + ". Supports a base path ex. https://example.com/basepath.",
 	},
@@ -169,6 +217,7 @@ var stringFlags = map[string]stringFlag{
 	},
 	BitbucketTokenFlag: {
 		description: "Bitbucket app password of API user. Can also be specified via the ATLANTIS_BITBUCKET_TOKEN environment variable.",
+		secret:      true,
 	},
 	BitbucketBaseURLFlag: {
 		description: "Base URL of Bitbucket Server (aka Stash) installation." +
@@ -181,6 +230,7 @@ var stringFlags = map[string]stringFlag{
 			" SECURITY WARNING: If not specified, Atlantis won't be able to validate that the incoming webhook call came from Bitbucket. " +
 			"This means that an attacker could spoof calls to Atlantis and cause it to perform malicious actions. " +
 			"Should be specified via the ATLANTIS_BITBUCKET_WEBHOOK_SECRET environment variable.",
+		secret: true,
 	},
 	CheckoutStrategyFlag: {
 		description: "How to check out pull requests. Accepts either 'branch' (default) or 'merge'." +
@@ -219,10 +269,12 @@ var stringFlags = map[string]stringFlag{
 	},
 	GHTokenFlag: {
 		description: "GitHub token of API user. Can also be specified via the ATLANTIS_GH_TOKEN environment variable.",
+		secret:      true,
 	},
 	GHAppKeyFlag: {
 		description:  "The GitHub App's private key",
 		defaultValue: "",
+		secret:       true,
 	},
 	GHAppKeyFileFlag: {
 		description:  "A path to a file containing the GitHub App's private key",
@@ -240,6 +292,25 @@ var stringFlags = map[string]stringFlag{
 			" SECURITY WARNING: If not specified, Atlantis won't be able to validate that the incoming webhook call came from GitHub. " +
 			"This means that an attacker could spoof calls to Atlantis and cause it to perform malicious actions. " +
 			"Should be specified via the ATLANTIS_GH_WEBHOOK_SECRET environment variable.",
+		secret: true,
+	},
+	GiteaHostnameFlag: {
+		description:  "Hostname of your Gitea or Forgejo installation, ex. gitea.example.com. A scheme and port can be included, ex. http://gitea.example.com:3000. If using gitea.com, no need to set.",
+		defaultValue: DefaultGiteaHostname,
+	},
+	GiteaUserFlag: {
+		description: "Gitea or Forgejo username of API user.",
+	},
+	GiteaTokenFlag: {
+		description: "Gitea or Forgejo access token of API user. Can also be specified via the ATLANTIS_GITEA_TOKEN environment variable.",
+		secret:      true,
+	},
+	GiteaWebhookSecretFlag: {
+		description: "Optional secret used to validate Gitea or Forgejo webhooks." +
+			" SECURITY WARNING: If not specified, Atlantis won't be able to validate that the incoming webhook call came from Gitea. " +
+			"This means that an attacker could spoof calls to Atlantis and cause it to perform malicious actions. " +
+			"Should be specified via the ATLANTIS_GITEA_WEBHOOK_SECRET environment variable.",
+		secret: true,
 	},
 	GitlabHostnameFlag: {
 		description:  "Hostname of your GitLab Enterprise installation. If using gitlab.com, no need to set.",
@@ -250,12 +321,37 @@ var stringFlags = map[string]stringFlag{
 	},
 	GitlabTokenFlag: {
 		description: "GitLab token of API user. Can also be specified via the ATLANTIS_GITLAB_TOKEN environment variable.",
+		secret:      true,
 	},
 	GitlabWebhookSecretFlag: {
 		description: "Optional secret used to validate GitLab webhooks." +
 			" SECURITY WARNING: If not specified, Atlantis won't be able to validate that the incoming webhook call came from GitLab. " +
 			"This means that an attacker could spoof calls to Atlantis and cause it to perform malicious actions. " +
 			"Should be specified via the ATLANTIS_GITLAB_WEBHOOK_SECRET environment variable.",
+		secret: true,
+	},
+	JobStoreFlag: {
+		description:  "Where the output of completed jobs is stored so the job links in pull request comments keep working after restarts. Either memory, disk or s3.",
+		defaultValue: DefaultJobStore,
+	},
+	JobStoreDirFlag: {
+		description: "Directory the output of completed jobs is stored in when --" + JobStoreFlag + " is disk. Defaults to the jobs directory in --" + DataDirFlag + ".",
+	},
+	JobStoreS3BucketFlag: {
+		description: "S3 bucket the output of completed jobs is stored in when --" + JobStoreFlag + " is s3.",
+	},
+	JobStoreS3EndpointFlag: {
+		description: "Endpoint of an S3 compatible service, such as MinIO, to use instead of AWS S3 when --" + JobStoreFlag + " is s3.",
+	},
+	JobStoreS3PrefixFlag: {
+		description: "Prefix of the S3 keys that the output of completed jobs is stored under when --" + JobStoreFlag + " is s3.",
+	},
+	JobStoreS3RegionFlag: {
+		description: "AWS region of the S3 bucket when --" + JobStoreFlag + " is s3. Defaults to the region in the AWS environment or shared config.",
+	},
+	LockingDBType: {
+		description:  "The locking database type to use for storing plan and apply locks. Either boltdb or redis.",
+		defaultValue: DefaultLockingDBType,
 	},
 	LogLevelFlag: {
 		description:  "Log level. Either debug, info, warn, or error.",
@@ -265,6 +361,14 @@ var stringFlags = map[string]stringFlag{
 		description:  "Namespace for aggregating stats.",
 		defaultValue: DefaultStatsNamespace,
 	},
+	RedisHost: {
+		description: "The Redis Hostname for when using a Locking DB type of 'redis'.",
+	},
+	RedisPassword: {
+		description: "The Redis Password for when using a Locking DB type of 'redis'." +
+			" Should be specified via the ATLANTIS_REDIS_PASSWORD environment variable.",
+		secret: true,
+	},
 	RepoConfigFlag: {
 		description: "Path to a repo config file, used to customize how Atlantis runs on each repo. See runatlantis.io/docs for more details.",
 	},
@@ -283,6 +387,7 @@ var stringFlags = map[string]stringFlag{
 	},
 	SlackTokenFlag: {
 		description: "API token for Slack notifications.",
+		secret:      true,
 	},
 	SSLCertFileFlag: {
 		description: "File containing x509 Certificate used for serving HTTPS. If the cert is signed by a CA, the file should be the concatenation of the server's certificate, any intermediates, and the CA's certificate.",
@@ -302,6 +407,7 @@ var stringFlags = map[string]stringFlag{
 		description: "API token for Terraform Cloud/Enterprise. This will be used to generate a ~/.terraformrc file." +
 			" Only set if using TFC/E as a remote backend." +
 			" Should be specified via the ATLANTIS_TFE_TOKEN environment variable for security.",
+		secret: true,
 	},
 	DefaultTFVersionFlag: {
 		description: "Terraform version to default to (ex. v0.12.0). Will download if not yet on disk." +
@@ -318,6 +424,7 @@ var stringFlags = map[string]stringFlag{
 	WebPasswordFlag: {
 		description:  "Password used for Web Basic Authentication on Atlantis HTTP Middleware",
 		defaultValue: DefaultWebPassword,
+		secret:       true,
 	},
 }
 
@@ -337,6 +444,10 @@ var boolFlags = map[string]boolFlag{
 		description:  "Automatically merge pull requests when all plans are successfully applied.",
 		defaultValue: false,
 	},
+	AutoplanModulesFlag: {
+		description:  "Autoplan projects when a local module they use, directly or through other modules, is modified. Modules are found by parsing each project's module sources.",
+		defaultValue: false,
+	},
 	DisableApplyAllFlag: {
 		description:  "Disable \"atlantis apply\" command without any flags (i.e. apply all). A specific project/workspace/directory has to be specified for applies.",
 		defaultValue: false,
@@ -364,6 +475,18 @@ var boolFlags = map[string]boolFlag{
 		description:  "Enable Atlantis to format Terraform plan output into a markdown-diff friendly format for color-coding purposes.",
 		defaultValue: false,
 	},
+	EnablePlanSummaryFlag: {
+		description:  "Analyze every plan with terraform show to summarize the resources it changes in the plan comment and the plan commit status. This runs an extra Terraform command per plan unless the plan stage already runs show.",
+		defaultValue: false,
+	},
+	EnableRemoteWorkersFlag: {
+		description:  "Run the steps of project commands, ex. plan and apply, on workers started with atlantis worker instead of on the server. Workers must share --" + DataDirFlag + " with the server. Requires --" + APISecretFlag + ".",
+		defaultValue: false,
+	},
+	TerragruntDiscoveryFlag: {
+		description:  "Discover projects by parsing terragrunt.hcl files instead of looking for .tf files. Changes to included files, files read via read_terragrunt_config, local Terraform sources and dependencies also cause a project to be autoplanned.",
+		defaultValue: false,
+	},
 	AllowDraftPRs: {
 		description:  "Enable autoplan for Github Draft Pull Requests",
 		defaultValue: false,
@@ -373,6 +496,14 @@ var boolFlags = map[string]boolFlag{
 			"VCS support is limited to: GitHub.",
 		defaultValue: false,
 	},
+	RedisTLSEnabled: {
+		description:  "Enable TLS on the connection to Redis with a min TLS version of 1.2.",
+		defaultValue: DefaultRedisTLSEnabled,
+	},
+	RedisInsecureSkipVerify: {
+		description:  "Controls whether the Redis client verifies the Redis server's certificate chain and host name. If true, accepts any certificate presented by the server and any host name in that certificate.",
+		defaultValue: DefaultRedisInsecureSkipVerify,
+	},
 	RequireApprovalFlag: {
 		description:  "Require pull requests to be \"Approved\" before allowing the apply command to be run.",
 		defaultValue: false,
@@ -423,14 +554,42 @@ var boolFlags = map[string]boolFlag{
 	},
 }
 var intFlags = map[string]intFlag{
+	ConcurrencyLimitFlag: {
+		description:  "Max number of plans and applies that run at a time across all pull requests. Others wait in a queue. 0 means no limit.",
+		defaultValue: 0,
+	},
+	JobStoreRetentionDaysFlag: {
+		description:  "Days the output of completed jobs is kept in the job store before it's deleted.",
+		defaultValue: DefaultJobStoreRetentionDays,
+	},
 	ParallelPoolSize: {
 		description:  "Max size of the wait group that runs parallel plans and applies (if enabled).",
 		defaultValue: DefaultParallelPoolSize,
 	},
+	RepoConcurrencyLimitFlag: {
+		description:  "Max number of plans and applies of each repo that run at a time. Others wait in a queue. 0 means no limit.",
+		defaultValue: 0,
+	},
+	PolicyRefreshIntervalFlag: {
+		description:  "Minutes between downloads of policy sets with a git, http or oci source. Policy sets are downloaded again the first time they're used after this interval.",
+		defaultValue: DefaultPolicyRefreshInterval,
+	},
 	PortFlag: {
 		description:  "Port to bind to.",
 		defaultValue: DefaultPort,
 	},
+	RedisDB: {
+		description:  "The Redis Database to use when using a Locking DB type of 'redis'.",
+		defaultValue: DefaultRedisDB,
+	},
+	RedisPort: {
+		description:  "The Redis Port for when using a Locking DB type of 'redis'.",
+		defaultValue: DefaultRedisPort,
+	},
+	WorkerLeaseTimeoutFlag: {
+		description:  "Seconds a worker can go without renewing the lease of the job it's running before the job is given to another worker. Workers renew their leases every 10 seconds. Must be at least 30.",
+		defaultValue: DefaultWorkerLeaseTimeout,
+	},
 }
 
 var int64Flags = map[string]int64Flag{
@@ -447,6 +606,10 @@ type stringFlag struct {
 	description  string
 	defaultValue string
 	hidden       bool
+	// secret is true if the flag's value is a secret, ex. a token. The
+	// variables of secret flags aren't passed to Terraform, run steps or
+	// workflow hooks by default.
+	secret bool
 }
 type intFlag struct {
 	description  string
@@ -614,6 +777,7 @@ func (s *ServerCmd) run() error {
 	}
 	s.securityWarnings(&userConfig)
 	s.trimAtSymbolFromUsers(&userConfig)
+	valid.SecretEnvVars = secretEnvVars()
 
 	// Config looks good. Start the server.
 	server, err := s.ServerCreator.NewServer(userConfig, server.Config{
@@ -649,21 +813,39 @@ func (s *ServerCmd) setDefaults(c *server.UserConfig) {
 	if c.GitlabHostname == "" {
 		c.GitlabHostname = DefaultGitlabHostname
 	}
+	if c.GiteaHostname == "" {
+		c.GiteaHostname = DefaultGiteaHostname
+	}
 	if c.BitbucketBaseURL == "" {
 		c.BitbucketBaseURL = DefaultBitbucketBaseURL
 	}
+	if c.JobStore == "" {
+		c.JobStore = DefaultJobStore
+	}
+	if c.JobStoreRetentionDays == 0 {
+		c.JobStoreRetentionDays = DefaultJobStoreRetentionDays
+	}
+	if c.LockingDBType == "" {
+		c.LockingDBType = DefaultLockingDBType
+	}
 	if c.LogLevel == "" {
 		c.LogLevel = DefaultLogLevel
 	}
 	if c.ParallelPoolSize == 0 {
 		c.ParallelPoolSize = DefaultParallelPoolSize
 	}
+	if c.PolicyRefreshInterval == 0 {
+		c.PolicyRefreshInterval = DefaultPolicyRefreshInterval
+	}
 	if c.StatsNamespace == "" {
 		c.StatsNamespace = DefaultStatsNamespace
 	}
 	if c.Port == 0 {
 		c.Port = DefaultPort
 	}
+	if c.RedisPort == 0 {
+		c.RedisPort = DefaultRedisPort
+	}
 	if c.TFDownloadURL == "" {
 		c.TFDownloadURL = DefaultTFDownloadURL
 	}
@@ -679,6 +861,9 @@ func (s *ServerCmd) setDefaults(c *server.UserConfig) {
 	if c.WebPassword == "" {
 		c.WebPassword = DefaultWebPassword
 	}
+	if c.WorkerLeaseTimeout == 0 {
+		c.WorkerLeaseTimeout = DefaultWorkerLeaseTimeout
+	}
 }
 
 func (s *ServerCmd) validate(userConfig server.UserConfig) error {
@@ -692,6 +877,45 @@ func (s *ServerCmd) validate(userConfig server.UserConfig) error {
 		return errors.New("invalid checkout strategy: not one of branch or merge")
 	}
 
+	lockingDBType := userConfig.LockingDBType
+	if lockingDBType != "boltdb" && lockingDBType != "redis" {
+		return errors.New("invalid locking db type: not one of boltdb or redis")
+	}
+	if lockingDBType == "redis" && userConfig.RedisHost == "" {
+		return fmt.Errorf("--%s must be set when --%s is redis", RedisHost, LockingDBType)
+	}
+
+	jobStore := userConfig.JobStore
+	if jobStore != "memory" && jobStore != "disk" && jobStore != "s3" {
+		return errors.New("invalid job store: not one of memory, disk or s3")
+	}
+	if jobStore == "s3" && userConfig.JobStoreS3Bucket == "" {
+		return fmt.Errorf("--%s must be set when --%s is s3", JobStoreS3BucketFlag, JobStoreFlag)
+	}
+	if userConfig.JobStoreRetentionDays < 0 {
+		return fmt.Errorf("--%s must be positive", JobStoreRetentionDaysFlag)
+	}
+	if userConfig.ConcurrencyLimit < 0 {
+		return fmt.Errorf("--%s must be positive", ConcurrencyLimitFlag)
+	}
+	if userConfig.RepoConcurrencyLimit < 0 {
+		return fmt.Errorf("--%s must be positive", RepoConcurrencyLimitFlag)
+	}
+
+	if userConfig.EnableRemoteWorkers {
+		if userConfig.APISecret == "" {
+			return fmt.Errorf("--%s must be set when --%s is set", APISecretFlag, EnableRemoteWorkersFlag)
+		}
+		// Remote operations update commit statuses from the plan and apply
+		// steps, which workers can't do.
+		if userConfig.TFEToken != "" {
+			return fmt.Errorf("--%s can't be used with --%s", EnableRemoteWorkersFlag, TFETokenFlag)
+		}
+	}
+	if userConfig.WorkerLeaseTimeout < 30 {
+		return fmt.Errorf("--%s must be at least 30", WorkerLeaseTimeoutFlag)
+	}
+
 	if (userConfig.SSLKeyFile == "") != (userConfig.SSLCertFile == "") {
 		return fmt.Errorf("--%s and --%s are both required for ssl", SSLKeyFileFlag, SSLCertFileFlag)
 	}
@@ -702,9 +926,10 @@ func (s *ServerCmd) validate(userConfig server.UserConfig) error {
 	// 3. gitlab user and token set
 	// 4. bitbucket user and token set
 	// 5. azuredevops user and token set
-	// 6. any combination of the above
-	vcsErr := fmt.Errorf("--%s/--%s or --%s/--%s or --%s/--%s or --%s/--%s or --%s/--%s or --%s/--%s must be set", GHUserFlag, GHTokenFlag, GHAppIDFlag, GHAppKeyFileFlag, GHAppIDFlag, GHAppKeyFlag, GitlabUserFlag, GitlabTokenFlag, BitbucketUserFlag, BitbucketTokenFlag, ADUserFlag, ADTokenFlag)
-	if ((userConfig.GithubUser == "") != (userConfig.GithubToken == "")) || ((userConfig.GitlabUser == "") != (userConfig.GitlabToken == "")) || ((userConfig.BitbucketUser == "") != (userConfig.BitbucketToken == "")) || ((userConfig.AzureDevopsUser == "") != (userConfig.AzureDevopsToken == "")) {
+	// 6. gitea user and token set
+	// 7. any combination of the above
+	vcsErr := fmt.Errorf("--%s/--%s or --%s/--%s or --%s/--%s or --%s/--%s or --%s/--%s or --%s/--%s or --%s/--%s must be set", GHUserFlag, GHTokenFlag, GHAppIDFlag, GHAppKeyFileFlag, GHAppIDFlag, GHAppKeyFlag, GitlabUserFlag, GitlabTokenFlag, BitbucketUserFlag, BitbucketTokenFlag, ADUserFlag, ADTokenFlag, GiteaUserFlag, GiteaTokenFlag)
+	if ((userConfig.GithubUser == "") != (userConfig.GithubToken == "")) || ((userConfig.GitlabUser == "") != (userConfig.GitlabToken == "")) || ((userConfig.BitbucketUser == "") != (userConfig.BitbucketToken == "")) || ((userConfig.AzureDevopsUser == "") != (userConfig.AzureDevopsToken == "")) || ((userConfig.GiteaUser == "") != (userConfig.GiteaToken == "")) {
 		return vcsErr
 	}
 	if (userConfig.GithubAppID != 0) && ((userConfig.GithubAppKey == "") && (userConfig.GithubAppKeyFile == "")) {
@@ -715,7 +940,7 @@ func (s *ServerCmd) validate(userConfig server.UserConfig) error {
 	}
 	// At this point, we know that there can't be a single user/token without
 	// its partner, but we haven't checked if any user/token is set at all.
-	if userConfig.GithubAppID == 0 && userConfig.GithubUser == "" && userConfig.GitlabUser == "" && userConfig.BitbucketUser == "" && userConfig.AzureDevopsUser == "" {
+	if userConfig.GithubAppID == 0 && userConfig.GithubUser == "" && userConfig.GitlabUser == "" && userConfig.BitbucketUser == "" && userConfig.AzureDevopsUser == "" && userConfig.GiteaUser == "" {
 		return vcsErr
 	}
 
@@ -754,12 +979,16 @@ func (s *ServerCmd) validate(userConfig server.UserConfig) error {
 
 	// Warn if any tokens have newlines.
 	for name, token := range map[string]string{
+		APISecretFlag:              userConfig.APISecret,
 		GHTokenFlag:                userConfig.GithubToken,
 		GHWebhookSecretFlag:        userConfig.GithubWebhookSecret,
 		GitlabTokenFlag:            userConfig.GitlabToken,
 		GitlabWebhookSecretFlag:    userConfig.GitlabWebhookSecret,
 		BitbucketTokenFlag:         userConfig.BitbucketToken,
 		BitbucketWebhookSecretFlag: userConfig.BitbucketWebhookSecret,
+		GiteaTokenFlag:             userConfig.GiteaToken,
+		GiteaWebhookSecretFlag:     userConfig.GiteaWebhookSecret,
+		RedisPassword:              userConfig.RedisPassword,
 	} {
 		if strings.Contains(token, "\n") {
 			s.Logger.Warn("--%s contains a newline which is usually unintentional", name)
@@ -820,6 +1049,7 @@ func (s *ServerCmd) trimAtSymbolFromUsers(userConfig *server.UserConfig) {
 	userConfig.GitlabUser = strings.TrimPrefix(userConfig.GitlabUser, "@")
 	userConfig.BitbucketUser = strings.TrimPrefix(userConfig.BitbucketUser, "@")
 	userConfig.AzureDevopsUser = strings.TrimPrefix(userConfig.AzureDevopsUser, "@")
+	userConfig.GiteaUser = strings.TrimPrefix(userConfig.GiteaUser, "@")
 }
 
 func (s *ServerCmd) securityWarnings(userConfig *server.UserConfig) {
@@ -838,6 +1068,9 @@ func (s *ServerCmd) securityWarnings(userConfig *server.UserConfig) {
 	if userConfig.AzureDevopsWebhookUser != "" && userConfig.AzureDevopsWebhookPassword == "" && !s.SilenceOutput {
 		s.Logger.Warn("no Azure DevOps webhook user and password set. This could allow attackers to spoof requests from Azure DevOps.")
 	}
+	if userConfig.GiteaUser != "" && userConfig.GiteaWebhookSecret == "" && !s.SilenceOutput {
+		s.Logger.Warn("no Gitea webhook secret set. This could allow attackers to spoof requests from Gitea")
+	}
 }
 
 // deprecationWarnings prints a warning if flags that are deprecated are
@@ -923,3 +1156,16 @@ func isValidLogLevel(level string) bool {
 
 	return false
 }
+
+// secretEnvVars returns the environment variables of the secret string flags,
+// sorted.
+func secretEnvVars() []string {
+	var envVars []string
+	for name, f := range stringFlags {
+		if f.secret {
+			envVars = append(envVars, "ATLANTIS_"+strings.ToUpper(strings.ReplaceAll(name, "-", "_")))
+		}
+	}
+	sort.Strings(envVars)
+	return envVars
+}
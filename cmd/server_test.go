@@ -23,6 +23,7 @@ import (
 
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/runatlantis/atlantis/server"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/events/vcs/fixtures"
 	"github.com/runatlantis/atlantis/server/logging"
 	. "github.com/runatlantis/atlantis/testing"
@@ -58,13 +59,16 @@ var testFlags = map[string]interface{}{
 	AtlantisURLFlag:            "url",
 	AllowForkPRsFlag:           true,
 	AllowRepoConfigFlag:        true,
+	APISecretFlag:              "api-secret",
 	AutomergeFlag:              true,
 	AutoplanFileListFlag:       "**/*.tf,**/*.yml",
+	AutoplanModulesFlag:        true,
 	BitbucketBaseURLFlag:       "https://bitbucket-base-url.com",
 	BitbucketTokenFlag:         "bitbucket-token",
 	BitbucketUserFlag:          "bitbucket-user",
 	BitbucketWebhookSecretFlag: "bitbucket-secret",
 	CheckoutStrategyFlag:       "merge",
+	ConcurrencyLimitFlag:       10,
 	DataDirFlag:                "/path",
 	DefaultTFVersionFlag:       "v0.11.0",
 	DisableApplyAllFlag:        true,
@@ -80,16 +84,36 @@ var testFlags = map[string]interface{}{
 	GHAppSlugFlag:              "atlantis",
 	GHOrganizationFlag:         "",
 	GHWebhookSecretFlag:        "secret",
+	GiteaHostnameFlag:          "gitea-hostname",
+	GiteaTokenFlag:             "gitea-token",
+	GiteaUserFlag:              "gitea-user",
+	GiteaWebhookSecretFlag:     "gitea-secret",
 	GitlabHostnameFlag:         "gitlab-hostname",
 	GitlabTokenFlag:            "gitlab-token",
 	GitlabUserFlag:             "gitlab-user",
 	GitlabWebhookSecretFlag:    "gitlab-secret",
+	JobStoreFlag:               "s3",
+	JobStoreDirFlag:            "/jobs",
+	JobStoreRetentionDaysFlag:  7,
+	JobStoreS3BucketFlag:       "atlantis-jobs",
+	JobStoreS3EndpointFlag:     "http://minio:9000",
+	JobStoreS3PrefixFlag:       "jobs",
+	JobStoreS3RegionFlag:       "us-east-1",
+	LockingDBType:              "redis",
 	LogLevelFlag:               "debug",
 	StatsNamespace:             "atlantis",
 	AllowDraftPRs:              true,
 	PortFlag:                   8181,
 	ParallelPoolSize:           100,
+	PolicyRefreshIntervalFlag:  30,
+	RedisDB:                    1,
+	RedisHost:                  "redis.example.com",
+	RedisInsecureSkipVerify:    true,
+	RedisPassword:              "redis-password",
+	RedisPort:                  6380,
+	RedisTLSEnabled:            true,
 	RepoAllowlistFlag:          "github.com/runatlantis/atlantis",
+	RepoConcurrencyLimitFlag:   2,
 	RequireApprovalFlag:        true,
 	RequireMergeableFlag:       true,
 	SilenceNoProjectsFlag:      false,
@@ -109,6 +133,10 @@ var testFlags = map[string]interface{}{
 	EnablePolicyChecksFlag:     false,
 	EnableRegExpCmdFlag:        false,
 	EnableDiffMarkdownFormat:   false,
+	EnablePlanSummaryFlag:      true,
+	EnableRemoteWorkersFlag:    false,
+	TerragruntDiscoveryFlag:    false,
+	WorkerLeaseTimeoutFlag:     120,
 }
 
 func TestExecute_Defaults(t *testing.T) {
@@ -304,6 +332,76 @@ func TestExecute_ValidateCheckoutStrategy(t *testing.T) {
 	ErrEquals(t, "invalid checkout strategy: not one of branch or merge", err)
 }
 
+func TestExecute_ValidateLockingDBType(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		LockingDBType: "invalid",
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "invalid locking db type: not one of boltdb or redis", err)
+}
+
+func TestExecute_RedisRequiresHost(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		LockingDBType: "redis",
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "--redis-host must be set when --locking-db-type is redis", err)
+}
+
+func TestExecute_ValidateJobStore(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		JobStoreFlag: "invalid",
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "invalid job store: not one of memory, disk or s3", err)
+}
+
+func TestExecute_S3JobStoreRequiresBucket(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		JobStoreFlag: "s3",
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "--job-store-s3-bucket must be set when --job-store is s3", err)
+}
+
+func TestExecute_RemoteWorkersRequireAPISecret(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		EnableRemoteWorkersFlag: true,
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "--api-secret must be set when --enable-remote-workers is set", err)
+}
+
+func TestExecute_RemoteWorkersWithTFE(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		EnableRemoteWorkersFlag: true,
+		APISecretFlag:           "secret",
+		TFETokenFlag:            "token",
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "--enable-remote-workers can't be used with --tfe-token", err)
+}
+
+func TestExecute_ValidateWorkerLeaseTimeout(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		WorkerLeaseTimeoutFlag: 5,
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "--worker-lease-timeout must be at least 30", err)
+}
+
+func TestExecute_ValidateConcurrencyLimits(t *testing.T) {
+	for _, flag := range []string{ConcurrencyLimitFlag, RepoConcurrencyLimitFlag} {
+		t.Run(flag, func(t *testing.T) {
+			c := setupWithDefaults(map[string]interface{}{
+				flag: -1,
+			}, t)
+			err := c.Execute()
+			ErrEquals(t, "--"+flag+" must be positive", err)
+		})
+	}
+}
+
 func TestExecute_ValidateSSLConfig(t *testing.T) {
 	expErr := "--ssl-key-file and --ssl-cert-file are both required for ssl"
 	cases := []struct {
@@ -353,7 +451,7 @@ func TestExecute_ValidateSSLConfig(t *testing.T) {
 }
 
 func TestExecute_ValidateVCSConfig(t *testing.T) {
-	expErr := "--gh-user/--gh-token or --gh-app-id/--gh-app-key-file or --gh-app-id/--gh-app-key or --gitlab-user/--gitlab-token or --bitbucket-user/--bitbucket-token or --azuredevops-user/--azuredevops-token must be set"
+	expErr := "--gh-user/--gh-token or --gh-app-id/--gh-app-key-file or --gh-app-id/--gh-app-key or --gitlab-user/--gitlab-token or --bitbucket-user/--bitbucket-token or --azuredevops-user/--azuredevops-token or --gitea-user/--gitea-token must be set"
 	cases := []struct {
 		description string
 		flags       map[string]interface{}
@@ -392,6 +490,13 @@ func TestExecute_ValidateVCSConfig(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"just gitea token set",
+			map[string]interface{}{
+				GiteaTokenFlag: "token",
+			},
+			true,
+		},
 		{
 			"just github user set",
 			map[string]interface{}{
@@ -441,6 +546,13 @@ func TestExecute_ValidateVCSConfig(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"just gitea user set",
+			map[string]interface{}{
+				GiteaUserFlag: "user",
+			},
+			true,
+		},
 		{
 			"github user and gitlab token set",
 			map[string]interface{}{
@@ -513,6 +625,14 @@ func TestExecute_ValidateVCSConfig(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"gitea user and gitea token set and should be successful",
+			map[string]interface{}{
+				GiteaUserFlag:  "user",
+				GiteaTokenFlag: "token",
+			},
+			false,
+		},
 		{
 			"all set should be successful",
 			map[string]interface{}{
@@ -524,6 +644,8 @@ func TestExecute_ValidateVCSConfig(t *testing.T) {
 				BitbucketTokenFlag: "token",
 				ADUserFlag:         "user",
 				ADTokenFlag:        "token",
+				GiteaUserFlag:      "user",
+				GiteaTokenFlag:     "token",
 			},
 			false,
 		},
@@ -858,3 +980,27 @@ func configVal(t *testing.T, u server.UserConfig, tag string) interface{} {
 	t.Fatalf("no field with tag %q found", tag)
 	return nil
 }
+
+// Flags whose names say they're secrets must be marked secret so that their
+// variables aren't passed to Terraform, run steps or workflow hooks.
+func TestSecretEnvVars(t *testing.T) {
+	for name, f := range stringFlags {
+		looksSecret := name == GHAppKeyFlag
+		for _, word := range []string{"token", "secret", "password"} {
+			looksSecret = looksSecret || strings.Contains(name, word)
+		}
+		Equals(t, looksSecret, f.secret)
+	}
+
+	c := setup(map[string]interface{}{
+		GHUserFlag:        "user",
+		GHTokenFlag:       "token",
+		RepoAllowlistFlag: "*",
+	}, t)
+	Ok(t, c.Execute())
+	Equals(t, 16, len(valid.SecretEnvVars))
+	Equals(t, "ATLANTIS_API_SECRET", valid.SecretEnvVars[0])
+	Equals(t, "ATLANTIS_GH_APP_KEY", valid.SecretEnvVars[5])
+	Equals(t, "ATLANTIS_GH_TOKEN", valid.SecretEnvVars[6])
+	Equals(t, "ATLANTIS_WEB_PASSWORD", valid.SecretEnvVars[15])
+}
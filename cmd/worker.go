@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Flags of atlantis worker that the server doesn't have.
+const (
+	WorkerServerURLFlag   = "server-url"
+	WorkerNameFlag        = "name"
+	WorkerConcurrencyFlag = "concurrency"
+
+	DefaultWorkerConcurrency = 1
+)
+
+// WorkerCreator creates workers.
+// It's an abstraction to help us test.
+type WorkerCreator interface {
+	NewWorker(config server.WorkerConfig, logger logging.SimpleLogging) (ServerStarter, error)
+}
+
+// DefaultWorkerCreator is the concrete implementation of WorkerCreator.
+type DefaultWorkerCreator struct{}
+
+// NewWorker returns the real Atlantis worker object.
+func (d *DefaultWorkerCreator) NewWorker(config server.WorkerConfig, logger logging.SimpleLogging) (ServerStarter, error) {
+	return server.NewWorker(config, logger)
+}
+
+// WorkerCmd runs the steps of project commands for an Atlantis server started
+// with --enable-remote-workers.
+type WorkerCmd struct {
+	WorkerCreator WorkerCreator
+	Viper         *viper.Viper
+	Logger        logging.SimpleLogging
+}
+
+// Init returns the runnable cobra command.
+func (w *WorkerCmd) Init() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "worker",
+		Short: "Run plans, applies and other steps for an Atlantis server",
+		Long: `Run the steps of project commands, ex. plan and apply, for an Atlantis server
+started with --enable-remote-workers. The worker leases jobs from the server
+at --server-url and streams their output back to it.
+
+The worker must share the server's --data-dir at the same path, and is
+authenticated with the server's --api-secret.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return w.run()
+		},
+	}
+
+	w.Viper.SetEnvPrefix("ATLANTIS")
+	w.Viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	w.Viper.AutomaticEnv()
+	w.Viper.SetTypeByDefaultValue(true)
+
+	for _, name := range []string{APISecretFlag, DataDirFlag, DefaultTFVersionFlag, LogLevelFlag, TFDownloadURLFlag} {
+		f := stringFlags[name]
+		c.Flags().String(name, f.defaultValue, f.description)
+		w.Viper.BindPFlag(name, c.Flags().Lookup(name)) // nolint: errcheck
+	}
+	f := intFlags[PolicyRefreshIntervalFlag]
+	c.Flags().Int(PolicyRefreshIntervalFlag, f.defaultValue, f.description)
+	w.Viper.BindPFlag(PolicyRefreshIntervalFlag, c.Flags().Lookup(PolicyRefreshIntervalFlag)) // nolint: errcheck
+
+	c.Flags().String(WorkerServerURLFlag, "", "URL the worker reaches the Atlantis server at, ex. http://atlantis:4141.")
+	c.Flags().String(WorkerNameFlag, "", "Name of the worker in the server's logs. Defaults to the hostname.")
+	c.Flags().Int(WorkerConcurrencyFlag, DefaultWorkerConcurrency, "Number of jobs the worker runs at a time.")
+	for _, name := range []string{WorkerServerURLFlag, WorkerNameFlag, WorkerConcurrencyFlag} {
+		w.Viper.BindPFlag("worker-"+name, c.Flags().Lookup(name)) // nolint: errcheck
+	}
+	return c
+}
+
+func (w *WorkerCmd) run() error {
+	var userConfig server.UserConfig
+	if err := w.Viper.Unmarshal(&userConfig); err != nil {
+		return err
+	}
+	(&ServerCmd{}).setDefaults(&userConfig)
+	userConfig.LogLevel = strings.ToLower(userConfig.LogLevel)
+	if !isValidLogLevel(userConfig.LogLevel) {
+		return fmt.Errorf("invalid log level: must be one of %v", ValidLogLevels)
+	}
+	w.Logger.SetLevel(userConfig.ToLogLevel())
+	if err := (&ServerCmd{}).setDataDir(&userConfig); err != nil {
+		return err
+	}
+	valid.SecretEnvVars = secretEnvVars()
+
+	config := server.WorkerConfig{
+		ServerURL:            w.Viper.GetString("worker-" + WorkerServerURLFlag),
+		APISecret:            userConfig.APISecret,
+		DataDir:              userConfig.DataDir,
+		DefaultTFVersion:     userConfig.DefaultTFVersion,
+		DefaultTFVersionFlag: DefaultTFVersionFlag,
+		TFDownloadURL:        userConfig.TFDownloadURL,
+		Name:                 w.Viper.GetString("worker-" + WorkerNameFlag),
+		Concurrency:          w.Viper.GetInt("worker-" + WorkerConcurrencyFlag),
+		// Well under the minimum --worker-lease-timeout of the server.
+		RenewInterval:         10 * time.Second,
+		RetryInterval:         10 * time.Second,
+		OutputInterval:        time.Second,
+		PolicyRefreshInterval: userConfig.PolicyRefreshInterval,
+	}
+	if config.ServerURL == "" {
+		return fmt.Errorf("--%s must be set", WorkerServerURLFlag)
+	}
+	if config.APISecret == "" {
+		return fmt.Errorf("--%s must be set to the server's --%s", APISecretFlag, APISecretFlag)
+	}
+	if config.Concurrency < 1 {
+		return fmt.Errorf("--%s must be at least 1", WorkerConcurrencyFlag)
+	}
+	if config.Name == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return errors.Wrap(err, "determining hostname for --"+WorkerNameFlag)
+		}
+		config.Name = hostname
+	}
+
+	worker, err := w.WorkerCreator.NewWorker(config, w.Logger)
+	if err != nil {
+		return errors.Wrap(err, "initializing worker")
+	}
+	return worker.Start()
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+	"github.com/spf13/viper"
+)
+
+type WorkerCreatorMock struct {
+	config server.WorkerConfig
+}
+
+func (w *WorkerCreatorMock) NewWorker(config server.WorkerConfig, _ logging.SimpleLogging) (ServerStarter, error) {
+	w.config = config
+	return &ServerStarterMock{}, nil
+}
+
+func TestWorkerCmd_Config(t *testing.T) {
+	dataDir, cleanup := TempDir(t)
+	defer cleanup()
+	creator, err := runWorkerCmd(t,
+		"--server-url", "http://atlantis:4141",
+		"--api-secret", "secret",
+		"--data-dir", dataDir,
+		"--concurrency", "2",
+		"--name", "worker-1",
+	)
+	Ok(t, err)
+	Equals(t, "http://atlantis:4141", creator.config.ServerURL)
+	Equals(t, "secret", creator.config.APISecret)
+	Equals(t, dataDir, creator.config.DataDir)
+	Equals(t, 2, creator.config.Concurrency)
+	Equals(t, "worker-1", creator.config.Name)
+	Equals(t, DefaultPolicyRefreshInterval, creator.config.PolicyRefreshInterval)
+}
+
+func TestWorkerCmd_Defaults(t *testing.T) {
+	dataDir, cleanup := TempDir(t)
+	defer cleanup()
+	creator, err := runWorkerCmd(t, "--server-url", "http://atlantis:4141", "--api-secret", "secret", "--data-dir", filepath.Join(dataDir, "."))
+	Ok(t, err)
+	hostname, err := os.Hostname()
+	Ok(t, err)
+	Equals(t, hostname, creator.config.Name)
+	Equals(t, DefaultWorkerConcurrency, creator.config.Concurrency)
+	Equals(t, DefaultTFDownloadURL, creator.config.TFDownloadURL)
+}
+
+func TestWorkerCmd_Validate(t *testing.T) {
+	cases := []struct {
+		args   []string
+		expErr string
+	}{
+		{[]string{"--api-secret", "secret"}, "--server-url must be set"},
+		{[]string{"--server-url", "http://atlantis:4141"}, "--api-secret must be set to the server's --api-secret"},
+		{[]string{"--server-url", "http://atlantis:4141", "--api-secret", "secret", "--concurrency", "0"}, "--concurrency must be at least 1"},
+		{[]string{"--server-url", "http://atlantis:4141", "--api-secret", "secret", "--log-level", "verbose"}, "invalid log level: must be one of [debug info warn error]"},
+	}
+	for _, c := range cases {
+		t.Run(c.expErr, func(t *testing.T) {
+			_, err := runWorkerCmd(t, c.args...)
+			ErrEquals(t, c.expErr, err)
+		})
+	}
+}
+
+func runWorkerCmd(t *testing.T, args ...string) (*WorkerCreatorMock, error) {
+	t.Helper()
+	creator := &WorkerCreatorMock{}
+	c := (&WorkerCmd{
+		WorkerCreator: creator,
+		Viper:         viper.New(),
+		Logger:        logging.NewNoopLogger(t),
+	}).Init()
+	c.SetArgs(args)
+	c.SilenceErrors = true
+	return creator, c.Execute()
+}
@@ -43,8 +43,18 @@ func main() {
 	}
 	version := &cmd.VersionCmd{AtlantisVersion: atlantisVersion}
 	testdrive := &cmd.TestdriveCmd{}
+	database := &cmd.DBCmd{Viper: viper.New()}
+	sandboxExec := &cmd.SandboxExecCmd{}
+	worker := &cmd.WorkerCmd{
+		WorkerCreator: &cmd.DefaultWorkerCreator{},
+		Viper:         viper.New(),
+		Logger:        logger,
+	}
 	cmd.RootCmd.AddCommand(server.Init())
 	cmd.RootCmd.AddCommand(version.Init())
 	cmd.RootCmd.AddCommand(testdrive.Init())
+	cmd.RootCmd.AddCommand(database.Init())
+	cmd.RootCmd.AddCommand(sandboxExec.Init())
+	cmd.RootCmd.AddCommand(worker.Init())
 	cmd.Execute()
 }
@@ -74,14 +74,16 @@ module.exports = {
                         'checkout-strategy',
                         'terraform-versions',
                         'terraform-cloud',
-                        'using-slack-hooks'
+                        'using-slack-hooks',
+                        'sending-http-webhooks'
                     ]
                 },
                 {
                     title: 'Using Atlantis',
                     collapsable: true,
                     children: [
-                        ['using-atlantis', 'Overview']
+                        ['using-atlantis', 'Overview'],
+                        'api-endpoints'
                     ]
                 },
                 {
@@ -0,0 +1,560 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/db"
+	"github.com/runatlantis/atlantis/server/core/locking"
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/vcs"
+	"github.com/runatlantis/atlantis/server/jobs"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/uber-go/tally"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// APISecretHeader is the header that API requests must set to the value of
+// --api-secret.
+const APISecretHeader = "X-Atlantis-Token"
+
+// APIController handles the /api routes. They run plan and apply for a repo
+// and ref without needing a pull request comment, ex. from a CI pipeline,
+// return the state of locks, pull requests and jobs as JSON, and export and
+// import the locking database.
+type APIController struct {
+	// APISecret authenticates requests. If it's empty the API is disabled.
+	APISecret                 []byte
+	DB                        db.Database
+	Locker                    locking.Locker
+	Logger                    logging.SimpleLogging
+	Parser                    events.EventParsing
+	ProjectCmdOutputHandler   jobs.ProjectCommandOutputHandler
+	ProjectCommandBuilder     events.ProjectCommandBuilder
+	ProjectPlanCommandRunner  events.ProjectPlanCommandRunner
+	ProjectApplyCommandRunner events.ProjectApplyCommandRunner
+	RepoAllowlistChecker      *events.RepoAllowlistChecker
+	Scope                     tally.Scope
+	VCSClient                 vcs.Client
+	// Webhooks is sent lock_released events for the locks released after
+	// requests without a pull request. It may be nil.
+	Webhooks events.WebhooksSender
+}
+
+// APIRequest is the body of a request to /api/plan or /api/apply.
+type APIRequest struct {
+	// Repository is the full name of the repo, ex. runatlantis/atlantis.
+	Repository string `validate:"required"`
+	// Ref is the branch or commit to run the command against.
+	Ref string `validate:"required"`
+	// Type is the VCS host of the repo, ex. Github or Gitlab.
+	Type string `validate:"required"`
+	// PR is the optional pull request number the command is for. Locks held
+	// for a request without a pull request are released once it completes.
+	PR int
+	// Projects are the names of the projects to run the command for.
+	Projects []string
+	// Paths are the dirs and workspaces to run the command for.
+	Paths []struct {
+		Directory string
+		Workspace string
+	}
+}
+
+// APIProjectResult is the JSON representation of a command.ProjectResult.
+type APIProjectResult struct {
+	command.ProjectResult
+	// Command and Error shadow the fields of command.ProjectResult which
+	// don't serialize to anything readable.
+	Command string
+	Error   string `json:",omitempty"`
+}
+
+// APIResponse is the body of the response to /api/plan or /api/apply.
+type APIResponse struct {
+	Error          string `json:",omitempty"`
+	Failure        string `json:",omitempty"`
+	ProjectResults []APIProjectResult
+}
+
+// APILock is a project lock returned by GET /api/locks.
+type APILock struct {
+	// ID is the id of the lock as used by the /lock and /locks routes.
+	ID string
+	models.ProjectLock
+}
+
+// APILocksResponse is the body of the response to GET /api/locks.
+type APILocksResponse struct {
+	Locks []APILock
+}
+
+// APIProjectStatus is the JSON representation of a models.ProjectStatus.
+type APIProjectStatus struct {
+	models.ProjectStatus
+	// Status shadows the field of models.ProjectStatus so that it's
+	// serialized as a string, ex. planned.
+	Status string
+}
+
+// APIPullStatus is the body of the response to GET /api/pulls/{repo}/{num}.
+type APIPullStatus struct {
+	Pull     models.PullRequest
+	Projects []APIProjectStatus
+}
+
+// APIJobsResponse is the body of the response to GET /api/jobs.
+type APIJobsResponse struct {
+	Jobs []jobs.Job
+}
+
+// APIDriftResponse is the body of the response to GET /api/drift.
+type APIDriftResponse struct {
+	Repos []models.DriftStatus
+}
+
+// APIDBImportResponse is the body of the response to POST /api/db/import.
+type APIDBImportResponse struct {
+	ProjectLocks  int
+	CommandLocks  int
+	PullStatuses  int
+	DriftStatuses int
+}
+
+func (c *APIRequest) getCommands(ctx *command.Context, cmdBuilder func(*command.Context, *events.CommentCommand) ([]command.ProjectContext, error)) ([]command.ProjectContext, error) {
+	cc := make([]*events.CommentCommand, 0)
+
+	for _, project := range c.Projects {
+		cc = append(cc, &events.CommentCommand{
+			ProjectName: project,
+		})
+	}
+	for _, path := range c.Paths {
+		cc = append(cc, &events.CommentCommand{
+			RepoRelDir: path.Directory,
+			Workspace:  path.Workspace,
+		})
+	}
+
+	cmds := make([]command.ProjectContext, 0)
+	for _, commentCommand := range cc {
+		projectCmds, err := cmdBuilder(ctx, commentCommand)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build command: %v", err)
+		}
+		cmds = append(cmds, projectCmds...)
+	}
+	return cmds, nil
+}
+
+// Plan is the POST /api/plan route. It runs plan for the projects and paths
+// in the request and responds with the results.
+func (a *APIController) Plan(w http.ResponseWriter, r *http.Request) {
+	request, ctx, code, err := a.apiParseAndValidate(r)
+	if err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+
+	result, err := a.apiPlan(request, ctx)
+	if err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if request.PR == 0 {
+		a.unlockAPILocks(ctx, result)
+	}
+	a.respondResult(w, result)
+}
+
+// Apply is the POST /api/apply route. It plans and then applies the projects
+// and paths in the request and responds with the results. If any plan fails
+// nothing is applied and the plan results are returned.
+func (a *APIController) Apply(w http.ResponseWriter, r *http.Request) {
+	request, ctx, code, err := a.apiParseAndValidate(r)
+	if err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+
+	// We must first make the plan for all projects.
+	planResult, err := a.apiPlan(request, ctx)
+	if err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if request.PR == 0 {
+		defer a.unlockAPILocks(ctx, planResult)
+	}
+	if planResult.HasErrors() {
+		a.respondResult(w, planResult)
+		return
+	}
+
+	// We can now apply all of them.
+	result, err := a.apiApply(request, ctx)
+	if err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, err)
+		return
+	}
+	a.respondResult(w, result)
+}
+
+// ListLocks is the GET /api/locks route. It responds with all the project
+// locks, newest first.
+func (a *APIController) ListLocks(w http.ResponseWriter, r *http.Request) {
+	if code, err := a.apiAuthenticate(r); err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+
+	locks, err := a.Locker.List()
+	if err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, errors.Wrap(err, "listing locks"))
+		return
+	}
+	response := APILocksResponse{Locks: []APILock{}}
+	for id, lock := range locks {
+		lock.Pull = sanitizePull(lock.Pull)
+		response.Locks = append(response.Locks, APILock{ID: id, ProjectLock: lock})
+	}
+	sort.SliceStable(response.Locks, func(i, j int) bool { return response.Locks[i].Time.After(response.Locks[j].Time) })
+	a.respondJSON(w, http.StatusOK, response)
+}
+
+// GetPull is the GET /api/pulls/{repo}/{num} route. It responds with the
+// status of each project in the pull request. repo is the full name of the
+// repo, ex. runatlantis/atlantis. If the same repo name exists on multiple
+// VCS hosts, the hostname query parameter selects the host.
+func (a *APIController) GetPull(w http.ResponseWriter, r *http.Request) {
+	if code, err := a.apiAuthenticate(r); err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+
+	vars := mux.Vars(r)
+	repo := vars["repo"]
+	num, err := strconv.Atoi(vars["num"])
+	if repo == "" || err != nil {
+		a.apiReportError(w, http.StatusBadRequest, fmt.Errorf("invalid pull request %q#%q", vars["repo"], vars["num"]))
+		return
+	}
+	hostname := r.URL.Query().Get("hostname")
+
+	statuses, err := a.DB.ListPullStatuses()
+	if err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, errors.Wrap(err, "listing pull statuses"))
+		return
+	}
+	for _, status := range statuses {
+		if status.Pull.BaseRepo.FullName != repo || status.Pull.Num != num {
+			continue
+		}
+		if hostname != "" && status.Pull.BaseRepo.VCSHost.Hostname != hostname {
+			continue
+		}
+		response := APIPullStatus{
+			Pull:     sanitizePull(status.Pull),
+			Projects: []APIProjectStatus{},
+		}
+		for _, project := range status.Projects {
+			response.Projects = append(response.Projects, APIProjectStatus{
+				ProjectStatus: project,
+				Status:        project.Status.String(),
+			})
+		}
+		a.respondJSON(w, http.StatusOK, response)
+		return
+	}
+	a.apiReportError(w, http.StatusNotFound, fmt.Errorf("no status found for pull request %s#%d", repo, num))
+}
+
+// ListJobs is the GET /api/jobs route. It responds with the jobs whose
+// output can still be streamed from /jobs/{job-id}.
+func (a *APIController) ListJobs(w http.ResponseWriter, r *http.Request) {
+	if code, err := a.apiAuthenticate(r); err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+
+	response := APIJobsResponse{Jobs: a.ProjectCmdOutputHandler.ListJobs()}
+	if response.Jobs == nil {
+		response.Jobs = []jobs.Job{}
+	}
+	sort.SliceStable(response.Jobs, func(i, j int) bool { return response.Jobs[i].JobID < response.Jobs[j].JobID })
+	a.respondJSON(w, http.StatusOK, response)
+}
+
+// ListDrift is the GET /api/drift route. It responds with the result of the
+// last drift detection run for each repo. If the drifted query parameter is
+// true, only repos with drifted projects are included.
+func (a *APIController) ListDrift(w http.ResponseWriter, r *http.Request) {
+	if code, err := a.apiAuthenticate(r); err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+
+	statuses, err := a.DB.ListDriftStatuses()
+	if err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, errors.Wrap(err, "listing drift statuses"))
+		return
+	}
+	onlyDrifted := r.URL.Query().Get("drifted") == "true"
+	response := APIDriftResponse{Repos: []models.DriftStatus{}}
+	for _, status := range statuses {
+		if onlyDrifted && len(status.DriftedProjects()) == 0 {
+			continue
+		}
+		response.Repos = append(response.Repos, status)
+	}
+	sort.SliceStable(response.Repos, func(i, j int) bool { return response.Repos[i].RepoFullName < response.Repos[j].RepoFullName })
+	a.respondJSON(w, http.StatusOK, response)
+}
+
+// ExportDB is the GET /api/db/export route. It responds with the contents of
+// the locking database in the format written by atlantis db export, so they
+// can be exported while the server is running. Like the other routes, the VCS
+// credentials in clone URLs are redacted; they aren't needed to import.
+func (a *APIController) ExportDB(w http.ResponseWriter, r *http.Request) {
+	if code, err := a.apiAuthenticate(r); err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+
+	e, err := db.Dump(a.DB)
+	if err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, errors.Wrap(err, "exporting database"))
+		return
+	}
+	for i := range e.ProjectLocks {
+		e.ProjectLocks[i].Pull = sanitizePull(e.ProjectLocks[i].Pull)
+	}
+	for i := range e.PullStatuses {
+		e.PullStatuses[i].Pull = sanitizePull(e.PullStatuses[i].Pull)
+	}
+	a.respondJSON(w, http.StatusOK, e)
+}
+
+// ImportDB is the POST /api/db/import route. It loads an export into the
+// locking database of the running server, see db.Load.
+func (a *APIController) ImportDB(w http.ResponseWriter, r *http.Request) {
+	if code, err := a.apiAuthenticate(r); err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+
+	bytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.apiReportError(w, http.StatusBadRequest, errors.Wrap(err, "failed to read request"))
+		return
+	}
+	var e db.Export
+	if err := json.Unmarshal(bytes, &e); err != nil {
+		a.apiReportError(w, http.StatusBadRequest, errors.Wrap(err, "failed to parse request"))
+		return
+	}
+	if e.Version != db.ExportVersion {
+		a.apiReportError(w, http.StatusBadRequest, fmt.Errorf("unsupported export version %d, expected %d", e.Version, db.ExportVersion))
+		return
+	}
+	if err := db.Load(a.DB, e); err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, errors.Wrap(err, "importing database"))
+		return
+	}
+	a.respondJSON(w, http.StatusOK, APIDBImportResponse{
+		ProjectLocks:  len(e.ProjectLocks),
+		CommandLocks:  len(e.CommandLocks),
+		PullStatuses:  len(e.PullStatuses),
+		DriftStatuses: len(e.DriftStatuses),
+	})
+}
+
+func (a *APIController) apiPlan(request *APIRequest, ctx *command.Context) (*command.Result, error) {
+	cmds, err := request.getCommands(ctx, a.ProjectCommandBuilder.BuildPlanCommands)
+	if err != nil {
+		return nil, err
+	}
+
+	var projectResults []command.ProjectResult
+	for _, cmd := range cmds {
+		projectResults = append(projectResults, a.ProjectPlanCommandRunner.Plan(cmd))
+	}
+	return &command.Result{ProjectResults: projectResults}, nil
+}
+
+func (a *APIController) apiApply(request *APIRequest, ctx *command.Context) (*command.Result, error) {
+	cmds, err := request.getCommands(ctx, a.ProjectCommandBuilder.BuildApplyCommands)
+	if err != nil {
+		return nil, err
+	}
+
+	var projectResults []command.ProjectResult
+	for _, cmd := range cmds {
+		projectResults = append(projectResults, a.ProjectApplyCommandRunner.Apply(cmd))
+	}
+	return &command.Result{ProjectResults: projectResults}, nil
+}
+
+// apiParseAndValidate authenticates the request and parses it into the
+// context that the project commands are built from. On error the returned
+// int is the HTTP status code to respond with.
+func (a *APIController) apiParseAndValidate(r *http.Request) (*APIRequest, *command.Context, int, error) {
+	if code, err := a.apiAuthenticate(r); err != nil {
+		return nil, nil, code, err
+	}
+
+	// Parse the JSON payload.
+	bytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, nil, http.StatusBadRequest, errors.Wrap(err, "failed to read request")
+	}
+	var request APIRequest
+	if err = json.Unmarshal(bytes, &request); err != nil {
+		return nil, nil, http.StatusBadRequest, errors.Wrap(err, "failed to parse request")
+	}
+	if err = validator.New().Struct(request); err != nil {
+		return nil, nil, http.StatusBadRequest, errors.Wrap(err, "request is missing fields")
+	}
+	if len(request.Projects) == 0 && len(request.Paths) == 0 {
+		return nil, nil, http.StatusBadRequest, errors.New("request must specify at least one project or path")
+	}
+
+	vcsHostType, err := parseVCSHostType(request.Type)
+	if err != nil {
+		return nil, nil, http.StatusBadRequest, err
+	}
+	cloneURL, err := a.VCSClient.GetCloneURL(vcsHostType, request.Repository)
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError, errors.Wrap(err, "getting clone url")
+	}
+	baseRepo, err := a.Parser.ParseAPIPlanRequest(vcsHostType, request.Repository, cloneURL)
+	if err != nil {
+		return nil, nil, http.StatusBadRequest, errors.Wrap(err, "parsing repository")
+	}
+
+	// Check if the repo is allowlisted.
+	if !a.RepoAllowlistChecker.IsAllowlisted(baseRepo.FullName, baseRepo.VCSHost.Hostname) {
+		return nil, nil, http.StatusForbidden, fmt.Errorf("repo %s is not allowlisted", baseRepo.FullName)
+	}
+
+	return &request, &command.Context{
+		HeadRepo: baseRepo,
+		Pull: models.PullRequest{
+			Num:        request.PR,
+			BaseBranch: request.Ref,
+			HeadBranch: request.Ref,
+			HeadCommit: request.Ref,
+			BaseRepo:   baseRepo,
+		},
+		Scope:   a.Scope.SubScope("api"),
+		Log:     a.Logger.WithHistory("repository", baseRepo.FullName, "ref", request.Ref),
+		Trigger: command.APITrigger,
+	}, http.StatusOK, nil
+}
+
+// apiAuthenticate checks that the request has the API secret. On error the
+// returned int is the HTTP status code to respond with.
+func (a *APIController) apiAuthenticate(r *http.Request) (int, error) {
+	if len(a.APISecret) == 0 {
+		return http.StatusBadRequest, errors.New("ignoring request since API is disabled")
+	}
+
+	// Validate the secret token.
+	secret := r.Header.Get(APISecretHeader)
+	if subtle.ConstantTimeCompare([]byte(secret), a.APISecret) != 1 {
+		return http.StatusUnauthorized, fmt.Errorf("header %s did not match expected secret", APISecretHeader)
+	}
+	return http.StatusOK, nil
+}
+
+// unlockAPILocks releases the locks taken by the plans in planResult, run
+// for a request that isn't for a pull request, so that the next request can
+// take them. Every request without a pull request has pull number 0 so only
+// the locks of the plans that succeeded are released rather than all the
+// locks of pull 0, which may be held by other requests. Plans that fail
+// release their lock themselves.
+func (a *APIController) unlockAPILocks(ctx *command.Context, planResult *command.Result) {
+	for _, result := range planResult.ProjectResults {
+		if result.PlanSuccess == nil {
+			continue
+		}
+		key := locking.Key(models.NewProject(ctx.Pull.BaseRepo.FullName, result.RepoRelDir), result.Workspace)
+		if err := events.UnlockProjectLock(a.Locker, a.Webhooks, ctx.Log, key); err != nil {
+			ctx.Log.Err("unable to release lock %q: %s", key, err)
+		}
+	}
+}
+
+func (a *APIController) respondResult(w http.ResponseWriter, result *command.Result) {
+	response := APIResponse{
+		Failure:        result.Failure,
+		ProjectResults: []APIProjectResult{},
+	}
+	if result.Error != nil {
+		response.Error = result.Error.Error()
+	}
+	for _, r := range result.ProjectResults {
+		projectResult := APIProjectResult{
+			ProjectResult: r,
+			Command:       r.Command.String(),
+		}
+		if r.Error != nil {
+			projectResult.Error = r.Error.Error()
+		}
+		response.ProjectResults = append(response.ProjectResults, projectResult)
+	}
+
+	code := http.StatusOK
+	if result.HasErrors() {
+		code = http.StatusInternalServerError
+	}
+	a.respondJSON(w, code, response)
+}
+
+func (a *APIController) apiReportError(w http.ResponseWriter, code int, err error) {
+	lvl := logging.Warn
+	if code == http.StatusInternalServerError {
+		lvl = logging.Error
+	}
+	a.Logger.Log(lvl, "api request failed: %s", err)
+	a.respondJSON(w, code, APIResponse{Error: err.Error(), ProjectResults: []APIProjectResult{}})
+}
+
+func (a *APIController) respondJSON(w http.ResponseWriter, code int, response interface{}) {
+	serialized, err := json.Marshal(response)
+	if err != nil {
+		a.Logger.Err("failed to serialize api response: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(serialized) // nolint: errcheck
+}
+
+// sanitizePull removes the VCS credentials from the clone URL of pull so that
+// it can be returned by the API.
+func sanitizePull(pull models.PullRequest) models.PullRequest {
+	pull.BaseRepo.CloneURL = pull.BaseRepo.SanitizedCloneURL
+	return pull
+}
+
+// parseVCSHostType returns the models.VCSHostType whose String() is t. Only
+// the hosts whose clients can return a clone URL are supported.
+func parseVCSHostType(t string) (models.VCSHostType, error) {
+	for _, h := range []models.VCSHostType{models.Github, models.Gitlab, models.Gitea} {
+		if h.String() == t {
+			return h, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported repository type %q", t)
+}
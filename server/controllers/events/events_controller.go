@@ -14,6 +14,7 @@
 package events
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -28,6 +29,7 @@ import (
 	"github.com/runatlantis/atlantis/server/events/vcs"
 	"github.com/runatlantis/atlantis/server/events/vcs/bitbucketcloud"
 	"github.com/runatlantis/atlantis/server/events/vcs/bitbucketserver"
+	"github.com/runatlantis/atlantis/server/events/vcs/gitea"
 	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/uber-go/tally"
 	gitlab "github.com/xanzy/go-gitlab"
@@ -86,11 +88,24 @@ type VCSEventsController struct {
 	// Azure DevOps Team Project. If empty, no request validation is done.
 	AzureDevopsWebhookBasicPassword []byte
 	AzureDevopsRequestValidator     AzureDevopsRequestValidator
+	// GiteaWebhookSecret is the secret added to this webhook via the Gitea
+	// or Forgejo UI that identifies this call as coming from Gitea. If empty,
+	// no request validation is done.
+	GiteaWebhookSecret []byte
 }
 
 // Post handles POST webhook requests.
 func (e *VCSEventsController) Post(w http.ResponseWriter, r *http.Request) {
-	if r.Header.Get(githubHeader) != "" {
+	// Gitea also sends the GitHub event header so it must be checked first.
+	if r.Header.Get(gitea.EventHeader) != "" {
+		if !e.supportsHost(models.Gitea) {
+			e.respond(w, logging.Debug, http.StatusBadRequest, "Ignoring request since not configured to support Gitea")
+			return
+		}
+		e.Logger.Debug("handling Gitea post")
+		e.handleGiteaPost(w, r)
+		return
+	} else if r.Header.Get(githubHeader) != "" {
 		if !e.supportsHost(models.Github) {
 			e.respond(w, logging.Debug, http.StatusBadRequest, "Ignoring request since not configured to support GitHub")
 			return
@@ -278,6 +293,36 @@ func (e *VCSEventsController) handleAzureDevopsPost(w http.ResponseWriter, r *ht
 	}
 }
 
+func (e *VCSEventsController) handleGiteaPost(w http.ResponseWriter, r *http.Request) {
+	eventType := r.Header.Get(gitea.EventHeader)
+	reqID := r.Header.Get(gitea.DeliveryHeader)
+	sig := r.Header.Get(gitea.SignatureHeader)
+	defer r.Body.Close() // nolint: errcheck
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		e.respond(w, logging.Error, http.StatusBadRequest, "Unable to read body: %s %s=%s", err, gitea.DeliveryHeader, reqID)
+		return
+	}
+	if len(e.GiteaWebhookSecret) > 0 {
+		if err := gitea.ValidateSignature(body, sig, e.GiteaWebhookSecret); err != nil {
+			e.respond(w, logging.Warn, http.StatusBadRequest, errors.Wrap(err, "request did not pass validation").Error())
+			return
+		}
+	}
+	switch eventType {
+	case gitea.PullRequestHeader:
+		e.Logger.Debug("handling as pull request event")
+		e.handleGiteaPullRequestEvent(w, body, reqID)
+		return
+	case gitea.IssueCommentHeader:
+		e.Logger.Debug("handling as comment event")
+		e.HandleGiteaCommentEvent(w, body, reqID)
+		return
+	default:
+		e.respond(w, logging.Debug, http.StatusOK, "Ignoring unsupported event type %s %s=%s", eventType, gitea.DeliveryHeader, reqID)
+	}
+}
+
 // HandleGithubCommentEvent handles comment events from GitHub where Atlantis
 // commands can come from. It's exported to make testing easier.
 func (e *VCSEventsController) HandleGithubCommentEvent(event *github.IssueCommentEvent, githubReqID string, logger logging.SimpleLogging) HTTPResponse {
@@ -391,6 +436,69 @@ func (e *VCSEventsController) handleBitbucketServerPullRequestEvent(w http.Respo
 	e.respond(w, lvl, code, msg)
 }
 
+// HandleGiteaCommentEvent handles comment events from Gitea where Atlantis
+// commands can come from. It's exported to make testing easier.
+func (e *VCSEventsController) HandleGiteaCommentEvent(w http.ResponseWriter, body []byte, reqID string) {
+	var event gitea.IssueCommentEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		e.respond(w, logging.Error, http.StatusBadRequest, "Error parsing webhook: %s %s=%s", err, gitea.DeliveryHeader, reqID)
+		return
+	}
+	// Gitea sends the same event for comments on issues and pull requests.
+	if !event.IsPull {
+		e.respond(w, logging.Debug, http.StatusOK, "Ignoring comment event since it was not on a pull request %s=%s", gitea.DeliveryHeader, reqID)
+		return
+	}
+	if event.Action == nil || *event.Action != "created" {
+		e.respond(w, logging.Debug, http.StatusOK, "Ignoring comment event since action was not created %s=%s", gitea.DeliveryHeader, reqID)
+		return
+	}
+	baseRepo, user, pullNum, comment, err := e.Parser.ParseGiteaIssueCommentEvent(event)
+	if err != nil {
+		e.respond(w, logging.Error, http.StatusBadRequest, "Error parsing comment data: %s %s=%s", err, gitea.DeliveryHeader, reqID)
+		return
+	}
+
+	// We pass in nil for maybeHeadRepo because the head repo data isn't
+	// available in the comment event.
+	resp := e.handleCommentEvent(e.Logger, baseRepo, nil, nil, user, pullNum, comment, models.Gitea)
+
+	lvl := logging.Debug
+	code := http.StatusOK
+	msg := resp.body
+	if resp.err.code != 0 {
+		lvl = logging.Error
+		code = resp.err.code
+		msg = resp.err.err.Error()
+	}
+	e.respond(w, lvl, code, msg)
+}
+
+func (e *VCSEventsController) handleGiteaPullRequestEvent(w http.ResponseWriter, body []byte, reqID string) {
+	var event gitea.PullRequestEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		e.respond(w, logging.Error, http.StatusBadRequest, "Error parsing webhook: %s %s=%s", err, gitea.DeliveryHeader, reqID)
+		return
+	}
+	pull, pullEventType, baseRepo, headRepo, user, err := e.Parser.ParseGiteaPullEvent(event)
+	if err != nil {
+		e.respond(w, logging.Error, http.StatusBadRequest, "Error parsing pull data: %s %s=%s", err, gitea.DeliveryHeader, reqID)
+		return
+	}
+	e.Logger.Info("identified event as type %q", pullEventType.String())
+	resp := e.handlePullRequestEvent(e.Logger, baseRepo, headRepo, pull, user, pullEventType)
+
+	lvl := logging.Debug
+	code := http.StatusOK
+	msg := resp.body
+	if resp.err.code != 0 {
+		lvl = logging.Error
+		code = resp.err.code
+		msg = resp.err.err.Error()
+	}
+	e.respond(w, lvl, code, msg)
+}
+
 // HandleGithubPullRequestEvent will delete any locks associated with the pull
 // request if the event is a pull request closed event. It's exported to make
 // testing easier.
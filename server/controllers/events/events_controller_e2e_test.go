@@ -943,7 +943,7 @@ func setupE2E(t *testing.T, repoDir string) (events_controllers.VCSEventsControl
 
 	conftestVersion, _ := version.NewVersion(ConftestVersion)
 
-	conftextExec := policy.NewConfTestExecutorWorkflow(logger, binDir, &NoopTFDownloader{})
+	conftextExec := policy.NewConfTestExecutorWorkflow(logger, binDir, filepath.Join(dataDir, "policies"), 0, &NoopTFDownloader{})
 
 	// swapping out version cache to something that always returns local contest
 	// binary
@@ -1051,6 +1051,7 @@ func setupE2E(t *testing.T, repoDir string) (events_controllers.VCSEventsControl
 		projectCommandRunner,
 		pullUpdater,
 		dbUpdater,
+		e2eVCSClient,
 		silenceNoProjects,
 		false,
 	)
@@ -1131,7 +1132,7 @@ func (m *mockLockURLGenerator) GenerateLockURL(lockID string) string {
 
 type mockWebhookSender struct{}
 
-func (w *mockWebhookSender) Send(log logging.SimpleLogging, result webhooks.ApplyResult) error {
+func (w *mockWebhookSender) Send(log logging.SimpleLogging, result webhooks.EventResult) error {
 	return nil
 }
 
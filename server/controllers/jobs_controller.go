@@ -9,6 +9,7 @@ import (
 	"github.com/runatlantis/atlantis/server/controllers/templates"
 	"github.com/runatlantis/atlantis/server/controllers/websocket"
 	"github.com/runatlantis/atlantis/server/core/db"
+	"github.com/runatlantis/atlantis/server/jobs"
 	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/runatlantis/atlantis/server/metrics"
 	"github.com/uber-go/tally"
@@ -31,10 +32,13 @@ type JobsController struct {
 	Logger                   logging.SimpleLogging
 	ProjectJobsTemplate      templates.TemplateWriter
 	ProjectJobsErrorTemplate templates.TemplateWriter
-	Db                       *db.BoltDB
+	Db                       db.Database
 	WsMux                    *websocket.Multiplexor
 	KeyGenerator             JobIDKeyGenerator
 	StatsScope               tally.Scope
+	// JobStore holds the output of completed jobs. If nil, jobs are only
+	// available while they're in memory.
+	JobStore jobs.JobStore
 }
 
 func (j *JobsController) getProjectJobs(w http.ResponseWriter, r *http.Request) error {
@@ -51,6 +55,21 @@ func (j *JobsController) getProjectJobs(w http.ResponseWriter, r *http.Request)
 		CleanedBasePath: j.AtlantisURL.Path,
 	}
 
+	// Completed jobs are rendered from the store since they may have
+	// finished on a previous run of Atlantis. Otherwise, the page streams
+	// the output over the websocket.
+	if j.JobStore != nil {
+		job, err := j.JobStore.Get(jobID)
+		if err != nil {
+			j.respond(w, logging.Error, http.StatusInternalServerError, "Error getting job: %s", err)
+			return err
+		}
+		if job != nil {
+			viewData.Completed = true
+			viewData.Output = job.Output
+		}
+	}
+
 	return j.ProjectJobsTemplate.Execute(w, viewData)
 }
 
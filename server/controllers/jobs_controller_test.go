@@ -0,0 +1,59 @@
+package controllers_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/runatlantis/atlantis/server/controllers"
+	"github.com/runatlantis/atlantis/server/controllers/templates"
+	"github.com/runatlantis/atlantis/server/jobs"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+	"github.com/uber-go/tally"
+)
+
+func TestJobsController_GetProjectJobs(t *testing.T) {
+	store, err := jobs.NewLocalJobStore(t.TempDir())
+	Ok(t, err)
+	Ok(t, store.Write(jobs.StoredJob{
+		Job:    jobs.Job{JobID: "completed-job", OperationComplete: true},
+		Output: []string{"Plan: 1 to add, 0 to change, 0 to destroy."},
+	}))
+	atlantisURL, err := url.Parse("https://example.com")
+	Ok(t, err)
+	j := &controllers.JobsController{
+		AtlantisURL:         atlantisURL,
+		Logger:              logging.NewNoopLogger(t),
+		ProjectJobsTemplate: templates.ProjectJobsTemplate,
+		StatsScope:          tally.NewTestScope("", nil),
+		JobStore:            store,
+	}
+
+	cases := []struct {
+		jobID          string
+		expOutput      bool
+		expWebsocketJS bool
+	}{
+		{"completed-job", true, false},
+		{"live-job", false, true},
+	}
+	for _, c := range cases {
+		t.Run(c.jobID, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/jobs/"+c.jobID, nil)
+			r = mux.SetURLVars(r, map[string]string{"job-id": c.jobID})
+			w := httptest.NewRecorder()
+			j.GetProjectJobs(w, r)
+
+			Equals(t, 200, w.Result().StatusCode)
+			body, err := io.ReadAll(w.Result().Body)
+			Ok(t, err)
+			Equals(t, c.expOutput, strings.Contains(string(body), "Plan: 1 to add, 0 to change, 0 to destroy."))
+			Equals(t, c.expWebsocketJS, strings.Contains(string(body), "new WebSocket("))
+		})
+	}
+}
@@ -6,11 +6,13 @@ import (
 	"net/url"
 
 	"github.com/runatlantis/atlantis/server/controllers/templates"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/db"
 
 	"github.com/gorilla/mux"
 	"github.com/runatlantis/atlantis/server/core/locking"
 	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/vcs"
 	"github.com/runatlantis/atlantis/server/logging"
@@ -27,8 +29,11 @@ type LocksController struct {
 	LockDetailTemplate templates.TemplateWriter
 	WorkingDir         events.WorkingDir
 	WorkingDirLocker   events.WorkingDirLocker
-	DB                 *db.BoltDB
+	DB                 db.Database
 	DeleteLockCommand  events.DeleteLockCommand
+	// GlobalCfg is used to refuse deleting the locks of repos that restrict
+	// who can run unlock.
+	GlobalCfg valid.GlobalCfg
 }
 
 // LockApply handles creating a global apply lock.
@@ -112,6 +117,22 @@ func (l *LocksController) DeleteLock(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Look up the lock's repo first if any repo restricts who can unlock.
+	// The UI has no identity of its own, the web basic auth credentials are
+	// shared, so locks of those repos can only be deleted with the unlock
+	// comment command which checks the commenter's permissions.
+	if l.GlobalCfg.RestrictsCommand(command.Unlock.String()) {
+		existingLock, err := l.Locker.GetLock(idUnencoded)
+		if err != nil {
+			l.respond(w, logging.Error, http.StatusInternalServerError, "Failed getting lock: %s", err)
+			return
+		}
+		if existingLock != nil && l.GlobalCfg.CommandPermission(existingLock.Pull.BaseRepo.ID(), command.Unlock.String()) != nil {
+			l.respond(w, logging.Warn, http.StatusForbidden, "Not allowed to delete lock id %q: %s restricts who can run unlock so its locks can't be deleted from the Atlantis UI. Comment `atlantis unlock` on pull request #%d instead", idUnencoded, existingLock.Project.RepoFullName, existingLock.Pull.Num)
+			return
+		}
+	}
+
 	lock, err := l.DeleteLockCommand.DeleteLock(idUnencoded)
 	if err != nil {
 		l.respond(w, logging.Error, http.StatusInternalServerError, "deleting lock failed with: %s", err)
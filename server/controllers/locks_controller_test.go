@@ -14,6 +14,7 @@ import (
 	"github.com/runatlantis/atlantis/server/controllers"
 	"github.com/runatlantis/atlantis/server/controllers/templates"
 	tMocks "github.com/runatlantis/atlantis/server/controllers/templates/mocks"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/db"
 	"github.com/runatlantis/atlantis/server/core/locking"
 
@@ -275,6 +276,90 @@ func TestDeleteLock_OldFormat(t *testing.T) {
 	cp.VerifyWasCalled(Never()).CreateComment(AnyRepo(), AnyInt(), AnyString(), AnyString())
 }
 
+func TestDeleteLock_Permissions(t *testing.T) {
+	repo := models.Repo{
+		FullName: "owner/repo",
+		VCSHost:  models.VCSHost{Hostname: "github.com"},
+	}
+	otherRepo := models.Repo{
+		FullName: "owner/other",
+		VCSHost:  models.VCSHost{Hostname: "github.com"},
+	}
+	globalCfg := valid.GlobalCfg{
+		Repos: []valid.Repo{
+			{
+				ID: "github.com/owner/repo",
+				Permissions: map[string]valid.CommandPermission{
+					"unlock": {Users: []string{"admin"}, Teams: []string{"platform"}},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		description string
+		repo        models.Repo
+		username    string
+		expCode     int
+		expBody     string
+	}{
+		{
+			description: "restricted repo",
+			repo:        repo,
+			expCode:     http.StatusForbidden,
+			expBody:     "Not allowed to delete lock id \"id\": owner/repo restricts who can run unlock so its locks can't be deleted from the Atlantis UI. Comment `atlantis unlock` on pull request #1 instead",
+		},
+		{
+			// The web username is shared so it isn't treated as a VCS user.
+			description: "restricted repo, allowed web username",
+			repo:        repo,
+			username:    "admin",
+			expCode:     http.StatusForbidden,
+			expBody:     "Not allowed to delete lock id \"id\"",
+		},
+		{
+			description: "unrestricted repo",
+			repo:        otherRepo,
+			expCode:     http.StatusOK,
+			expBody:     "Deleted lock id \"id\"",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			cp := vcsmocks.NewMockClient()
+			locker := mocks.NewMockLocker()
+			dlc := mocks2.NewMockDeleteLockCommand()
+			// Locks without a BaseRepo aren't commented on so we don't need
+			// to set up the working dir and DB.
+			lock := &models.ProjectLock{
+				Pull:    models.PullRequest{Num: 1, BaseRepo: c.repo},
+				Project: models.Project{RepoFullName: c.repo.FullName},
+			}
+			When(locker.GetLock("id")).ThenReturn(lock, nil)
+			When(dlc.DeleteLock("id")).ThenReturn(&models.ProjectLock{}, nil)
+			lc := controllers.LocksController{
+				Locker:            locker,
+				DeleteLockCommand: dlc,
+				Logger:            logging.NewNoopLogger(t),
+				VCSClient:         cp,
+				GlobalCfg:         globalCfg,
+			}
+			req, _ := http.NewRequest("DELETE", "", bytes.NewBuffer(nil))
+			if c.username != "" {
+				req.SetBasicAuth(c.username, "password")
+			}
+			req = mux.SetURLVars(req, map[string]string{"id": "id"})
+			w := httptest.NewRecorder()
+			lc.DeleteLock(w, req)
+			ResponseContains(t, w, c.expCode, c.expBody)
+			if c.expCode == http.StatusForbidden {
+				dlc.VerifyWasCalled(Never()).DeleteLock("id")
+			}
+		})
+	}
+}
+
 func TestDeleteLock_UpdateProjectStatus(t *testing.T) {
 	t.Log("When deleting a lock, pull status has to be updated to reflect discarded plan")
 	RegisterMockTestingT(t)
@@ -13,11 +13,16 @@ import (
 type StatusController struct {
 	Logger  logging.SimpleLogging
 	Drainer *events.Drainer
+	// ConcurrencyLimiter is nil unless plans and applies are limited.
+	ConcurrencyLimiter *events.ConcurrencyLimiter
 }
 
 type StatusResponse struct {
 	ShuttingDown  bool `json:"shutting_down"`
 	InProgressOps int  `json:"in_progress_operations"`
+	// QueuedOps is the number of plans and applies waiting for others to
+	// finish.
+	QueuedOps int `json:"queued_operations"`
 }
 
 // Get is the GET /status route.
@@ -26,6 +31,7 @@ func (d *StatusController) Get(w http.ResponseWriter, r *http.Request) {
 	data, err := json.MarshalIndent(&StatusResponse{
 		ShuttingDown:  status.ShuttingDown,
 		InProgressOps: status.InProgressOps,
+		QueuedOps:     d.ConcurrencyLimiter.Queued(),
 	}, "", "  ")
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -10,8 +10,10 @@ import (
 
 	"github.com/runatlantis/atlantis/server/controllers"
 	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/logging"
 	. "github.com/runatlantis/atlantis/testing"
+	"github.com/uber-go/tally"
 )
 
 func TestStatusController_Startup(t *testing.T) {
@@ -80,3 +82,32 @@ func TestStatusController_Shutdown(t *testing.T) {
 	Equals(t, true, result.ShuttingDown)
 	Equals(t, 0, result.InProgressOps)
 }
+
+func TestStatusController_Queued(t *testing.T) {
+	logger := logging.NewNoopLogger(t)
+	r, _ := http.NewRequest("GET", "/status", bytes.NewBuffer(nil))
+	w := httptest.NewRecorder()
+	limiter := events.NewConcurrencyLimiter(1, 0, tally.NoopScope)
+	ctx := command.ProjectContext{CommandName: command.Plan, Log: logger}
+	release, err := limiter.Wait(ctx, func(int) {})
+	Ok(t, err)
+	defer release()
+	queued := make(chan struct{})
+	go limiter.Wait(ctx, func(int) { close(queued) }) // nolint: errcheck
+	<-queued
+
+	d := &controllers.StatusController{
+		Logger:             logger,
+		Drainer:            &events.Drainer{},
+		ConcurrencyLimiter: limiter,
+	}
+	d.Get(w, r)
+
+	var result controllers.StatusResponse
+	body, err := io.ReadAll(w.Result().Body)
+	Ok(t, err)
+	Equals(t, 200, w.Result().StatusCode)
+	err = json.Unmarshal(body, &result)
+	Ok(t, err)
+	Equals(t, 1, result.QueuedOps)
+}
@@ -357,6 +357,10 @@ type ProjectJobData struct {
 	AtlantisVersion string
 	ProjectPath     string
 	CleanedBasePath string
+	// Completed is true if the job is complete and Output holds all of its
+	// output.
+	Completed bool
+	Output    []string
 }
 
 var ProjectJobsTemplate = template.Must(template.New("blank.html.tmpl").Parse(`
@@ -411,6 +415,13 @@ var ProjectJobsTemplate = template.Must(template.New("blank.html.tmpl").Parse(`
 
     <script>
       var term = new Terminal({scrollback: 15000});
+      var fitAddon = new FitAddon.FitAddon();
+      {{ if .Completed }}
+      var output = {{ .Output }} || [];
+      output.forEach(function(line) {
+        term.write(line + "\r\n");
+      });
+      {{ else }}
       var socket = new WebSocket(
         (document.location.protocol === "http:" ? "ws://" : "wss://") +
         document.location.host +
@@ -420,8 +431,8 @@ var ProjectJobsTemplate = template.Must(template.New("blank.html.tmpl").Parse(`
         websocket.close();
       })
       var attachAddon = new AttachAddon.AttachAddon(socket);
-      var fitAddon = new FitAddon.FitAddon();
       term.loadAddon(attachAddon);
+      {{ end }}
       term.loadAddon(fitAddon);
       term.open(document.getElementById("terminal"));
       fitAddon.fit();
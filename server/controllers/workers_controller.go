@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/workers"
+)
+
+// WorkersController handles the /workers routes. Workers started with
+// atlantis worker use them to lease jobs from the server's queue, stream the
+// jobs' output and report their results.
+type WorkersController struct {
+	// Secret authenticates workers. It's the server's --api-secret.
+	Secret []byte
+	Queue  *workers.Queue
+	Logger logging.SimpleLogging
+	// ClaimWait is how long a claim waits for a job to be queued.
+	ClaimWait time.Duration
+}
+
+// Claim leases the next job to the worker. It responds with no content if no
+// job was queued within ClaimWait.
+func (c *WorkersController) Claim(w http.ResponseWriter, r *http.Request) {
+	var request workers.ClaimRequest
+	if !c.parse(w, r, &request) {
+		return
+	}
+	job := c.Queue.Claim(request.Worker, c.ClaimWait)
+	if job == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	c.respondJSON(w, job)
+}
+
+// Renew extends a lease.
+func (c *WorkersController) Renew(w http.ResponseWriter, r *http.Request) {
+	if !c.parse(w, r, nil) {
+		return
+	}
+	cancelled, err := c.Queue.Renew(mux.Vars(r)["id"])
+	if err != nil {
+		c.respondErr(w, err)
+		return
+	}
+	c.respondJSON(w, workers.RenewResponse{Cancelled: cancelled})
+}
+
+// Output receives the output of a job.
+func (c *WorkersController) Output(w http.ResponseWriter, r *http.Request) {
+	var request workers.OutputRequest
+	if !c.parse(w, r, &request) {
+		return
+	}
+	if err := c.Queue.Output(mux.Vars(r)["id"], request.Lines); err != nil {
+		c.respondErr(w, err)
+		return
+	}
+	c.respondJSON(w, struct{}{})
+}
+
+// Complete receives the result of a job and ends its lease.
+func (c *WorkersController) Complete(w http.ResponseWriter, r *http.Request) {
+	var result workers.Result
+	if !c.parse(w, r, &result) {
+		return
+	}
+	if err := c.Queue.Complete(mux.Vars(r)["id"], result); err != nil {
+		c.respondErr(w, err)
+		return
+	}
+	c.respondJSON(w, struct{}{})
+}
+
+// parse authenticates the request and decodes its body into request if it
+// isn't nil. If it returns false, the error has been written to w.
+func (c *WorkersController) parse(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	secret := r.Header.Get(workers.SecretHeader)
+	if len(c.Secret) == 0 || subtle.ConstantTimeCompare([]byte(secret), c.Secret) != 1 {
+		c.respond(w, logging.Warn, http.StatusUnauthorized, "header %s did not match expected secret", workers.SecretHeader)
+		return false
+	}
+	if request == nil {
+		return true
+	}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		c.respond(w, logging.Warn, http.StatusBadRequest, "failed to parse request: %s", err)
+		return false
+	}
+	return true
+}
+
+func (c *WorkersController) respondErr(w http.ResponseWriter, err error) {
+	if errors.Is(err, workers.ErrLeaseNotFound) {
+		c.respond(w, logging.Warn, http.StatusNotFound, "%s", err)
+		return
+	}
+	c.respond(w, logging.Error, http.StatusInternalServerError, "%s", err)
+}
+
+func (c *WorkersController) respondJSON(w http.ResponseWriter, response interface{}) {
+	serialized, err := json.Marshal(response)
+	if err != nil {
+		c.respond(w, logging.Error, http.StatusInternalServerError, "failed to serialize response: %s", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(serialized) // nolint: errcheck
+}
+
+func (c *WorkersController) respond(w http.ResponseWriter, lvl logging.LogLevel, responseCode int, format string, args ...interface{}) {
+	response := fmt.Sprintf(format, args...)
+	c.Logger.Log(lvl, "workers request failed: %s", response)
+	w.WriteHeader(responseCode)
+	fmt.Fprintln(w, response)
+}
@@ -0,0 +1,57 @@
+package controllers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/runatlantis/atlantis/server/controllers"
+	"github.com/runatlantis/atlantis/server/jobs"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/workers"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestWorkersController_Claim(t *testing.T) {
+	cases := []struct {
+		description string
+		secret      string
+		body        string
+		expStatus   int
+	}{
+		{"no secret", "", `{"Worker":"worker"}`, http.StatusUnauthorized},
+		{"wrong secret", "wrong", `{"Worker":"worker"}`, http.StatusUnauthorized},
+		{"invalid body", "secret", `{`, http.StatusBadRequest},
+		{"no job", "secret", `{"Worker":"worker"}`, http.StatusNoContent},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("POST", "/workers/claim", bytes.NewBufferString(c.body))
+			r.Header.Set(workers.SecretHeader, c.secret)
+			newWorkersController(t).Claim(w, r)
+			Equals(t, c.expStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestWorkersController_UnknownLease(t *testing.T) {
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("POST", "/workers/leases/unknown/renew", nil)
+	r.Header.Set(workers.SecretHeader, "secret")
+	r = mux.SetURLVars(r, map[string]string{"id": "unknown"})
+	newWorkersController(t).Renew(w, r)
+	Equals(t, http.StatusNotFound, w.Result().StatusCode)
+}
+
+func newWorkersController(t *testing.T) *controllers.WorkersController {
+	return &controllers.WorkersController{
+		Secret:    []byte("secret"),
+		Queue:     workers.NewQueue(time.Minute, &jobs.NoopProjectOutputHandler{}),
+		Logger:    logging.NewNoopLogger(t),
+		ClaimWait: time.Millisecond,
+	}
+}
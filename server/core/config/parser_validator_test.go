@@ -171,6 +171,9 @@ workflows:
 						Name:        "custom",
 						Apply:       valid.DefaultApplyStage,
 						PolicyCheck: valid.DefaultPolicyCheckStage,
+						Import:      valid.DefaultImportStage,
+						StateRm:     valid.DefaultStateRmStage,
+						StateMv:     valid.DefaultStateMvStage,
 						Plan: valid.Stage{
 							Steps: []valid.Step{
 								{
@@ -350,6 +353,9 @@ workflows:
 						Plan:        valid.DefaultPlanStage,
 						Apply:       valid.DefaultApplyStage,
 						PolicyCheck: valid.DefaultPolicyCheckStage,
+						Import:      valid.DefaultImportStage,
+						StateRm:     valid.DefaultStateRmStage,
+						StateMv:     valid.DefaultStateMvStage,
 					},
 				},
 			},
@@ -387,6 +393,9 @@ workflows:
 						Apply:       valid.DefaultApplyStage,
 						Plan:        valid.DefaultPlanStage,
 						PolicyCheck: valid.DefaultPolicyCheckStage,
+						Import:      valid.DefaultImportStage,
+						StateRm:     valid.DefaultStateRmStage,
+						StateMv:     valid.DefaultStateMvStage,
 					},
 				},
 			},
@@ -426,6 +435,9 @@ workflows:
 						Apply:       valid.DefaultApplyStage,
 						Plan:        valid.DefaultPlanStage,
 						PolicyCheck: valid.DefaultPolicyCheckStage,
+						Import:      valid.DefaultImportStage,
+						StateRm:     valid.DefaultStateRmStage,
+						StateMv:     valid.DefaultStateMvStage,
 					},
 				},
 			},
@@ -465,6 +477,9 @@ workflows:
 						Apply:       valid.DefaultApplyStage,
 						Plan:        valid.DefaultPlanStage,
 						PolicyCheck: valid.DefaultPolicyCheckStage,
+						Import:      valid.DefaultImportStage,
+						StateRm:     valid.DefaultStateRmStage,
+						StateMv:     valid.DefaultStateMvStage,
 					},
 				},
 			},
@@ -504,6 +519,9 @@ workflows:
 						Apply:       valid.DefaultApplyStage,
 						Plan:        valid.DefaultPlanStage,
 						PolicyCheck: valid.DefaultPolicyCheckStage,
+						Import:      valid.DefaultImportStage,
+						StateRm:     valid.DefaultStateRmStage,
+						StateMv:     valid.DefaultStateMvStage,
 					},
 				},
 			},
@@ -543,6 +561,9 @@ workflows:
 						Apply:       valid.DefaultApplyStage,
 						Plan:        valid.DefaultPlanStage,
 						PolicyCheck: valid.DefaultPolicyCheckStage,
+						Import:      valid.DefaultImportStage,
+						StateRm:     valid.DefaultStateRmStage,
+						StateMv:     valid.DefaultStateMvStage,
 					},
 				},
 			},
@@ -582,6 +603,9 @@ workflows:
 						Apply:       valid.DefaultApplyStage,
 						Plan:        valid.DefaultPlanStage,
 						PolicyCheck: valid.DefaultPolicyCheckStage,
+						Import:      valid.DefaultImportStage,
+						StateRm:     valid.DefaultStateRmStage,
+						StateMv:     valid.DefaultStateMvStage,
 					},
 				},
 			},
@@ -621,6 +645,9 @@ workflows:
 						Apply:       valid.DefaultApplyStage,
 						Plan:        valid.DefaultPlanStage,
 						PolicyCheck: valid.DefaultPolicyCheckStage,
+						Import:      valid.DefaultImportStage,
+						StateRm:     valid.DefaultStateRmStage,
+						StateMv:     valid.DefaultStateMvStage,
 					},
 				},
 			},
@@ -660,6 +687,9 @@ workflows:
 						Apply:       valid.DefaultApplyStage,
 						Plan:        valid.DefaultPlanStage,
 						PolicyCheck: valid.DefaultPolicyCheckStage,
+						Import:      valid.DefaultImportStage,
+						StateRm:     valid.DefaultStateRmStage,
+						StateMv:     valid.DefaultStateMvStage,
 					},
 				},
 			},
@@ -836,6 +866,9 @@ workflows:
 								},
 							},
 						},
+						Import:  valid.DefaultImportStage,
+						StateRm: valid.DefaultStateRmStage,
+						StateMv: valid.DefaultStateMvStage,
 						Apply: valid.Stage{
 							Steps: []valid.Step{
 								{
@@ -913,6 +946,9 @@ workflows:
 								},
 							},
 						},
+						Import:  valid.DefaultImportStage,
+						StateRm: valid.DefaultStateRmStage,
+						StateMv: valid.DefaultStateMvStage,
 						Apply: valid.Stage{
 							Steps: []valid.Step{
 								{
@@ -978,6 +1014,9 @@ workflows:
 								},
 							},
 						},
+						Import:  valid.DefaultImportStage,
+						StateRm: valid.DefaultStateRmStage,
+						StateMv: valid.DefaultStateMvStage,
 						Apply: valid.Stage{
 							Steps: []valid.Step{
 								{
@@ -1047,6 +1086,9 @@ workflows:
 								},
 							},
 						},
+						Import:  valid.DefaultImportStage,
+						StateRm: valid.DefaultStateRmStage,
+						StateMv: valid.DefaultStateMvStage,
 						Apply: valid.Stage{
 							Steps: []valid.Step{
 								{
@@ -1175,6 +1217,9 @@ func TestParseGlobalCfg(t *testing.T) {
 				},
 			},
 		},
+		Import:  valid.DefaultImportStage,
+		StateRm: valid.DefaultStateRmStage,
+		StateMv: valid.DefaultStateMvStage,
 		Apply: valid.Stage{
 			Steps: []valid.Step{
 				{
@@ -1235,7 +1280,43 @@ func TestParseGlobalCfg(t *testing.T) {
 			input: `repos:
 - id: /.*/
   apply_requirements: [invalid]`,
-			expErr: "repos: (0: (apply_requirements: \"invalid\" is not a valid apply_requirement, only \"approved\", \"mergeable\" and \"undiverged\" are supported.).).",
+			expErr: "repos: (0: (apply_requirements: \"invalid\" is not a valid apply_requirement, only \"approved\", \"mergeable\", \"undiverged\", \"max_destroys:<n>\" and \"no_replace:<resource type>\" are supported.).).",
+		},
+		"invalid permissions command": {
+			input: `repos:
+- id: /.*/
+  permissions:
+    destroy:
+      users: [admin]`,
+			expErr: "repos: (0: (permissions: \"destroy\" is not a valid command, only plan, apply, unlock, approve_policies, import, state_rm, state_mv, cancel are supported.).).",
+		},
+		"drift detection with regex id": {
+			input: `repos:
+- id: /.*/
+  drift_detection:
+    schedule: "@daily"`,
+			expErr: "repos: (0: (drift_detection: only supported for repos with an exact match id.).).",
+		},
+		"drift detection with invalid schedule": {
+			input: `repos:
+- id: github.com/owner/repo
+  drift_detection:
+    schedule: "* * *"`,
+			expErr: "repos: (0: (drift_detection: (schedule: cron expression \"* * *\" must have 5 fields: minute, hour, day of month, month and day of week.).).).",
+		},
+		"env passthrough with invalid pattern": {
+			input: `repos:
+- id: /.*/
+  env_passthrough:
+    deny: ["AWS_["]`,
+			expErr: "repos: (0: (env_passthrough: (deny: \"AWS_[\" is not a valid pattern.).).).",
+		},
+		"sandbox with invalid cpu time": {
+			input: `repos:
+- id: /.*/
+  sandbox:
+    max_cpu_time: 500ms`,
+			expErr: "repos: (0: (sandbox: (max_cpu_time: \"500ms\" is not a valid duration, expected at least 1s, ex. 30s, 10m or 1h.).).).",
 		},
 		"no workflows key": {
 			input: `repos: []`,
@@ -1258,6 +1339,9 @@ workflows:
 						Apply:       valid.DefaultApplyStage,
 						Plan:        valid.DefaultPlanStage,
 						PolicyCheck: valid.DefaultPolicyCheckStage,
+						Import:      valid.DefaultImportStage,
+						StateRm:     valid.DefaultStateRmStage,
+						StateMv:     valid.DefaultStateMvStage,
 					},
 				},
 			},
@@ -1278,6 +1362,9 @@ workflows:
 						Apply:       valid.DefaultApplyStage,
 						Plan:        valid.DefaultPlanStage,
 						PolicyCheck: valid.DefaultPolicyCheckStage,
+						Import:      valid.DefaultImportStage,
+						StateRm:     valid.DefaultStateRmStage,
+						StateMv:     valid.DefaultStateMvStage,
 					},
 				},
 			},
@@ -1298,6 +1385,9 @@ workflows:
 						Name:        "name",
 						Plan:        valid.DefaultPlanStage,
 						PolicyCheck: valid.DefaultPolicyCheckStage,
+						Import:      valid.DefaultImportStage,
+						StateRm:     valid.DefaultStateRmStage,
+						StateMv:     valid.DefaultStateMvStage,
 						Apply:       valid.DefaultApplyStage,
 					},
 				},
@@ -1444,6 +1534,9 @@ workflows:
 							PolicyCheck: valid.Stage{
 								Steps: nil,
 							},
+							Import:  valid.DefaultImportStage,
+							StateRm: valid.DefaultStateRmStage,
+							StateMv: valid.DefaultStateMvStage,
 							Plan: valid.Stage{
 								Steps: []valid.Step{
 									{
@@ -1473,6 +1566,9 @@ workflows:
 								},
 							},
 						},
+						Import:  valid.DefaultImportStage,
+						StateRm: valid.DefaultStateRmStage,
+						StateMv: valid.DefaultStateMvStage,
 					},
 				},
 			},
@@ -1554,6 +1650,9 @@ func TestParserValidator_ParseGlobalCfgJSON(t *testing.T) {
 				},
 			},
 		},
+		Import:  valid.DefaultImportStage,
+		StateRm: valid.DefaultStateRmStage,
+		StateMv: valid.DefaultStateMvStage,
 		Apply: valid.Stage{
 			Steps: []valid.Step{
 				{
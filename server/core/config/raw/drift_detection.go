@@ -0,0 +1,38 @@
+package raw
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+)
+
+// DriftDetection is the raw schema for scheduled drift detection of a repo
+// in the server-side repo config.
+type DriftDetection struct {
+	Schedule    string `yaml:"schedule" json:"schedule"`
+	Branch      string `yaml:"branch,omitempty" json:"branch,omitempty"`
+	CreateIssue bool   `yaml:"create_issue,omitempty" json:"create_issue,omitempty"`
+}
+
+func (d DriftDetection) Validate() error {
+	scheduleValid := func(value interface{}) error {
+		_, err := valid.ParseCronSchedule(value.(string))
+		return err
+	}
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.Schedule, validation.Required, validation.By(scheduleValid)),
+	)
+}
+
+func (d DriftDetection) ToValid() *valid.DriftDetection {
+	// Safe to ignore the error because we test it in Validate().
+	schedule, _ := valid.ParseCronSchedule(d.Schedule)
+	branch := d.Branch
+	if branch == "" {
+		branch = valid.DefaultDriftDetectionBranch
+	}
+	return &valid.DriftDetection{
+		Schedule:    schedule,
+		Branch:      branch,
+		CreateIssue: d.CreateIssue,
+	}
+}
@@ -0,0 +1,49 @@
+package raw_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/config/raw"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	. "github.com/runatlantis/atlantis/testing"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestDriftDetection_YAMLMarshalling(t *testing.T) {
+	input := `
+schedule: "0 */6 * * *"
+branch: master
+create_issue: true
+`
+	var d raw.DriftDetection
+	Ok(t, yaml.UnmarshalStrict([]byte(input), &d))
+	Equals(t, raw.DriftDetection{
+		Schedule:    "0 */6 * * *",
+		Branch:      "master",
+		CreateIssue: true,
+	}, d)
+}
+
+func TestDriftDetection_Validate(t *testing.T) {
+	Ok(t, raw.DriftDetection{Schedule: "@daily"}.Validate())
+	ErrEquals(t, "schedule: cannot be blank.", raw.DriftDetection{}.Validate())
+	ErrEquals(t, "schedule: invalid hour field: \"24\" is out of range 0-23.", raw.DriftDetection{Schedule: "0 24 * * *"}.Validate())
+}
+
+func TestDriftDetection_ToValid(t *testing.T) {
+	schedule, err := valid.ParseCronSchedule("@daily")
+	Ok(t, err)
+	Equals(t, &valid.DriftDetection{
+		Schedule: schedule,
+		Branch:   "main",
+	}, raw.DriftDetection{Schedule: "@daily"}.ToValid())
+	Equals(t, &valid.DriftDetection{
+		Schedule:    schedule,
+		Branch:      "master",
+		CreateIssue: true,
+	}, raw.DriftDetection{
+		Schedule:    "@daily",
+		Branch:      "master",
+		CreateIssue: true,
+	}.ToValid())
+}
@@ -0,0 +1,42 @@
+package raw
+
+import (
+	"fmt"
+	"path"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+)
+
+// EnvPassthrough is the raw schema for which environment variables of the
+// Atlantis process are passed to Terraform and run steps in the server-side
+// repo config.
+type EnvPassthrough struct {
+	Allow []string `yaml:"allow,omitempty" json:"allow,omitempty"`
+	Deny  []string `yaml:"deny,omitempty" json:"deny,omitempty"`
+}
+
+func (e EnvPassthrough) Validate() error {
+	patternsValid := func(value interface{}) error {
+		for _, pattern := range value.([]string) {
+			if pattern == "" {
+				return fmt.Errorf("patterns can't be empty")
+			}
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%q is not a valid pattern", pattern)
+			}
+		}
+		return nil
+	}
+	return validation.ValidateStruct(&e,
+		validation.Field(&e.Allow, validation.By(patternsValid)),
+		validation.Field(&e.Deny, validation.By(patternsValid)),
+	)
+}
+
+func (e EnvPassthrough) ToValid() *valid.EnvPassthrough {
+	return &valid.EnvPassthrough{
+		Allow: e.Allow,
+		Deny:  e.Deny,
+	}
+}
@@ -0,0 +1,40 @@
+package raw_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/config/raw"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	. "github.com/runatlantis/atlantis/testing"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestEnvPassthrough_YAMLMarshalling(t *testing.T) {
+	input := `
+allow: [AWS_*, TF_VAR_*]
+deny: [AWS_SECRET_ACCESS_KEY]
+`
+	var e raw.EnvPassthrough
+	Ok(t, yaml.UnmarshalStrict([]byte(input), &e))
+	Equals(t, raw.EnvPassthrough{
+		Allow: []string{"AWS_*", "TF_VAR_*"},
+		Deny:  []string{"AWS_SECRET_ACCESS_KEY"},
+	}, e)
+}
+
+func TestEnvPassthrough_Validate(t *testing.T) {
+	Ok(t, raw.EnvPassthrough{}.Validate())
+	Ok(t, raw.EnvPassthrough{Allow: []string{"AWS_*"}, Deny: []string{"*_TOKEN"}}.Validate())
+	ErrEquals(t, "allow: patterns can't be empty.", raw.EnvPassthrough{Allow: []string{""}}.Validate())
+	ErrEquals(t, "deny: \"AWS_[\" is not a valid pattern.", raw.EnvPassthrough{Deny: []string{"AWS_["}}.Validate())
+}
+
+func TestEnvPassthrough_ToValid(t *testing.T) {
+	Equals(t, &valid.EnvPassthrough{
+		Allow: []string{"AWS_*"},
+		Deny:  []string{"AWS_SECRET_ACCESS_KEY"},
+	}, raw.EnvPassthrough{
+		Allow: []string{"AWS_*"},
+		Deny:  []string{"AWS_SECRET_ACCESS_KEY"},
+	}.ToValid())
+}
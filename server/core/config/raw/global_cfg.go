@@ -20,16 +20,20 @@ type GlobalCfg struct {
 
 // Repo is the raw schema for repos in the server-side repo config.
 type Repo struct {
-	ID                        string         `yaml:"id" json:"id"`
-	Branch                    string         `yaml:"branch" json:"branch"`
-	ApplyRequirements         []string       `yaml:"apply_requirements" json:"apply_requirements"`
-	PreWorkflowHooks          []WorkflowHook `yaml:"pre_workflow_hooks" json:"pre_workflow_hooks"`
-	Workflow                  *string        `yaml:"workflow,omitempty" json:"workflow,omitempty"`
-	PostWorkflowHooks         []WorkflowHook `yaml:"post_workflow_hooks" json:"post_workflow_hooks"`
-	AllowedWorkflows          []string       `yaml:"allowed_workflows,omitempty" json:"allowed_workflows,omitempty"`
-	AllowedOverrides          []string       `yaml:"allowed_overrides" json:"allowed_overrides"`
-	AllowCustomWorkflows      *bool          `yaml:"allow_custom_workflows,omitempty" json:"allow_custom_workflows,omitempty"`
-	DeleteSourceBranchOnMerge *bool          `yaml:"delete_source_branch_on_merge,omitempty" json:"delete_source_branch_on_merge,omitempty"`
+	ID                        string          `yaml:"id" json:"id"`
+	Branch                    string          `yaml:"branch" json:"branch"`
+	ApplyRequirements         []string        `yaml:"apply_requirements" json:"apply_requirements"`
+	PreWorkflowHooks          []WorkflowHook  `yaml:"pre_workflow_hooks" json:"pre_workflow_hooks"`
+	Workflow                  *string         `yaml:"workflow,omitempty" json:"workflow,omitempty"`
+	PostWorkflowHooks         []WorkflowHook  `yaml:"post_workflow_hooks" json:"post_workflow_hooks"`
+	AllowedWorkflows          []string        `yaml:"allowed_workflows,omitempty" json:"allowed_workflows,omitempty"`
+	AllowedOverrides          []string        `yaml:"allowed_overrides" json:"allowed_overrides"`
+	AllowCustomWorkflows      *bool           `yaml:"allow_custom_workflows,omitempty" json:"allow_custom_workflows,omitempty"`
+	DeleteSourceBranchOnMerge *bool           `yaml:"delete_source_branch_on_merge,omitempty" json:"delete_source_branch_on_merge,omitempty"`
+	Permissions               Permissions     `yaml:"permissions,omitempty" json:"permissions,omitempty"`
+	DriftDetection            *DriftDetection `yaml:"drift_detection,omitempty" json:"drift_detection,omitempty"`
+	EnvPassthrough            *EnvPassthrough `yaml:"env_passthrough,omitempty" json:"env_passthrough,omitempty"`
+	Sandbox                   *Sandbox        `yaml:"sandbox,omitempty" json:"sandbox,omitempty"`
 }
 
 func (g GlobalCfg) Validate() error {
@@ -187,6 +191,14 @@ func (r Repo) Validate() error {
 		return nil
 	}
 
+	driftDetectionValid := func(value interface{}) error {
+		driftDetection := value.(*DriftDetection)
+		if driftDetection != nil && r.HasRegexID() {
+			return errors.New("only supported for repos with an exact match id")
+		}
+		return nil
+	}
+
 	return validation.ValidateStruct(&r,
 		validation.Field(&r.ID, validation.Required, validation.By(idValid)),
 		validation.Field(&r.Branch, validation.By(branchValid)),
@@ -194,6 +206,10 @@ func (r Repo) Validate() error {
 		validation.Field(&r.ApplyRequirements, validation.By(validApplyReq)),
 		validation.Field(&r.Workflow, validation.By(workflowExists)),
 		validation.Field(&r.DeleteSourceBranchOnMerge, validation.By(deleteSourceBranchOnMergeValid)),
+		validation.Field(&r.Permissions),
+		validation.Field(&r.DriftDetection, validation.By(driftDetectionValid)),
+		validation.Field(&r.EnvPassthrough),
+		validation.Field(&r.Sandbox),
 	)
 }
 
@@ -252,6 +268,21 @@ OUTER:
 		mergedApplyReqs = append(mergedApplyReqs, globalReq)
 	}
 
+	var driftDetection *valid.DriftDetection
+	if r.DriftDetection != nil {
+		driftDetection = r.DriftDetection.ToValid()
+	}
+
+	var envPassthrough *valid.EnvPassthrough
+	if r.EnvPassthrough != nil {
+		envPassthrough = r.EnvPassthrough.ToValid()
+	}
+
+	var sandbox *valid.Sandbox
+	if r.Sandbox != nil {
+		sandbox = r.Sandbox.ToValid()
+	}
+
 	return valid.Repo{
 		ID:                        id,
 		IDRegex:                   idRegex,
@@ -264,5 +295,9 @@ OUTER:
 		AllowedOverrides:          r.AllowedOverrides,
 		AllowCustomWorkflows:      r.AllowCustomWorkflows,
 		DeleteSourceBranchOnMerge: r.DeleteSourceBranchOnMerge,
+		Permissions:               r.Permissions.ToValid(),
+		DriftDetection:            driftDetection,
+		EnvPassthrough:            envPassthrough,
+		Sandbox:                   sandbox,
 	}
 }
@@ -1,13 +1,17 @@
 package raw
 
 import (
+	"errors"
+	"strings"
+
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/go-ozzo/ozzo-validation/is"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 )
 
 type Metrics struct {
-	Statsd *Statsd `yaml:"statsd" json:"statsd"`
+	Statsd     *Statsd     `yaml:"statsd" json:"statsd"`
+	Prometheus *Prometheus `yaml:"prometheus" json:"prometheus"`
 }
 
 type Statsd struct {
@@ -15,6 +19,10 @@ type Statsd struct {
 	Host string `yaml:"host" json:"host"`
 }
 
+type Prometheus struct {
+	Endpoint string `yaml:"endpoint" json:"endpoint"`
+}
+
 func (s *Statsd) Validate() error {
 	return validation.ValidateStruct(s,
 		validation.Field(&s.Host, validation.Required),
@@ -23,9 +31,25 @@ func (s *Statsd) Validate() error {
 		validation.Field(&s.Port, is.Int))
 }
 
+func (p *Prometheus) Validate() error {
+	endpointValid := func(value interface{}) error {
+		endpoint := value.(string)
+		if !strings.HasPrefix(endpoint, "/") {
+			return errors.New("must start with a /")
+		}
+		return nil
+	}
+	return validation.ValidateStruct(p,
+		validation.Field(&p.Endpoint, validation.Required, validation.By(endpointValid)))
+}
+
 func (m Metrics) Validate() error {
+	if m.Statsd != nil && m.Prometheus != nil {
+		return errors.New("only one of statsd or prometheus can be configured")
+	}
 	return validation.ValidateStruct(&m,
 		validation.Field(&m.Statsd),
+		validation.Field(&m.Prometheus),
 	)
 }
 
@@ -40,5 +64,13 @@ func (m Metrics) ToValid() valid.Metrics {
 		}
 	}
 
+	if m.Prometheus != nil {
+		return valid.Metrics{
+			Prometheus: &valid.Prometheus{
+				Endpoint: m.Prometheus.Endpoint,
+			},
+		}
+	}
+
 	return valid.Metrics{}
 }
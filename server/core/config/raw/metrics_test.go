@@ -24,6 +24,20 @@ statsd:
 		assert.NoError(t, err)
 	})
 
+	t.Run("prometheus yaml", func(t *testing.T) {
+
+		rawYaml := `
+prometheus:
+  endpoint: /metrics
+`
+
+		var result raw.Metrics
+
+		err := yaml.UnmarshalStrict([]byte(rawYaml), &result)
+		assert.NoError(t, err)
+		assert.Equal(t, "/metrics", result.ToValid().Prometheus.Endpoint)
+	})
+
 	t.Run("json", func(t *testing.T) {
 		rawJSON := `
 {
@@ -58,6 +72,14 @@ func TestMetrics_Validate_Success(t *testing.T) {
 		{
 			description: "missing stats",
 		},
+		{
+			description: "prometheus",
+			subject: raw.Metrics{
+				Prometheus: &raw.Prometheus{
+					Endpoint: "/metrics",
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -106,6 +128,32 @@ func TestMetrics_Validate_Error(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "missing prometheus endpoint",
+			subject: raw.Metrics{
+				Prometheus: &raw.Prometheus{},
+			},
+		},
+		{
+			description: "relative prometheus endpoint",
+			subject: raw.Metrics{
+				Prometheus: &raw.Prometheus{
+					Endpoint: "metrics",
+				},
+			},
+		},
+		{
+			description: "statsd and prometheus",
+			subject: raw.Metrics{
+				Statsd: &raw.Statsd{
+					Host: "127.0.0.1",
+					Port: "8125",
+				},
+				Prometheus: &raw.Prometheus{
+					Endpoint: "/metrics",
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -0,0 +1,55 @@
+package raw
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+)
+
+// Permissions is the raw schema for the permissions of a repo in the
+// server-side repo config. It maps command names to who can run them.
+type Permissions map[string]CommandPermission
+
+// CommandPermission is the users and teams that can run a command.
+type CommandPermission struct {
+	Users []string `yaml:"users,omitempty" json:"users,omitempty"`
+	Teams []string `yaml:"teams,omitempty" json:"teams,omitempty"`
+}
+
+func (p Permissions) Validate() error {
+	var cmds []string
+	for cmd := range p {
+		cmds = append(cmds, cmd)
+	}
+	// Sort so the error is deterministic.
+	sort.Strings(cmds)
+	for _, cmd := range cmds {
+		supported := false
+		for _, c := range valid.PermissionCommands {
+			if cmd == c {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return fmt.Errorf("%q is not a valid command, only %s are supported", cmd, strings.Join(valid.PermissionCommands, ", "))
+		}
+	}
+	return nil
+}
+
+func (p Permissions) ToValid() map[string]valid.CommandPermission {
+	if p == nil {
+		return nil
+	}
+	permissions := make(map[string]valid.CommandPermission)
+	for cmd, permission := range p {
+		permissions[cmd] = valid.CommandPermission{
+			Users: permission.Users,
+			Teams: permission.Teams,
+		}
+	}
+	return permissions
+}
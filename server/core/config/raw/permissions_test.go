@@ -0,0 +1,47 @@
+package raw_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/config/raw"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	. "github.com/runatlantis/atlantis/testing"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestPermissions_YAMLMarshalling(t *testing.T) {
+	input := `
+apply:
+  users: [admin]
+  teams: [platform]
+unlock: {}
+`
+	var p raw.Permissions
+	Ok(t, yaml.UnmarshalStrict([]byte(input), &p))
+	Equals(t, raw.Permissions{
+		"apply":  {Users: []string{"admin"}, Teams: []string{"platform"}},
+		"unlock": {},
+	}, p)
+}
+
+func TestPermissions_Validate(t *testing.T) {
+	Ok(t, raw.Permissions{
+		"plan":             {Users: []string{"admin"}},
+		"apply":            {Teams: []string{"platform"}},
+		"unlock":           {},
+		"approve_policies": {Teams: []string{"security"}},
+		"import":           {Users: []string{"admin"}},
+	}.Validate())
+	ErrEquals(t, `"destroy" is not a valid command, only plan, apply, unlock, approve_policies, import, state_rm, state_mv, cancel are supported`, raw.Permissions{
+		"destroy": {Users: []string{"admin"}},
+	}.Validate())
+}
+
+func TestPermissions_ToValid(t *testing.T) {
+	Equals(t, map[string]valid.CommandPermission(nil), raw.Permissions(nil).ToValid())
+	Equals(t, map[string]valid.CommandPermission{
+		"apply": {Users: []string{"admin"}, Teams: []string{"platform"}},
+	}, raw.Permissions{
+		"apply": {Users: []string{"admin"}, Teams: []string{"platform"}},
+	}.ToValid())
+}
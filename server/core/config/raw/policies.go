@@ -40,6 +40,7 @@ func (p PolicySets) ToValid() valid.PolicySets {
 
 type PolicyOwners struct {
 	Users []string `yaml:"users,omitempty" json:"users,omitempty"`
+	Teams []string `yaml:"teams,omitempty" json:"teams,omitempty"`
 }
 
 func (o PolicyOwners) ToValid() valid.PolicyOwners {
@@ -48,6 +49,9 @@ func (o PolicyOwners) ToValid() valid.PolicyOwners {
 	if len(o.Users) > 0 {
 		policyOwners.Users = o.Users
 	}
+	if len(o.Teams) > 0 {
+		policyOwners.Teams = o.Teams
+	}
 	return policyOwners
 }
 
@@ -63,7 +67,7 @@ func (p PolicySet) Validate() error {
 		validation.Field(&p.Name, validation.Required.Error("is required")),
 		validation.Field(&p.Owners),
 		validation.Field(&p.Path, validation.Required.Error("is required")),
-		validation.Field(&p.Source, validation.In(valid.LocalPolicySet, valid.GithubPolicySet).Error("only 'local' and 'github' source types are supported")),
+		validation.Field(&p.Source, validation.In(valid.LocalPolicySet, valid.GithubPolicySet, valid.GitPolicySet, valid.HTTPPolicySet, valid.OCIPolicySet).Error("only 'local', 'github', 'git', 'http' and 'oci' source types are supported")),
 	)
 }
 
@@ -21,18 +21,33 @@ func TestPolicySetsConfig_YAMLMarshalling(t *testing.T) {
 			description: "valid yaml",
 			input: `
 conftest_version: v1.0.0
+owners:
+  users:
+  - john-doe
+  teams:
+  - platform
 policy_sets:
 - name: policy-name
   source: "local"
   path: "rel/path/to/policy-set"
+  owners:
+    teams:
+    - security
 `,
 			exp: raw.PolicySets{
 				Version: String("v1.0.0"),
+				Owners: raw.PolicyOwners{
+					Users: []string{"john-doe"},
+					Teams: []string{"platform"},
+				},
 				PolicySets: []raw.PolicySet{
 					{
 						Name:   "policy-name",
 						Source: valid.LocalPolicySet,
 						Path:   "rel/path/to/policy-set",
+						Owners: raw.PolicyOwners{
+							Teams: []string{"security"},
+						},
 					},
 				},
 			},
@@ -93,6 +108,29 @@ func TestPolicySets_Validate(t *testing.T) {
 			},
 			expErr: "",
 		},
+		{
+			description: "remote policies",
+			input: raw.PolicySets{
+				PolicySets: []raw.PolicySet{
+					{
+						Name:   "git-policies",
+						Path:   "https://github.com/org/policies.git//rego?ref=v1.0.0",
+						Source: valid.GitPolicySet,
+					},
+					{
+						Name:   "http-policies",
+						Path:   "https://example.com/policies.tar.gz",
+						Source: valid.HTTPPolicySet,
+					},
+					{
+						Name:   "oci-policies",
+						Path:   "ghcr.io/org/policies:v1.0.0",
+						Source: valid.OCIPolicySet,
+					},
+				},
+			},
+			expErr: "",
+		},
 
 		// Invalid inputs.
 		{
@@ -121,7 +159,7 @@ func TestPolicySets_Validate(t *testing.T) {
 					},
 				},
 			},
-			expErr: "policy_sets: (0: (source: only 'local' and 'github' source types are supported.).).",
+			expErr: "policy_sets: (0: (source: only 'local', 'github', 'git', 'http' and 'oci' source types are supported.).).",
 		},
 		{
 			description: "empty string version",
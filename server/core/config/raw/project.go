@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	validation "github.com/go-ozzo/ozzo-validation"
@@ -17,6 +18,12 @@ const (
 	ApprovedApplyRequirement   = "approved"
 	MergeableApplyRequirement  = "mergeable"
 	UnDivergedApplyRequirement = "undiverged"
+	// MaxDestroysApplyRequirement is used as max_destroys:<n> to block applies
+	// that destroy or replace more than n resources.
+	MaxDestroysApplyRequirement = "max_destroys"
+	// NoReplaceApplyRequirement is used as no_replace:<resource type> to block
+	// applies that replace resources of that type.
+	NoReplaceApplyRequirement = "no_replace"
 )
 
 type Project struct {
@@ -28,6 +35,7 @@ type Project struct {
 	Autoplan                  *Autoplan `yaml:"autoplan,omitempty"`
 	ApplyRequirements         []string  `yaml:"apply_requirements,omitempty"`
 	DeleteSourceBranchOnMerge *bool     `yaml:"delete_source_branch_on_merge,omitempty"`
+	DependsOn                 []string  `yaml:"depends_on,omitempty"`
 }
 
 func (p Project) Validate() error {
@@ -92,6 +100,8 @@ func (p Project) ToValid() valid.Project {
 		v.DeleteSourceBranchOnMerge = p.DeleteSourceBranchOnMerge
 	}
 
+	v.DependsOn = p.DependsOn
+
 	return v
 }
 
@@ -107,9 +117,35 @@ func validProjectName(name string) bool {
 func validApplyReq(value interface{}) error {
 	reqs := value.([]string)
 	for _, r := range reqs {
-		if r != ApprovedApplyRequirement && r != MergeableApplyRequirement && r != UnDivergedApplyRequirement {
-			return fmt.Errorf("%q is not a valid apply_requirement, only %q, %q and %q are supported", r, ApprovedApplyRequirement, MergeableApplyRequirement, UnDivergedApplyRequirement)
+		name, arg := SplitApplyRequirement(r)
+		switch name {
+		case ApprovedApplyRequirement, MergeableApplyRequirement, UnDivergedApplyRequirement:
+			if arg == "" {
+				continue
+			}
+		case MaxDestroysApplyRequirement:
+			if n, err := strconv.Atoi(arg); err == nil && n >= 0 {
+				continue
+			}
+			return fmt.Errorf("%q is not a valid apply_requirement, %s must be followed by the maximum number of resources, ex. %s:5", r, MaxDestroysApplyRequirement, MaxDestroysApplyRequirement)
+		case NoReplaceApplyRequirement:
+			if arg != "" {
+				continue
+			}
+			return fmt.Errorf("%q is not a valid apply_requirement, %s must be followed by a resource type, ex. %s:aws_db_instance", r, NoReplaceApplyRequirement, NoReplaceApplyRequirement)
 		}
+		return fmt.Errorf("%q is not a valid apply_requirement, only %q, %q, %q, %q and %q are supported", r, ApprovedApplyRequirement, MergeableApplyRequirement, UnDivergedApplyRequirement, MaxDestroysApplyRequirement+":<n>", NoReplaceApplyRequirement+":<resource type>")
 	}
 	return nil
 }
+
+// SplitApplyRequirement splits an apply requirement that takes an argument,
+// ex. max_destroys:5, into its name and argument. The argument is empty for
+// requirements without one.
+func SplitApplyRequirement(req string) (name string, arg string) {
+	parts := strings.SplitN(req, ":", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
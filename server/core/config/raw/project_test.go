@@ -42,7 +42,9 @@ autoplan:
   when_modified: []
   enabled: false
 apply_requirements:
-- mergeable`,
+- mergeable
+depends_on:
+- network`,
 			exp: raw.Project{
 				Name:             String("myname"),
 				Dir:              String("mydir"),
@@ -54,6 +56,7 @@ apply_requirements:
 					Enabled:      Bool(false),
 				},
 				ApplyRequirements: []string{"mergeable"},
+				DependsOn:         []string{"network"},
 			},
 		},
 	}
@@ -101,7 +104,39 @@ func TestProject_Validate(t *testing.T) {
 				Dir:               String("."),
 				ApplyRequirements: []string{"unsupported"},
 			},
-			expErr: "apply_requirements: \"unsupported\" is not a valid apply_requirement, only \"approved\", \"mergeable\" and \"undiverged\" are supported.",
+			expErr: "apply_requirements: \"unsupported\" is not a valid apply_requirement, only \"approved\", \"mergeable\", \"undiverged\", \"max_destroys:<n>\" and \"no_replace:<resource type>\" are supported.",
+		},
+		{
+			description: "apply reqs with max_destroys and no_replace requirements",
+			input: raw.Project{
+				Dir:               String("."),
+				ApplyRequirements: []string{"max_destroys:0", "no_replace:aws_db_instance"},
+			},
+			expErr: "",
+		},
+		{
+			description: "apply reqs with invalid max_destroys",
+			input: raw.Project{
+				Dir:               String("."),
+				ApplyRequirements: []string{"max_destroys:many"},
+			},
+			expErr: "apply_requirements: \"max_destroys:many\" is not a valid apply_requirement, max_destroys must be followed by the maximum number of resources, ex. max_destroys:5.",
+		},
+		{
+			description: "apply reqs with no_replace without resource type",
+			input: raw.Project{
+				Dir:               String("."),
+				ApplyRequirements: []string{"no_replace"},
+			},
+			expErr: "apply_requirements: \"no_replace\" is not a valid apply_requirement, no_replace must be followed by a resource type, ex. no_replace:aws_db_instance.",
+		},
+		{
+			description: "apply reqs with argument for approved",
+			input: raw.Project{
+				Dir:               String("."),
+				ApplyRequirements: []string{"approved:2"},
+			},
+			expErr: "apply_requirements: \"approved:2\" is not a valid apply_requirement, only \"approved\", \"mergeable\", \"undiverged\", \"max_destroys:<n>\" and \"no_replace:<resource type>\" are supported.",
 		},
 		{
 			description: "apply reqs with approved requirement",
@@ -283,6 +318,7 @@ func TestProject_ToValid(t *testing.T) {
 				},
 				ApplyRequirements: []string{"approved"},
 				Name:              String("myname"),
+				DependsOn:         []string{"network"},
 			},
 			exp: valid.Project{
 				Dir:              ".",
@@ -295,6 +331,7 @@ func TestProject_ToValid(t *testing.T) {
 				},
 				ApplyRequirements: []string{"approved"},
 				Name:              String("myname"),
+				DependsOn:         []string{"network"},
 			},
 		},
 		{
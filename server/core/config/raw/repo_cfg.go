@@ -2,6 +2,8 @@ package raw
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
@@ -48,7 +50,7 @@ func (r RepoCfg) Validate() error {
 	}
 	return validation.ValidateStruct(&r,
 		validation.Field(&r.Version, validation.By(equals2)),
-		validation.Field(&r.Projects),
+		validation.Field(&r.Projects, validation.By(validProjectDependencies)),
 		validation.Field(&r.Workflows),
 	)
 }
@@ -91,3 +93,66 @@ func (r RepoCfg) ToValid() valid.RepoCfg {
 		AllowedRegexpPrefixes:     r.AllowedRegexpPrefixes,
 	}
 }
+
+// validProjectDependencies checks that every depends_on refers to a project
+// name in the config and that there are no dependency cycles.
+func validProjectDependencies(value interface{}) error {
+	projects := value.([]Project)
+
+	// Multiple projects can have the same name if they're in different
+	// workspaces so we merge their dependencies.
+	deps := make(map[string][]string)
+	var names []string
+	for _, p := range projects {
+		if p.Name == nil {
+			continue
+		}
+		if _, ok := deps[*p.Name]; !ok {
+			names = append(names, *p.Name)
+		}
+		deps[*p.Name] = append(deps[*p.Name], p.DependsOn...)
+	}
+	for _, p := range projects {
+		for _, dep := range p.DependsOn {
+			if _, ok := deps[dep]; !ok {
+				return fmt.Errorf("depends_on: %q is not the name of a project", dep)
+			}
+		}
+	}
+
+	// Depth-first search for cycles. visiting holds the projects on the
+	// current path.
+	visiting := make(map[string]bool)
+	visited := make(map[string]bool)
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		if visiting[name] {
+			for i, n := range path {
+				if n == name {
+					return fmt.Errorf("depends_on: found dependency cycle %s", strings.Join(append(path[i:], name), " -> "))
+				}
+			}
+		}
+		if visited[name] {
+			return nil
+		}
+		visiting[name] = true
+		path = append(path, name)
+		for _, dep := range deps[name] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		visiting[name] = false
+		visited[name] = true
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
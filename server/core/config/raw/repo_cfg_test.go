@@ -217,6 +217,51 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			expErr: "version: only versions 2 and 3 are supported.",
 		},
+		{
+			description: "depends_on valid project",
+			input: raw.RepoCfg{
+				Version: Int(3),
+				Projects: []raw.Project{
+					{Name: String("network"), Dir: String("network")},
+					{Name: String("app"), Dir: String("app"), DependsOn: []string{"network"}},
+					{Dir: String("other"), DependsOn: []string{"network", "app"}},
+				},
+			},
+			expErr: "",
+		},
+		{
+			description: "depends_on unknown project",
+			input: raw.RepoCfg{
+				Version: Int(3),
+				Projects: []raw.Project{
+					{Name: String("app"), Dir: String("app"), DependsOn: []string{"network"}},
+				},
+			},
+			expErr: "projects: depends_on: \"network\" is not the name of a project.",
+		},
+		{
+			description: "depends_on itself",
+			input: raw.RepoCfg{
+				Version: Int(3),
+				Projects: []raw.Project{
+					{Name: String("app"), Dir: String("app"), DependsOn: []string{"app"}},
+				},
+			},
+			expErr: "projects: depends_on: found dependency cycle app -> app.",
+		},
+		{
+			description: "depends_on cycle",
+			input: raw.RepoCfg{
+				Version: Int(3),
+				Projects: []raw.Project{
+					{Name: String("network"), Dir: String("network")},
+					{Name: String("a"), Dir: String("a"), DependsOn: []string{"network", "c"}},
+					{Name: String("b"), Dir: String("b"), DependsOn: []string{"a"}},
+					{Name: String("c"), Dir: String("c"), DependsOn: []string{"b"}},
+				},
+			},
+			expErr: "projects: depends_on: found dependency cycle a -> c -> b -> a.",
+		},
 	}
 	validation.ErrorTag = "yaml"
 	for _, c := range cases {
@@ -328,6 +373,9 @@ func TestConfig_ToValid(t *testing.T) {
 								},
 							},
 						},
+						Import:  valid.DefaultImportStage,
+						StateRm: valid.DefaultStateRmStage,
+						StateMv: valid.DefaultStateMvStage,
 						Apply: valid.Stage{
 							Steps: []valid.Step{
 								{
@@ -397,6 +445,9 @@ func TestConfig_ToValid(t *testing.T) {
 								},
 							},
 						},
+						Import:  valid.DefaultImportStage,
+						StateRm: valid.DefaultStateRmStage,
+						StateMv: valid.DefaultStateMvStage,
 						Plan: valid.Stage{
 							Steps: []valid.Step{
 								{
@@ -0,0 +1,48 @@
+package raw
+
+import (
+	"fmt"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+)
+
+// Sandbox is the raw schema for running a repo's run steps and workflow hooks
+// in a sandbox in the server-side repo config.
+type Sandbox struct {
+	MaxMemoryMB   int    `yaml:"max_memory_mb,omitempty" json:"max_memory_mb,omitempty"`
+	MaxCPUTime    string `yaml:"max_cpu_time,omitempty" json:"max_cpu_time,omitempty"`
+	MaxFileSizeMB int    `yaml:"max_file_size_mb,omitempty" json:"max_file_size_mb,omitempty"`
+	MaxOpenFiles  int    `yaml:"max_open_files,omitempty" json:"max_open_files,omitempty"`
+}
+
+func (s Sandbox) Validate() error {
+	cpuTimeValid := func(value interface{}) error {
+		cpuTime := value.(string)
+		if cpuTime == "" {
+			return nil
+		}
+		if d, err := time.ParseDuration(cpuTime); err != nil || d < time.Second {
+			return fmt.Errorf("%q is not a valid duration, expected at least 1s, ex. 30s, 10m or 1h", cpuTime)
+		}
+		return nil
+	}
+	return validation.ValidateStruct(&s,
+		validation.Field(&s.MaxMemoryMB, validation.Min(0)),
+		validation.Field(&s.MaxCPUTime, validation.By(cpuTimeValid)),
+		validation.Field(&s.MaxFileSizeMB, validation.Min(0)),
+		validation.Field(&s.MaxOpenFiles, validation.Min(0)),
+	)
+}
+
+func (s Sandbox) ToValid() *valid.Sandbox {
+	// Safe to ignore the error because we test it in Validate().
+	cpuTime, _ := time.ParseDuration(s.MaxCPUTime)
+	return &valid.Sandbox{
+		MaxMemoryMB:   s.MaxMemoryMB,
+		MaxCPUTime:    cpuTime,
+		MaxFileSizeMB: s.MaxFileSizeMB,
+		MaxOpenFiles:  s.MaxOpenFiles,
+	}
+}
@@ -0,0 +1,52 @@
+package raw_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/config/raw"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	. "github.com/runatlantis/atlantis/testing"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestSandbox_YAMLMarshalling(t *testing.T) {
+	input := `
+max_memory_mb: 2048
+max_cpu_time: 10m
+max_file_size_mb: 512
+max_open_files: 1024
+`
+	var s raw.Sandbox
+	Ok(t, yaml.UnmarshalStrict([]byte(input), &s))
+	Equals(t, raw.Sandbox{
+		MaxMemoryMB:   2048,
+		MaxCPUTime:    "10m",
+		MaxFileSizeMB: 512,
+		MaxOpenFiles:  1024,
+	}, s)
+}
+
+func TestSandbox_Validate(t *testing.T) {
+	Ok(t, raw.Sandbox{}.Validate())
+	Ok(t, raw.Sandbox{MaxMemoryMB: 2048, MaxCPUTime: "1h"}.Validate())
+	ErrEquals(t, "max_memory_mb: must be no less than 0.", raw.Sandbox{MaxMemoryMB: -1}.Validate())
+	ErrEquals(t, "max_open_files: must be no less than 0.", raw.Sandbox{MaxOpenFiles: -1}.Validate())
+	ErrEquals(t, "max_cpu_time: \"ten\" is not a valid duration, expected at least 1s, ex. 30s, 10m or 1h.", raw.Sandbox{MaxCPUTime: "ten"}.Validate())
+	ErrEquals(t, "max_cpu_time: \"500ms\" is not a valid duration, expected at least 1s, ex. 30s, 10m or 1h.", raw.Sandbox{MaxCPUTime: "500ms"}.Validate())
+}
+
+func TestSandbox_ToValid(t *testing.T) {
+	Equals(t, &valid.Sandbox{}, raw.Sandbox{}.ToValid())
+	Equals(t, &valid.Sandbox{
+		MaxMemoryMB:   2048,
+		MaxCPUTime:    10 * time.Minute,
+		MaxFileSizeMB: 512,
+		MaxOpenFiles:  1024,
+	}, raw.Sandbox{
+		MaxMemoryMB:   2048,
+		MaxCPUTime:    "10m",
+		MaxFileSizeMB: 512,
+		MaxOpenFiles:  1024,
+	}.ToValid())
+}
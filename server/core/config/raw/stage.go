@@ -1,17 +1,23 @@
 package raw
 
 import (
+	"fmt"
+	"time"
+
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 )
 
 type Stage struct {
 	Steps []Step `yaml:"steps,omitempty" json:"steps,omitempty"`
+	// Timeout is how long all the steps of the stage can run for, ex. 30m.
+	Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 }
 
 func (s Stage) Validate() error {
 	return validation.ValidateStruct(&s,
 		validation.Field(&s.Steps),
+		validation.Field(&s.Timeout, validation.By(validTimeout)),
 	)
 }
 
@@ -21,6 +27,29 @@ func (s Stage) ToValid() valid.Stage {
 		validSteps = append(validSteps, s.ToValid())
 	}
 	return valid.Stage{
-		Steps: validSteps,
+		Steps:   validSteps,
+		Timeout: parseTimeout(s.Timeout),
+	}
+}
+
+// validTimeout validates that value, a string, is empty or a positive
+// duration like 10m.
+func validTimeout(value interface{}) error {
+	timeout := value.(string)
+	if timeout == "" {
+		return nil
 	}
+	d, err := time.ParseDuration(timeout)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("%q is not a valid timeout, expected a duration like 30s, 10m or 1h", timeout)
+	}
+	return nil
+}
+
+// parseTimeout parses a timeout that's been validated with validTimeout. An
+// empty timeout is returned as 0, meaning there is no timeout.
+func parseTimeout(timeout string) time.Duration {
+	// Safe to ignore the error because we test it in Validate().
+	d, _ := time.ParseDuration(timeout)
+	return d
 }
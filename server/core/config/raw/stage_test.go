@@ -2,6 +2,7 @@ package raw_test
 
 import (
 	"testing"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/runatlantis/atlantis/server/core/config/raw"
@@ -27,6 +28,7 @@ func TestStage_UnmarshalYAML(t *testing.T) {
 			description: "all fields set",
 			input: `
 steps: [step1]
+timeout: 30m
 `,
 			exp: raw.Stage{
 				Steps: []raw.Step{
@@ -34,6 +36,7 @@ steps: [step1]
 						Key: String("step1"),
 					},
 				},
+				Timeout: "30m",
 			},
 		},
 	}
@@ -62,6 +65,9 @@ func TestStage_Validate(t *testing.T) {
 
 	// Empty steps should validate.
 	Ok(t, (raw.Stage{}).Validate())
+
+	s = raw.Stage{Timeout: "soon"}
+	ErrEquals(t, "timeout: \"soon\" is not a valid timeout, expected a duration like 30s, 10m or 1h.", s.Validate())
 }
 
 func TestStage_ToValid(t *testing.T) {
@@ -85,6 +91,7 @@ func TestStage_ToValid(t *testing.T) {
 						Key: String("init"),
 					},
 				},
+				Timeout: "30m",
 			},
 			exp: valid.Stage{
 				Steps: []valid.Step{
@@ -92,6 +99,7 @@ func TestStage_ToValid(t *testing.T) {
 						StepName: "init",
 					},
 				},
+				Timeout: 30 * time.Minute,
 			},
 		},
 	}
@@ -16,6 +16,7 @@ const (
 	NameArgKey          = "name"
 	CommandArgKey       = "command"
 	ValueArgKey         = "value"
+	TimeoutArgKey       = "timeout"
 	RunStepName         = "run"
 	PlanStepName        = "plan"
 	ShowStepName        = "show"
@@ -23,6 +24,9 @@ const (
 	ApplyStepName       = "apply"
 	InitStepName        = "init"
 	EnvStepName         = "env"
+	ImportStepName      = "import"
+	StateRmStepName     = "state_rm"
+	StateMvStepName     = "state_mv"
 )
 
 // Step represents a single action/command to perform. In YAML, it can be set as
@@ -40,6 +44,12 @@ const (
 //        extra_args: [-var-file=staging.tfvars]
 // 4. A map for a custom run command:
 //    - run: my custom command
+// Steps #2, #3 and #4 can also set a timeout:
+//    - plan:
+//        extra_args: [-var-file=staging.tfvars]
+//        timeout: 30m
+//    - run: my custom command
+//      timeout: 5m
 // Here we parse step in the most generic fashion possible. See fields for more
 // details.
 type Step struct {
@@ -52,6 +62,16 @@ type Step struct {
 	Map map[string]map[string][]string
 	// StringVal will be set in case #4 above.
 	StringVal map[string]string
+	// Timeout is set if the step has a timeout key, ex. 5m. It's removed
+	// from Env, Map or StringVal.
+	Timeout string
+}
+
+// builtInStepArgs are the arguments of a built-in step that sets a timeout.
+// They can't be parsed into Step.Map because timeout isn't a list.
+type builtInStepArgs struct {
+	ExtraArgs []string `yaml:"extra_args,omitempty" json:"extra_args,omitempty"`
+	Timeout   string   `yaml:"timeout" json:"timeout"`
 }
 
 func (s *Step) UnmarshalYAML(unmarshal func(interface{}) error) error {
@@ -82,7 +102,10 @@ func (s Step) validStepName(stepName string) bool {
 		stepName == ApplyStepName ||
 		stepName == EnvStepName ||
 		stepName == ShowStepName ||
-		stepName == PolicyCheckStepName
+		stepName == PolicyCheckStepName ||
+		stepName == ImportStepName ||
+		stepName == StateRmStepName ||
+		stepName == StateMvStepName
 }
 
 func (s Step) Validate() error {
@@ -198,6 +221,9 @@ func (s Step) Validate() error {
 		return nil
 	}
 
+	if err := validTimeout(s.Timeout); err != nil {
+		return fmt.Errorf("%s: %s", TimeoutArgKey, err)
+	}
 	if s.Key != nil {
 		return validation.Validate(s.Key, validation.By(validStep))
 	}
@@ -220,6 +246,7 @@ func (s Step) ToValid() valid.Step {
 			StepName: *s.Key,
 		}
 	}
+	timeout := parseTimeout(s.Timeout)
 
 	// This will trigger in case #2 (see Step docs).
 	if len(s.Env) > 0 {
@@ -231,6 +258,7 @@ func (s Step) ToValid() valid.Step {
 				EnvVarName:  stepArgs[NameArgKey],
 				RunCommand:  stepArgs[CommandArgKey],
 				EnvVarValue: stepArgs[ValueArgKey],
+				Timeout:     timeout,
 			}
 		}
 	}
@@ -243,6 +271,7 @@ func (s Step) ToValid() valid.Step {
 			return valid.Step{
 				StepName:  stepName,
 				ExtraArgs: stepArgs[ExtraArgsKey],
+				Timeout:   timeout,
 			}
 		}
 	}
@@ -255,6 +284,7 @@ func (s Step) ToValid() valid.Step {
 			return valid.Step{
 				StepName:   RunStepName,
 				RunCommand: v,
+				Timeout:    timeout,
 			}
 		}
 	}
@@ -296,6 +326,25 @@ func (s *Step) unmarshalGeneric(unmarshal func(interface{}) error) error {
 		return nil
 	}
 
+	// This represents a built-in step with a timeout, ex:
+	//   plan:
+	//     extra_args: [a, b]
+	//     timeout: 10m
+	// Env steps with a timeout are parsed below.
+	var timeoutStep map[string]builtInStepArgs
+	err = unmarshal(&timeoutStep)
+	if err == nil && isBuiltInTimeoutStep(timeoutStep) {
+		s.Map = make(map[string]map[string][]string)
+		for stepName, args := range timeoutStep {
+			s.Map[stepName] = map[string][]string{}
+			if args.ExtraArgs != nil {
+				s.Map[stepName][ExtraArgsKey] = args.ExtraArgs
+			}
+			s.Timeout = args.Timeout
+		}
+		return nil
+	}
+
 	// This represents an env step, ex:
 	//   env:
 	//     name: k
@@ -304,6 +353,12 @@ func (s *Step) unmarshalGeneric(unmarshal func(interface{}) error) error {
 	var envStep map[string]map[string]string
 	err = unmarshal(&envStep)
 	if err == nil {
+		for _, args := range envStep {
+			if timeout, ok := args[TimeoutArgKey]; ok {
+				s.Timeout = timeout
+				delete(args, TimeoutArgKey)
+			}
+		}
 		s.Env = envStep
 		return nil
 	}
@@ -315,6 +370,11 @@ func (s *Step) unmarshalGeneric(unmarshal func(interface{}) error) error {
 	var runStep map[string]string
 	err = unmarshal(&runStep)
 	if err == nil {
+		// A timeout on its own is validated as an unknown step.
+		if timeout, ok := runStep[TimeoutArgKey]; ok && len(runStep) > 1 {
+			s.Timeout = timeout
+			delete(runStep, TimeoutArgKey)
+		}
 		s.StringVal = runStep
 		return nil
 	}
@@ -323,6 +383,9 @@ func (s *Step) unmarshalGeneric(unmarshal func(interface{}) error) error {
 }
 
 func (s Step) marshalGeneric() (interface{}, error) {
+	if s.Timeout != "" {
+		return s.marshalWithTimeout(), nil
+	}
 	if len(s.StringVal) != 0 {
 		return s.StringVal, nil
 	} else if len(s.Map) != 0 {
@@ -337,3 +400,42 @@ func (s Step) marshalGeneric() (interface{}, error) {
 	// unexpected behavior.
 	return nil, nil
 }
+
+// isBuiltInTimeoutStep returns true if step was a built-in step with a
+// timeout. JSON ignores unknown keys so other kinds of steps can be parsed as
+// one.
+func isBuiltInTimeoutStep(step map[string]builtInStepArgs) bool {
+	for stepName, args := range step {
+		if stepName == EnvStepName || args.Timeout == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// marshalWithTimeout adds the timeout back to the step's map like it was
+// written in the config.
+func (s Step) marshalWithTimeout() interface{} {
+	if len(s.StringVal) != 0 {
+		out := map[string]string{TimeoutArgKey: s.Timeout}
+		for k, v := range s.StringVal {
+			out[k] = v
+		}
+		return out
+	}
+	if len(s.Env) != 0 {
+		out := make(map[string]map[string]string)
+		for stepName, args := range s.Env {
+			out[stepName] = map[string]string{TimeoutArgKey: s.Timeout}
+			for k, v := range args {
+				out[stepName][k] = v
+			}
+		}
+		return out
+	}
+	out := make(map[string]builtInStepArgs)
+	for stepName, args := range s.Map {
+		out[stepName] = builtInStepArgs{ExtraArgs: args[ExtraArgsKey], Timeout: s.Timeout}
+	}
+	return out
+}
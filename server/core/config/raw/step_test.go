@@ -2,6 +2,7 @@ package raw_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/runatlantis/atlantis/server/core/config/raw"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
@@ -141,6 +142,62 @@ key: value`,
 			},
 		},
 
+		// Timeouts.
+		{
+			description: "run step with timeout",
+			input: `
+run: my command
+timeout: 5m`,
+			exp: raw.Step{
+				StringVal: map[string]string{"run": "my command"},
+				Timeout:   "5m",
+			},
+		},
+		{
+			description: "extra_args style with timeout",
+			input: `
+plan:
+  extra_args: [arg1, arg2]
+  timeout: 10m`,
+			exp: raw.Step{
+				Map: MapType{
+					"plan": {
+						"extra_args": {"arg1", "arg2"},
+					},
+				},
+				Timeout: "10m",
+			},
+		},
+		{
+			description: "built-in step with only a timeout",
+			input: `
+init:
+  timeout: 10m`,
+			exp: raw.Step{
+				Map: MapType{
+					"init": {},
+				},
+				Timeout: "10m",
+			},
+		},
+		{
+			description: "env step with timeout",
+			input: `
+env:
+  name: test
+  command: echo 123
+  timeout: 1m`,
+			exp: raw.Step{
+				Env: EnvType{
+					"env": {
+						"name":    "test",
+						"command": "echo 123",
+					},
+				},
+				Timeout: "1m",
+			},
+		},
+
 		// Errors
 		{
 			description: "extra args style no slice strings",
@@ -163,8 +220,11 @@ key:
 			Ok(t, err)
 			Equals(t, c.exp, got)
 
-			_, err = yaml.Marshal(got)
+			marshalled, err := yaml.Marshal(got)
 			Ok(t, err)
+			var unmarshalled raw.Step
+			Ok(t, yaml.UnmarshalStrict(marshalled, &unmarshalled))
+			Equals(t, got, unmarshalled)
 
 			var got2 raw.Step
 			err = yaml.UnmarshalStrict([]byte(c.input), &got2)
@@ -386,6 +446,36 @@ func TestStep_Validate(t *testing.T) {
 			},
 			expErr: "env steps only support one of the \"value\" or \"command\" keys, found both",
 		},
+		{
+			description: "run step with timeout",
+			input: raw.Step{
+				StringVal: map[string]string{"run": "my command"},
+				Timeout:   "5m",
+			},
+		},
+		{
+			description: "invalid timeout",
+			input: raw.Step{
+				StringVal: map[string]string{"run": "my command"},
+				Timeout:   "5",
+			},
+			expErr: "timeout: \"5\" is not a valid timeout, expected a duration like 30s, 10m or 1h",
+		},
+		{
+			description: "negative timeout",
+			input: raw.Step{
+				Map:     MapType{"plan": {}},
+				Timeout: "-5m",
+			},
+			expErr: "timeout: \"-5m\" is not a valid timeout, expected a duration like 30s, 10m or 1h",
+		},
+		{
+			description: "timeout without a step",
+			input: raw.Step{
+				StringVal: map[string]string{"timeout": "5m"},
+			},
+			expErr: "\"timeout\" is not a valid step type",
+		},
 		{
 			// For atlantis.yaml v2, this wouldn't parse, but now there should
 			// be no error.
@@ -481,6 +571,18 @@ func TestStep_ToValid(t *testing.T) {
 				ExtraArgs: []string{"arg1", "arg2"},
 			},
 		},
+		{
+			description: "run step with timeout",
+			input: raw.Step{
+				StringVal: map[string]string{"run": "my command"},
+				Timeout:   "5m",
+			},
+			exp: valid.Step{
+				StepName:   "run",
+				RunCommand: "my command",
+				Timeout:    5 * time.Minute,
+			},
+		},
 		{
 			description: "plan extra_args",
 			input: raw.Step{
@@ -2,6 +2,7 @@ package raw_test
 
 import (
 	"testing"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/runatlantis/atlantis/server/core/config/raw"
@@ -148,6 +149,9 @@ func TestWorkflow_ToValid(t *testing.T) {
 				Apply:       valid.DefaultApplyStage,
 				Plan:        valid.DefaultPlanStage,
 				PolicyCheck: valid.DefaultPolicyCheckStage,
+				Import:      valid.DefaultImportStage,
+				StateRm:     valid.DefaultStateRmStage,
+				StateMv:     valid.DefaultStateMvStage,
 			},
 		},
 		{
@@ -190,6 +194,9 @@ func TestWorkflow_ToValid(t *testing.T) {
 						},
 					},
 				},
+				Import:  valid.DefaultImportStage,
+				StateRm: valid.DefaultStateRmStage,
+				StateMv: valid.DefaultStateMvStage,
 				Plan: valid.Stage{
 					Steps: []valid.Step{
 						{
@@ -199,6 +206,75 @@ func TestWorkflow_ToValid(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "import and state stages set",
+			input: raw.Workflow{
+				Import: &raw.Stage{
+					Steps: []raw.Step{
+						{
+							Key: String("init"),
+						},
+						{
+							Map: map[string]map[string][]string{
+								"import": {
+									"extra_args": {"-var-file=prod.tfvars"},
+								},
+							},
+						},
+					},
+				},
+				StateRm: &raw.Stage{
+					Steps: []raw.Step{
+						{
+							Key: String("state_rm"),
+						},
+					},
+				},
+			},
+			exp: valid.Workflow{
+				Apply:       valid.DefaultApplyStage,
+				Plan:        valid.DefaultPlanStage,
+				PolicyCheck: valid.DefaultPolicyCheckStage,
+				Import: valid.Stage{
+					Steps: []valid.Step{
+						{
+							StepName: "init",
+						},
+						{
+							StepName:  "import",
+							ExtraArgs: []string{"-var-file=prod.tfvars"},
+						},
+					},
+				},
+				StateRm: valid.Stage{
+					Steps: []valid.Step{
+						{
+							StepName: "state_rm",
+						},
+					},
+				},
+				StateMv: valid.DefaultStateMvStage,
+			},
+		},
+		{
+			description: "timeout without steps",
+			input: raw.Workflow{
+				Apply: &raw.Stage{
+					Timeout: "1h",
+				},
+			},
+			exp: valid.Workflow{
+				Apply: valid.Stage{
+					Steps:   valid.DefaultApplyStage.Steps,
+					Timeout: time.Hour,
+				},
+				Plan:        valid.DefaultPlanStage,
+				PolicyCheck: valid.DefaultPolicyCheckStage,
+				Import:      valid.DefaultImportStage,
+				StateRm:     valid.DefaultStateRmStage,
+				StateMv:     valid.DefaultStateMvStage,
+			},
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.description, func(t *testing.T) {
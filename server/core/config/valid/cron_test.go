@@ -0,0 +1,60 @@
+package valid_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestParseCronSchedule_Errors(t *testing.T) {
+	cases := []struct {
+		spec   string
+		expErr string
+	}{
+		{"* * * *", `cron expression "* * * *" must have 5 fields: minute, hour, day of month, month and day of week`},
+		{"60 * * * *", `invalid minute field: "60" is out of range 0-59`},
+		{"* 5-1 * * *", `invalid hour field: "5-1" is out of range 0-23`},
+		{"* * 0 * *", `invalid day of month field: "0" is out of range 1-31`},
+		{"* * * jan *", `invalid month field: invalid value "jan"`},
+		{"*/0 * * * *", `invalid minute field: invalid step in "*/0"`},
+		{"@yearly", `cron expression "@yearly" must have 5 fields: minute, hour, day of month, month and day of week`},
+	}
+	for _, c := range cases {
+		t.Run(c.spec, func(t *testing.T) {
+			_, err := valid.ParseCronSchedule(c.spec)
+			ErrEquals(t, c.expErr, err)
+		})
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	// A Wednesday.
+	now := time.Date(2022, 6, 15, 10, 30, 45, 0, time.UTC)
+	cases := []struct {
+		spec string
+		exp  time.Time
+	}{
+		{"* * * * *", time.Date(2022, 6, 15, 10, 31, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2022, 6, 15, 11, 0, 0, 0, time.UTC)},
+		{"*/20 * * * *", time.Date(2022, 6, 15, 10, 40, 0, 0, time.UTC)},
+		{"15,45 9-17 * * *", time.Date(2022, 6, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 */6 * * *", time.Date(2022, 6, 15, 12, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2022, 6, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 8 * * 1-5", time.Date(2022, 6, 16, 8, 0, 0, 0, time.UTC)},
+		{"0 8 * * 7", time.Date(2022, 6, 19, 8, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)},
+		// When both days are restricted either can match.
+		{"0 0 20 * 5", time.Date(2022, 6, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, c := range cases {
+		t.Run(c.spec, func(t *testing.T) {
+			schedule, err := valid.ParseCronSchedule(c.spec)
+			Ok(t, err)
+			Equals(t, c.exp, schedule.Next(now))
+		})
+	}
+}
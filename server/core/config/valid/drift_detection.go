@@ -0,0 +1,29 @@
+package valid
+
+// DefaultDriftDetectionBranch is the branch that's checked for drift if the
+// repo's drift detection config doesn't set one.
+const DefaultDriftDetectionBranch = "main"
+
+// DriftDetection configures when a repo is checked for drift, meaning
+// changes to the real infrastructure that aren't in its Terraform code.
+type DriftDetection struct {
+	// Schedule is when the repo is checked.
+	Schedule *CronSchedule
+	// Branch is the branch whose projects are checked.
+	Branch string
+	// CreateIssue is true if an issue should be opened when projects drift.
+	// It's only supported on GitHub.
+	CreateIssue bool
+}
+
+// DriftDetectionRepos returns the repos that have drift detection configured.
+// Only repos with an exact match id can be checked for drift.
+func (g GlobalCfg) DriftDetectionRepos() []Repo {
+	var repos []Repo
+	for _, repo := range g.Repos {
+		if repo.DriftDetection != nil && repo.ID != "" {
+			repos = append(repos, repo)
+		}
+	}
+	return repos
+}
@@ -0,0 +1,80 @@
+package valid
+
+import (
+	"path"
+	"strings"
+)
+
+// SecretEnvVars are the environment variables of Atlantis' own secret flags.
+// They're never passed to Terraform, run steps or workflow hooks unless a
+// repo's env passthrough allows them by name. The server and worker commands
+// set them from their flag definitions.
+var SecretEnvVars []string
+
+// EnvPassthrough is which of the Atlantis process's environment variables are
+// passed to Terraform, run steps and workflow hooks. Patterns can use * as a
+// wildcard, ex. AWS_*. A nil *EnvPassthrough passes every variable except
+// SecretEnvVars.
+type EnvPassthrough struct {
+	// Allow is the patterns of the variables that are passed. If it's empty
+	// every variable that isn't denied is passed.
+	Allow []string
+	// Deny is the patterns of the variables that are never passed.
+	Deny []string
+}
+
+// Allows returns true if the environment variable name can be passed.
+func (e *EnvPassthrough) Allows(name string) bool {
+	var allow, deny []string
+	if e != nil {
+		allow, deny = e.Allow, e.Deny
+	}
+	if matchesEnvPattern(deny, name) {
+		return false
+	}
+	for _, secret := range SecretEnvVars {
+		if name == secret {
+			// Secrets must be allowed explicitly, a wildcard isn't enough.
+			for _, pattern := range allow {
+				if pattern == name {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return len(allow) == 0 || matchesEnvPattern(allow, name)
+}
+
+// Filter returns the variables in environ, in the KEY=value form of
+// os.Environ, that can be passed.
+func (e *EnvPassthrough) Filter(environ []string) []string {
+	var filtered []string
+	for _, env := range environ {
+		name := strings.SplitN(env, "=", 2)[0]
+		if e.Allows(name) {
+			filtered = append(filtered, env)
+		}
+	}
+	return filtered
+}
+
+func matchesEnvPattern(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		// Patterns are validated when the config is parsed.
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// EnvPassthrough returns the env passthrough of the repo with repoID or nil
+// if it isn't configured.
+func (g GlobalCfg) EnvPassthrough(repoID string) *EnvPassthrough {
+	repo := g.matchingRepoWith(repoID, func(r Repo) bool { return r.EnvPassthrough != nil })
+	if repo == nil {
+		return nil
+	}
+	return repo.EnvPassthrough
+}
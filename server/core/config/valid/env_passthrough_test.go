@@ -0,0 +1,87 @@
+package valid_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestEnvPassthrough_Filter(t *testing.T) {
+	defer func(secrets []string) { valid.SecretEnvVars = secrets }(valid.SecretEnvVars)
+	valid.SecretEnvVars = []string{"ATLANTIS_GH_TOKEN"}
+	environ := []string{
+		"AWS_ACCESS_KEY_ID=id",
+		"AWS_SECRET_ACCESS_KEY=secret",
+		"HOME=/home/atlantis",
+		"ATLANTIS_GH_TOKEN=token",
+		"ATLANTIS_GH_USER=atlantis",
+		"MULTI=a=b",
+	}
+	cases := []struct {
+		description string
+		passthrough *valid.EnvPassthrough
+		exp         []string
+	}{
+		{
+			"nil passes everything except secrets",
+			nil,
+			[]string{"AWS_ACCESS_KEY_ID=id", "AWS_SECRET_ACCESS_KEY=secret", "HOME=/home/atlantis", "ATLANTIS_GH_USER=atlantis", "MULTI=a=b"},
+		},
+		{
+			"deny",
+			&valid.EnvPassthrough{Deny: []string{"AWS_*", "MULTI"}},
+			[]string{"HOME=/home/atlantis", "ATLANTIS_GH_USER=atlantis"},
+		},
+		{
+			"allow",
+			&valid.EnvPassthrough{Allow: []string{"AWS_*", "HOME"}},
+			[]string{"AWS_ACCESS_KEY_ID=id", "AWS_SECRET_ACCESS_KEY=secret", "HOME=/home/atlantis"},
+		},
+		{
+			"deny wins over allow",
+			&valid.EnvPassthrough{Allow: []string{"AWS_*"}, Deny: []string{"AWS_SECRET_ACCESS_KEY"}},
+			[]string{"AWS_ACCESS_KEY_ID=id"},
+		},
+		{
+			"secrets aren't allowed by wildcards",
+			&valid.EnvPassthrough{Allow: []string{"ATLANTIS_*"}},
+			[]string{"ATLANTIS_GH_USER=atlantis"},
+		},
+		{
+			"secrets can be allowed by name",
+			&valid.EnvPassthrough{Allow: []string{"ATLANTIS_GH_TOKEN"}},
+			[]string{"ATLANTIS_GH_TOKEN=token"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			Equals(t, c.exp, c.passthrough.Filter(environ))
+		})
+	}
+}
+
+func TestGlobalCfg_EnvPassthrough(t *testing.T) {
+	passthrough := &valid.EnvPassthrough{Allow: []string{"AWS_*"}}
+	cfg := valid.GlobalCfg{
+		Repos: []valid.Repo{
+			{
+				IDRegex:        regexp.MustCompile(".*"),
+				EnvPassthrough: &valid.EnvPassthrough{Deny: []string{"AWS_*"}},
+			},
+			{
+				ID:             "github.com/owner/repo",
+				EnvPassthrough: passthrough,
+			},
+			{
+				ID: "github.com/owner/repo",
+			},
+		},
+	}
+	Equals(t, passthrough, cfg.EnvPassthrough("github.com/owner/repo"))
+	Equals(t, []string{"AWS_*"}, cfg.EnvPassthrough("github.com/owner/other").Deny)
+
+	var empty valid.GlobalCfg
+	Assert(t, empty.EnvPassthrough("github.com/owner/repo") == nil, "exp nil env passthrough")
+}
@@ -39,7 +39,8 @@ type GlobalCfg struct {
 }
 
 type Metrics struct {
-	Statsd *Statsd
+	Statsd     *Statsd
+	Prometheus *Prometheus
 }
 
 type Statsd struct {
@@ -47,6 +48,12 @@ type Statsd struct {
 	Host string
 }
 
+type Prometheus struct {
+	// Endpoint is the path on the Atlantis server that metrics are served
+	// from, ex. /metrics.
+	Endpoint string
+}
+
 // Repo is the final parsed version of server-side repo config.
 type Repo struct {
 	// ID is the exact match id of this config.
@@ -64,6 +71,17 @@ type Repo struct {
 	AllowedOverrides          []string
 	AllowCustomWorkflows      *bool
 	DeleteSourceBranchOnMerge *bool
+	// Permissions maps command names to who can run them. Commands that
+	// aren't in the map can be run by anyone.
+	Permissions map[string]CommandPermission
+	// DriftDetection is nil if the repo isn't checked for drift.
+	DriftDetection *DriftDetection
+	// EnvPassthrough is nil if the repo doesn't configure which environment
+	// variables are passed to Terraform and run steps.
+	EnvPassthrough *EnvPassthrough
+	// Sandbox is nil if the repo's run steps and workflow hooks don't run in
+	// a sandbox.
+	Sandbox *Sandbox
 }
 
 type MergedProjectCfg struct {
@@ -79,6 +97,9 @@ type MergedProjectCfg struct {
 	RepoCfgVersion            int
 	PolicySets                PolicySets
 	DeleteSourceBranchOnMerge bool
+	DependsOn                 []string
+	EnvPassthrough            *EnvPassthrough
+	Sandbox                   *Sandbox
 }
 
 // WorkflowHook is a map of custom run commands to run before or after workflows.
@@ -120,6 +141,42 @@ var DefaultPlanStage = Stage{
 	},
 }
 
+// DefaultImportStage is the Atlantis default import stage.
+var DefaultImportStage = Stage{
+	Steps: []Step{
+		{
+			StepName: "init",
+		},
+		{
+			StepName: "import",
+		},
+	},
+}
+
+// DefaultStateRmStage is the Atlantis default state rm stage.
+var DefaultStateRmStage = Stage{
+	Steps: []Step{
+		{
+			StepName: "init",
+		},
+		{
+			StepName: "state_rm",
+		},
+	},
+}
+
+// DefaultStateMvStage is the Atlantis default state mv stage.
+var DefaultStateMvStage = Stage{
+	Steps: []Step{
+		{
+			StepName: "init",
+		},
+		{
+			StepName: "state_mv",
+		},
+	},
+}
+
 // Deprecated: use NewGlobalCfgFromArgs
 func NewGlobalCfgWithHooks(allowRepoCfg bool, mergeableReq bool, approvedReq bool, unDivergedReq bool, preWorkflowHooks []*WorkflowHook, postWorkflowHooks []*WorkflowHook) GlobalCfg {
 	return NewGlobalCfgFromArgs(GlobalCfgArgs{
@@ -163,6 +220,9 @@ func NewGlobalCfgFromArgs(args GlobalCfgArgs) GlobalCfg {
 		Apply:       DefaultApplyStage,
 		Plan:        DefaultPlanStage,
 		PolicyCheck: DefaultPolicyCheckStage,
+		Import:      DefaultImportStage,
+		StateRm:     DefaultStateRmStage,
+		StateMv:     DefaultStateMvStage,
 	}
 	// Must construct slices here instead of using a `var` declaration because
 	// we treat nil slices differently.
@@ -303,6 +363,9 @@ func (g GlobalCfg) MergeProjectCfg(log logging.SimpleLogging, repoID string, pro
 		RepoCfgVersion:            rCfg.Version,
 		PolicySets:                g.PolicySets,
 		DeleteSourceBranchOnMerge: deleteSourceBranchOnMerge,
+		DependsOn:                 proj.DependsOn,
+		EnvPassthrough:            g.EnvPassthrough(repoID),
+		Sandbox:                   g.Sandbox(repoID),
 	}
 }
 
@@ -321,6 +384,8 @@ func (g GlobalCfg) DefaultProjCfg(log logging.SimpleLogging, repoID string, repo
 		TerraformVersion:          nil,
 		PolicySets:                g.PolicySets,
 		DeleteSourceBranchOnMerge: deleteSourceBranchOnMerge,
+		EnvPassthrough:            g.EnvPassthrough(repoID),
+		Sandbox:                   g.Sandbox(repoID),
 	}
 }
 
@@ -494,3 +559,16 @@ func (g GlobalCfg) MatchingRepo(repoID string) *Repo {
 	}
 	return nil
 }
+
+// matchingRepoWith returns the last repo which matches repoID and for which
+// configured returns true, or nil if there isn't one. Like getMatchingCfg,
+// a setting comes from the last repo that configures it.
+func (g GlobalCfg) matchingRepoWith(repoID string, configured func(Repo) bool) *Repo {
+	for i := len(g.Repos) - 1; i >= 0; i-- {
+		repo := g.Repos[i]
+		if repo.IDMatches(repoID) && configured(repo) {
+			return &repo
+		}
+	}
+	return nil
+}
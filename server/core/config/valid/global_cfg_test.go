@@ -35,6 +35,9 @@ func TestNewGlobalCfg(t *testing.T) {
 				},
 			},
 		},
+		Import:  valid.DefaultImportStage,
+		StateRm: valid.DefaultStateRmStage,
+		StateMv: valid.DefaultStateMvStage,
 		Plan: valid.Stage{
 			Steps: []valid.Step{
 				{
@@ -595,6 +598,9 @@ policies:
 					Apply:       valid.DefaultApplyStage,
 					Plan:        valid.DefaultPlanStage,
 					PolicyCheck: valid.DefaultPolicyCheckStage,
+					Import:      valid.DefaultImportStage,
+					StateRm:     valid.DefaultStateRmStage,
+					StateMv:     valid.DefaultStateMvStage,
 				},
 				PolicySets: valid.PolicySets{
 					Version: nil,
@@ -636,6 +642,9 @@ policies:
 					Apply:       valid.DefaultApplyStage,
 					Plan:        valid.DefaultPlanStage,
 					PolicyCheck: valid.DefaultPolicyCheckStage,
+					Import:      valid.DefaultImportStage,
+					StateRm:     valid.DefaultStateRmStage,
+					StateMv:     valid.DefaultStateMvStage,
 				},
 				PolicySets: valid.PolicySets{
 					Version: version,
@@ -720,6 +729,9 @@ workflows:
 					Name:        "custom",
 					Apply:       valid.DefaultApplyStage,
 					PolicyCheck: valid.DefaultPolicyCheckStage,
+					Import:      valid.DefaultImportStage,
+					StateRm:     valid.DefaultStateRmStage,
+					StateMv:     valid.DefaultStateMvStage,
 					Plan: valid.Stage{
 						Steps: []valid.Step{
 							{
@@ -755,6 +767,9 @@ repos:
 					Name:        "default",
 					Apply:       valid.DefaultApplyStage,
 					PolicyCheck: valid.DefaultPolicyCheckStage,
+					Import:      valid.DefaultImportStage,
+					StateRm:     valid.DefaultStateRmStage,
+					StateMv:     valid.DefaultStateMvStage,
 					Plan:        valid.DefaultPlanStage,
 				},
 				RepoRelDir:      ".",
@@ -787,6 +802,9 @@ repos:
 					Name:        "default",
 					Apply:       valid.DefaultApplyStage,
 					PolicyCheck: valid.DefaultPolicyCheckStage,
+					Import:      valid.DefaultImportStage,
+					StateRm:     valid.DefaultStateRmStage,
+					StateMv:     valid.DefaultStateMvStage,
 					Plan:        valid.DefaultPlanStage,
 				},
 				RepoRelDir:      "mydir",
@@ -815,6 +833,9 @@ repos:
 					Name:        "default",
 					Apply:       valid.DefaultApplyStage,
 					PolicyCheck: valid.DefaultPolicyCheckStage,
+					Import:      valid.DefaultImportStage,
+					StateRm:     valid.DefaultStateRmStage,
+					StateMv:     valid.DefaultStateMvStage,
 					Plan:        valid.DefaultPlanStage,
 				},
 				RepoRelDir:      "mydir",
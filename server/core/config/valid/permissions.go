@@ -0,0 +1,73 @@
+package valid
+
+import "strings"
+
+// PermissionCommands are the commands whose permissions can be configured
+// per repo.
+var PermissionCommands = []string{"plan", "apply", "unlock", "approve_policies", "import", "state_rm", "state_mv", "cancel"}
+
+// CommandPermission is who can run a command in a repo.
+type CommandPermission struct {
+	Users []string
+	Teams []string
+}
+
+// IsAllowed returns true if username is one of the allowed users or if one of
+// userTeams, the teams username belongs to, is allowed.
+func (c CommandPermission) IsAllowed(username string, userTeams []string) bool {
+	for _, u := range c.Users {
+		if strings.EqualFold(u, username) {
+			return true
+		}
+	}
+	for _, team := range c.Teams {
+		for _, userTeam := range userTeams {
+			if strings.EqualFold(team, userTeam) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CommandPermission returns who can run cmdName in the repo with repoID or
+// nil if anyone can.
+func (g GlobalCfg) CommandPermission(repoID string, cmdName string) *CommandPermission {
+	repo := g.matchingRepoWith(repoID, func(r Repo) bool {
+		_, ok := r.Permissions[cmdName]
+		return ok
+	})
+	if repo == nil {
+		return nil
+	}
+	permission := repo.Permissions[cmdName]
+	return &permission
+}
+
+// RestrictsCommand returns true if any repo restricts who can run cmdName.
+func (g GlobalCfg) RestrictsCommand(cmdName string) bool {
+	for _, repo := range g.Repos {
+		if _, ok := repo.Permissions[cmdName]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionTeams returns the teams that are allowed to run commands in any
+// repo, without duplicates.
+func (g GlobalCfg) PermissionTeams() []string {
+	var teams []string
+	seen := make(map[string]bool)
+	for _, repo := range g.Repos {
+		for _, permission := range repo.Permissions {
+			for _, team := range permission.Teams {
+				if !seen[strings.ToLower(team)] {
+					seen[strings.ToLower(team)] = true
+					teams = append(teams, team)
+				}
+			}
+		}
+	}
+	return teams
+}
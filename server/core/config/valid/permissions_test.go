@@ -0,0 +1,48 @@
+package valid_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestGlobalCfg_CommandPermission(t *testing.T) {
+	globalCfg := valid.GlobalCfg{
+		Repos: []valid.Repo{
+			{
+				IDRegex: regexp.MustCompile(".*"),
+				Permissions: map[string]valid.CommandPermission{
+					"apply":  {Teams: []string{"platform"}},
+					"unlock": {Teams: []string{"platform"}},
+				},
+			},
+			{
+				ID: "github.com/owner/repo",
+				Permissions: map[string]valid.CommandPermission{
+					"apply": {Users: []string{"admin"}, Teams: []string{"Platform", "security"}},
+				},
+			},
+		},
+	}
+
+	Equals(t, &valid.CommandPermission{Users: []string{"admin"}, Teams: []string{"Platform", "security"}}, globalCfg.CommandPermission("github.com/owner/repo", "apply"))
+	Equals(t, &valid.CommandPermission{Teams: []string{"platform"}}, globalCfg.CommandPermission("github.com/owner/repo", "unlock"))
+	Equals(t, &valid.CommandPermission{Teams: []string{"platform"}}, globalCfg.CommandPermission("github.com/owner/other", "apply"))
+	Assert(t, globalCfg.CommandPermission("github.com/owner/repo", "plan") == nil, "exp plan to be unrestricted")
+	Equals(t, true, globalCfg.RestrictsCommand("unlock"))
+	Equals(t, false, globalCfg.RestrictsCommand("plan"))
+	Equals(t, []string{"platform", "security"}, globalCfg.PermissionTeams())
+}
+
+func TestCommandPermission_IsAllowed(t *testing.T) {
+	permission := valid.CommandPermission{
+		Users: []string{"admin"},
+		Teams: []string{"platform"},
+	}
+	Equals(t, true, permission.IsAllowed("Admin", nil))
+	Equals(t, true, permission.IsAllowed("someone", []string{"dev", "Platform"}))
+	Equals(t, false, permission.IsAllowed("someone", []string{"dev"}))
+	Equals(t, false, valid.CommandPermission{}.IsAllowed("admin", []string{"platform"}))
+}
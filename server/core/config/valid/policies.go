@@ -9,6 +9,9 @@ import (
 const (
 	LocalPolicySet  string = "local"
 	GithubPolicySet string = "github"
+	GitPolicySet    string = "git"
+	HTTPPolicySet   string = "http"
+	OCIPolicySet    string = "oci"
 )
 
 // PolicySets defines version of policy checker binary(conftest) and a list of
@@ -22,6 +25,7 @@ type PolicySets struct {
 
 type PolicyOwners struct {
 	Users []string
+	Teams []string
 }
 
 type PolicySet struct {
@@ -35,12 +39,77 @@ func (p *PolicySets) HasPolicies() bool {
 	return len(p.PolicySets) > 0
 }
 
-func (p *PolicySets) IsOwner(username string) bool {
-	for _, uname := range p.Owners.Users {
-		if strings.EqualFold(uname, username) {
+func (p *PolicySets) IsOwner(username string, userTeams []string) bool {
+	return p.Owners.IsOwner(username, userTeams)
+}
+
+// IsPolicySetOwner returns true if username, or one of userTeams, can approve
+// the policy set named policySetName. Policy sets with their own owners can
+// only be approved by those owners. Otherwise they can be approved by the
+// top-level owners.
+func (p *PolicySets) IsPolicySetOwner(policySetName string, username string, userTeams []string) bool {
+	for _, policySet := range p.PolicySets {
+		if policySet.Name != policySetName {
+			continue
+		}
+		if policySet.Owners.IsSet() {
+			return policySet.Owners.IsOwner(username, userTeams)
+		}
+		return p.IsOwner(username, userTeams)
+	}
+	return false
+}
+
+// IsAnyPolicySetOwner returns true if username, or one of userTeams, can
+// approve at least one of the policy sets.
+func (p *PolicySets) IsAnyPolicySetOwner(username string, userTeams []string) bool {
+	for _, policySet := range p.PolicySets {
+		if p.IsPolicySetOwner(policySet.Name, username, userTeams) {
 			return true
 		}
 	}
+	return false
+}
+
+// OwnerTeams returns the teams that own any of the policy sets, without
+// duplicates.
+func (p *PolicySets) OwnerTeams() []string {
+	var teams []string
+	seen := make(map[string]bool)
+	owners := []PolicyOwners{p.Owners}
+	for _, policySet := range p.PolicySets {
+		owners = append(owners, policySet.Owners)
+	}
+	for _, o := range owners {
+		for _, team := range o.Teams {
+			if !seen[strings.ToLower(team)] {
+				seen[strings.ToLower(team)] = true
+				teams = append(teams, team)
+			}
+		}
+	}
+	return teams
+}
+
+// IsSet returns true if any users or teams are owners.
+func (o PolicyOwners) IsSet() bool {
+	return len(o.Users) > 0 || len(o.Teams) > 0
+}
 
+// IsOwner returns true if username is one of the owners or if one of
+// userTeams, the teams username belongs to, is.
+func (o PolicyOwners) IsOwner(username string, userTeams []string) bool {
+	for _, uname := range o.Users {
+		if strings.EqualFold(uname, username) {
+			return true
+		}
+	}
+	for _, team := range o.Teams {
+		for _, userTeam := range userTeams {
+			if strings.EqualFold(team, userTeam) {
+				return true
+			}
+		}
+	}
 	return false
 }
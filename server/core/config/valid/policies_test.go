@@ -0,0 +1,82 @@
+package valid_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestPolicySets_IsPolicySetOwner(t *testing.T) {
+	policySets := valid.PolicySets{
+		Owners: valid.PolicyOwners{
+			Users: []string{"global-owner"},
+		},
+		PolicySets: []valid.PolicySet{
+			{
+				Name: "security",
+				Owners: valid.PolicyOwners{
+					Users: []string{"Security-Owner"},
+				},
+			},
+			{
+				Name: "cost",
+			},
+			{
+				Name: "network",
+				Owners: valid.PolicyOwners{
+					Teams: []string{"Network-Team", "platform"},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		policySet string
+		user      string
+		teams     []string
+		exp       bool
+	}{
+		{"security", "security-owner", nil, true},
+		{"security", "global-owner", nil, false},
+		{"cost", "global-owner", nil, true},
+		{"cost", "security-owner", nil, false},
+		{"network", "someone", []string{"network-team"}, true},
+		{"network", "someone", []string{"dev", "platform"}, true},
+		{"network", "someone", []string{"dev"}, false},
+		{"network", "global-owner", nil, false},
+		{"unknown", "global-owner", nil, false},
+	}
+	for _, c := range cases {
+		t.Run(c.policySet+"/"+c.user, func(t *testing.T) {
+			Equals(t, c.exp, policySets.IsPolicySetOwner(c.policySet, c.user, c.teams))
+		})
+	}
+
+	Equals(t, true, policySets.IsAnyPolicySetOwner("security-owner", nil))
+	Equals(t, true, policySets.IsAnyPolicySetOwner("global-owner", nil))
+	Equals(t, true, policySets.IsAnyPolicySetOwner("someone", []string{"platform"}))
+	Equals(t, false, policySets.IsAnyPolicySetOwner("someone-else", []string{"dev"}))
+}
+
+func TestPolicySets_OwnerTeams(t *testing.T) {
+	policySets := valid.PolicySets{
+		Owners: valid.PolicyOwners{
+			Teams: []string{"platform"},
+		},
+		PolicySets: []valid.PolicySet{
+			{
+				Name: "security",
+				Owners: valid.PolicyOwners{
+					Users: []string{"security-owner"},
+					Teams: []string{"security", "Platform"},
+				},
+			},
+			{
+				Name: "cost",
+			},
+		},
+	}
+	Equals(t, []string{"platform", "security"}, policySets.OwnerTeams())
+	Equals(t, 0, len((&valid.PolicySets{}).OwnerTeams()))
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"regexp"
 	"strings"
+	"time"
 
 	version "github.com/hashicorp/go-version"
 )
@@ -126,6 +127,9 @@ type Project struct {
 	Autoplan                  Autoplan
 	ApplyRequirements         []string
 	DeleteSourceBranchOnMerge *bool
+	// DependsOn are the names of the projects that must be planned and
+	// applied before this project.
+	DependsOn []string
 }
 
 // GetName returns the name of the project or an empty string if there is no
@@ -144,6 +148,9 @@ type Autoplan struct {
 
 type Stage struct {
 	Steps []Step
+	// Timeout is how long all the steps can run for before they're
+	// cancelled. It's 0 if there's no timeout.
+	Timeout time.Duration
 }
 
 type Step struct {
@@ -157,6 +164,9 @@ type Step struct {
 	EnvVarName string
 	// EnvVarValue is the value to set EnvVarName to.
 	EnvVarValue string
+	// Timeout is how long the step can run for before it's cancelled. It's 0
+	// if there's no timeout.
+	Timeout time.Duration
 }
 
 type Workflow struct {
@@ -164,4 +174,7 @@ type Workflow struct {
 	Apply       Stage
 	Plan        Stage
 	PolicyCheck Stage
+	Import      Stage
+	StateRm     Stage
+	StateMv     Stage
 }
@@ -41,6 +41,9 @@ func TestConfig_FindProjectsByDir(t *testing.T) {
 						Apply:       valid.DefaultApplyStage,
 						Plan:        valid.DefaultPlanStage,
 						PolicyCheck: valid.DefaultPolicyCheckStage,
+						Import:      valid.DefaultImportStage,
+						StateRm:     valid.DefaultStateRmStage,
+						StateMv:     valid.DefaultStateMvStage,
 					},
 				},
 				AllowedRegexpPrefixes: []string{"dev", "staging"},
@@ -94,6 +97,9 @@ func TestConfig_FindProjectsByDir(t *testing.T) {
 						Apply:       valid.DefaultApplyStage,
 						Plan:        valid.DefaultPlanStage,
 						PolicyCheck: valid.DefaultPolicyCheckStage,
+						Import:      valid.DefaultImportStage,
+						StateRm:     valid.DefaultStateRmStage,
+						StateMv:     valid.DefaultStateMvStage,
 					},
 				},
 				AllowedRegexpPrefixes: []string{"dev", "staging"},
@@ -135,6 +141,9 @@ func TestConfig_FindProjectsByDir(t *testing.T) {
 						Apply:       valid.DefaultApplyStage,
 						Plan:        valid.DefaultPlanStage,
 						PolicyCheck: valid.DefaultPolicyCheckStage,
+						Import:      valid.DefaultImportStage,
+						StateRm:     valid.DefaultStateRmStage,
+						StateMv:     valid.DefaultStateMvStage,
 					},
 				},
 				AllowedRegexpPrefixes: nil,
@@ -188,6 +197,9 @@ func TestConfig_FindProjectsByDir(t *testing.T) {
 						Apply:       valid.DefaultApplyStage,
 						Plan:        valid.DefaultPlanStage,
 						PolicyCheck: valid.DefaultPolicyCheckStage,
+						Import:      valid.DefaultImportStage,
+						StateRm:     valid.DefaultStateRmStage,
+						StateMv:     valid.DefaultStateMvStage,
 					},
 				},
 				AllowedRegexpPrefixes: []string{"dev", "staging"},
@@ -0,0 +1,27 @@
+package valid
+
+import "time"
+
+// Sandbox configures the isolated processes that a repo's run steps and
+// workflow hooks run in. Limits that are zero aren't set.
+type Sandbox struct {
+	// MaxMemoryMB is the most virtual memory each process can use.
+	MaxMemoryMB int
+	// MaxCPUTime is the most CPU time each process can use. It's rounded up
+	// to the second.
+	MaxCPUTime time.Duration
+	// MaxFileSizeMB is the size of the largest file a process can write.
+	MaxFileSizeMB int
+	// MaxOpenFiles is the most files each process can have open.
+	MaxOpenFiles int
+}
+
+// Sandbox returns the sandbox config of the repo with repoID or nil if its
+// commands don't run in a sandbox.
+func (g GlobalCfg) Sandbox(repoID string) *Sandbox {
+	repo := g.matchingRepoWith(repoID, func(r Repo) bool { return r.Sandbox != nil })
+	if repo == nil {
+		return nil
+	}
+	return repo.Sandbox
+}
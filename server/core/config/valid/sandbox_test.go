@@ -0,0 +1,33 @@
+package valid_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestGlobalCfg_Sandbox(t *testing.T) {
+	sandbox := &valid.Sandbox{MaxOpenFiles: 64}
+	cfg := valid.GlobalCfg{
+		Repos: []valid.Repo{
+			{
+				IDRegex: regexp.MustCompile(".*"),
+				Sandbox: &valid.Sandbox{},
+			},
+			{
+				ID:      "github.com/owner/repo",
+				Sandbox: sandbox,
+			},
+			{
+				ID: "github.com/owner/repo",
+			},
+		},
+	}
+	Equals(t, sandbox, cfg.Sandbox("github.com/owner/repo"))
+	Equals(t, &valid.Sandbox{}, cfg.Sandbox("github.com/owner/other"))
+
+	var empty valid.GlobalCfg
+	Assert(t, empty.Sandbox("github.com/owner/repo") == nil, "exp nil sandbox")
+}
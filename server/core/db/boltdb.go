@@ -22,12 +22,14 @@ type BoltDB struct {
 	locksBucketName       []byte
 	pullsBucketName       []byte
 	globalLocksBucketName []byte
+	driftBucketName       []byte
 }
 
 const (
 	locksBucketName       = "runLocks"
 	pullsBucketName       = "pulls"
 	globalLocksBucketName = "globalLocks"
+	driftBucketName       = "drift"
 	pullKeySeparator      = "::"
 )
 
@@ -56,6 +58,9 @@ func New(dataDir string) (*BoltDB, error) {
 		if _, err = tx.CreateBucketIfNotExists([]byte(globalLocksBucketName)); err != nil {
 			return errors.Wrapf(err, "creating bucket %q", globalLocksBucketName)
 		}
+		if _, err = tx.CreateBucketIfNotExists([]byte(driftBucketName)); err != nil {
+			return errors.Wrapf(err, "creating bucket %q", driftBucketName)
+		}
 		return nil
 	})
 	if err != nil {
@@ -67,6 +72,7 @@ func New(dataDir string) (*BoltDB, error) {
 		locksBucketName:       []byte(locksBucketName),
 		pullsBucketName:       []byte(pullsBucketName),
 		globalLocksBucketName: []byte(globalLocksBucketName),
+		driftBucketName:       []byte(driftBucketName),
 	}, nil
 }
 
@@ -77,9 +83,16 @@ func NewWithDB(db *bolt.DB, bucket string, globalBucket string) (*BoltDB, error)
 		locksBucketName:       []byte(bucket),
 		pullsBucketName:       []byte(pullsBucketName),
 		globalLocksBucketName: []byte(globalBucket),
+		driftBucketName:       []byte(driftBucketName),
 	}, nil
 }
 
+// Close closes the underlying database file, releasing its file lock so
+// another process can open it.
+func (b *BoltDB) Close() error {
+	return b.db.Close()
+}
+
 // TryLock attempts to create a new lock. If the lock is
 // acquired, it will return true and the lock returned will be newLock.
 // If the lock is not acquired, it will return false and the current
@@ -252,6 +265,23 @@ func (b *BoltDB) CheckCommandLock(cmdName command.Name) (*command.Lock, error) {
 	return nil, err
 }
 
+// ListCommandLocks lists all current command locks.
+func (b *BoltDB) ListCommandLocks() ([]command.Lock, error) {
+	var cmdLocks []command.Lock
+	err := b.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(b.globalLocksBucketName).Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			var cmdLock command.Lock
+			if err := json.Unmarshal(v, &cmdLock); err != nil {
+				return errors.Wrapf(err, "deserializing command lock at key %q", string(k))
+			}
+			cmdLocks = append(cmdLocks, cmdLock)
+		}
+		return nil
+	})
+	return cmdLocks, errors.Wrap(err, "DB transaction failed")
+}
+
 // UnlockByPull deletes all locks associated with that pull request and returns them.
 func (b *BoltDB) UnlockByPull(repoFullName string, pullNum int) ([]models.ProjectLock, error) {
 	var locks []models.ProjectLock
@@ -357,6 +387,14 @@ func (b *BoltDB) UpdatePullWithResults(pull models.PullRequest, newResults []com
 						res.ProjectName == proj.ProjectName {
 
 						proj.Status = res.PlanStatus()
+						// A new plan invalidates the previous policy check results
+						// and approvals.
+						if res.Command == command.Plan || res.Command == command.PolicyCheck {
+							proj.PolicyStatus = res.PolicyStatus
+						}
+						if res.Command == command.Plan {
+							proj.PlanChanges = res.PlanChangeCounts()
+						}
 						updatedExisting = true
 						break
 					}
@@ -393,6 +431,66 @@ func (b *BoltDB) GetPullStatus(pull models.PullRequest) (*models.PullStatus, err
 	return s, errors.Wrap(err, "DB transaction failed")
 }
 
+// ListPullStatuses returns the statuses of all pulls.
+func (b *BoltDB) ListPullStatuses() ([]models.PullStatus, error) {
+	var statuses []models.PullStatus
+	err := b.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(b.pullsBucketName).Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			var p models.PullStatus
+			if err := json.Unmarshal(v, &p); err != nil {
+				return errors.Wrapf(err, "deserializing pull at %q", string(k))
+			}
+			statuses = append(statuses, p)
+		}
+		return nil
+	})
+	return statuses, errors.Wrap(err, "DB transaction failed")
+}
+
+// SetPullStatus overwrites the status for status.Pull with status.
+func (b *BoltDB) SetPullStatus(status models.PullStatus) error {
+	key, err := b.pullKey(status.Pull)
+	if err != nil {
+		return err
+	}
+	err = b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(b.pullsBucketName)
+		return b.writePullToBucket(bucket, key, status)
+	})
+	return errors.Wrap(err, "DB transaction failed")
+}
+
+// SetDriftStatus overwrites the drift status for status's repo with status.
+func (b *BoltDB) SetDriftStatus(status models.DriftStatus) error {
+	serialized, err := json.Marshal(status)
+	if err != nil {
+		return errors.Wrap(err, "serializing")
+	}
+	err = b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(b.driftBucketName)
+		return bucket.Put(b.driftKey(status), serialized)
+	})
+	return errors.Wrap(err, "DB transaction failed")
+}
+
+// ListDriftStatuses returns the drift statuses of all repos.
+func (b *BoltDB) ListDriftStatuses() ([]models.DriftStatus, error) {
+	var statuses []models.DriftStatus
+	err := b.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(b.driftBucketName).Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			var d models.DriftStatus
+			if err := json.Unmarshal(v, &d); err != nil {
+				return errors.Wrapf(err, "deserializing drift status at %q", string(k))
+			}
+			statuses = append(statuses, d)
+		}
+		return nil
+	})
+	return statuses, errors.Wrap(err, "DB transaction failed")
+}
+
 // DeletePullStatus deletes the status for pull.
 func (b *BoltDB) DeletePullStatus(pull models.PullRequest) error {
 	key, err := b.pullKey(pull)
@@ -452,6 +550,10 @@ func (b *BoltDB) pullKey(pull models.PullRequest) ([]byte, error) {
 		nil
 }
 
+func (b *BoltDB) driftKey(status models.DriftStatus) []byte {
+	return []byte(fmt.Sprintf("%s/%s", status.Hostname, status.RepoFullName))
+}
+
 func (b *BoltDB) commandLockKey(cmdName command.Name) string {
 	return fmt.Sprintf("%s/lock", cmdName)
 }
@@ -483,9 +585,11 @@ func (b *BoltDB) writePullToBucket(bucket *bolt.Bucket, key []byte, pull models.
 
 func (b *BoltDB) projectResultToProject(p command.ProjectResult) models.ProjectStatus {
 	return models.ProjectStatus{
-		Workspace:   p.Workspace,
-		RepoRelDir:  p.RepoRelDir,
-		ProjectName: p.ProjectName,
-		Status:      p.PlanStatus(),
+		Workspace:    p.Workspace,
+		RepoRelDir:   p.RepoRelDir,
+		ProjectName:  p.ProjectName,
+		Status:       p.PlanStatus(),
+		PolicyStatus: p.PolicyStatus,
+		PlanChanges:  p.PlanChangeCounts(),
 	}
 }
@@ -766,6 +766,88 @@ func TestPullStatus_UpdateMerge(t *testing.T) {
 	}
 }
 
+// Test that the resource counts of a plan are kept until the project is
+// planned again.
+func TestPullStatus_UpdatePlanChanges(t *testing.T) {
+	b, cleanup := newTestDB2(t)
+	defer cleanup()
+
+	pull := models.PullRequest{
+		Num:        1,
+		HeadCommit: "sha",
+		BaseRepo: models.Repo{
+			FullName: "runatlantis/atlantis",
+			VCSHost: models.VCSHost{
+				Hostname: "github.com",
+				Type:     models.Github,
+			},
+		},
+	}
+	_, err := b.UpdatePullWithResults(pull, []command.ProjectResult{
+		{
+			Command:    command.Plan,
+			RepoRelDir: ".",
+			Workspace:  "default",
+			PlanSuccess: &models.PlanSuccess{
+				Analysis: &models.PlanAnalysis{
+					Changes: []models.ResourceTypeChanges{
+						{Type: "aws_instance", Creates: 1, Deletes: 1},
+					},
+				},
+			},
+		},
+	})
+	Ok(t, err)
+
+	status, err := b.UpdatePullWithResults(pull, []command.ProjectResult{
+		{
+			Command:      command.Apply,
+			RepoRelDir:   ".",
+			Workspace:    "default",
+			ApplySuccess: "applied!",
+		},
+	})
+	Ok(t, err)
+	Equals(t, &models.PlanChangeCounts{Add: 1, Destroy: 1}, status.Projects[0].PlanChanges)
+
+	status, err = b.UpdatePullWithResults(pull, []command.ProjectResult{
+		{
+			Command:    command.Plan,
+			RepoRelDir: ".",
+			Workspace:  "default",
+			Error:      errors.New("plan error"),
+		},
+	})
+	Ok(t, err)
+	Equals(t, (*models.PlanChangeCounts)(nil), status.Projects[0].PlanChanges)
+}
+
+func TestDriftStatus_SetList(t *testing.T) {
+	b, cleanup := newTestDB2(t)
+	defer cleanup()
+
+	statuses, err := b.ListDriftStatuses()
+	Ok(t, err)
+	Equals(t, 0, len(statuses))
+
+	status := models.DriftStatus{
+		RepoFullName: "runatlantis/atlantis",
+		Hostname:     "github.com",
+		Branch:       "main",
+		CheckedAt:    time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+		Projects: []models.ProjectDriftStatus{
+			{RepoRelDir: ".", Workspace: "default", Drifted: true, Summary: "Plan: 1 to add, 0 to change, 0 to destroy."},
+		},
+	}
+	Ok(t, b.SetDriftStatus(status))
+	// Setting the status again overwrites it.
+	status.Projects[0].Drifted = false
+	Ok(t, b.SetDriftStatus(status))
+	statuses, err = b.ListDriftStatuses()
+	Ok(t, err)
+	Equals(t, []models.DriftStatus{status}, statuses)
+}
+
 // newTestDB returns a TestDB using a temporary path.
 func newTestDB() (*bolt.DB, *db.BoltDB) {
 	// Retrieve a temporary path.
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"time"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+)
+
+// Database is an implementation of the database API we require.
+// It is the superset of the locking.Backend interface plus the pull status
+// methods used by the command runners and the drift status methods used by
+// drift detection. BoltDB and RedisDB both implement it.
+type Database interface {
+	TryLock(lock models.ProjectLock) (bool, models.ProjectLock, error)
+	Unlock(project models.Project, workspace string) (*models.ProjectLock, error)
+	List() ([]models.ProjectLock, error)
+	GetLock(project models.Project, workspace string) (*models.ProjectLock, error)
+	UnlockByPull(repoFullName string, pullNum int) ([]models.ProjectLock, error)
+
+	UpdateProjectStatus(pull models.PullRequest, workspace string, repoRelDir string, newStatus models.ProjectPlanStatus) error
+	GetPullStatus(pull models.PullRequest) (*models.PullStatus, error)
+	DeletePullStatus(pull models.PullRequest) error
+	UpdatePullWithResults(pull models.PullRequest, newResults []command.ProjectResult) (models.PullStatus, error)
+	ListPullStatuses() ([]models.PullStatus, error)
+	SetPullStatus(status models.PullStatus) error
+
+	SetDriftStatus(status models.DriftStatus) error
+	ListDriftStatuses() ([]models.DriftStatus, error)
+
+	LockCommand(cmdName command.Name, lockTime time.Time) (*command.Lock, error)
+	UnlockCommand(cmdName command.Name) error
+	CheckCommandLock(cmdName command.Name) (*command.Lock, error)
+	ListCommandLocks() ([]command.Lock, error)
+
+	Close() error
+}
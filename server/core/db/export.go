@@ -0,0 +1,127 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+)
+
+// ExportVersion is the version of the Export format written by Dump.
+// Bump it whenever a change to Export, models.ProjectLock, command.Lock,
+// models.PullStatus or models.DriftStatus isn't backwards compatible and teach
+// Load how to handle the older versions. Exports written before drift
+// statuses were added simply have none.
+const ExportVersion = 1
+
+// Export is everything stored in a Database. It's serialized to JSON so that
+// the data can be moved between data dirs and Database implementations.
+type Export struct {
+	// Version is the version of this format. See ExportVersion.
+	Version      int                  `json:"version"`
+	ProjectLocks []models.ProjectLock `json:"project_locks"`
+	CommandLocks []command.Lock       `json:"command_locks"`
+	PullStatuses []models.PullStatus  `json:"pull_statuses"`
+	// DriftStatuses is omitted by exports written before drift detection.
+	DriftStatuses []models.DriftStatus `json:"drift_statuses,omitempty"`
+}
+
+// Dump returns everything stored in d.
+func Dump(d Database) (Export, error) {
+	projectLocks, err := d.List()
+	if err != nil {
+		return Export{}, errors.Wrap(err, "listing project locks")
+	}
+	commandLocks, err := d.ListCommandLocks()
+	if err != nil {
+		return Export{}, errors.Wrap(err, "listing command locks")
+	}
+	pullStatuses, err := d.ListPullStatuses()
+	if err != nil {
+		return Export{}, errors.Wrap(err, "listing pull statuses")
+	}
+	driftStatuses, err := d.ListDriftStatuses()
+	if err != nil {
+		return Export{}, errors.Wrap(err, "listing drift statuses")
+	}
+	return Export{
+		Version:       ExportVersion,
+		ProjectLocks:  projectLocks,
+		CommandLocks:  commandLocks,
+		PullStatuses:  pullStatuses,
+		DriftStatuses: driftStatuses,
+	}, nil
+}
+
+// Load writes everything in e into d. Locks that d already holds for the
+// same pull request are left alone, but if a project lock is held by a
+// different pull request Load returns an error rather than silently dropping
+// one of the locks. Pull and drift statuses in e overwrite the ones in d.
+//
+// Every project lock is checked for conflicts before anything is written so
+// a conflict leaves d unchanged. If writing to d fails part way through, ex.
+// because the connection to Redis is lost, d holds part of e and Load can be
+// run again with the same export once the problem is fixed.
+func Load(d Database, e Export) error {
+	if e.Version != ExportVersion {
+		return fmt.Errorf("unsupported export version %d, expected %d", e.Version, ExportVersion)
+	}
+
+	for _, lock := range e.ProjectLocks {
+		currLock, err := d.GetLock(lock.Project, lock.Workspace)
+		if err != nil {
+			return errors.Wrapf(err, "checking lock of repo %s, path %s, workspace %s", lock.Project.RepoFullName, lock.Project.Path, lock.Workspace)
+		}
+		if currLock != nil && !samePull(*currLock, lock) {
+			return lockConflictErr(lock, *currLock)
+		}
+	}
+
+	for _, lock := range e.ProjectLocks {
+		acquired, currLock, err := d.TryLock(lock)
+		if err != nil {
+			return errors.Wrapf(err, "locking repo %s, path %s, workspace %s", lock.Project.RepoFullName, lock.Project.Path, lock.Workspace)
+		}
+		// The lock can still be taken between the check above and now if
+		// the server is running.
+		if !acquired && !samePull(currLock, lock) {
+			return lockConflictErr(lock, currLock)
+		}
+	}
+
+	for _, cmdLock := range e.CommandLocks {
+		currLock, err := d.CheckCommandLock(cmdLock.CommandName)
+		if err != nil {
+			return errors.Wrapf(err, "checking %s command lock", cmdLock.CommandName)
+		}
+		if currLock != nil {
+			continue
+		}
+		if _, err := d.LockCommand(cmdLock.CommandName, cmdLock.LockTime()); err != nil {
+			return errors.Wrapf(err, "locking %s command", cmdLock.CommandName)
+		}
+	}
+
+	for _, status := range e.PullStatuses {
+		if err := d.SetPullStatus(status); err != nil {
+			return errors.Wrapf(err, "writing status for pull %s#%d", status.Pull.BaseRepo.FullName, status.Pull.Num)
+		}
+	}
+
+	for _, status := range e.DriftStatuses {
+		if err := d.SetDriftStatus(status); err != nil {
+			return errors.Wrapf(err, "writing drift status for %s branch %s", status.RepoFullName, status.Branch)
+		}
+	}
+	return nil
+}
+
+func samePull(a models.ProjectLock, b models.ProjectLock) bool {
+	return a.Pull.Num == b.Pull.Num && a.Pull.BaseRepo.FullName == b.Pull.BaseRepo.FullName
+}
+
+func lockConflictErr(lock models.ProjectLock, currLock models.ProjectLock) error {
+	return fmt.Errorf("repo %s, path %s, workspace %s is already locked by pull request #%d",
+		lock.Project.RepoFullName, lock.Project.Path, lock.Workspace, currLock.Pull.Num)
+}
@@ -0,0 +1,124 @@
+package db_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/db"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+var exportPull = models.PullRequest{
+	Num:        1,
+	HeadCommit: "sha",
+	BaseRepo: models.Repo{
+		FullName: "runatlantis/atlantis",
+		VCSHost: models.VCSHost{
+			Hostname: "github.com",
+			Type:     models.Github,
+		},
+	},
+}
+
+func TestDumpLoad_RoundTrip(t *testing.T) {
+	src, cleanupSrc := newTestDB2(t)
+	defer cleanupSrc()
+	dst, cleanupDst := newTestDB2(t)
+	defer cleanupDst()
+
+	_, _, err := src.TryLock(lock)
+	Ok(t, err)
+	lockTime := time.Unix(1600000000, 0)
+	_, err = src.LockCommand(command.Apply, lockTime)
+	Ok(t, err)
+	_, err = src.UpdatePullWithResults(exportPull, []command.ProjectResult{
+		{
+			Command:      command.Apply,
+			RepoRelDir:   ".",
+			Workspace:    "default",
+			ApplySuccess: "success!",
+		},
+	})
+	Ok(t, err)
+	driftStatus := models.DriftStatus{
+		RepoFullName: "owner/repo",
+		Hostname:     "github.com",
+		Branch:       "main",
+		CheckedAt:    time.Unix(1600000000, 0).UTC(),
+		Projects:     []models.ProjectDriftStatus{{RepoRelDir: ".", Workspace: "default", Drifted: true}},
+	}
+	Ok(t, src.SetDriftStatus(driftStatus))
+
+	e, err := db.Dump(src)
+	Ok(t, err)
+	Equals(t, db.ExportVersion, e.Version)
+	Equals(t, 1, len(e.ProjectLocks))
+	Equals(t, 1, len(e.CommandLocks))
+	Equals(t, 1, len(e.PullStatuses))
+	Equals(t, 1, len(e.DriftStatuses))
+
+	Ok(t, db.Load(dst, e))
+
+	l, err := dst.GetLock(project, workspace)
+	Ok(t, err)
+	Assert(t, l != nil, "exp lock to be imported")
+	Equals(t, lock.Pull, l.Pull)
+
+	cmdLock, err := dst.CheckCommandLock(command.Apply)
+	Ok(t, err)
+	Equals(t, lockTime, cmdLock.LockTime())
+
+	status, err := dst.GetPullStatus(exportPull)
+	Ok(t, err)
+	Equals(t, []models.ProjectStatus{
+		{
+			RepoRelDir: ".",
+			Workspace:  "default",
+			Status:     models.AppliedPlanStatus,
+		},
+	}, status.Projects)
+
+	driftStatuses, err := dst.ListDriftStatuses()
+	Ok(t, err)
+	Equals(t, []models.DriftStatus{driftStatus}, driftStatuses)
+
+	// Loading the same data again is a no-op.
+	Ok(t, db.Load(dst, e))
+}
+
+func TestLoad_ConflictingLock(t *testing.T) {
+	dst, cleanup := newTestDB2(t)
+	defer cleanup()
+
+	otherLock := lock
+	otherLock.Pull.Num = lock.Pull.Num + 1
+	_, _, err := dst.TryLock(otherLock)
+	Ok(t, err)
+
+	otherWorkspaceLock := lock
+	otherWorkspaceLock.Workspace = "staging"
+	err = db.Load(dst, db.Export{
+		Version:      db.ExportVersion,
+		ProjectLocks: []models.ProjectLock{otherWorkspaceLock, lock},
+		PullStatuses: []models.PullStatus{{Pull: exportPull}},
+	})
+	ErrEquals(t, "repo owner/repo, path parent/child, workspace default is already locked by pull request #2", err)
+
+	// Nothing is written if there's a conflict.
+	l, err := dst.GetLock(project, "staging")
+	Ok(t, err)
+	Assert(t, l == nil, "exp lock not to be imported")
+	status, err := dst.GetPullStatus(exportPull)
+	Ok(t, err)
+	Assert(t, status == nil, "exp pull status not to be imported")
+}
+
+func TestLoad_UnsupportedVersion(t *testing.T) {
+	dst, cleanup := newTestDB2(t)
+	defer cleanup()
+
+	err := db.Load(dst, db.Export{Version: db.ExportVersion + 1})
+	ErrEquals(t, "unsupported export version 2, expected 1", err)
+}
@@ -87,7 +87,7 @@ func (c *Client) TryLock(p models.Project, workspace string, pull models.PullReq
 	if err != nil {
 		return TryLockResponse{}, err
 	}
-	return TryLockResponse{lockAcquired, currLock, c.key(p, workspace)}, nil
+	return TryLockResponse{lockAcquired, currLock, Key(p, workspace)}, nil
 }
 
 // Unlock attempts to unlock a project and workspace. If successful,
@@ -111,7 +111,7 @@ func (c *Client) List() (map[string]models.ProjectLock, error) {
 		return m, err
 	}
 	for _, lock := range locks {
-		m[c.key(lock.Project, lock.Workspace)] = lock
+		m[Key(lock.Project, lock.Workspace)] = lock
 	}
 	return m, nil
 }
@@ -139,7 +139,8 @@ func (c *Client) GetLock(key string) (*models.ProjectLock, error) {
 	return projectLock, nil
 }
 
-func (c *Client) key(p models.Project, workspace string) string {
+// Key returns the key the lock of project p in workspace is stored under.
+func Key(p models.Project, workspace string) string {
 	return fmt.Sprintf("%s/%s/%s", p.RepoFullName, p.Path, workspace)
 }
 
@@ -161,7 +162,7 @@ func NewNoOpLocker() *NoOpLocker {
 
 // TryLock attempts to acquire a lock to a project and workspace.
 func (c *NoOpLocker) TryLock(p models.Project, workspace string, pull models.PullRequest, user models.User) (TryLockResponse, error) {
-	return TryLockResponse{true, models.ProjectLock{}, c.key(p, workspace)}, nil
+	return TryLockResponse{true, models.ProjectLock{}, Key(p, workspace)}, nil
 }
 
 // Unlock attempts to unlock a project and workspace. If successful,
@@ -191,7 +192,3 @@ func (c *NoOpLocker) UnlockByPull(repoFullName string, pullNum int) ([]models.Pr
 func (c *NoOpLocker) GetLock(key string) (*models.ProjectLock, error) {
 	return nil, nil
 }
-
-func (c *NoOpLocker) key(p models.Project, workspace string) string {
-	return fmt.Sprintf("%s/%s/%s", p.RepoFullName, p.Path, workspace)
-}
@@ -0,0 +1,555 @@
+// Package redis handles our remote database layer.
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+)
+
+var ctx = context.Background()
+
+// RedisDB is a database using Redis. Unlike BoltDB it can be shared by
+// multiple Atlantis instances.
+type RedisDB struct { // nolint: revive
+	client *redis.Client
+}
+
+const (
+	locksKeyPrefix       = "lock/"
+	pullsKeyPrefix       = "pull/"
+	globalLocksKeyPrefix = "global/"
+	driftKeyPrefix       = "drift/"
+	pullKeySeparator     = "::"
+	// scanCount is the hint passed to SCAN for how many keys to return per
+	// iteration.
+	scanCount = 100
+)
+
+// New returns a RedisDB connected to the Redis server at hostname:port.
+// It returns an error if the server can't be reached.
+func New(hostname string, port int, password string, tlsEnabled bool, insecureSkipVerify bool, db int) (*RedisDB, error) {
+	var tlsConfig *tls.Config
+	if tlsEnabled {
+		tlsConfig = &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: insecureSkipVerify, // nolint: gosec
+		}
+	}
+	rdb := redis.NewClient(&redis.Options{
+		Addr:      fmt.Sprintf("%s:%d", hostname, port),
+		Password:  password,
+		DB:        db,
+		TLSConfig: tlsConfig,
+	})
+
+	// Check if connection is valid.
+	if _, err := rdb.Ping(ctx).Result(); err != nil {
+		return nil, errors.Wrap(err, "failed to connect to redis instance")
+	}
+
+	return &RedisDB{
+		client: rdb,
+	}, nil
+}
+
+// Close closes the connection to the Redis server.
+func (r *RedisDB) Close() error {
+	return r.client.Close()
+}
+
+// TryLock attempts to create a new lock. If the lock is
+// acquired, it will return true and the lock returned will be newLock.
+// If the lock is not acquired, it will return false and the current
+// lock that is preventing this lock from being acquired.
+func (r *RedisDB) TryLock(newLock models.ProjectLock) (bool, models.ProjectLock, error) {
+	var currLock models.ProjectLock
+	key := r.lockKey(newLock.Project, newLock.Workspace)
+	newLockSerialized, _ := json.Marshal(newLock)
+
+	// SETNX is atomic so only one Atlantis instance can acquire the lock.
+	acquired, err := r.client.SetNX(ctx, key, newLockSerialized, 0).Result()
+	if err != nil {
+		return false, currLock, errors.Wrap(err, "db transaction failed")
+	}
+	if acquired {
+		return true, newLock, nil
+	}
+
+	// Otherwise the lock fails, return to caller the run that's holding the lock.
+	currLockSerialized, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		// The lock was released between our SETNX and GET so try again.
+		return r.TryLock(newLock)
+	}
+	if err != nil {
+		return false, currLock, errors.Wrap(err, "db transaction failed")
+	}
+	if err := json.Unmarshal([]byte(currLockSerialized), &currLock); err != nil {
+		return false, currLock, errors.Wrap(err, "failed to deserialize current lock")
+	}
+	return false, currLock, nil
+}
+
+// Unlock attempts to unlock the project and workspace.
+// If there is no lock, then it will return a nil pointer.
+// If there is a lock, then it will delete it, and then return a pointer
+// to the deleted lock.
+func (r *RedisDB) Unlock(project models.Project, workspace string) (*models.ProjectLock, error) {
+	key := r.lockKey(project, workspace)
+	// GETDEL would be simpler but requires Redis 6.2.
+	var serialized *redis.StringCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		serialized = pipe.Get(ctx, key)
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "db transaction failed")
+	}
+
+	var lock models.ProjectLock
+	if err := json.Unmarshal([]byte(serialized.Val()), &lock); err != nil {
+		return nil, errors.Wrap(err, "failed to deserialize lock")
+	}
+	return &lock, nil
+}
+
+// List lists all current locks.
+func (r *RedisDB) List() ([]models.ProjectLock, error) {
+	var locks []models.ProjectLock
+	iter := r.client.Scan(ctx, 0, locksKeyPrefix+"*", scanCount).Iterator()
+	for iter.Next(ctx) {
+		lock, err := r.getLockAtKey(iter.Val())
+		if err != nil {
+			return locks, err
+		}
+		// The lock may have been deleted since we scanned its key.
+		if lock != nil {
+			locks = append(locks, *lock)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return locks, errors.Wrap(err, "db transaction failed")
+	}
+	return locks, nil
+}
+
+// LockCommand attempts to create a new lock for a CommandName.
+// If the lock doesn't exists, it will create a lock and return a pointer to it.
+// If the lock already exists, it will return an "lock already exists" error
+func (r *RedisDB) LockCommand(cmdName command.Name, lockTime time.Time) (*command.Lock, error) {
+	lock := command.Lock{
+		CommandName: cmdName,
+		LockMetadata: command.LockMetadata{
+			UnixTime: lockTime.Unix(),
+		},
+	}
+
+	newLockSerialized, _ := json.Marshal(lock)
+	acquired, err := r.client.SetNX(ctx, r.commandLockKey(cmdName), newLockSerialized, 0).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "db transaction failed")
+	}
+	if !acquired {
+		return nil, errors.New("db transaction failed: lock already exists")
+	}
+	return &lock, nil
+}
+
+// UnlockCommand removes CommandName lock if present.
+// If there are no lock it returns an error.
+func (r *RedisDB) UnlockCommand(cmdName command.Name) error {
+	deleted, err := r.client.Del(ctx, r.commandLockKey(cmdName)).Result()
+	if err != nil {
+		return errors.Wrap(err, "db transaction failed")
+	}
+	if deleted == 0 {
+		return errors.New("db transaction failed: no lock exists")
+	}
+	return nil
+}
+
+// CheckCommandLock checks if CommandName lock was set.
+// If the lock exists return the pointer to the lock object, otherwise return nil
+func (r *RedisDB) CheckCommandLock(cmdName command.Name) (*command.Lock, error) {
+	serialized, err := r.client.Get(ctx, r.commandLockKey(cmdName)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "db transaction failed")
+	}
+
+	cmdLock := command.Lock{}
+	if err := json.Unmarshal([]byte(serialized), &cmdLock); err != nil {
+		return nil, errors.Wrap(err, "failed to deserialize command lock")
+	}
+	return &cmdLock, nil
+}
+
+// ListCommandLocks lists all current command locks.
+func (r *RedisDB) ListCommandLocks() ([]command.Lock, error) {
+	var cmdLocks []command.Lock
+	iter := r.client.Scan(ctx, 0, globalLocksKeyPrefix+"*", scanCount).Iterator()
+	for iter.Next(ctx) {
+		serialized, err := r.client.Get(ctx, iter.Val()).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return cmdLocks, errors.Wrap(err, "db transaction failed")
+		}
+		var cmdLock command.Lock
+		if err := json.Unmarshal([]byte(serialized), &cmdLock); err != nil {
+			return cmdLocks, errors.Wrapf(err, "deserializing command lock at key %q", iter.Val())
+		}
+		cmdLocks = append(cmdLocks, cmdLock)
+	}
+	if err := iter.Err(); err != nil {
+		return cmdLocks, errors.Wrap(err, "db transaction failed")
+	}
+	return cmdLocks, nil
+}
+
+// UnlockByPull deletes all locks associated with that pull request and returns them.
+func (r *RedisDB) UnlockByPull(repoFullName string, pullNum int) ([]models.ProjectLock, error) {
+	var locks []models.ProjectLock
+
+	// We can use the repoFullName as a prefix search since that's the first
+	// part of the key.
+	iter := r.client.Scan(ctx, 0, fmt.Sprintf("%s%s/*", locksKeyPrefix, repoFullName), scanCount).Iterator()
+	for iter.Next(ctx) {
+		lock, err := r.getLockAtKey(iter.Val())
+		if err != nil {
+			return locks, err
+		}
+		if lock != nil && lock.Pull.Num == pullNum {
+			locks = append(locks, *lock)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return locks, errors.Wrap(err, "db transaction failed")
+	}
+
+	// Delete the locks.
+	for _, lock := range locks {
+		if _, err := r.Unlock(lock.Project, lock.Workspace); err != nil {
+			return locks, errors.Wrapf(err, "unlocking repo %s, path %s, workspace %s", lock.Project.RepoFullName, lock.Project.Path, lock.Workspace)
+		}
+	}
+	return locks, nil
+}
+
+// GetLock returns a pointer to the lock for that project and workspace.
+// If there is no lock, it returns a nil pointer.
+func (r *RedisDB) GetLock(project models.Project, workspace string) (*models.ProjectLock, error) {
+	lock, err := r.getLockAtKey(r.lockKey(project, workspace))
+	if err != nil {
+		return nil, errors.Wrap(err, "getting lock data")
+	}
+	if lock == nil {
+		return nil, nil
+	}
+
+	// need to set it to Local after deserialization due to https://github.com/golang/go/issues/19486
+	lock.Time = lock.Time.Local()
+	return lock, nil
+}
+
+// UpdatePullWithResults updates pull's status with the latest project results.
+// It returns the new PullStatus object.
+func (r *RedisDB) UpdatePullWithResults(pull models.PullRequest, newResults []command.ProjectResult) (models.PullStatus, error) {
+	key, err := r.pullKey(pull)
+	if err != nil {
+		return models.PullStatus{}, err
+	}
+
+	var newStatus models.PullStatus
+	err = r.updatePull(key, func(currStatus *models.PullStatus) (*models.PullStatus, error) {
+		// If there is no pull OR if the pull we have is out of date, we
+		// just write a new pull.
+		if currStatus == nil || currStatus.Pull.HeadCommit != pull.HeadCommit {
+			var statuses []models.ProjectStatus
+			for _, res := range newResults {
+				statuses = append(statuses, r.projectResultToProject(res))
+			}
+			newStatus = models.PullStatus{
+				Pull:     pull,
+				Projects: statuses,
+			}
+			return &newStatus, nil
+		}
+
+		// If there's an existing pull at the right commit then we have to
+		// merge our project results with the existing ones. We do a merge
+		// because it's possible a user is just applying a single project
+		// in this command and so we don't want to delete our data about
+		// other projects that aren't affected by this command.
+		newStatus = *currStatus
+		for _, res := range newResults {
+			// First, check if we should update any existing projects.
+			updatedExisting := false
+			for i := range newStatus.Projects {
+				// NOTE: We're using a reference here because we are
+				// in-place updating its Status field.
+				proj := &newStatus.Projects[i]
+				if res.Workspace == proj.Workspace &&
+					res.RepoRelDir == proj.RepoRelDir &&
+					res.ProjectName == proj.ProjectName {
+
+					proj.Status = res.PlanStatus()
+					// A new plan invalidates the previous policy check results
+					// and approvals.
+					if res.Command == command.Plan || res.Command == command.PolicyCheck {
+						proj.PolicyStatus = res.PolicyStatus
+					}
+					if res.Command == command.Plan {
+						proj.PlanChanges = res.PlanChangeCounts()
+					}
+					updatedExisting = true
+					break
+				}
+			}
+
+			if !updatedExisting {
+				// If we didn't update an existing project, then we need to
+				// add this because it's a new one.
+				newStatus.Projects = append(newStatus.Projects, r.projectResultToProject(res))
+			}
+		}
+		return &newStatus, nil
+	})
+	return newStatus, errors.Wrap(err, "db transaction failed")
+}
+
+// GetPullStatus returns the status for pull.
+// If there is no status, returns a nil pointer.
+func (r *RedisDB) GetPullStatus(pull models.PullRequest) (*models.PullStatus, error) {
+	key, err := r.pullKey(pull)
+	if err != nil {
+		return nil, err
+	}
+	s, err := r.getPull(r.client, key)
+	return s, errors.Wrap(err, "db transaction failed")
+}
+
+// ListPullStatuses returns the statuses of all pulls.
+func (r *RedisDB) ListPullStatuses() ([]models.PullStatus, error) {
+	var statuses []models.PullStatus
+	iter := r.client.Scan(ctx, 0, pullsKeyPrefix+"*", scanCount).Iterator()
+	for iter.Next(ctx) {
+		s, err := r.getPull(r.client, iter.Val())
+		if err != nil {
+			return statuses, errors.Wrap(err, "db transaction failed")
+		}
+		// The pull may have been deleted since we scanned its key.
+		if s != nil {
+			statuses = append(statuses, *s)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return statuses, errors.Wrap(err, "db transaction failed")
+	}
+	return statuses, nil
+}
+
+// SetPullStatus overwrites the status for status.Pull with status.
+func (r *RedisDB) SetPullStatus(status models.PullStatus) error {
+	key, err := r.pullKey(status.Pull)
+	if err != nil {
+		return err
+	}
+	serialized, err := json.Marshal(status)
+	if err != nil {
+		return errors.Wrap(err, "serializing")
+	}
+	err = r.client.Set(ctx, key, serialized, 0).Err()
+	return errors.Wrap(err, "db transaction failed")
+}
+
+// SetDriftStatus overwrites the drift status for status's repo with status.
+func (r *RedisDB) SetDriftStatus(status models.DriftStatus) error {
+	serialized, err := json.Marshal(status)
+	if err != nil {
+		return errors.Wrap(err, "serializing")
+	}
+	err = r.client.Set(ctx, r.driftKey(status), serialized, 0).Err()
+	return errors.Wrap(err, "db transaction failed")
+}
+
+// ListDriftStatuses returns the drift statuses of all repos.
+func (r *RedisDB) ListDriftStatuses() ([]models.DriftStatus, error) {
+	var statuses []models.DriftStatus
+	iter := r.client.Scan(ctx, 0, driftKeyPrefix+"*", scanCount).Iterator()
+	for iter.Next(ctx) {
+		serialized, err := r.client.Get(ctx, iter.Val()).Result()
+		// The status may have been deleted since we scanned its key.
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return statuses, errors.Wrap(err, "db transaction failed")
+		}
+		var d models.DriftStatus
+		if err := json.Unmarshal([]byte(serialized), &d); err != nil {
+			return statuses, errors.Wrapf(err, "deserializing drift status at %q", iter.Val())
+		}
+		statuses = append(statuses, d)
+	}
+	if err := iter.Err(); err != nil {
+		return statuses, errors.Wrap(err, "db transaction failed")
+	}
+	return statuses, nil
+}
+
+// DeletePullStatus deletes the status for pull.
+func (r *RedisDB) DeletePullStatus(pull models.PullRequest) error {
+	key, err := r.pullKey(pull)
+	if err != nil {
+		return err
+	}
+	err = r.client.Del(ctx, key).Err()
+	return errors.Wrap(err, "db transaction failed")
+}
+
+// UpdateProjectStatus updates project status.
+func (r *RedisDB) UpdateProjectStatus(pull models.PullRequest, workspace string, repoRelDir string, newStatus models.ProjectPlanStatus) error {
+	key, err := r.pullKey(pull)
+	if err != nil {
+		return err
+	}
+	err = r.updatePull(key, func(currStatus *models.PullStatus) (*models.PullStatus, error) {
+		if currStatus == nil {
+			return nil, nil
+		}
+
+		// Update the status.
+		for i := range currStatus.Projects {
+			// NOTE: We're using a reference here because we are
+			// in-place updating its Status field.
+			proj := &currStatus.Projects[i]
+			if proj.Workspace == workspace && proj.RepoRelDir == repoRelDir {
+				proj.Status = newStatus
+				break
+			}
+		}
+		return currStatus, nil
+	})
+	return errors.Wrap(err, "db transaction failed")
+}
+
+// updatePull reads the pull status at key, passes it to update and writes
+// back the status update returns, if any. The read and write happen in an
+// optimistic transaction so concurrent updates from other Atlantis instances
+// aren't lost.
+func (r *RedisDB) updatePull(key string, update func(currStatus *models.PullStatus) (*models.PullStatus, error)) error {
+	txf := func(tx *redis.Tx) error {
+		currStatus, err := r.getPull(tx, key)
+		if err != nil {
+			return err
+		}
+		newStatus, err := update(currStatus)
+		if err != nil || newStatus == nil {
+			return err
+		}
+		serialized, err := json.Marshal(newStatus)
+		if err != nil {
+			return errors.Wrap(err, "serializing")
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, serialized, 0)
+			return nil
+		})
+		return err
+	}
+
+	const maxRetries = 10
+	for i := 0; i < maxRetries; i++ {
+		err := r.client.Watch(ctx, txf, key)
+		if err == redis.TxFailedErr {
+			// Another instance modified the key, retry.
+			continue
+		}
+		return err
+	}
+	return errors.New("reached maximum number of retries")
+}
+
+func (r *RedisDB) getLockAtKey(key string) (*models.ProjectLock, error) {
+	serialized, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var lock models.ProjectLock
+	if err := json.Unmarshal([]byte(serialized), &lock); err != nil {
+		return nil, errors.Wrapf(err, "deserializing lock at key %q", key)
+	}
+	return &lock, nil
+}
+
+func (r *RedisDB) getPull(c redis.Cmdable, key string) (*models.PullStatus, error) {
+	serialized, err := c.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var p models.PullStatus
+	if err := json.Unmarshal([]byte(serialized), &p); err != nil {
+		return nil, errors.Wrapf(err, "deserializing pull at %q with contents %q", key, serialized)
+	}
+	return &p, nil
+}
+
+func (r *RedisDB) pullKey(pull models.PullRequest) (string, error) {
+	hostname := pull.BaseRepo.VCSHost.Hostname
+	if strings.Contains(hostname, pullKeySeparator) {
+		return "", fmt.Errorf("vcs hostname %q contains illegal string %q", hostname, pullKeySeparator)
+	}
+	repo := pull.BaseRepo.FullName
+	if strings.Contains(repo, pullKeySeparator) {
+		return "", fmt.Errorf("repo name %q contains illegal string %q", hostname, pullKeySeparator)
+	}
+
+	return fmt.Sprintf("%s%s::%s::%d", pullsKeyPrefix, hostname, repo, pull.Num), nil
+}
+
+func (r *RedisDB) driftKey(status models.DriftStatus) string {
+	return fmt.Sprintf("%s%s/%s", driftKeyPrefix, status.Hostname, status.RepoFullName)
+}
+
+func (r *RedisDB) commandLockKey(cmdName command.Name) string {
+	return fmt.Sprintf("%s%s/lock", globalLocksKeyPrefix, cmdName)
+}
+
+func (r *RedisDB) lockKey(p models.Project, workspace string) string {
+	return fmt.Sprintf("%s%s/%s/%s", locksKeyPrefix, p.RepoFullName, p.Path, workspace)
+}
+
+func (r *RedisDB) projectResultToProject(p command.ProjectResult) models.ProjectStatus {
+	return models.ProjectStatus{
+		Workspace:    p.Workspace,
+		RepoRelDir:   p.RepoRelDir,
+		ProjectName:  p.ProjectName,
+		Status:       p.PlanStatus(),
+		PolicyStatus: p.PolicyStatus,
+		PlanChanges:  p.PlanChangeCounts(),
+	}
+}
@@ -0,0 +1,820 @@
+package redis_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/redis"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+var project = models.NewProject("owner/repo", "parent/child")
+var workspace = "default"
+var pullNum = 1
+var lock = models.ProjectLock{
+	Pull: models.PullRequest{
+		Num: pullNum,
+	},
+	User: models.User{
+		Username: "lkysow",
+	},
+	Workspace: workspace,
+	Project:   project,
+	Time:      time.Now(),
+}
+
+func TestLockCommandNotSet(t *testing.T) {
+	t.Log("retrieving apply lock when there are none should return empty LockCommand")
+	b := newTestDB(t)
+	exists, err := b.CheckCommandLock(command.Apply)
+	Ok(t, err)
+	Assert(t, exists == nil, "exp nil")
+}
+
+func TestLockCommandEnabled(t *testing.T) {
+	t.Log("setting the apply lock")
+	b := newTestDB(t)
+	timeNow := time.Now()
+	_, err := b.LockCommand(command.Apply, timeNow)
+	Ok(t, err)
+
+	config, err := b.CheckCommandLock(command.Apply)
+	Ok(t, err)
+	Equals(t, true, config.IsLocked())
+}
+
+func TestLockCommandFail(t *testing.T) {
+	t.Log("setting the apply lock")
+	b := newTestDB(t)
+	timeNow := time.Now()
+	_, err := b.LockCommand(command.Apply, timeNow)
+	Ok(t, err)
+
+	_, err = b.LockCommand(command.Apply, timeNow)
+	ErrEquals(t, "db transaction failed: lock already exists", err)
+}
+
+func TestUnlockCommandDisabled(t *testing.T) {
+	t.Log("unsetting the apply lock")
+	b := newTestDB(t)
+	timeNow := time.Now()
+	_, err := b.LockCommand(command.Apply, timeNow)
+	Ok(t, err)
+
+	config, err := b.CheckCommandLock(command.Apply)
+	Ok(t, err)
+	Equals(t, true, config.IsLocked())
+
+	err = b.UnlockCommand(command.Apply)
+	Ok(t, err)
+
+	config, err = b.CheckCommandLock(command.Apply)
+	Ok(t, err)
+	Assert(t, config == nil, "exp nil object")
+}
+
+func TestUnlockCommandFail(t *testing.T) {
+	t.Log("setting the apply lock")
+	b := newTestDB(t)
+	err := b.UnlockCommand(command.Apply)
+	ErrEquals(t, "db transaction failed: no lock exists", err)
+}
+
+func TestMixedLocksPresent(t *testing.T) {
+	b := newTestDB(t)
+	timeNow := time.Now()
+	_, err := b.LockCommand(command.Apply, timeNow)
+	Ok(t, err)
+
+	_, _, err = b.TryLock(lock)
+	Ok(t, err)
+	ls, err := b.List()
+	Ok(t, err)
+	Equals(t, 1, len(ls))
+}
+
+func TestListNoLocks(t *testing.T) {
+	t.Log("listing locks when there are none should return an empty list")
+	b := newTestDB(t)
+	ls, err := b.List()
+	Ok(t, err)
+	Equals(t, 0, len(ls))
+}
+
+func TestListOneLock(t *testing.T) {
+	t.Log("listing locks when there is one should return it")
+	b := newTestDB(t)
+	_, _, err := b.TryLock(lock)
+	Ok(t, err)
+	ls, err := b.List()
+	Ok(t, err)
+	Equals(t, 1, len(ls))
+}
+
+func TestListMultipleLocks(t *testing.T) {
+	t.Log("listing locks when there are multiple should return them")
+	b := newTestDB(t)
+
+	// add multiple locks
+	repos := []string{
+		"owner/repo1",
+		"owner/repo2",
+		"owner/repo3",
+		"owner/repo4",
+	}
+
+	for _, r := range repos {
+		newLock := lock
+		newLock.Project = models.NewProject(r, "path")
+		_, _, err := b.TryLock(newLock)
+		Ok(t, err)
+	}
+	ls, err := b.List()
+	Ok(t, err)
+	Equals(t, 4, len(ls))
+	for _, r := range repos {
+		found := false
+		for _, l := range ls {
+			if l.Project.RepoFullName == r {
+				found = true
+			}
+		}
+		Assert(t, found, "expected %s in %v", r, ls)
+	}
+}
+
+func TestListAddRemove(t *testing.T) {
+	t.Log("listing after adding and removing should return none")
+	b := newTestDB(t)
+	_, _, err := b.TryLock(lock)
+	Ok(t, err)
+	_, err = b.Unlock(project, workspace)
+	Ok(t, err)
+
+	ls, err := b.List()
+	Ok(t, err)
+	Equals(t, 0, len(ls))
+}
+
+func TestLockingNoLocks(t *testing.T) {
+	t.Log("with no locks yet, lock should succeed")
+	b := newTestDB(t)
+	acquired, currLock, err := b.TryLock(lock)
+	Ok(t, err)
+	Equals(t, true, acquired)
+	Equals(t, lock, currLock)
+}
+
+func TestLockingExistingLock(t *testing.T) {
+	t.Log("if there is an existing lock, lock should...")
+	b := newTestDB(t)
+	_, _, err := b.TryLock(lock)
+	Ok(t, err)
+
+	t.Log("...succeed if the new project has a different path")
+	{
+		newLock := lock
+		newLock.Project = models.NewProject(project.RepoFullName, "different/path")
+		acquired, currLock, err := b.TryLock(newLock)
+		Ok(t, err)
+		Equals(t, true, acquired)
+		Equals(t, pullNum, currLock.Pull.Num)
+	}
+
+	t.Log("...succeed if the new project has a different workspace")
+	{
+		newLock := lock
+		newLock.Workspace = "different-workspace"
+		acquired, currLock, err := b.TryLock(newLock)
+		Ok(t, err)
+		Equals(t, true, acquired)
+		Equals(t, newLock, currLock)
+	}
+
+	t.Log("...succeed if the new project has a different repoName")
+	{
+		newLock := lock
+		newLock.Project = models.NewProject("different/repo", project.Path)
+		acquired, currLock, err := b.TryLock(newLock)
+		Ok(t, err)
+		Equals(t, true, acquired)
+		Equals(t, newLock, currLock)
+	}
+
+	t.Log("...not succeed if the new project only has a different pullNum")
+	{
+		newLock := lock
+		newLock.Pull.Num = lock.Pull.Num + 1
+		acquired, currLock, err := b.TryLock(newLock)
+		Ok(t, err)
+		Equals(t, false, acquired)
+		Equals(t, currLock.Pull.Num, pullNum)
+	}
+}
+
+func TestUnlockingNoLocks(t *testing.T) {
+	t.Log("unlocking with no locks should succeed")
+	b := newTestDB(t)
+	_, err := b.Unlock(project, workspace)
+
+	Ok(t, err)
+}
+
+func TestUnlocking(t *testing.T) {
+	t.Log("unlocking with an existing lock should succeed")
+	b := newTestDB(t)
+
+	_, _, err := b.TryLock(lock)
+	Ok(t, err)
+	_, err = b.Unlock(project, workspace)
+	Ok(t, err)
+
+	// should be no locks listed
+	ls, err := b.List()
+	Ok(t, err)
+	Equals(t, 0, len(ls))
+
+	// should be able to re-lock that repo with a new pull num
+	newLock := lock
+	newLock.Pull.Num = lock.Pull.Num + 1
+	acquired, currLock, err := b.TryLock(newLock)
+	Ok(t, err)
+	Equals(t, true, acquired)
+	Equals(t, newLock, currLock)
+}
+
+func TestUnlockingMultiple(t *testing.T) {
+	t.Log("unlocking and locking multiple locks should succeed")
+	b := newTestDB(t)
+
+	_, _, err := b.TryLock(lock)
+	Ok(t, err)
+
+	new := lock
+	new.Project.RepoFullName = "new/repo"
+	_, _, err = b.TryLock(new)
+	Ok(t, err)
+
+	new2 := lock
+	new2.Project.Path = "new/path"
+	_, _, err = b.TryLock(new2)
+	Ok(t, err)
+
+	new3 := lock
+	new3.Workspace = "new-workspace"
+	_, _, err = b.TryLock(new3)
+	Ok(t, err)
+
+	// now try and unlock them
+	_, err = b.Unlock(new3.Project, new3.Workspace)
+	Ok(t, err)
+	_, err = b.Unlock(new2.Project, workspace)
+	Ok(t, err)
+	_, err = b.Unlock(new.Project, workspace)
+	Ok(t, err)
+	_, err = b.Unlock(project, workspace)
+	Ok(t, err)
+
+	// should be none left
+	ls, err := b.List()
+	Ok(t, err)
+	Equals(t, 0, len(ls))
+}
+
+func TestUnlockByPullNone(t *testing.T) {
+	t.Log("UnlockByPull should be successful when there are no locks")
+	b := newTestDB(t)
+
+	_, err := b.UnlockByPull("any/repo", 1)
+	Ok(t, err)
+}
+
+func TestUnlockByPullOne(t *testing.T) {
+	t.Log("with one lock, UnlockByPull should...")
+	b := newTestDB(t)
+	_, _, err := b.TryLock(lock)
+	Ok(t, err)
+
+	t.Log("...delete nothing when its the same repo but a different pull")
+	{
+		_, err := b.UnlockByPull(project.RepoFullName, pullNum+1)
+		Ok(t, err)
+		ls, err := b.List()
+		Ok(t, err)
+		Equals(t, 1, len(ls))
+	}
+	t.Log("...delete nothing when its the same pull but a different repo")
+	{
+		_, err := b.UnlockByPull("different/repo", pullNum)
+		Ok(t, err)
+		ls, err := b.List()
+		Ok(t, err)
+		Equals(t, 1, len(ls))
+	}
+	t.Log("...delete the lock when its the same repo and pull")
+	{
+		_, err := b.UnlockByPull(project.RepoFullName, pullNum)
+		Ok(t, err)
+		ls, err := b.List()
+		Ok(t, err)
+		Equals(t, 0, len(ls))
+	}
+}
+
+func TestUnlockByPullAfterUnlock(t *testing.T) {
+	t.Log("after locking and unlocking, UnlockByPull should be successful")
+	b := newTestDB(t)
+	_, _, err := b.TryLock(lock)
+	Ok(t, err)
+	_, err = b.Unlock(project, workspace)
+	Ok(t, err)
+
+	_, err = b.UnlockByPull(project.RepoFullName, pullNum)
+	Ok(t, err)
+	ls, err := b.List()
+	Ok(t, err)
+	Equals(t, 0, len(ls))
+}
+
+func TestUnlockByPullMatching(t *testing.T) {
+	t.Log("UnlockByPull should delete all locks in that repo and pull num")
+	b := newTestDB(t)
+	_, _, err := b.TryLock(lock)
+	Ok(t, err)
+
+	// add additional locks with the same repo and pull num but different paths/workspaces
+	new := lock
+	new.Project.Path = "dif/path"
+	_, _, err = b.TryLock(new)
+	Ok(t, err)
+	new2 := lock
+	new2.Workspace = "new-workspace"
+	_, _, err = b.TryLock(new2)
+	Ok(t, err)
+
+	// there should now be 3
+	ls, err := b.List()
+	Ok(t, err)
+	Equals(t, 3, len(ls))
+
+	// should all be unlocked
+	_, err = b.UnlockByPull(project.RepoFullName, pullNum)
+	Ok(t, err)
+	ls, err = b.List()
+	Ok(t, err)
+	Equals(t, 0, len(ls))
+}
+
+func TestGetLockNotThere(t *testing.T) {
+	t.Log("getting a lock that doesn't exist should return a nil pointer")
+	b := newTestDB(t)
+	l, err := b.GetLock(project, workspace)
+	Ok(t, err)
+	Equals(t, (*models.ProjectLock)(nil), l)
+}
+
+func TestGetLock(t *testing.T) {
+	t.Log("getting a lock should return the lock")
+	b := newTestDB(t)
+	_, _, err := b.TryLock(lock)
+	Ok(t, err)
+
+	l, err := b.GetLock(project, workspace)
+	Ok(t, err)
+	// can't compare against time so doing each field
+	Equals(t, lock.Project, l.Project)
+	Equals(t, lock.Workspace, l.Workspace)
+	Equals(t, lock.Pull, l.Pull)
+	Equals(t, lock.User, l.User)
+}
+
+// Test we can create a status and then getCommandLock it.
+func TestPullStatus_UpdateGet(t *testing.T) {
+	b := newTestDB(t)
+
+	pull := models.PullRequest{
+		Num:        1,
+		HeadCommit: "sha",
+		URL:        "url",
+		HeadBranch: "head",
+		BaseBranch: "base",
+		Author:     "lkysow",
+		State:      models.OpenPullState,
+		BaseRepo: models.Repo{
+			FullName:          "runatlantis/atlantis",
+			Owner:             "runatlantis",
+			Name:              "atlantis",
+			CloneURL:          "clone-url",
+			SanitizedCloneURL: "clone-url",
+			VCSHost: models.VCSHost{
+				Hostname: "github.com",
+				Type:     models.Github,
+			},
+		},
+	}
+	status, err := b.UpdatePullWithResults(
+		pull,
+		[]command.ProjectResult{
+			{
+				Command:    command.Plan,
+				RepoRelDir: ".",
+				Workspace:  "default",
+				Failure:    "failure",
+			},
+		})
+	Ok(t, err)
+
+	maybeStatus, err := b.GetPullStatus(pull)
+	Ok(t, err)
+	Equals(t, pull, maybeStatus.Pull) // nolint: staticcheck
+	Equals(t, []models.ProjectStatus{
+		{
+			Workspace:   "default",
+			RepoRelDir:  ".",
+			ProjectName: "",
+			Status:      models.ErroredPlanStatus,
+		},
+	}, status.Projects)
+}
+
+// Test we can create a status, delete it, and then we shouldn't be able to getCommandLock
+// it.
+func TestPullStatus_UpdateDeleteGet(t *testing.T) {
+	b := newTestDB(t)
+
+	pull := models.PullRequest{
+		Num:        1,
+		HeadCommit: "sha",
+		URL:        "url",
+		HeadBranch: "head",
+		BaseBranch: "base",
+		Author:     "lkysow",
+		State:      models.OpenPullState,
+		BaseRepo: models.Repo{
+			FullName:          "runatlantis/atlantis",
+			Owner:             "runatlantis",
+			Name:              "atlantis",
+			CloneURL:          "clone-url",
+			SanitizedCloneURL: "clone-url",
+			VCSHost: models.VCSHost{
+				Hostname: "github.com",
+				Type:     models.Github,
+			},
+		},
+	}
+	_, err := b.UpdatePullWithResults(
+		pull,
+		[]command.ProjectResult{
+			{
+				RepoRelDir: ".",
+				Workspace:  "default",
+				Failure:    "failure",
+			},
+		})
+	Ok(t, err)
+
+	err = b.DeletePullStatus(pull)
+	Ok(t, err)
+
+	maybeStatus, err := b.GetPullStatus(pull)
+	Ok(t, err)
+	Assert(t, maybeStatus == nil, "exp nil")
+}
+
+// Test we can create a status, update a specific project's status within that
+// pull status, and when we getCommandLock all the project statuses, that specific project
+// should be updated.
+func TestPullStatus_UpdateProject(t *testing.T) {
+	b := newTestDB(t)
+
+	pull := models.PullRequest{
+		Num:        1,
+		HeadCommit: "sha",
+		URL:        "url",
+		HeadBranch: "head",
+		BaseBranch: "base",
+		Author:     "lkysow",
+		State:      models.OpenPullState,
+		BaseRepo: models.Repo{
+			FullName:          "runatlantis/atlantis",
+			Owner:             "runatlantis",
+			Name:              "atlantis",
+			CloneURL:          "clone-url",
+			SanitizedCloneURL: "clone-url",
+			VCSHost: models.VCSHost{
+				Hostname: "github.com",
+				Type:     models.Github,
+			},
+		},
+	}
+	_, err := b.UpdatePullWithResults(
+		pull,
+		[]command.ProjectResult{
+			{
+				RepoRelDir: ".",
+				Workspace:  "default",
+				Failure:    "failure",
+			},
+			{
+				RepoRelDir:   ".",
+				Workspace:    "staging",
+				ApplySuccess: "success!",
+			},
+		})
+	Ok(t, err)
+
+	err = b.UpdateProjectStatus(pull, "default", ".", models.DiscardedPlanStatus)
+	Ok(t, err)
+
+	status, err := b.GetPullStatus(pull)
+	Ok(t, err)
+	Equals(t, pull, status.Pull) // nolint: staticcheck
+	Equals(t, []models.ProjectStatus{
+		{
+			Workspace:   "default",
+			RepoRelDir:  ".",
+			ProjectName: "",
+			Status:      models.DiscardedPlanStatus,
+		},
+		{
+			Workspace:   "staging",
+			RepoRelDir:  ".",
+			ProjectName: "",
+			Status:      models.AppliedPlanStatus,
+		},
+	}, status.Projects) // nolint: staticcheck
+}
+
+// Test that if we update an existing pull status and our new status is for a
+// different HeadSHA, that we just overwrite the old status.
+func TestPullStatus_UpdateNewCommit(t *testing.T) {
+	b := newTestDB(t)
+
+	pull := models.PullRequest{
+		Num:        1,
+		HeadCommit: "sha",
+		URL:        "url",
+		HeadBranch: "head",
+		BaseBranch: "base",
+		Author:     "lkysow",
+		State:      models.OpenPullState,
+		BaseRepo: models.Repo{
+			FullName:          "runatlantis/atlantis",
+			Owner:             "runatlantis",
+			Name:              "atlantis",
+			CloneURL:          "clone-url",
+			SanitizedCloneURL: "clone-url",
+			VCSHost: models.VCSHost{
+				Hostname: "github.com",
+				Type:     models.Github,
+			},
+		},
+	}
+	_, err := b.UpdatePullWithResults(
+		pull,
+		[]command.ProjectResult{
+			{
+				RepoRelDir: ".",
+				Workspace:  "default",
+				Failure:    "failure",
+			},
+		})
+	Ok(t, err)
+
+	pull.HeadCommit = "newsha"
+	status, err := b.UpdatePullWithResults(pull,
+		[]command.ProjectResult{
+			{
+				RepoRelDir:   ".",
+				Workspace:    "staging",
+				ApplySuccess: "success!",
+			},
+		})
+
+	Ok(t, err)
+	Equals(t, 1, len(status.Projects))
+
+	maybeStatus, err := b.GetPullStatus(pull)
+	Ok(t, err)
+	Equals(t, pull, maybeStatus.Pull)
+	Equals(t, []models.ProjectStatus{
+		{
+			Workspace:   "staging",
+			RepoRelDir:  ".",
+			ProjectName: "",
+			Status:      models.AppliedPlanStatus,
+		},
+	}, maybeStatus.Projects)
+}
+
+// Test that if we update an existing pull status and our new status is for a
+// the same commit, that we merge the statuses.
+func TestPullStatus_UpdateMerge(t *testing.T) {
+	b := newTestDB(t)
+
+	pull := models.PullRequest{
+		Num:        1,
+		HeadCommit: "sha",
+		URL:        "url",
+		HeadBranch: "head",
+		BaseBranch: "base",
+		Author:     "lkysow",
+		State:      models.OpenPullState,
+		BaseRepo: models.Repo{
+			FullName:          "runatlantis/atlantis",
+			Owner:             "runatlantis",
+			Name:              "atlantis",
+			CloneURL:          "clone-url",
+			SanitizedCloneURL: "clone-url",
+			VCSHost: models.VCSHost{
+				Hostname: "github.com",
+				Type:     models.Github,
+			},
+		},
+	}
+	_, err := b.UpdatePullWithResults(
+		pull,
+		[]command.ProjectResult{
+			{
+				Command:    command.Plan,
+				RepoRelDir: "mergeme",
+				Workspace:  "default",
+				Failure:    "failure",
+			},
+			{
+				Command:     command.Plan,
+				RepoRelDir:  "projectname",
+				Workspace:   "default",
+				ProjectName: "projectname",
+				Failure:     "failure",
+			},
+			{
+				Command:    command.Plan,
+				RepoRelDir: "staythesame",
+				Workspace:  "default",
+				PlanSuccess: &models.PlanSuccess{
+					TerraformOutput: "tf out",
+					LockURL:         "lock-url",
+					RePlanCmd:       "plan command",
+					ApplyCmd:        "apply command",
+				},
+			},
+		})
+	Ok(t, err)
+
+	updateStatus, err := b.UpdatePullWithResults(pull,
+		[]command.ProjectResult{
+			{
+				Command:      command.Apply,
+				RepoRelDir:   "mergeme",
+				Workspace:    "default",
+				ApplySuccess: "applied!",
+			},
+			{
+				Command:     command.Apply,
+				RepoRelDir:  "projectname",
+				Workspace:   "default",
+				ProjectName: "projectname",
+				Error:       errors.New("apply error"),
+			},
+			{
+				Command:      command.Apply,
+				RepoRelDir:   "newresult",
+				Workspace:    "default",
+				ApplySuccess: "success!",
+			},
+		})
+	Ok(t, err)
+
+	getStatus, err := b.GetPullStatus(pull)
+	Ok(t, err)
+
+	// Test both the pull state returned from the update call *and* the getCommandLock
+	// call.
+	for _, s := range []models.PullStatus{updateStatus, *getStatus} {
+		Equals(t, pull, s.Pull)
+		Equals(t, []models.ProjectStatus{
+			{
+				RepoRelDir: "mergeme",
+				Workspace:  "default",
+				Status:     models.AppliedPlanStatus,
+			},
+			{
+				RepoRelDir:  "projectname",
+				Workspace:   "default",
+				ProjectName: "projectname",
+				Status:      models.ErroredApplyStatus,
+			},
+			{
+				RepoRelDir: "staythesame",
+				Workspace:  "default",
+				Status:     models.PlannedPlanStatus,
+			},
+			{
+				RepoRelDir: "newresult",
+				Workspace:  "default",
+				Status:     models.AppliedPlanStatus,
+			},
+		}, updateStatus.Projects)
+	}
+}
+
+// Test that two Atlantis instances pointed at the same Redis server see each
+// other's locks.
+func TestLockingSharedBetweenInstances(t *testing.T) {
+	s := miniredis.RunT(t)
+	b1 := newTestDBForServer(t, s)
+	b2 := newTestDBForServer(t, s)
+
+	acquired, _, err := b1.TryLock(lock)
+	Ok(t, err)
+	Equals(t, true, acquired)
+
+	newLock := lock
+	newLock.Pull.Num = lock.Pull.Num + 1
+	acquired, currLock, err := b2.TryLock(newLock)
+	Ok(t, err)
+	Equals(t, false, acquired)
+	Equals(t, pullNum, currLock.Pull.Num)
+
+	_, err = b1.Unlock(project, workspace)
+	Ok(t, err)
+	acquired, _, err = b2.TryLock(newLock)
+	Ok(t, err)
+	Equals(t, true, acquired)
+}
+
+// newTestDB returns a RedisDB connected to an in-process Redis server that
+// is shut down when the test finishes.
+func newTestDB(t *testing.T) *redis.RedisDB {
+	return newTestDBForServer(t, miniredis.RunT(t))
+}
+
+func newTestDBForServer(t *testing.T, s *miniredis.Miniredis) *redis.RedisDB {
+	port, err := strconv.Atoi(s.Port())
+	Ok(t, err)
+	r, err := redis.New(s.Host(), port, "", false, false, 0)
+	Ok(t, err)
+	return r
+}
+
+func TestListCommandLocksAndPullStatuses(t *testing.T) {
+	b := newTestDB(t)
+	_, err := b.LockCommand(command.Apply, time.Now())
+	Ok(t, err)
+	cmdLocks, err := b.ListCommandLocks()
+	Ok(t, err)
+	Equals(t, 1, len(cmdLocks))
+	Equals(t, command.Apply, cmdLocks[0].CommandName)
+
+	status := models.PullStatus{
+		Pull: models.PullRequest{
+			Num: 1,
+			BaseRepo: models.Repo{
+				FullName: "runatlantis/atlantis",
+				VCSHost:  models.VCSHost{Hostname: "github.com"},
+			},
+		},
+		Projects: []models.ProjectStatus{
+			{
+				RepoRelDir: ".",
+				Workspace:  "default",
+				Status:     models.PlannedPlanStatus,
+			},
+		},
+	}
+	Ok(t, b.SetPullStatus(status))
+	statuses, err := b.ListPullStatuses()
+	Ok(t, err)
+	Equals(t, []models.PullStatus{status}, statuses)
+}
+
+func TestDriftStatus_SetList(t *testing.T) {
+	b := newTestDB(t)
+
+	statuses, err := b.ListDriftStatuses()
+	Ok(t, err)
+	Equals(t, 0, len(statuses))
+
+	status := models.DriftStatus{
+		RepoFullName: "runatlantis/atlantis",
+		Hostname:     "github.com",
+		Branch:       "main",
+		CheckedAt:    time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+		Projects: []models.ProjectDriftStatus{
+			{RepoRelDir: ".", Workspace: "default", Drifted: true, Summary: "Plan: 1 to add, 0 to change, 0 to destroy."},
+		},
+	}
+	Ok(t, b.SetDriftStatus(status))
+	// Setting the status again overwrites it.
+	status.Projects[0].Drifted = false
+	Ok(t, b.SetDriftStatus(status))
+	statuses, err = b.ListDriftStatuses()
+	Ok(t, err)
+	Equals(t, []models.DriftStatus{status}, statuses)
+}
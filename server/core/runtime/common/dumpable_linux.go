@@ -0,0 +1,19 @@
+package common
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// SetNotDumpable stops other processes of the same user, ex. the commands of
+// run steps, from reading this process's memory and environment through
+// /proc/<pid>, ex. /proc/<pid>/environ which holds Atlantis' secrets. It
+// doesn't affect the processes this one starts. Processes running as root
+// can still read them.
+func SetNotDumpable() error {
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, syscall.PR_SET_DUMPABLE, 0, 0); errno != 0 {
+		return errors.Wrap(errno, "setting the process as not dumpable")
+	}
+	return nil
+}
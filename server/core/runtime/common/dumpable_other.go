@@ -0,0 +1,9 @@
+//go:build !linux
+// +build !linux
+
+package common
+
+// SetNotDumpable is a no-op on systems without /proc/<pid>/environ.
+func SetNotDumpable() error {
+	return nil
+}
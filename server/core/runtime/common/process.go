@@ -0,0 +1,62 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"time"
+)
+
+// ProcessKillGracePeriod is how long a command has to exit after it's
+// terminated because its context is done. Terraform uses this time to stop
+// gracefully and release the state lock. After it, the command is killed.
+var ProcessKillGracePeriod = 30 * time.Second
+
+// StopOnDone terminates the process group of cmd when ctx is done and kills it
+// if it hasn't exited after ProcessKillGracePeriod. Signalling the group
+// stops the processes cmd started too, ex. Terraform's providers. cmd must
+// have been started after calling SetProcessGroup. The returned function must
+// be called once cmd has exited and returns once cmd won't be signalled again.
+func StopOnDone(ctx context.Context, cmd *exec.Cmd) func() {
+	gracePeriod := ProcessKillGracePeriod
+	exited := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-exited:
+			return
+		case <-ctx.Done():
+		}
+		terminateProcessGroup(cmd)
+		select {
+		case <-exited:
+		case <-time.After(gracePeriod):
+			killProcessGroup(cmd)
+		}
+	}()
+	return func() {
+		close(exited)
+		<-stopped
+	}
+}
+
+// CombinedOutput runs cmd and returns its combined stdout and stderr like
+// cmd.CombinedOutput. If ctx is done before cmd exits, cmd is stopped with
+// StopOnDone and the error is ctx's error.
+func CombinedOutput(ctx context.Context, cmd *exec.Cmd) ([]byte, error) {
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	SetProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	stop := StopOnDone(ctx, cmd)
+	err := cmd.Wait()
+	stop()
+	if err != nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return out.Bytes(), err
+}
@@ -0,0 +1,41 @@
+package common
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestCombinedOutput(t *testing.T) {
+	out, err := CombinedOutput(context.Background(), exec.Command("sh", "-c", "echo out; echo err >&2"))
+	Ok(t, err)
+	Equals(t, "out\nerr\n", string(out))
+}
+
+func TestCombinedOutput_StopsProcessGroup(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// The background sleep keeps the output open so this only returns
+	// quickly if every process in the group is stopped.
+	start := time.Now()
+	out, err := CombinedOutput(ctx, exec.Command("sh", "-c", "echo started; sleep 30 & sleep 30"))
+	Equals(t, context.DeadlineExceeded, err)
+	Equals(t, "started\n", string(out))
+	Assert(t, time.Since(start) < 10*time.Second, "expected the command to be stopped, took %s", time.Since(start))
+}
+
+func TestCombinedOutput_KillsAfterGracePeriod(t *testing.T) {
+	defer func(period time.Duration) { ProcessKillGracePeriod = period }(ProcessKillGracePeriod)
+	ProcessKillGracePeriod = 100 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	_, err := CombinedOutput(ctx, exec.Command("sh", "-c", "trap '' TERM; sleep 30"))
+	Equals(t, context.Canceled, err)
+	Assert(t, time.Since(start) < 10*time.Second, "expected the command to be killed, took %s", time.Since(start))
+}
@@ -0,0 +1,35 @@
+//go:build !windows
+// +build !windows
+
+package common
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// SetProcessGroup makes cmd start in its own process group so it and the
+// processes it starts can be stopped together. Other attributes already set
+// on cmd, ex. by a sandbox, are kept.
+func SetProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+func terminateProcessGroup(cmd *exec.Cmd) {
+	signalProcessGroup(cmd, syscall.SIGTERM)
+}
+
+func killProcessGroup(cmd *exec.Cmd) {
+	signalProcessGroup(cmd, syscall.SIGKILL)
+}
+
+func signalProcessGroup(cmd *exec.Cmd, sig syscall.Signal) {
+	if cmd.Process == nil {
+		return
+	}
+	// A negative pid signals every process in the group.
+	_ = syscall.Kill(-cmd.Process.Pid, sig)
+}
@@ -0,0 +1,19 @@
+package common
+
+import "os/exec"
+
+// SetProcessGroup is a no-op on Windows which doesn't have process groups.
+// Only cmd itself is stopped when its context is done.
+func SetProcessGroup(cmd *exec.Cmd) {}
+
+// terminateProcessGroup kills cmd since Windows doesn't have signals.
+func terminateProcessGroup(cmd *exec.Cmd) {
+	killProcessGroup(cmd)
+}
+
+func killProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	_ = cmd.Process.Kill()
+}
@@ -0,0 +1,27 @@
+package runtime
+
+import (
+	"path/filepath"
+
+	"github.com/hashicorp/go-version"
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+// ImportStepRunner runs terraform import with the address and ID from the
+// atlantis import comment.
+type ImportStepRunner struct {
+	TerraformExecutor TerraformExec
+	DefaultTFVersion  *version.Version
+}
+
+// Run runs terraform import in path. The comment args end with the address and
+// ID to import, which must come after any other flags.
+func (p *ImportStepRunner) Run(ctx command.ProjectContext, extraArgs []string, path string, envs map[string]string) (string, error) {
+	tfVersion := p.DefaultTFVersion
+	if ctx.TerraformVersion != nil {
+		tfVersion = ctx.TerraformVersion
+	}
+
+	importCmd := append(append([]string{"import", "-input=false"}, extraArgs...), ctx.EscapedCommentArgs...)
+	return p.TerraformExecutor.RunCommandWithVersion(ctx, filepath.Clean(path), importCmd, envs, tfVersion, ctx.Workspace)
+}
@@ -0,0 +1,49 @@
+package runtime_test
+
+import (
+	"testing"
+
+	version "github.com/hashicorp/go-version"
+	. "github.com/petergtz/pegomock"
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	"github.com/runatlantis/atlantis/server/core/terraform/mocks"
+	matchers2 "github.com/runatlantis/atlantis/server/core/terraform/mocks/matchers"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/mocks/matchers"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestImportStepRunner_Run(t *testing.T) {
+	RegisterMockTestingT(t)
+	ctx := command.ProjectContext{
+		Log:                logging.NewNoopLogger(t),
+		EscapedCommentArgs: []string{`\-\l\o\c\k\=\f\a\l\s\e`, `\a\w\s\_\i\n\s\t\a\n\c\e\.\w\e\b`, `\i\-\1\2\3`},
+		Workspace:          "default",
+		RepoRelDir:         ".",
+	}
+
+	terraform := mocks.NewMockClient()
+	When(terraform.RunCommandWithVersion(matchers.AnyModelsProjectCommandContext(), AnyString(), AnyStringSlice(), matchers2.AnyMapOfStringToString(), matchers2.AnyPtrToGoVersionVersion(), AnyString())).
+		ThenReturn("Import successful!", nil)
+	tfVersion, _ := version.NewVersion("1.3.0")
+	tmpDir, cleanup := TempDir(t)
+	defer cleanup()
+
+	s := &runtime.ImportStepRunner{
+		TerraformExecutor: terraform,
+		DefaultTFVersion:  tfVersion,
+	}
+
+	out, err := s.Run(ctx, []string{"-var-file=prod.tfvars"}, tmpDir, map[string]string(nil))
+	Ok(t, err)
+	Equals(t, "Import successful!", out)
+	terraform.VerifyWasCalledOnce().RunCommandWithVersion(
+		ctx,
+		tmpDir,
+		[]string{"import", "-input=false", "-var-file=prod.tfvars", `\-\l\o\c\k\=\f\a\l\s\e`, `\a\w\s\_\i\n\s\t\a\n\c\e\.\w\e\b`, `\i\-\1\2\3`},
+		map[string]string(nil),
+		tfVersion,
+		"default",
+	)
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PolicySetsFailedError is returned when a plan fails the checks of one or
+// more policy sets.
+type PolicySetsFailedError struct {
+	// PolicySets are the names of the policy sets that failed.
+	PolicySets []string
+}
+
+// Error implements the error interface.
+func (e PolicySetsFailedError) Error() string {
+	return fmt.Sprintf("policy set(s) failed: %s", strings.Join(e.PolicySets, ", "))
+}
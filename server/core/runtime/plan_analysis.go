@@ -0,0 +1,88 @@
+package runtime
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/events/models"
+)
+
+// showJSON is the subset of the output of terraform show -json that's needed
+// to analyze a plan.
+type showJSON struct {
+	ResourceChanges []struct {
+		Address string `json:"address"`
+		Mode    string `json:"mode"`
+		Type    string `json:"type"`
+		Change  struct {
+			Actions []string `json:"actions"`
+			// Importing is set if the resource will be imported.
+			Importing *struct{} `json:"importing"`
+		} `json:"change"`
+	} `json:"resource_changes"`
+}
+
+// AnalyzePlan counts the creates, updates, deletes, replaces and imports for
+// each resource type in showOutput, the output of terraform show -json for a
+// plan. Data sources and resources without changes aren't counted.
+func AnalyzePlan(showOutput []byte) (*models.PlanAnalysis, error) {
+	var show showJSON
+	if err := json.Unmarshal(showOutput, &show); err != nil {
+		return nil, errors.Wrap(err, "parsing terraform show output")
+	}
+
+	changesByType := make(map[string]*models.ResourceTypeChanges)
+	for _, rc := range show.ResourceChanges {
+		if rc.Mode == "data" {
+			continue
+		}
+		changes, ok := changesByType[rc.Type]
+		if !ok {
+			changes = &models.ResourceTypeChanges{Type: rc.Type}
+		}
+		importing := rc.Change.Importing != nil
+		if importing {
+			changes.Imports++
+		}
+		switch actions := rc.Change.Actions; {
+		// Replaces are either ["delete", "create"] or ["create", "delete"]
+		// if create_before_destroy is set.
+		case len(actions) == 2:
+			changes.Replaces++
+			changes.ReplacedAddresses = append(changes.ReplacedAddresses, rc.Address)
+		case len(actions) == 1 && actions[0] == "create":
+			changes.Creates++
+		case len(actions) == 1 && actions[0] == "update":
+			changes.Updates++
+		case len(actions) == 1 && actions[0] == "delete":
+			changes.Deletes++
+		default:
+			// no-op and read aren't changes, unless the resource is imported.
+			if !importing {
+				continue
+			}
+		}
+		changesByType[rc.Type] = changes
+	}
+
+	analysis := &models.PlanAnalysis{}
+	for _, changes := range changesByType {
+		analysis.Changes = append(analysis.Changes, *changes)
+	}
+	sort.Slice(analysis.Changes, func(i, j int) bool {
+		return analysis.Changes[i].Type < analysis.Changes[j].Type
+	})
+	return analysis, nil
+}
+
+// AnalyzePlanFile analyzes the terraform show -json output written to
+// showResultFile by the show step.
+func AnalyzePlanFile(showResultFile string) (*models.PlanAnalysis, error) {
+	showOutput, err := os.ReadFile(showResultFile)
+	if err != nil {
+		return nil, err
+	}
+	return AnalyzePlan(showOutput)
+}
@@ -0,0 +1,82 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events/models"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestAnalyzePlan(t *testing.T) {
+	showOutput := `{
+  "format_version": "1.0",
+  "resource_changes": [
+    {"address": "aws_instance.new[0]", "mode": "managed", "type": "aws_instance", "change": {"actions": ["create"]}},
+    {"address": "aws_instance.new[1]", "mode": "managed", "type": "aws_instance", "change": {"actions": ["create"]}},
+    {"address": "aws_instance.changed", "mode": "managed", "type": "aws_instance", "change": {"actions": ["update"]}},
+    {"address": "aws_instance.removed", "mode": "managed", "type": "aws_instance", "change": {"actions": ["delete"]}},
+    {"address": "aws_instance.same", "mode": "managed", "type": "aws_instance", "change": {"actions": ["no-op"]}},
+    {"address": "aws_db_instance.main", "mode": "managed", "type": "aws_db_instance", "change": {"actions": ["delete", "create"]}},
+    {"address": "aws_db_instance.replica", "mode": "managed", "type": "aws_db_instance", "change": {"actions": ["create", "delete"]}},
+    {"address": "data.aws_ami.ubuntu", "mode": "data", "type": "aws_ami", "change": {"actions": ["read"]}},
+    {"address": "null_resource.same", "mode": "managed", "type": "null_resource", "change": {"actions": ["no-op"]}}
+  ]
+}`
+	analysis, err := AnalyzePlan([]byte(showOutput))
+	Ok(t, err)
+	Equals(t, &models.PlanAnalysis{
+		Changes: []models.ResourceTypeChanges{
+			{
+				Type:              "aws_db_instance",
+				Replaces:          2,
+				ReplacedAddresses: []string{"aws_db_instance.main", "aws_db_instance.replica"},
+			},
+			{
+				Type:    "aws_instance",
+				Creates: 2,
+				Updates: 1,
+				Deletes: 1,
+			},
+		},
+	}, analysis)
+	Equals(t, 2, analysis.NumCreates())
+	Equals(t, 1, analysis.NumUpdates())
+	Equals(t, 1, analysis.NumDeletes())
+	Equals(t, 2, analysis.NumReplaces())
+	Equals(t, 3, analysis.NumDestroys())
+	Equals(t, models.PlanChangeCounts{Add: 4, Change: 1, Destroy: 3}, analysis.Counts())
+}
+
+func TestAnalyzePlan_Imports(t *testing.T) {
+	showOutput := `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {"address": "aws_s3_bucket.logs", "mode": "managed", "type": "aws_s3_bucket", "change": {"actions": ["no-op"], "importing": {"id": "logs"}}},
+    {"address": "aws_s3_bucket.assets", "mode": "managed", "type": "aws_s3_bucket", "change": {"actions": ["update"], "importing": {"id": "assets"}}}
+  ]
+}`
+	analysis, err := AnalyzePlan([]byte(showOutput))
+	Ok(t, err)
+	Equals(t, &models.PlanAnalysis{
+		Changes: []models.ResourceTypeChanges{
+			{
+				Type:    "aws_s3_bucket",
+				Updates: 1,
+				Imports: 2,
+			},
+		},
+	}, analysis)
+	Equals(t, models.PlanChangeCounts{Change: 1, Import: 2}, analysis.Counts())
+	Equals(t, "1 to change, 2 to import", analysis.Counts().String())
+}
+
+func TestAnalyzePlan_NoChanges(t *testing.T) {
+	analysis, err := AnalyzePlan([]byte(`{"format_version": "1.0"}`))
+	Ok(t, err)
+	Equals(t, false, analysis.HasChanges())
+}
+
+func TestAnalyzePlan_InvalidJSON(t *testing.T) {
+	_, err := AnalyzePlan([]byte("Error: Failed to read plan"))
+	ErrContains(t, "parsing terraform show output", err)
+}
@@ -40,7 +40,7 @@ func (p *PlanStepRunner) Run(ctx command.ProjectContext, extraArgs []string, pat
 
 	// We only need to switch workspaces in version 0.9.*. In older versions,
 	// there is no such thing as a workspace so we don't need to do anything.
-	if err := p.switchWorkspace(ctx, path, tfVersion, envs); err != nil {
+	if err := SwitchWorkspace(p.TerraformExecutor, ctx, path, tfVersion, envs); err != nil {
 		return "", err
 	}
 
@@ -100,9 +100,10 @@ func (p *PlanStepRunner) remotePlan(ctx command.ProjectContext, extraArgs []stri
 	return p.fmtPlanOutput(output, tfVersion), nil
 }
 
-// switchWorkspace changes the terraform workspace if necessary and will create
-// it if it doesn't exist. It handles differences between versions.
-func (p *PlanStepRunner) switchWorkspace(ctx command.ProjectContext, path string, tfVersion *version.Version, envs map[string]string) error {
+// SwitchWorkspace changes the terraform workspace in path to ctx.Workspace if
+// necessary and will create it if it doesn't exist. It handles differences
+// between versions.
+func SwitchWorkspace(tf TerraformExec, ctx command.ProjectContext, path string, tfVersion *version.Version, envs map[string]string) error {
 	// In versions less than 0.9 there is no support for workspaces.
 	noWorkspaceSupport := MustConstraint("<0.9").Check(tfVersion)
 	// If the user tried to set a specific workspace in the comment but their
@@ -125,7 +126,7 @@ func (p *PlanStepRunner) switchWorkspace(ctx command.ProjectContext, path string
 	// already in the right workspace then no need to switch. This will save us
 	// about ten seconds. This command is only available in > 0.10.
 	if !runningZeroPointNine {
-		workspaceShowOutput, err := p.TerraformExecutor.RunCommandWithVersion(ctx, path, []string{workspaceCmd, "show"}, envs, tfVersion, ctx.Workspace)
+		workspaceShowOutput, err := tf.RunCommandWithVersion(ctx, path, []string{workspaceCmd, "show"}, envs, tfVersion, ctx.Workspace)
 		if err != nil {
 			return err
 		}
@@ -140,11 +141,11 @@ func (p *PlanStepRunner) switchWorkspace(ctx command.ProjectContext, path string
 	// To do this we can either select and catch the error or use list and then
 	// look for the workspace. Both commands take the same amount of time so
 	// that's why we're running select here.
-	_, err := p.TerraformExecutor.RunCommandWithVersion(ctx, path, []string{workspaceCmd, "select", ctx.Workspace}, envs, tfVersion, ctx.Workspace)
+	_, err := tf.RunCommandWithVersion(ctx, path, []string{workspaceCmd, "select", ctx.Workspace}, envs, tfVersion, ctx.Workspace)
 	if err != nil {
 		// If terraform workspace select fails we run terraform workspace
 		// new to create a new workspace automatically.
-		out, err := p.TerraformExecutor.RunCommandWithVersion(ctx, path, []string{workspaceCmd, "new", ctx.Workspace}, envs, tfVersion, ctx.Workspace)
+		out, err := tf.RunCommandWithVersion(ctx, path, []string{workspaceCmd, "new", ctx.Workspace}, envs, tfVersion, ctx.Workspace)
 		if err != nil {
 			return fmt.Errorf("%s: %s", err, out)
 		}
@@ -1,11 +1,13 @@
 package policy
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	version "github.com/hashicorp/go-version"
 	"github.com/pkg/errors"
@@ -14,6 +16,7 @@ import (
 	runtime_models "github.com/runatlantis/atlantis/server/core/runtime/models"
 	"github.com/runatlantis/atlantis/server/core/terraform"
 	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/logging"
 )
 
@@ -61,7 +64,7 @@ func (c ConftestTestCommandArgs) build() ([]string, error) {
 	}
 
 	// add hardcoded options
-	commandArgs = append(commandArgs, c.InputFile, "--no-color")
+	commandArgs = append(commandArgs, c.InputFile, "--no-color", "--output", "json")
 
 	// add extra args provided through server config
 	commandArgs = append(commandArgs, c.ExtraArgs...)
@@ -86,13 +89,16 @@ func (p *LocalSourceResolver) Resolve(policySet valid.PolicySet) (string, error)
 
 // SourceResolverProxy proxies to underlying source resolvers dynamically
 type SourceResolverProxy struct {
-	localSourceResolver SourceResolver
+	localSourceResolver  SourceResolver
+	remoteSourceResolver SourceResolver
 }
 
 func (p *SourceResolverProxy) Resolve(policySet valid.PolicySet) (string, error) {
 	switch source := policySet.Source; source {
 	case valid.LocalPolicySet:
 		return p.localSourceResolver.Resolve(policySet)
+	case valid.GitPolicySet, valid.HTTPPolicySet, valid.OCIPolicySet:
+		return p.remoteSourceResolver.Resolve(policySet)
 	default:
 		return "", fmt.Errorf("unable to resolve policy set source %s", source)
 	}
@@ -132,7 +138,10 @@ type ConfTestExecutorWorkflow struct {
 	Exec                   runtime_models.Exec
 }
 
-func NewConfTestExecutorWorkflow(log logging.SimpleLogging, versionRootDir string, conftestDownloder terraform.Downloader) *ConfTestExecutorWorkflow {
+// NewConfTestExecutorWorkflow returns a workflow that downloads conftest
+// versions into versionRootDir and remote policy sets into policyCacheDir,
+// downloading remote policy sets again every policyRefreshInterval.
+func NewConfTestExecutorWorkflow(log logging.SimpleLogging, versionRootDir string, policyCacheDir string, policyRefreshInterval time.Duration, conftestDownloder terraform.Downloader) *ConfTestExecutorWorkflow {
 	downloader := ConfTestVersionDownloader{
 		downloader: conftestDownloder,
 	}
@@ -154,15 +163,26 @@ func NewConfTestExecutorWorkflow(log logging.SimpleLogging, versionRootDir strin
 		DefaultConftestVersion: version,
 		SourceResolver: &SourceResolverProxy{
 			localSourceResolver: &LocalSourceResolver{},
+			remoteSourceResolver: &RemoteSourceResolver{
+				Downloader:      conftestDownloder,
+				Logger:          log,
+				CacheDir:        policyCacheDir,
+				RefreshInterval: policyRefreshInterval,
+			},
 		},
 		Exec: runtime_models.LocalExec{},
 	}
 }
 
+// Run runs conftest against the plan once for each policy set so we know
+// which policy sets failed. If any failed, it returns a
+// models.PolicySetsFailedError with their names. The structured results are
+// written to ctx.GetPolicyCheckResultFileName() in workdir.
 func (c *ConfTestExecutorWorkflow) Run(ctx command.ProjectContext, executablePath string, envs map[string]string, workdir string, extraArgs []string) (string, error) {
-	policyArgs := []Arg{}
-	policySetNames := []string{}
+	var policySetNames []string
+	var policySetArgs [][]string
 	ctx.Log.Debug("policy sets, %s ", ctx.PolicySets)
+	inputFile := filepath.Join(workdir, ctx.GetShowResultFileName())
 	for _, policySet := range ctx.PolicySets.PolicySets {
 		path, err := c.SourceResolver.Resolve(policySet)
 
@@ -172,35 +192,151 @@ func (c *ConfTestExecutorWorkflow) Run(ctx command.ProjectContext, executablePat
 			continue
 		}
 
-		policyArg := NewPolicyArg(path)
-		policyArgs = append(policyArgs, policyArg)
+		args := ConftestTestCommandArgs{
+			PolicyArgs: []Arg{NewPolicyArg(path)},
+			ExtraArgs:  extraArgs,
+			InputFile:  inputFile,
+			Command:    executablePath,
+		}
+		serializedArgs, err := args.build()
+		if err != nil {
+			return "", errors.Wrap(err, "building args")
+		}
 
 		policySetNames = append(policySetNames, policySet.Name)
+		policySetArgs = append(policySetArgs, serializedArgs)
 	}
 
-	inputFile := filepath.Join(workdir, ctx.GetShowResultFileName())
-
-	args := ConftestTestCommandArgs{
-		PolicyArgs: policyArgs,
-		ExtraArgs:  extraArgs,
-		InputFile:  inputFile,
-		Command:    executablePath,
+	if len(policySetNames) == 0 {
+		ctx.Log.Warn("No policies have been configured")
+		return "", nil
+		// TODO: enable when we can pass policies in otherwise e2e tests with policy checks fail
+		// return "", errors.New("no policies specified")
 	}
 
-	serializedArgs, err := args.build()
+	output := fmt.Sprintf("Checking plan against the following policies: \n  %s\n", strings.Join(policySetNames, "\n  "))
+	var failed []string
+	var results []models.PolicySetResult
+	for i, name := range policySetNames {
+		cmdOutput, err := c.Exec.CombinedOutput(policySetArgs[i], envs, workdir)
+		result, text := parseConftestOutput(name, cmdOutput, err)
+		if !result.Passed {
+			ctx.Log.Debug("policy set %s failed: %s", name, err)
+			failed = append(failed, name)
+		}
+		result.Error = c.sanitizeOutput(inputFile, result.Error)
+		results = append(results, result)
+		output += fmt.Sprintf("\n%s:\n%s", name, text)
+	}
 
+	output = c.sanitizeOutput(inputFile, output)
+	resultsJSON, err := json.Marshal(results)
 	if err != nil {
-		ctx.Log.Warn("No policies have been configured")
-		return "", nil
-		// TODO: enable when we can pass policies in otherwise e2e tests with policy checks fail
-		// return "", errors.Wrap(err, "building args")
+		return output, errors.Wrap(err, "serializing policy check results")
+	}
+	if err := os.WriteFile(filepath.Join(workdir, ctx.GetPolicyCheckResultFileName()), resultsJSON, 0600); err != nil {
+		return output, errors.Wrap(err, "writing policy check results")
 	}
+	if len(failed) > 0 {
+		return output, runtime_models.PolicySetsFailedError{PolicySets: failed}
+	}
+	return output, nil
+}
 
-	initialOutput := fmt.Sprintf("Checking plan against the following policies: \n  %s\n", strings.Join(policySetNames, "\n  "))
-	cmdOutput, err := c.Exec.CombinedOutput(serializedArgs, envs, workdir)
+// conftestCheckResult is the result of one namespace in conftest's JSON output.
+type conftestCheckResult struct {
+	Filename   string           `json:"filename"`
+	Namespace  string           `json:"namespace"`
+	Successes  int              `json:"successes"`
+	Warnings   []conftestResult `json:"warnings"`
+	Failures   []conftestResult `json:"failures"`
+	Exceptions []conftestResult `json:"exceptions"`
+}
 
-	return c.sanitizeOutput(inputFile, initialOutput+cmdOutput), err
+type conftestResult struct {
+	Msg      string                 `json:"msg"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
 
+// parseConftestOutput parses the JSON output of conftest for a policy set
+// into a structured result and renders it as text in the format of conftest's
+// default output. A policy set only fails if a rule fails; warnings don't
+// fail it.
+func parseConftestOutput(policySetName string, output string, cmdErr error) (models.PolicySetResult, string) {
+	result := models.PolicySetResult{
+		PolicySetName: policySetName,
+		Passed:        true,
+	}
+	var checkResults []conftestCheckResult
+	if err := json.Unmarshal([]byte(output), &checkResults); err != nil {
+		// The output isn't JSON if conftest couldn't run the policies, ex.
+		// because they don't compile, or if the output format was changed
+		// with extra_args.
+		if cmdErr != nil {
+			result.Passed = false
+			result.Error = output
+		}
+		return result, output
+	}
+
+	var text strings.Builder
+	var numSuccesses, numWarnings, numFailures, numExceptions int
+	for _, cr := range checkResults {
+		nsResult := models.PolicyNamespaceResult{
+			Namespace:  cr.Namespace,
+			Successes:  cr.Successes,
+			Failures:   toPolicyRuleMessages(cr.Namespace, cr.Failures),
+			Warnings:   toPolicyRuleMessages(cr.Namespace, cr.Warnings),
+			Exceptions: toPolicyRuleMessages(cr.Namespace, cr.Exceptions),
+		}
+		if len(nsResult.Failures) > 0 {
+			result.Passed = false
+		}
+		result.Results = append(result.Results, nsResult)
+
+		for _, w := range cr.Warnings {
+			fmt.Fprintf(&text, "WARN - %s - %s - %s\n", cr.Filename, cr.Namespace, w.Msg)
+		}
+		for _, f := range cr.Failures {
+			fmt.Fprintf(&text, "FAIL - %s - %s - %s\n", cr.Filename, cr.Namespace, f.Msg)
+		}
+		for _, e := range cr.Exceptions {
+			fmt.Fprintf(&text, "EXCP - %s - %s - %s\n", cr.Filename, cr.Namespace, e.Msg)
+		}
+		numSuccesses += cr.Successes
+		numWarnings += len(cr.Warnings)
+		numFailures += len(cr.Failures)
+		numExceptions += len(cr.Exceptions)
+	}
+	if text.Len() > 0 {
+		text.WriteString("\n")
+	}
+	numTests := numSuccesses + numWarnings + numFailures + numExceptions
+	fmt.Fprintf(&text, "%s, %d passed, %s, %s, %s\n",
+		plural(numTests, "test"), numSuccesses, plural(numWarnings, "warning"), plural(numFailures, "failure"), plural(numExceptions, "exception"))
+	return result, text.String()
+}
+
+// toPolicyRuleMessages converts conftest results to rule messages. conftest
+// includes the query that produced the result, ex. data.main.deny, in its
+// metadata which we use to get the rule name.
+func toPolicyRuleMessages(namespace string, results []conftestResult) []models.PolicyRuleMessage {
+	var messages []models.PolicyRuleMessage
+	for _, r := range results {
+		query, _ := r.Metadata["query"].(string)
+		messages = append(messages, models.PolicyRuleMessage{
+			Rule:    strings.TrimPrefix(query, fmt.Sprintf("data.%s.", namespace)),
+			Message: r.Msg,
+		})
+	}
+	return messages
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
 }
 
 func (c *ConfTestExecutorWorkflow) sanitizeOutput(inputFile string, output string) string {
@@ -1,8 +1,10 @@
 package policy
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -12,10 +14,12 @@ import (
 	. "github.com/petergtz/pegomock"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/runtime/cache/mocks"
+	"github.com/runatlantis/atlantis/server/core/runtime/models"
 	models_mocks "github.com/runatlantis/atlantis/server/core/runtime/models/mocks"
 	conftest_mocks "github.com/runatlantis/atlantis/server/core/runtime/policy/mocks"
 	terraform_mocks "github.com/runatlantis/atlantis/server/core/terraform/mocks"
 	"github.com/runatlantis/atlantis/server/events/command"
+	events_models "github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/logging"
 	. "github.com/runatlantis/atlantis/testing"
 )
@@ -145,7 +149,8 @@ func TestRun(t *testing.T) {
 	envs := map[string]string{
 		"key": "val",
 	}
-	workdir := "/some_workdir"
+	workdir := t.TempDir()
+	inputFile := filepath.Join(workdir, "testproj-default.json")
 
 	policySet1 := valid.PolicySet{
 		Source: valid.LocalPolicySet,
@@ -171,105 +176,174 @@ func TestRun(t *testing.T) {
 		Log:         log,
 	}
 
+	readResults := func(t *testing.T) []events_models.PolicySetResult {
+		contents, err := os.ReadFile(filepath.Join(workdir, "testproj-default-policyout.json"))
+		Ok(t, err)
+		var results []events_models.PolicySetResult
+		Ok(t, json.Unmarshal(contents, &results))
+		return results
+	}
+	successOutput := fmt.Sprintf(`[{"filename": %q, "namespace": "main", "successes": 2}]`, inputFile)
+	successText := "2 tests, 2 passed, 0 warnings, 0 failures, 0 exceptions\n"
+
 	t.Run("success", func(t *testing.T) {
 		var extraArgs []string
 
-		expectedOutput := "Success"
-		expectedResult := "Checking plan against the following policies: \n  policy1\n  policy2\nSuccess"
-		expectedArgs := []string{executablePath, "test", "-p", localPolicySetPath1, "-p", localPolicySetPath2, "/some_workdir/testproj-default.json", "--no-color"}
+		expectedResult := "Checking plan against the following policies: \n  policy1\n  policy2\n\npolicy1:\n" + successText + "\npolicy2:\n" + successText
+		expectedArgs1 := []string{executablePath, "test", "-p", localPolicySetPath1, inputFile, "--no-color", "--output", "json"}
+		expectedArgs2 := []string{executablePath, "test", "-p", localPolicySetPath2, inputFile, "--no-color", "--output", "json"}
 
 		When(mockResolver.Resolve(policySet1)).ThenReturn(localPolicySetPath1, nil)
 		When(mockResolver.Resolve(policySet2)).ThenReturn(localPolicySetPath2, nil)
 
-		When(mockExec.CombinedOutput(expectedArgs, envs, workdir)).ThenReturn(expectedOutput, nil)
+		When(mockExec.CombinedOutput(expectedArgs1, envs, workdir)).ThenReturn(successOutput, nil)
+		When(mockExec.CombinedOutput(expectedArgs2, envs, workdir)).ThenReturn(successOutput, nil)
 
 		result, err := subject.Run(ctx, executablePath, envs, workdir, extraArgs)
 
-		fmt.Println(result)
-
 		Ok(t, err)
-
-		Assert(t, result == expectedResult, "result is expected")
-
+		Equals(t, expectedResult, result)
+		Equals(t, []events_models.PolicySetResult{
+			{
+				PolicySetName: "policy1",
+				Passed:        true,
+				Results:       []events_models.PolicyNamespaceResult{{Namespace: "main", Successes: 2}},
+			},
+			{
+				PolicySetName: "policy2",
+				Passed:        true,
+				Results:       []events_models.PolicyNamespaceResult{{Namespace: "main", Successes: 2}},
+			},
+		}, readResults(t))
 	})
 
 	t.Run("success extra args", func(t *testing.T) {
 		extraArgs := []string{"--all-namespaces"}
 
-		expectedOutput := "Success"
-		expectedResult := "Checking plan against the following policies: \n  policy1\n  policy2\nSuccess"
-		expectedArgs := []string{executablePath, "test", "-p", localPolicySetPath1, "-p", localPolicySetPath2, "/some_workdir/testproj-default.json", "--no-color", "--all-namespaces"}
+		expectedResult := "Checking plan against the following policies: \n  policy1\n  policy2\n\npolicy1:\n" + successText + "\npolicy2:\n" + successText
+		expectedArgs1 := []string{executablePath, "test", "-p", localPolicySetPath1, inputFile, "--no-color", "--output", "json", "--all-namespaces"}
+		expectedArgs2 := []string{executablePath, "test", "-p", localPolicySetPath2, inputFile, "--no-color", "--output", "json", "--all-namespaces"}
 
 		When(mockResolver.Resolve(policySet1)).ThenReturn(localPolicySetPath1, nil)
 		When(mockResolver.Resolve(policySet2)).ThenReturn(localPolicySetPath2, nil)
 
-		When(mockExec.CombinedOutput(expectedArgs, envs, workdir)).ThenReturn(expectedOutput, nil)
+		When(mockExec.CombinedOutput(expectedArgs1, envs, workdir)).ThenReturn(successOutput, nil)
+		When(mockExec.CombinedOutput(expectedArgs2, envs, workdir)).ThenReturn(successOutput, nil)
 
 		result, err := subject.Run(ctx, executablePath, envs, workdir, extraArgs)
 
-		fmt.Println(result)
-
 		Ok(t, err)
-
-		Assert(t, result == expectedResult, "result is expected")
-
+		Equals(t, expectedResult, result)
 	})
 
 	t.Run("error resolving one policy source", func(t *testing.T) {
 		var extraArgs []string
 
-		expectedOutput := "Success"
-		expectedResult := "Checking plan against the following policies: \n  policy1\nSuccess"
-		expectedArgs := []string{executablePath, "test", "-p", localPolicySetPath1, "/some_workdir/testproj-default.json", "--no-color"}
+		expectedResult := "Checking plan against the following policies: \n  policy1\n\npolicy1:\n" + successText
+		expectedArgs := []string{executablePath, "test", "-p", localPolicySetPath1, inputFile, "--no-color", "--output", "json"}
 
 		When(mockResolver.Resolve(policySet1)).ThenReturn(localPolicySetPath1, nil)
 		When(mockResolver.Resolve(policySet2)).ThenReturn("", errors.New("err"))
 
-		When(mockExec.CombinedOutput(expectedArgs, envs, workdir)).ThenReturn(expectedOutput, nil)
+		When(mockExec.CombinedOutput(expectedArgs, envs, workdir)).ThenReturn(successOutput, nil)
 
 		result, err := subject.Run(ctx, executablePath, envs, workdir, extraArgs)
 
 		Ok(t, err)
-
-		Assert(t, result == expectedResult, "result is expected")
-
+		Equals(t, expectedResult, result)
 	})
 
 	t.Run("error resolving both policy sources", func(t *testing.T) {
 		var extraArgs []string
 
-		expectedResult := "Success"
-		expectedArgs := []string{executablePath, "test", "-p", localPolicySetPath1, "/some_workdir/testproj-default.json", "--no-color"}
-
 		When(mockResolver.Resolve(policySet1)).ThenReturn("", errors.New("err"))
 		When(mockResolver.Resolve(policySet2)).ThenReturn("", errors.New("err"))
 
-		When(mockExec.CombinedOutput(expectedArgs, envs, workdir)).ThenReturn(expectedResult, nil)
-
 		result, err := subject.Run(ctx, executablePath, envs, workdir, extraArgs)
 
 		Ok(t, err)
-
-		Assert(t, result == "", "result is expected")
-
+		Equals(t, "", result)
 	})
 
 	t.Run("error running cmd", func(t *testing.T) {
 		var extraArgs []string
 
-		expectedOutput := "FAIL - /some_workdir/testproj-default.json - failure"
-		expectedResult := "Checking plan against the following policies: \n  policy1\n  policy2\nFAIL - <redacted plan file> - failure"
-		expectedArgs := []string{executablePath, "test", "-p", localPolicySetPath1, "-p", localPolicySetPath2, "/some_workdir/testproj-default.json", "--no-color"}
+		expectedResult := "Checking plan against the following policies: \n  policy1\n  policy2\n\n" +
+			"policy1:\n" +
+			"WARN - <redacted plan file> - main - tags are missing\n" +
+			"FAIL - <redacted plan file> - main - null resources cannot be created\n" +
+			"\n" +
+			"3 tests, 1 passed, 1 warning, 1 failure, 0 exceptions\n" +
+			"\npolicy2:\n" +
+			"WARN - <redacted plan file> - main - tags are missing\n" +
+			"\n" +
+			"1 test, 0 passed, 1 warning, 0 failures, 0 exceptions\n"
+		expectedArgs1 := []string{executablePath, "test", "-p", localPolicySetPath1, inputFile, "--no-color", "--output", "json"}
+		expectedArgs2 := []string{executablePath, "test", "-p", localPolicySetPath2, inputFile, "--no-color", "--output", "json"}
 
 		When(mockResolver.Resolve(policySet1)).ThenReturn(localPolicySetPath1, nil)
 		When(mockResolver.Resolve(policySet2)).ThenReturn(localPolicySetPath2, nil)
 
-		When(mockExec.CombinedOutput(expectedArgs, envs, workdir)).ThenReturn(expectedOutput, errors.New("exit status code 1"))
+		When(mockExec.CombinedOutput(expectedArgs1, envs, workdir)).ThenReturn(fmt.Sprintf(`[{
+  "filename": %q,
+  "namespace": "main",
+  "successes": 1,
+  "warnings": [{"msg": "tags are missing", "metadata": {"query": "data.main.warn"}}],
+  "failures": [{"msg": "null resources cannot be created", "metadata": {"query": "data.main.deny"}}]
+}]`, inputFile), errors.New("exit status code 1"))
+		// Warnings don't fail the policy set even if conftest is run with --fail-on-warn.
+		When(mockExec.CombinedOutput(expectedArgs2, envs, workdir)).ThenReturn(fmt.Sprintf(`[{
+  "filename": %q,
+  "namespace": "main",
+  "warnings": [{"msg": "tags are missing"}]
+}]`, inputFile), errors.New("exit status code 1"))
 
 		result, err := subject.Run(ctx, executablePath, envs, workdir, extraArgs)
 
-		Assert(t, result == expectedResult, "rseult is expected")
-		Assert(t, err != nil, "error is expected")
+		Equals(t, expectedResult, result)
+		Equals(t, models.PolicySetsFailedError{PolicySets: []string{"policy1"}}, err)
+		Equals(t, []events_models.PolicySetResult{
+			{
+				PolicySetName: "policy1",
+				Passed:        false,
+				Results: []events_models.PolicyNamespaceResult{{
+					Namespace: "main",
+					Successes: 1,
+					Failures:  []events_models.PolicyRuleMessage{{Rule: "deny", Message: "null resources cannot be created"}},
+					Warnings:  []events_models.PolicyRuleMessage{{Rule: "warn", Message: "tags are missing"}},
+				}},
+			},
+			{
+				PolicySetName: "policy2",
+				Passed:        true,
+				Results: []events_models.PolicyNamespaceResult{{
+					Namespace: "main",
+					Warnings:  []events_models.PolicyRuleMessage{{Message: "tags are missing"}},
+				}},
+			},
+		}, readResults(t))
+	})
+
+	t.Run("error running policies", func(t *testing.T) {
+		var extraArgs []string
+
+		expectedResult := "Checking plan against the following policies: \n  policy1\n  policy2\n\npolicy1:\nError: running test: load: loading policies: 1 error occurred\npolicy2:\n" + successText
+		expectedArgs1 := []string{executablePath, "test", "-p", localPolicySetPath1, inputFile, "--no-color", "--output", "json"}
+		expectedArgs2 := []string{executablePath, "test", "-p", localPolicySetPath2, inputFile, "--no-color", "--output", "json"}
+
+		When(mockResolver.Resolve(policySet1)).ThenReturn(localPolicySetPath1, nil)
+		When(mockResolver.Resolve(policySet2)).ThenReturn(localPolicySetPath2, nil)
+
+		When(mockExec.CombinedOutput(expectedArgs1, envs, workdir)).ThenReturn("Error: running test: load: loading policies: 1 error occurred", errors.New("exit status code 1"))
+		When(mockExec.CombinedOutput(expectedArgs2, envs, workdir)).ThenReturn(successOutput, nil)
+
+		result, err := subject.Run(ctx, executablePath, envs, workdir, extraArgs)
 
+		Equals(t, expectedResult, result)
+		Equals(t, models.PolicySetsFailedError{PolicySets: []string{"policy1"}}, err)
+		results := readResults(t)
+		Equals(t, "Error: running test: load: loading policies: 1 error occurred", results[0].Error)
+		Equals(t, false, results[0].Passed)
+		Equals(t, true, results[1].Passed)
 	})
 }
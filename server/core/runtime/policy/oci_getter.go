@@ -0,0 +1,351 @@
+package policy
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	getter "github.com/hashicorp/go-getter"
+	"github.com/pkg/errors"
+)
+
+const (
+	ociManifestMediaType    = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+	ociTitleAnnotation      = "org.opencontainers.image.title"
+	ociDefaultTag           = "latest"
+	ociInsecureQueryParam   = "insecure"
+	ociMaxManifestSize      = 4 << 20
+)
+
+// bearerParamRegex matches the key="value" params of a WWW-Authenticate header.
+var bearerParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// withOCIGetter adds a getter for oci:// addresses to the go-getter client,
+// ex. oci://ghcr.io/org/policies:v1.0.0.
+func withOCIGetter() getter.ClientOption {
+	return func(c *getter.Client) error {
+		getters := make(map[string]getter.Getter)
+		for scheme, g := range getter.Getters {
+			getters[scheme] = g
+		}
+		getters["oci"] = &OCIGetter{}
+		c.Getters = getters
+		return nil
+	}
+}
+
+// OCIGetter is a go-getter Getter that downloads policy bundles stored as
+// OCI artifacts, ex. those pushed with `conftest push`. Layers that are
+// tarballs are extracted into the destination directory and other layers
+// are written to the file named by their title annotation.
+//
+// Credentials can be passed as userinfo in the address and plain HTTP
+// registries can be used with the insecure=true query param.
+type OCIGetter struct {
+	client *getter.Client
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+func (g *OCIGetter) ClientMode(_ *url.URL) (getter.ClientMode, error) {
+	return getter.ClientModeDir, nil
+}
+
+func (g *OCIGetter) SetClient(c *getter.Client) {
+	g.client = c
+}
+
+func (g *OCIGetter) GetFile(_ string, u *url.URL) error {
+	return fmt.Errorf("oci artifact %s can only be downloaded into a directory", u.String())
+}
+
+// Get downloads the artifact referenced by u into dst.
+func (g *OCIGetter) Get(dst string, u *url.URL) error {
+	repo, reference, err := parseOCIReference(u.Path)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if g.client != nil && g.client.Ctx != nil {
+		ctx = g.client.Ctx
+	}
+	reg := &ociRegistry{
+		ctx:        ctx,
+		httpClient: http.DefaultClient,
+		baseURL:    fmt.Sprintf("https://%s/v2/%s", u.Host, repo),
+		user:       u.User,
+	}
+	if u.Query().Get(ociInsecureQueryParam) == "true" {
+		reg.baseURL = fmt.Sprintf("http://%s/v2/%s", u.Host, repo)
+	}
+
+	body, err := reg.get("manifests/"+reference, strings.Join([]string{ociManifestMediaType, dockerManifestMediaType}, ", "))
+	if err != nil {
+		return errors.Wrapf(err, "fetching manifest for %s", u.Host+u.Path)
+	}
+	defer body.Close() // nolint: errcheck
+	var manifest ociManifest
+	if err := json.NewDecoder(io.LimitReader(body, ociMaxManifestSize)).Decode(&manifest); err != nil {
+		return errors.Wrap(err, "decoding manifest")
+	}
+	if len(manifest.Layers) == 0 {
+		return fmt.Errorf("manifest for %s has no layers; only image manifests are supported", u.Host+u.Path)
+	}
+
+	if err := os.MkdirAll(dst, 0700); err != nil {
+		return err
+	}
+	for _, layer := range manifest.Layers {
+		if err := g.getLayer(reg, dst, layer); err != nil {
+			return errors.Wrapf(err, "fetching layer %s", layer.Digest)
+		}
+	}
+	return nil
+}
+
+func (g *OCIGetter) getLayer(reg *ociRegistry, dst string, layer ociDescriptor) error {
+	// Download the blob to a temp file first so we can verify its digest
+	// before unpacking it.
+	tmp, err := os.CreateTemp("", "atlantis-oci-layer")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // nolint: errcheck
+	defer tmp.Close()           // nolint: errcheck
+
+	body, err := reg.get("blobs/"+layer.Digest, "")
+	if err != nil {
+		return err
+	}
+	defer body.Close() // nolint: errcheck
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), body); err != nil {
+		return err
+	}
+	if digest := "sha256:" + hex.EncodeToString(hash.Sum(nil)); digest != layer.Digest {
+		return fmt.Errorf("digest mismatch, got %s", digest)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	switch {
+	case strings.HasSuffix(layer.MediaType, "tar+gzip") || strings.HasSuffix(layer.MediaType, "tar.gzip"):
+		gz, err := gzip.NewReader(tmp)
+		if err != nil {
+			return err
+		}
+		defer gz.Close() // nolint: errcheck
+		return untar(gz, dst)
+	case strings.HasSuffix(layer.MediaType, ".tar"):
+		return untar(tmp, dst)
+	case layer.Annotations[ociTitleAnnotation] != "":
+		path, err := safeJoin(dst, layer.Annotations[ociTitleAnnotation])
+		if err != nil {
+			return err
+		}
+		return writeFile(path, tmp, 0600)
+	default:
+		// Layers we don't know how to unpack, ex. an empty config blob, aren't policies.
+		return nil
+	}
+}
+
+// ociRegistry is a minimal client for the OCI distribution API that supports
+// anonymous, basic and bearer token auth.
+type ociRegistry struct {
+	ctx        context.Context
+	httpClient *http.Client
+	baseURL    string
+	user       *url.Userinfo
+	token      string
+}
+
+func (r *ociRegistry) get(path string, accept string) (io.ReadCloser, error) {
+	resp, err := r.do(path, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && r.token == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close() // nolint: errcheck
+		if err := r.authenticate(challenge); err != nil {
+			return nil, errors.Wrap(err, "authenticating")
+		}
+		if resp, err = r.do(path, accept); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close() // nolint: errcheck
+		return nil, fmt.Errorf("GET %s returned %s", r.baseURL+"/"+path, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func (r *ociRegistry) do(path string, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.baseURL+"/"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	} else if r.user != nil {
+		password, _ := r.user.Password()
+		req.SetBasicAuth(r.user.Username(), password)
+	}
+	return r.httpClient.Do(req)
+}
+
+// authenticate gets a bearer token for the challenge returned by the registry.
+func (r *ociRegistry) authenticate(challenge string) error {
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return fmt.Errorf("unsupported auth challenge %q", challenge)
+	}
+	params := make(map[string]string)
+	for _, match := range bearerParamRegex.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	if params["realm"] == "" {
+		return fmt.Errorf("auth challenge %q has no realm", challenge)
+	}
+
+	tokenURL, err := url.Parse(params["realm"])
+	if err != nil {
+		return errors.Wrap(err, "parsing realm")
+	}
+	query := tokenURL.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	tokenURL.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	if r.user != nil {
+		password, _ := r.user.Password()
+		req.SetBasicAuth(r.user.Username(), password)
+	}
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint: errcheck
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token request returned %s", resp.Status)
+	}
+
+	var tokenResp struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return errors.Wrap(err, "decoding token response")
+	}
+	r.token = tokenResp.Token
+	if r.token == "" {
+		r.token = tokenResp.AccessToken
+	}
+	if r.token == "" {
+		return errors.New("token response has no token")
+	}
+	return nil
+}
+
+// parseOCIReference splits the path of an oci:// address, ex.
+// /org/policies:v1.0.0 or /org/policies@sha256:abc, into the repository and
+// the tag or digest.
+func parseOCIReference(path string) (string, string, error) {
+	path = strings.Trim(path, "/")
+	if i := strings.Index(path, "@"); i != -1 {
+		return path[:i], path[i+1:], nil
+	}
+	repo, reference := path, ociDefaultTag
+	if i := strings.LastIndex(path, ":"); i > strings.LastIndex(path, "/") {
+		repo, reference = path[:i], path[i+1:]
+	}
+	if repo == "" {
+		return "", "", fmt.Errorf("invalid oci reference %q: missing repository", path)
+	}
+	return repo, reference, nil
+}
+
+// untar extracts the regular files and directories of the tar stream into dst.
+func untar(r io.Reader, dst string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		path, err := safeJoin(dst, hdr.Name)
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(path, tr, 0600); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// safeJoin joins name to dir, erroring if the result would be outside dir.
+func safeJoin(dir string, name string) (string, error) {
+	path := filepath.Join(dir, name)
+	if path != dir && !strings.HasPrefix(path, filepath.Clean(dir)+string(os.PathSeparator)) {
+		return "", fmt.Errorf("path %q is outside the destination directory", name)
+	}
+	return path, nil
+}
+
+func writeFile(path string, r io.Reader, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close() // nolint: errcheck
+		return err
+	}
+	return f.Close()
+}
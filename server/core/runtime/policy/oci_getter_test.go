@@ -0,0 +1,143 @@
+package policy
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/terraform"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestOCIGetter_Get(t *testing.T) {
+	// A bundle with a tarball layer and a layer for a single file like the
+	// ones pushed by `conftest push`.
+	var tarball bytes.Buffer
+	gz := gzip.NewWriter(&tarball)
+	tw := tar.NewWriter(gz)
+	tarContents := "package main\n"
+	Ok(t, tw.WriteHeader(&tar.Header{Name: "policy/", Typeflag: tar.TypeDir, Mode: 0700}))
+	Ok(t, tw.WriteHeader(&tar.Header{Name: "policy/main.rego", Typeflag: tar.TypeReg, Mode: 0600, Size: int64(len(tarContents))}))
+	_, err := tw.Write([]byte(tarContents))
+	Ok(t, err)
+	Ok(t, tw.Close())
+	Ok(t, gz.Close())
+	fileContents := []byte("package data\n")
+
+	blobs := map[string][]byte{}
+	addBlob := func(b []byte) string {
+		hash := sha256.Sum256(b)
+		digest := "sha256:" + hex.EncodeToString(hash[:])
+		blobs[digest] = b
+		return digest
+	}
+	manifest, err := json.Marshal(ociManifest{
+		MediaType: ociManifestMediaType,
+		Layers: []ociDescriptor{
+			{
+				MediaType: "application/vnd.oci.image.layer.v1.tar+gzip",
+				Digest:    addBlob(tarball.Bytes()),
+			},
+			{
+				MediaType:   "application/vnd.cncf.openpolicyagent.policy.layer.v1+rego",
+				Digest:      addBlob(fileContents),
+				Annotations: map[string]string{ociTitleAnnotation: "data/data.rego"},
+			},
+		},
+	})
+	Ok(t, err)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			Equals(t, "repository:org/policies:pull", r.URL.Query().Get("scope"))
+			fmt.Fprint(w, `{"token": "secret"}`) // nolint: errcheck
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:org/policies:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/v2/org/policies/manifests/v1.0.0":
+			w.Header().Set("Content-Type", ociManifestMediaType)
+			w.Write(manifest) // nolint: errcheck
+		case strings.HasPrefix(r.URL.Path, "/v2/org/policies/blobs/"):
+			blob, ok := blobs[strings.TrimPrefix(r.URL.Path, "/v2/org/policies/blobs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(blob) // nolint: errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	t.Run("downloads bundle", func(t *testing.T) {
+		dst := filepath.Join(t.TempDir(), "policies")
+		err := (&terraform.DefaultDownloader{}).GetAny(dst, fmt.Sprintf("oci://%s/org/policies:v1.0.0?insecure=true", host), withOCIGetter())
+		Ok(t, err)
+
+		contents, err := os.ReadFile(filepath.Join(dst, "policy", "main.rego"))
+		Ok(t, err)
+		Equals(t, tarContents, string(contents))
+		contents, err = os.ReadFile(filepath.Join(dst, "data", "data.rego"))
+		Ok(t, err)
+		Equals(t, string(fileContents), string(contents))
+	})
+
+	t.Run("unknown tag", func(t *testing.T) {
+		dst := filepath.Join(t.TempDir(), "policies")
+		err := (&terraform.DefaultDownloader{}).GetAny(dst, fmt.Sprintf("oci://%s/org/policies:v2.0.0?insecure=true", host), withOCIGetter())
+		ErrContains(t, "404 Not Found", err)
+	})
+}
+
+func TestParseOCIReference(t *testing.T) {
+	cases := []struct {
+		path         string
+		expRepo      string
+		expReference string
+		expErr       string
+	}{
+		{"/org/policies:v1.0.0", "org/policies", "v1.0.0", ""},
+		{"/org/policies", "org/policies", "latest", ""},
+		{"/org/policies@sha256:abc", "org/policies", "sha256:abc", ""},
+		{"/", "", "", `invalid oci reference "": missing repository`},
+	}
+	for _, c := range cases {
+		t.Run(c.path, func(t *testing.T) {
+			repo, reference, err := parseOCIReference(c.path)
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+				return
+			}
+			Ok(t, err)
+			Equals(t, c.expRepo, repo)
+			Equals(t, c.expReference, reference)
+		})
+	}
+}
+
+func TestSafeJoin(t *testing.T) {
+	_, err := safeJoin("/tmp/policies", "../etc/passwd")
+	ErrEquals(t, `path "../etc/passwd" is outside the destination directory`, err)
+
+	path, err := safeJoin("/tmp/policies", "policy/main.rego")
+	Ok(t, err)
+	Equals(t, "/tmp/policies/policy/main.rego", path)
+}
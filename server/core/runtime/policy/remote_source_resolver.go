@@ -0,0 +1,123 @@
+package policy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/terraform"
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+// RemoteSourceResolver resolves policy sets hosted in git repos, HTTP archives
+// and OCI registries by downloading them into a cache dir on disk. Each
+// address, including its ref or tag, gets its own cache dir. Cached policy
+// sets are downloaded again once they're older than RefreshInterval.
+type RemoteSourceResolver struct {
+	Downloader terraform.Downloader
+	Logger     logging.SimpleLogging
+	CacheDir   string
+	// RefreshInterval is how long a downloaded policy set is used before
+	// downloading it again. If 0, policy sets are only downloaded once.
+	RefreshInterval time.Duration
+
+	// now is overridden in tests.
+	now  func() time.Time
+	lock sync.Mutex
+}
+
+func (r *RemoteSourceResolver) Resolve(policySet valid.PolicySet) (string, error) {
+	src, err := remoteSourceAddress(policySet)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256([]byte(src))
+	destDir := filepath.Join(r.CacheDir, policySet.Source, hex.EncodeToString(hash[:])[:16])
+
+	// Policy checks for different projects run in parallel so we serialize
+	// downloads to avoid fetching the same policy set more than once.
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	info, err := os.Stat(destDir)
+	cached := err == nil && info.IsDir()
+	if cached && (r.RefreshInterval <= 0 || r.timeNow().Sub(info.ModTime()) < r.RefreshInterval) {
+		return destDir, nil
+	}
+
+	if err := r.download(src, destDir); err != nil {
+		if cached {
+			r.Logger.Warn("failed to refresh policy set %s, using cached copy: %s", policySet.Name, err)
+			return destDir, nil
+		}
+		return "", errors.Wrapf(err, "downloading policy set %s", policySet.Name)
+	}
+	return destDir, nil
+}
+
+// download downloads src into a temp dir and then replaces destDir with it so
+// a failed download never leaves a partial policy set behind.
+func (r *RemoteSourceResolver) download(src string, destDir string) error {
+	if err := os.MkdirAll(filepath.Dir(destDir), 0700); err != nil {
+		return err
+	}
+	tmpDir, err := os.MkdirTemp(filepath.Dir(destDir), ".download-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir) // nolint: errcheck
+
+	// go-getter expects to create the destination dir itself.
+	downloadDir := filepath.Join(tmpDir, "policies")
+	if err := r.Downloader.GetAny(downloadDir, src, withOCIGetter()); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(destDir); err != nil {
+		return err
+	}
+	if err := os.Rename(downloadDir, destDir); err != nil {
+		return err
+	}
+	// The mod time of the dir records when it was downloaded.
+	now := r.timeNow()
+	return os.Chtimes(destDir, now, now)
+}
+
+func (r *RemoteSourceResolver) timeNow() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// remoteSourceAddress returns the go-getter address for the policy set.
+func remoteSourceAddress(policySet valid.PolicySet) (string, error) {
+	path := policySet.Path
+	switch policySet.Source {
+	case valid.GitPolicySet:
+		if !strings.HasPrefix(path, "git::") {
+			path = "git::" + path
+		}
+		return path, nil
+	case valid.HTTPPolicySet:
+		if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+			return "", fmt.Errorf("http policy set path %q must be an http:// or https:// URL", path)
+		}
+		return path, nil
+	case valid.OCIPolicySet:
+		if !strings.HasPrefix(path, "oci://") {
+			path = "oci://" + path
+		}
+		return path, nil
+	default:
+		return "", fmt.Errorf("policy set source %s is not a remote source", policySet.Source)
+	}
+}
@@ -0,0 +1,165 @@
+package policy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	getter "github.com/hashicorp/go-getter"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+// fakeDownloader writes a policy file containing the download count into dst.
+type fakeDownloader struct {
+	srcs []string
+	err  error
+}
+
+func (d *fakeDownloader) GetFile(dst, src string, opts ...getter.ClientOption) error {
+	return errors.New("not implemented")
+}
+
+func (d *fakeDownloader) GetAny(dst, src string, opts ...getter.ClientOption) error {
+	d.srcs = append(d.srcs, src)
+	if d.err != nil {
+		return d.err
+	}
+	if err := os.MkdirAll(dst, 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dst, "policy.rego"), []byte{byte('0' + len(d.srcs))}, 0600)
+}
+
+func TestRemoteSourceResolver_Resolve(t *testing.T) {
+	cases := []struct {
+		description string
+		policySet   valid.PolicySet
+		expSrc      string
+		expErr      string
+	}{
+		{
+			description: "git",
+			policySet: valid.PolicySet{
+				Name:   "policies",
+				Source: valid.GitPolicySet,
+				Path:   "https://github.com/org/policies.git//rego?ref=v1.0.0",
+			},
+			expSrc: "git::https://github.com/org/policies.git//rego?ref=v1.0.0",
+		},
+		{
+			description: "git with forced getter",
+			policySet: valid.PolicySet{
+				Name:   "policies",
+				Source: valid.GitPolicySet,
+				Path:   "git::ssh://git@github.com/org/policies.git?ref=main",
+			},
+			expSrc: "git::ssh://git@github.com/org/policies.git?ref=main",
+		},
+		{
+			description: "http",
+			policySet: valid.PolicySet{
+				Name:   "policies",
+				Source: valid.HTTPPolicySet,
+				Path:   "https://example.com/policies.tar.gz",
+			},
+			expSrc: "https://example.com/policies.tar.gz",
+		},
+		{
+			description: "http without scheme",
+			policySet: valid.PolicySet{
+				Name:   "policies",
+				Source: valid.HTTPPolicySet,
+				Path:   "example.com/policies.tar.gz",
+			},
+			expErr: `http policy set path "example.com/policies.tar.gz" must be an http:// or https:// URL`,
+		},
+		{
+			description: "oci",
+			policySet: valid.PolicySet{
+				Name:   "policies",
+				Source: valid.OCIPolicySet,
+				Path:   "ghcr.io/org/policies:v1.0.0",
+			},
+			expSrc: "oci://ghcr.io/org/policies:v1.0.0",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			downloader := &fakeDownloader{}
+			resolver := &RemoteSourceResolver{
+				Downloader: downloader,
+				Logger:     logging.NewNoopLogger(t),
+				CacheDir:   t.TempDir(),
+			}
+			path, err := resolver.Resolve(c.policySet)
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+				return
+			}
+			Ok(t, err)
+			Equals(t, []string{c.expSrc}, downloader.srcs)
+			Assert(t, filepath.Dir(filepath.Dir(path)) == resolver.CacheDir, "exp %s to be in the cache dir", path)
+			_, err = os.Stat(filepath.Join(path, "policy.rego"))
+			Ok(t, err)
+		})
+	}
+}
+
+func TestRemoteSourceResolver_Refresh(t *testing.T) {
+	now := time.Now()
+	downloader := &fakeDownloader{}
+	resolver := &RemoteSourceResolver{
+		Downloader:      downloader,
+		Logger:          logging.NewNoopLogger(t),
+		CacheDir:        t.TempDir(),
+		RefreshInterval: time.Hour,
+		now:             func() time.Time { return now },
+	}
+	policySet := valid.PolicySet{
+		Name:   "policies",
+		Source: valid.GitPolicySet,
+		Path:   "https://github.com/org/policies.git?ref=main",
+	}
+	readPolicy := func(path string) string {
+		contents, err := os.ReadFile(filepath.Join(path, "policy.rego"))
+		Ok(t, err)
+		return string(contents)
+	}
+
+	path, err := resolver.Resolve(policySet)
+	Ok(t, err)
+	Equals(t, "1", readPolicy(path))
+
+	t.Log("cached policy set is used within the refresh interval")
+	now = now.Add(30 * time.Minute)
+	cachedPath, err := resolver.Resolve(policySet)
+	Ok(t, err)
+	Equals(t, path, cachedPath)
+	Equals(t, 1, len(downloader.srcs))
+
+	t.Log("policy set is downloaded again after the refresh interval")
+	now = now.Add(time.Hour)
+	refreshedPath, err := resolver.Resolve(policySet)
+	Ok(t, err)
+	Equals(t, path, refreshedPath)
+	Equals(t, 2, len(downloader.srcs))
+	Equals(t, "2", readPolicy(path))
+
+	t.Log("cached policy set is used if the refresh fails")
+	now = now.Add(2 * time.Hour)
+	downloader.err = errors.New("network error")
+	stalePath, err := resolver.Resolve(policySet)
+	Ok(t, err)
+	Equals(t, path, stalePath)
+	Equals(t, 3, len(downloader.srcs))
+	Equals(t, "2", readPolicy(path))
+
+	t.Log("a different ref is downloaded separately")
+	policySet.Path = "https://github.com/org/policies.git?ref=v1.0.0"
+	_, err = resolver.Resolve(policySet)
+	ErrContains(t, "downloading policy set policies: network error", err)
+}
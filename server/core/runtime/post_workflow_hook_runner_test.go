@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	. "github.com/petergtz/pegomock"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/runtime"
 	"github.com/runatlantis/atlantis/server/core/terraform/mocks"
 	matchers2 "github.com/runatlantis/atlantis/server/core/terraform/mocks/matchers"
@@ -109,3 +110,25 @@ func TestPostWorkflowHookRunner_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestPostWorkflowHookRunner_RunEnvPassthrough(t *testing.T) {
+	defer func(secrets []string) { valid.SecretEnvVars = secrets }(valid.SecretEnvVars)
+	valid.SecretEnvVars = []string{"ATLANTIS_GH_TOKEN"}
+	t.Setenv("ATLANTIS_GH_TOKEN", "token")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	r := runtime.DefaultPostWorkflowHookRunner{}
+	script := "echo token=$ATLANTIS_GH_TOKEN region=$AWS_REGION secret=$AWS_SECRET_ACCESS_KEY"
+
+	ctx := models.WorkflowHookCommandContext{
+		Log: logging.NewNoopLogger(t),
+	}
+	out, err := r.Run(ctx, script, t.TempDir())
+	Ok(t, err)
+	Equals(t, "token= region=us-east-1 secret=secret\n", out)
+
+	ctx.EnvPassthrough = &valid.EnvPassthrough{Deny: []string{"AWS_SECRET_*"}}
+	out, err = r.Run(ctx, script, t.TempDir())
+	Ok(t, err)
+	Equals(t, "token= region=us-east-1 secret=\n", out)
+}
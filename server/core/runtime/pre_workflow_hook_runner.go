@@ -3,8 +3,8 @@ package runtime
 import (
 	"fmt"
 	"os"
-	"os/exec"
 
+	"github.com/runatlantis/atlantis/server/core/runtime/sandbox"
 	"github.com/runatlantis/atlantis/server/events/models"
 )
 
@@ -13,13 +13,17 @@ type PreWorkflowHookRunner interface {
 	Run(ctx models.WorkflowHookCommandContext, command string, path string) (string, error)
 }
 
-type DefaultPreWorkflowHookRunner struct{}
+type DefaultPreWorkflowHookRunner struct {
+	// Executor builds the commands. If it's nil, hooks are never run in a
+	// sandbox.
+	Executor sandbox.Executor
+}
 
 func (wh DefaultPreWorkflowHookRunner) Run(ctx models.WorkflowHookCommandContext, command string, path string) (string, error) {
-	cmd := exec.Command("sh", "-c", command) // #nosec
+	cmd := shellCommand(wh.Executor, ctx.Sandbox, path, command)
 	cmd.Dir = path
 
-	baseEnvVars := os.Environ()
+	baseEnvVars := ctx.EnvPassthrough.Filter(os.Environ())
 	customEnvVars := map[string]string{
 		"BASE_BRANCH_NAME": ctx.Pull.BaseBranch,
 		"BASE_REPO_NAME":   ctx.BaseRepo.Name,
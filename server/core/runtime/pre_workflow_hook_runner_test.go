@@ -1,11 +1,15 @@
 package runtime_test
 
 import (
+	"os/exec"
 	"strings"
 	"testing"
 
 	. "github.com/petergtz/pegomock"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/runtime"
+	sandboxmocks "github.com/runatlantis/atlantis/server/core/runtime/sandbox/mocks"
+	sandboxmatchers "github.com/runatlantis/atlantis/server/core/runtime/sandbox/mocks/matchers"
 	"github.com/runatlantis/atlantis/server/core/terraform/mocks"
 	matchers2 "github.com/runatlantis/atlantis/server/core/terraform/mocks/matchers"
 	"github.com/runatlantis/atlantis/server/events/mocks/matchers"
@@ -109,3 +113,44 @@ func TestPreWorkflowHookRunner_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestPreWorkflowHookRunner_RunEnvPassthrough(t *testing.T) {
+	defer func(secrets []string) { valid.SecretEnvVars = secrets }(valid.SecretEnvVars)
+	valid.SecretEnvVars = []string{"ATLANTIS_GH_TOKEN"}
+	t.Setenv("ATLANTIS_GH_TOKEN", "token")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	r := runtime.DefaultPreWorkflowHookRunner{}
+	script := "echo token=$ATLANTIS_GH_TOKEN region=$AWS_REGION secret=$AWS_SECRET_ACCESS_KEY"
+
+	ctx := models.WorkflowHookCommandContext{
+		Log: logging.NewNoopLogger(t),
+	}
+	out, err := r.Run(ctx, script, t.TempDir())
+	Ok(t, err)
+	Equals(t, "token= region=us-east-1 secret=secret\n", out)
+
+	ctx.EnvPassthrough = &valid.EnvPassthrough{Deny: []string{"AWS_SECRET_*"}}
+	out, err = r.Run(ctx, script, t.TempDir())
+	Ok(t, err)
+	Equals(t, "token= region=us-east-1 secret=\n", out)
+}
+
+func TestPreWorkflowHookRunner_RunSandbox(t *testing.T) {
+	RegisterMockTestingT(t)
+	executor := sandboxmocks.NewMockExecutor()
+	When(executor.Command(sandboxmatchers.AnyPtrToValidSandbox(), AnyString(), AnyString())).
+		ThenReturn(exec.Command("sh", "-c", "echo sandboxed")) // #nosec
+	r := runtime.DefaultPreWorkflowHookRunner{Executor: executor}
+
+	tmpDir := t.TempDir()
+	cfg := &valid.Sandbox{MaxMemoryMB: 512}
+	ctx := models.WorkflowHookCommandContext{
+		Log:     logging.NewNoopLogger(t),
+		Sandbox: cfg,
+	}
+	out, err := r.Run(ctx, "echo hi", tmpDir)
+	Ok(t, err)
+	Equals(t, "sandboxed\n", out)
+	executor.VerifyWasCalledOnce().Command(cfg, tmpDir, "echo hi")
+}
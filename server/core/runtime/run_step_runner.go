@@ -8,6 +8,9 @@ import (
 	"strings"
 
 	"github.com/hashicorp/go-version"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/runtime/common"
+	"github.com/runatlantis/atlantis/server/core/runtime/sandbox"
 	"github.com/runatlantis/atlantis/server/events/command"
 )
 
@@ -17,6 +20,9 @@ type RunStepRunner struct {
 	DefaultTFVersion  *version.Version
 	// TerraformBinDir is the directory where Atlantis downloads Terraform binaries.
 	TerraformBinDir string
+	// Executor builds the commands. If it's nil, commands are never run in a
+	// sandbox.
+	Executor sandbox.Executor
 }
 
 func (r *RunStepRunner) Run(ctx command.ProjectContext, command string, path string, envs map[string]string) (string, error) {
@@ -32,10 +38,10 @@ func (r *RunStepRunner) Run(ctx command.ProjectContext, command string, path str
 		return "", err
 	}
 
-	cmd := exec.Command("sh", "-c", command) // #nosec
+	cmd := shellCommand(r.Executor, ctx.Sandbox, cloneDir(path, ctx.RepoRelDir), command)
 	cmd.Dir = path
 
-	baseEnvVars := os.Environ()
+	baseEnvVars := ctx.EnvPassthrough.Filter(os.Environ())
 	customEnvVars := map[string]string{
 		"ATLANTIS_TERRAFORM_VERSION": tfVersion.String(),
 		"BASE_BRANCH_NAME":           ctx.Pull.BaseBranch,
@@ -66,7 +72,7 @@ func (r *RunStepRunner) Run(ctx command.ProjectContext, command string, path str
 		finalEnvVars = append(finalEnvVars, fmt.Sprintf("%s=%s", key, val))
 	}
 	cmd.Env = finalEnvVars
-	out, err := cmd.CombinedOutput()
+	out, err := common.CombinedOutput(ctx.Context(), cmd)
 
 	if err != nil {
 		err = fmt.Errorf("%s: running %q in %q: \n%s", err, command, path, out)
@@ -76,3 +82,23 @@ func (r *RunStepRunner) Run(ctx command.ProjectContext, command string, path str
 	ctx.Log.Info("successfully ran %q in %q", command, path)
 	return string(out), nil
 }
+
+// shellCommand returns the command that runs command with sh, in a sandbox
+// where repoDir is writable if executor and cfg aren't nil.
+func shellCommand(executor sandbox.Executor, cfg *valid.Sandbox, repoDir string, command string) *exec.Cmd {
+	if executor == nil {
+		return exec.Command("sh", "-c", command) // #nosec
+	}
+	return executor.Command(cfg, repoDir, command)
+}
+
+// cloneDir returns the root of the clone that contains path, the dir of the
+// project at repoRelDir, so run steps can use the rest of the repo, ex. shared
+// modules.
+func cloneDir(path string, repoRelDir string) string {
+	dir := filepath.Clean(path)
+	for rel := filepath.Clean(repoRelDir); rel != "." && rel != string(filepath.Separator); rel = filepath.Dir(rel) {
+		dir = filepath.Dir(dir)
+	}
+	return dir
+}
@@ -1,14 +1,21 @@
 package runtime_test
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	version "github.com/hashicorp/go-version"
 	. "github.com/petergtz/pegomock"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/runtime"
+	sandboxmocks "github.com/runatlantis/atlantis/server/core/runtime/sandbox/mocks"
+	sandboxmatchers "github.com/runatlantis/atlantis/server/core/runtime/sandbox/mocks/matchers"
 	"github.com/runatlantis/atlantis/server/core/terraform/mocks"
 	matchers2 "github.com/runatlantis/atlantis/server/core/terraform/mocks/matchers"
 	"github.com/runatlantis/atlantis/server/events/command"
@@ -157,3 +164,84 @@ func TestRunStepRunner_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestRunStepRunner_RunCancelled(t *testing.T) {
+	RegisterMockTestingT(t)
+	terraform := mocks.NewMockClient()
+	r := runtime.RunStepRunner{
+		TerraformExecutor: terraform,
+		DefaultTFVersion:  version.Must(version.NewVersion("1.0.0")),
+	}
+	cmdCtx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	ctx := command.ProjectContext{
+		Log: logging.NewNoopLogger(t),
+		Ctx: cmdCtx,
+	}
+
+	start := time.Now()
+	_, err := r.Run(ctx, "echo started; sleep 30", t.TempDir(), nil)
+	ErrContains(t, "context deadline exceeded", err)
+	ErrContains(t, "started", err)
+	Assert(t, time.Since(start) < 10*time.Second, "expected the step to be stopped, took %s", time.Since(start))
+}
+
+func TestRunStepRunner_RunEnvPassthrough(t *testing.T) {
+	defer func(secrets []string) { valid.SecretEnvVars = secrets }(valid.SecretEnvVars)
+	valid.SecretEnvVars = []string{"ATLANTIS_GH_TOKEN"}
+	RegisterMockTestingT(t)
+	t.Setenv("ATLANTIS_GH_TOKEN", "token")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	terraform := mocks.NewMockClient()
+	r := runtime.RunStepRunner{
+		TerraformExecutor: terraform,
+		DefaultTFVersion:  version.Must(version.NewVersion("1.0.0")),
+	}
+	script := "echo token=$ATLANTIS_GH_TOKEN region=$AWS_REGION secret=$AWS_SECRET_ACCESS_KEY"
+
+	ctx := command.ProjectContext{
+		Log: logging.NewNoopLogger(t),
+	}
+	out, err := r.Run(ctx, script, t.TempDir(), nil)
+	Ok(t, err)
+	Equals(t, "token= region=us-east-1 secret=secret\n", out)
+
+	ctx.EnvPassthrough = &valid.EnvPassthrough{Deny: []string{"AWS_SECRET_*"}}
+	out, err = r.Run(ctx, script, t.TempDir(), nil)
+	Ok(t, err)
+	Equals(t, "token= region=us-east-1 secret=\n", out)
+}
+
+func TestRunStepRunner_RunSandbox(t *testing.T) {
+	RegisterMockTestingT(t)
+	terraform := mocks.NewMockClient()
+	executor := sandboxmocks.NewMockExecutor()
+	When(executor.Command(sandboxmatchers.AnyPtrToValidSandbox(), AnyString(), AnyString())).Then(func(params []Param) ReturnValues {
+		return ReturnValues{exec.Command("sh", "-c", "echo sandboxed")} // #nosec
+	})
+	r := runtime.RunStepRunner{
+		TerraformExecutor: terraform,
+		DefaultTFVersion:  version.Must(version.NewVersion("1.0.0")),
+		Executor:          executor,
+	}
+
+	// The whole clone is writable, not just the project's dir.
+	for _, repoRelDir := range []string{".", "modules/vpc", "./modules/vpc/"} {
+		t.Run(repoRelDir, func(t *testing.T) {
+			repoDir := t.TempDir()
+			path := filepath.Join(repoDir, repoRelDir)
+			Ok(t, os.MkdirAll(path, 0700))
+			cfg := &valid.Sandbox{MaxOpenFiles: 64}
+			ctx := command.ProjectContext{
+				Log:        logging.NewNoopLogger(t),
+				RepoRelDir: repoRelDir,
+				Sandbox:    cfg,
+			}
+			out, err := r.Run(ctx, "echo hi", path, nil)
+			Ok(t, err)
+			Equals(t, "sandboxed\n", out)
+			executor.VerifyWasCalledOnce().Command(cfg, repoDir, "echo hi")
+		})
+	}
+}
@@ -0,0 +1,34 @@
+// Code generated by pegomock. DO NOT EDIT.
+package matchers
+
+import (
+	"reflect"
+
+	"github.com/petergtz/pegomock"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+)
+
+func AnyPtrToValidSandbox() *valid.Sandbox {
+	pegomock.RegisterMatcher(pegomock.NewAnyMatcher(reflect.TypeOf((*(*valid.Sandbox))(nil)).Elem()))
+	var nullValue *valid.Sandbox
+	return nullValue
+}
+
+func EqPtrToValidSandbox(value *valid.Sandbox) *valid.Sandbox {
+	pegomock.RegisterMatcher(&pegomock.EqMatcher{Value: value})
+	var nullValue *valid.Sandbox
+	return nullValue
+}
+
+func NotEqPtrToValidSandbox(value *valid.Sandbox) *valid.Sandbox {
+	pegomock.RegisterMatcher(&pegomock.NotEqMatcher{Value: value})
+	var nullValue *valid.Sandbox
+	return nullValue
+}
+
+func PtrToValidSandboxThat(matcher pegomock.ArgumentMatcher) *valid.Sandbox {
+	pegomock.RegisterMatcher(matcher)
+	var nullValue *valid.Sandbox
+	return nullValue
+}
@@ -0,0 +1,115 @@
+// Code generated by pegomock. DO NOT EDIT.
+// Source: github.com/runatlantis/atlantis/server/core/runtime/sandbox (interfaces: Executor)
+
+package mocks
+
+import (
+	exec "os/exec"
+	"reflect"
+	"time"
+
+	pegomock "github.com/petergtz/pegomock"
+	valid "github.com/runatlantis/atlantis/server/core/config/valid"
+)
+
+type MockExecutor struct {
+	fail func(message string, callerSkip ...int)
+}
+
+func NewMockExecutor(options ...pegomock.Option) *MockExecutor {
+	mock := &MockExecutor{}
+	for _, option := range options {
+		option.Apply(mock)
+	}
+	return mock
+}
+
+func (mock *MockExecutor) SetFailHandler(fh pegomock.FailHandler) { mock.fail = fh }
+func (mock *MockExecutor) FailHandler() pegomock.FailHandler      { return mock.fail }
+
+func (mock *MockExecutor) Command(cfg *valid.Sandbox, repoDir string, command string) *exec.Cmd {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockExecutor().")
+	}
+	params := []pegomock.Param{cfg, repoDir, command}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("Command", params, []reflect.Type{reflect.TypeOf((**exec.Cmd)(nil)).Elem()})
+	var ret0 *exec.Cmd
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(*exec.Cmd)
+		}
+	}
+	return ret0
+}
+
+func (mock *MockExecutor) VerifyWasCalledOnce() *VerifierMockExecutor {
+	return &VerifierMockExecutor{
+		mock:                   mock,
+		invocationCountMatcher: pegomock.Times(1),
+	}
+}
+
+func (mock *MockExecutor) VerifyWasCalled(invocationCountMatcher pegomock.InvocationCountMatcher) *VerifierMockExecutor {
+	return &VerifierMockExecutor{
+		mock:                   mock,
+		invocationCountMatcher: invocationCountMatcher,
+	}
+}
+
+func (mock *MockExecutor) VerifyWasCalledInOrder(invocationCountMatcher pegomock.InvocationCountMatcher, inOrderContext *pegomock.InOrderContext) *VerifierMockExecutor {
+	return &VerifierMockExecutor{
+		mock:                   mock,
+		invocationCountMatcher: invocationCountMatcher,
+		inOrderContext:         inOrderContext,
+	}
+}
+
+func (mock *MockExecutor) VerifyWasCalledEventually(invocationCountMatcher pegomock.InvocationCountMatcher, timeout time.Duration) *VerifierMockExecutor {
+	return &VerifierMockExecutor{
+		mock:                   mock,
+		invocationCountMatcher: invocationCountMatcher,
+		timeout:                timeout,
+	}
+}
+
+type VerifierMockExecutor struct {
+	mock                   *MockExecutor
+	invocationCountMatcher pegomock.InvocationCountMatcher
+	inOrderContext         *pegomock.InOrderContext
+	timeout                time.Duration
+}
+
+func (verifier *VerifierMockExecutor) Command(cfg *valid.Sandbox, repoDir string, command string) *MockExecutor_Command_OngoingVerification {
+	params := []pegomock.Param{cfg, repoDir, command}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "Command", params, verifier.timeout)
+	return &MockExecutor_Command_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockExecutor_Command_OngoingVerification struct {
+	mock              *MockExecutor
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockExecutor_Command_OngoingVerification) GetCapturedArguments() (*valid.Sandbox, string, string) {
+	cfg, repoDir, command := c.GetAllCapturedArguments()
+	return cfg[len(cfg)-1], repoDir[len(repoDir)-1], command[len(command)-1]
+}
+
+func (c *MockExecutor_Command_OngoingVerification) GetAllCapturedArguments() (_param0 []*valid.Sandbox, _param1 []string, _param2 []string) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]*valid.Sandbox, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(*valid.Sandbox)
+		}
+		_param1 = make([]string, len(c.methodInvocations))
+		for u, param := range params[1] {
+			_param1[u] = param.(string)
+		}
+		_param2 = make([]string, len(c.methodInvocations))
+		for u, param := range params[2] {
+			_param2[u] = param.(string)
+		}
+	}
+	return
+}
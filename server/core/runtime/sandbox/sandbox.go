@@ -0,0 +1,199 @@
+// Package sandbox runs the commands of run steps and workflow hooks in
+// isolated processes so repos that aren't fully trusted can't read the
+// Atlantis data dir, ex. atlantis.db and the clones of other repos, or the
+// Atlantis user's home dir and the files given to server flags.
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/spf13/pflag"
+)
+
+// ExecCommandName is the name of the hidden atlantis command that sets up the
+// sandbox and runs a command in it. Main implements it.
+const ExecCommandName = "sandbox-exec"
+
+const (
+	hideFlag          = "hide"
+	bindFlag          = "bind"
+	readOnlyBindFlag  = "ro-bind"
+	privateFlag       = "private"
+	maxMemoryMBFlag   = "max-memory-mb"
+	maxCPUTimeFlag    = "max-cpu-time"
+	maxFileSizeMBFlag = "max-file-size-mb"
+	maxOpenFilesFlag  = "max-open-files"
+	stageFlag         = "stage"
+
+	// initStage sets up the sandbox's mounts and runs the exec stage.
+	initStage = "init"
+	// execStage sets the sandbox's limits and replaces itself with the
+	// command.
+	execStage = "exec"
+)
+
+//go:generate pegomock generate -m --use-experimental-model-gen --package mocks -o mocks/mock_executor.go Executor
+
+// Executor builds the commands that run steps and workflow hooks run.
+type Executor interface {
+	// Command returns a command that runs command with sh. If cfg isn't nil,
+	// the command runs in a sandbox where repoDir is the only writable
+	// directory of the repo's data.
+	Command(cfg *valid.Sandbox, repoDir string, command string) *exec.Cmd
+}
+
+// NamespaceExecutor runs sandboxed commands in their own Linux user, mount and
+// PID namespaces, as a non-root user without capabilities. The Atlantis data
+// dir and HiddenPaths are hidden by an empty tmpfs, or /dev/null for files, and
+// only the repo's clone and the shared dirs are mounted back into them. Other
+// directories, ex. /usr, are shared with the Atlantis process.
+type NamespaceExecutor struct {
+	// AtlantisPath is the path of the atlantis binary that sets up the
+	// sandbox.
+	AtlantisPath string
+	// DataDir is the Atlantis data dir.
+	DataDir string
+	// HiddenPaths are the other files and dirs sandboxed commands can't
+	// read, ex. the home dir and the files given to server flags.
+	HiddenPaths []string
+	// PrivateDirs are replaced by an empty dir that only one sandboxed
+	// command can see, ex. the Terraform plugin cache so that a command
+	// can't change the providers the commands of other repos run.
+	PrivateDirs []string
+	// ReadOnlyDirs are shared with every sandboxed command but can't be
+	// written to, ex. the dir of the Terraform binaries.
+	ReadOnlyDirs []string
+}
+
+// NewNamespaceExecutor returns a NamespaceExecutor that sets up sandboxes with
+// the running atlantis binary.
+func NewNamespaceExecutor(dataDir string, hiddenPaths []string, privateDirs []string, readOnlyDirs []string) (*NamespaceExecutor, error) {
+	atlantisPath, err := os.Executable()
+	if err != nil {
+		return nil, errors.Wrap(err, "finding the atlantis binary")
+	}
+	return &NamespaceExecutor{
+		AtlantisPath: atlantisPath,
+		DataDir:      dataDir,
+		HiddenPaths:  hiddenPaths,
+		PrivateDirs:  privateDirs,
+		ReadOnlyDirs: readOnlyDirs,
+	}, nil
+}
+
+func (e *NamespaceExecutor) Command(cfg *valid.Sandbox, repoDir string, command string) *exec.Cmd {
+	if cfg == nil {
+		return exec.Command("sh", "-c", command) // #nosec
+	}
+	opts := Options{
+		HiddenPaths:  append([]string{e.DataDir}, e.HiddenPaths...),
+		WritableDirs: []string{repoDir},
+		ReadOnlyDirs: e.ReadOnlyDirs,
+		PrivateDirs:  e.PrivateDirs,
+		Limits:       *cfg,
+	}
+	args := append([]string{ExecCommandName}, opts.args()...)
+	args = append(args, "--", "sh", "-c", command)
+	cmd := exec.Command(e.AtlantisPath, args...) // #nosec
+	setNamespaces(cmd)
+	return cmd
+}
+
+// Options configure a sandbox.
+type Options struct {
+	// HiddenPaths are replaced by empty directories, or by /dev/null if
+	// they're files. Paths that don't exist are ignored.
+	HiddenPaths []string
+	// WritableDirs are mounted back into the hidden dirs.
+	WritableDirs []string
+	// ReadOnlyDirs are mounted back into the hidden dirs read-only.
+	ReadOnlyDirs []string
+	// PrivateDirs are replaced by empty directories that are discarded when
+	// the sandbox exits.
+	PrivateDirs []string
+	// Limits are the resource limits of each process in the sandbox.
+	Limits valid.Sandbox
+}
+
+// args returns opts as the flags of ExecCommandName.
+func (o Options) args() []string {
+	var args []string
+	for _, path := range o.HiddenPaths {
+		args = append(args, "--"+hideFlag, path)
+	}
+	for _, dir := range o.WritableDirs {
+		args = append(args, "--"+bindFlag, dir)
+	}
+	for _, dir := range o.ReadOnlyDirs {
+		args = append(args, "--"+readOnlyBindFlag, dir)
+	}
+	for _, dir := range o.PrivateDirs {
+		args = append(args, "--"+privateFlag, dir)
+	}
+	if o.Limits.MaxMemoryMB > 0 {
+		args = append(args, "--"+maxMemoryMBFlag, strconv.Itoa(o.Limits.MaxMemoryMB))
+	}
+	if o.Limits.MaxCPUTime > 0 {
+		args = append(args, "--"+maxCPUTimeFlag, o.Limits.MaxCPUTime.String())
+	}
+	if o.Limits.MaxFileSizeMB > 0 {
+		args = append(args, "--"+maxFileSizeMBFlag, strconv.Itoa(o.Limits.MaxFileSizeMB))
+	}
+	if o.Limits.MaxOpenFiles > 0 {
+		args = append(args, "--"+maxOpenFilesFlag, strconv.Itoa(o.Limits.MaxOpenFiles))
+	}
+	return args
+}
+
+// Main runs ExecCommandName with args, the arguments after the command name,
+// and returns its exit code. It's run in the namespaces created by
+// NamespaceExecutor. It first sets up the sandbox's mounts, then runs itself
+// again to set the limits and run the command so the limits don't apply to
+// the process waiting for the command.
+func Main(args []string) int {
+	var opts Options
+	var stage string
+	flags := pflag.NewFlagSet(ExecCommandName, pflag.ContinueOnError)
+	flags.StringArrayVar(&opts.HiddenPaths, hideFlag, nil, "Directory or file to replace by an empty directory or /dev/null.")
+	flags.StringArrayVar(&opts.WritableDirs, bindFlag, nil, "Directory to mount back into the hidden directories.")
+	flags.StringArrayVar(&opts.ReadOnlyDirs, readOnlyBindFlag, nil, "Directory to mount back into the hidden directories read-only.")
+	flags.StringArrayVar(&opts.PrivateDirs, privateFlag, nil, "Directory to replace by an empty directory that's discarded on exit.")
+	flags.IntVar(&opts.Limits.MaxMemoryMB, maxMemoryMBFlag, 0, "Most virtual memory each process can use.")
+	flags.DurationVar(&opts.Limits.MaxCPUTime, maxCPUTimeFlag, 0, "Most CPU time each process can use.")
+	flags.IntVar(&opts.Limits.MaxFileSizeMB, maxFileSizeMBFlag, 0, "Size of the largest file a process can write.")
+	flags.IntVar(&opts.Limits.MaxOpenFiles, maxOpenFilesFlag, 0, "Most files each process can have open.")
+	flags.StringVar(&stage, stageFlag, initStage, "Internal, the stage of setting up the sandbox.")
+	if err := flags.Parse(args); err != nil {
+		return fail(err)
+	}
+	command := flags.Args()
+	if len(command) == 0 {
+		return fail(errors.New("no command to run"))
+	}
+
+	if stage == execStage {
+		// execWithLimits only returns if the command couldn't be run.
+		return fail(execWithLimits(opts.Limits, command))
+	}
+	code, err := runInit(opts, command)
+	if err != nil {
+		return fail(err)
+	}
+	return code
+}
+
+func fail(err error) int {
+	fmt.Fprintf(os.Stderr, "atlantis sandbox: %s\n", err)
+	return 1
+}
+
+// cpuSeconds returns d in seconds, rounded up, for RLIMIT_CPU.
+func cpuSeconds(d time.Duration) uint64 {
+	return uint64((d + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,286 @@
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+)
+
+const (
+	// stRelatime is ST_RELATIME, the statfs flag for MS_RELATIME.
+	stRelatime = 0x1000
+
+	capSetpcap              = 8
+	capSysAdmin             = 21
+	prSetNoNewPrivs         = 38
+	prCapAmbient            = 47
+	prCapAmbientClearAll    = 4
+	linuxCapabilityVersion3 = 0x20080522
+)
+
+// sandboxID is the uid and gid of the Atlantis user in the sandbox's user
+// namespace. It isn't root so processes in the sandbox don't get capabilities
+// when they run a binary.
+const sandboxID = 1000
+
+// setNamespaces makes cmd start in new user, mount and PID namespaces, where
+// the Atlantis user is sandboxID whether or not Atlantis runs as root. The
+// sandbox's init needs CAP_SYS_ADMIN in the namespace to set up its mounts,
+// and CAP_SETPCAP to drop its bounding capabilities, so they're given as
+// ambient capabilities, which init drops before it runs the command.
+func setNamespaces(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags:  syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID,
+		UidMappings: []syscall.SysProcIDMap{{ContainerID: sandboxID, HostID: os.Getuid(), Size: 1}},
+		GidMappings: []syscall.SysProcIDMap{{ContainerID: sandboxID, HostID: os.Getgid(), Size: 1}},
+		AmbientCaps: []uintptr{capSetpcap, capSysAdmin},
+	}
+}
+
+// bindMount is a directory that's mounted back into a hidden directory.
+type bindMount struct {
+	dir      string
+	readOnly bool
+	// f is the directory opened before it was hidden.
+	f *os.File
+}
+
+// runInit sets up the sandbox's mounts and runs the exec stage in it. It's the
+// first process in the sandbox's PID namespace so the sandbox is torn down
+// once it exits.
+func runInit(opts Options, command []string) (int, error) {
+	// Keep our mounts out of the Atlantis process's mount namespace.
+	if err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, ""); err != nil {
+		return 0, errors.Wrap(err, "making mounts private")
+	}
+	wd, err := syscall.Getwd()
+	if err != nil {
+		return 0, errors.Wrap(err, "getting working directory")
+	}
+
+	// The dirs are opened before they're hidden so they can be mounted from
+	// their file descriptors afterwards.
+	var binds []bindMount
+	for _, dir := range opts.WritableDirs {
+		binds = append(binds, bindMount{dir: dir})
+	}
+	for _, dir := range opts.ReadOnlyDirs {
+		binds = append(binds, bindMount{dir: dir, readOnly: true})
+	}
+	for i := range binds {
+		f, err := os.Open(binds[i].dir)
+		if err != nil {
+			return 0, errors.Wrapf(err, "opening %s", binds[i].dir)
+		}
+		defer f.Close() // nolint: errcheck
+		binds[i].f = f
+	}
+
+	var hiddenDirs, hiddenFiles []string
+	for _, path := range opts.HiddenPaths {
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return 0, errors.Wrapf(err, "reading %s", path)
+		}
+		if info.IsDir() {
+			hiddenDirs = append(hiddenDirs, path)
+		} else {
+			hiddenFiles = append(hiddenFiles, path)
+		}
+	}
+
+	for _, dir := range hiddenDirs {
+		// The dir is already hidden if it's inside another hidden dir.
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+		if err := mountTmpfs(dir); err != nil {
+			return 0, errors.Wrapf(err, "hiding %s", dir)
+		}
+	}
+	for _, b := range binds {
+		if err := mountBind(b); err != nil {
+			return 0, err
+		}
+	}
+	for _, dir := range opts.PrivateDirs {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return 0, errors.Wrapf(err, "creating %s", dir)
+		}
+		if err := mountTmpfs(dir); err != nil {
+			return 0, errors.Wrapf(err, "mounting %s", dir)
+		}
+	}
+	// Files are hidden last so that they're also hidden if they're inside a
+	// dir that was mounted back.
+	for _, file := range hiddenFiles {
+		if _, err := os.Stat(file); os.IsNotExist(err) {
+			continue
+		}
+		if err := syscall.Mount("/dev/null", file, "", syscall.MS_BIND, ""); err != nil {
+			return 0, errors.Wrapf(err, "hiding %s", file)
+		}
+	}
+	// A new /proc only shows the processes in the sandbox so the Atlantis
+	// process's files can't be reached through /proc/<pid>/root.
+	if err := syscall.Mount("proc", "/proc", "proc", syscall.MS_NOSUID|syscall.MS_NODEV|syscall.MS_NOEXEC, ""); err != nil {
+		return 0, errors.Wrap(err, "mounting /proc")
+	}
+	// The working directory may have been hidden so change to the mounted
+	// directory at the same path.
+	if err := os.Chdir(wd); err != nil {
+		return 0, errors.Wrapf(err, "changing to %s", wd)
+	}
+
+	args := append([]string{ExecCommandName}, opts.args()...)
+	args = append(args, "--"+stageFlag, execStage, "--")
+	args = append(args, command...)
+	cmd := exec.Command("/proc/self/exe", args...) // #nosec
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	// Without capabilities the command can't undo the mounts, ex. unmount
+	// the tmpfs hiding the data dir. They belong to threads so they're
+	// dropped from the thread that starts the command, which stays locked to
+	// this goroutine.
+	runtime.LockOSThread()
+	if err := dropPrivileges(); err != nil {
+		return 0, err
+	}
+
+	// As the first process in the PID namespace, we only get the signals we
+	// handle, ex. when the command is cancelled, so they're forwarded.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP)
+	defer signal.Stop(signals)
+	if err := cmd.Start(); err != nil {
+		return 0, errors.Wrap(err, "starting command")
+	}
+	go func() {
+		for sig := range signals {
+			_ = cmd.Process.Signal(sig)
+		}
+	}()
+
+	err = cmd.Wait()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return 128 + int(status.Signal()), nil
+		}
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "running command")
+	}
+	return 0, nil
+}
+
+// capHeader and capData are the arguments of capset.
+type capHeader struct {
+	version uint32
+	pid     int32
+}
+
+type capData struct {
+	effective   uint32
+	permitted   uint32
+	inheritable uint32
+}
+
+// dropPrivileges drops the calling thread's bounding, effective, permitted,
+// inheritable and ambient capabilities and sets no_new_privs so it and the
+// processes it starts can't gain any, ex. by running a setuid binary.
+func dropPrivileges() error {
+	for c := uintptr(0); ; c++ {
+		_, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, syscall.PR_CAPBSET_DROP, c, 0)
+		// EINVAL means c is past the last capability.
+		if errno == syscall.EINVAL {
+			break
+		}
+		if errno != 0 {
+			return errors.Wrap(errno, "dropping bounding capabilities")
+		}
+	}
+	if _, _, errno := syscall.RawSyscall6(syscall.SYS_PRCTL, prCapAmbient, prCapAmbientClearAll, 0, 0, 0, 0); errno != 0 {
+		return errors.Wrap(errno, "dropping ambient capabilities")
+	}
+	header := capHeader{version: linuxCapabilityVersion3}
+	var data [2]capData
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_CAPSET, uintptr(unsafe.Pointer(&header)), uintptr(unsafe.Pointer(&data[0])), 0); errno != 0 { // #nosec
+		return errors.Wrap(errno, "dropping capabilities")
+	}
+	if _, _, errno := syscall.RawSyscall6(syscall.SYS_PRCTL, prSetNoNewPrivs, 1, 0, 0, 0, 0); errno != 0 {
+		return errors.Wrap(errno, "setting no_new_privs")
+	}
+	return nil
+}
+
+func mountTmpfs(dir string) error {
+	return syscall.Mount("tmpfs", dir, "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV, "mode=0755")
+}
+
+func mountBind(b bindMount) error {
+	if err := os.MkdirAll(b.dir, 0700); err != nil {
+		return errors.Wrapf(err, "creating %s", b.dir)
+	}
+	source := fmt.Sprintf("/proc/self/fd/%d", b.f.Fd())
+	if err := syscall.Mount(source, b.dir, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+		return errors.Wrapf(err, "mounting %s", b.dir)
+	}
+	if !b.readOnly {
+		return nil
+	}
+	// A bind mount can only be made read-only by remounting it. In a user
+	// namespace, the remount must keep the flags of the original mount.
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(b.dir, &stat); err != nil {
+		return errors.Wrapf(err, "reading mount flags of %s", b.dir)
+	}
+	flags := uintptr(syscall.MS_BIND | syscall.MS_REMOUNT | syscall.MS_RDONLY)
+	flags |= uintptr(stat.Flags) & (syscall.MS_NOSUID | syscall.MS_NODEV | syscall.MS_NOEXEC | syscall.MS_NOATIME | syscall.MS_NODIRATIME)
+	if stat.Flags&stRelatime != 0 {
+		flags |= syscall.MS_RELATIME
+	}
+	if err := syscall.Mount("", b.dir, "", flags, ""); err != nil {
+		return errors.Wrapf(err, "making %s read-only", b.dir)
+	}
+	return nil
+}
+
+// execWithLimits sets limits and replaces the process with command. It only
+// returns if command couldn't be run.
+func execWithLimits(limits valid.Sandbox, command []string) error {
+	path, err := exec.LookPath(command[0])
+	if err != nil {
+		return err
+	}
+	rlimits := map[int]uint64{}
+	if limits.MaxMemoryMB > 0 {
+		rlimits[syscall.RLIMIT_AS] = uint64(limits.MaxMemoryMB) * 1024 * 1024
+	}
+	if limits.MaxCPUTime > 0 {
+		rlimits[syscall.RLIMIT_CPU] = cpuSeconds(limits.MaxCPUTime)
+	}
+	if limits.MaxFileSizeMB > 0 {
+		rlimits[syscall.RLIMIT_FSIZE] = uint64(limits.MaxFileSizeMB) * 1024 * 1024
+	}
+	if limits.MaxOpenFiles > 0 {
+		rlimits[syscall.RLIMIT_NOFILE] = uint64(limits.MaxOpenFiles)
+	}
+	for resource, limit := range rlimits {
+		if err := syscall.Setrlimit(resource, &syscall.Rlimit{Cur: limit, Max: limit}); err != nil {
+			return errors.Wrap(err, "setting resource limits")
+		}
+	}
+	return syscall.Exec(path, command, os.Environ()) // #nosec
+}
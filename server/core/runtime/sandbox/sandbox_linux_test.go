@@ -0,0 +1,167 @@
+package sandbox_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/runtime/sandbox"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+// TestMain lets the test binary set up sandboxes like the atlantis binary.
+func TestMain(m *testing.M) {
+	if len(os.Args) > 1 && os.Args[1] == sandbox.ExecCommandName {
+		os.Exit(sandbox.Main(os.Args[2:]))
+	}
+	os.Exit(m.Run())
+}
+
+func newExecutor(t *testing.T) (*sandbox.NamespaceExecutor, string) {
+	dataDir := t.TempDir()
+	repoDir := filepath.Join(dataDir, "repos", "owner", "repo", "1", "default")
+	for _, dir := range []string{repoDir, filepath.Join(dataDir, "repos", "owner", "other"), filepath.Join(dataDir, "plugin-cache"), filepath.Join(dataDir, "bin")} {
+		Ok(t, os.MkdirAll(dir, 0700))
+	}
+	Ok(t, os.WriteFile(filepath.Join(dataDir, "atlantis.db"), []byte("db"), 0600))
+	Ok(t, os.WriteFile(filepath.Join(dataDir, "plugin-cache", "provider"), []byte("provider"), 0600))
+	homeDir := t.TempDir()
+	Ok(t, os.WriteFile(filepath.Join(homeDir, ".git-credentials"), []byte("creds"), 0600))
+	keyFile := filepath.Join(t.TempDir(), "key.pem")
+	Ok(t, os.WriteFile(keyFile, []byte("key"), 0600))
+
+	e, err := sandbox.NewNamespaceExecutor(dataDir, []string{homeDir, keyFile, filepath.Join(t.TempDir(), "missing")}, []string{filepath.Join(dataDir, "plugin-cache")}, []string{filepath.Join(dataDir, "bin")})
+	Ok(t, err)
+	// Check that we can create namespaces here, ex. containers may not allow
+	// it.
+	cmd := e.Command(&valid.Sandbox{}, repoDir, "true")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("sandboxes aren't supported here: %s: %s", err, out)
+	}
+	return e, repoDir
+}
+
+func TestNamespaceExecutor_Command(t *testing.T) {
+	e, repoDir := newExecutor(t)
+	dataDir, homeDir, keyFile := e.DataDir, e.HiddenPaths[0], e.HiddenPaths[1]
+
+	script := strings.Join([]string{
+		"test -e $DATA/atlantis.db && echo db is visible",
+		"test -e $DATA/repos/owner/other && echo other repo is visible",
+		"test -e $HOME_DIR/.git-credentials && echo home is visible",
+		"test -s $KEY_FILE && echo key file is visible",
+		"test -e $DATA/plugin-cache/provider && echo plugin cache is shared",
+		"echo written > $DATA/repos/owner/repo/1/default/out",
+		"echo cached > $DATA/plugin-cache/out",
+		"(echo bin > $DATA/bin/out) 2>/dev/null || echo bin is read-only",
+		"pwd",
+		// Only the processes in the sandbox are visible: its init and sh.
+		"set -- /proc/[0-9]*; echo $#",
+	}, "; ")
+	cmd := e.Command(&valid.Sandbox{}, repoDir, script)
+	cmd.Dir = repoDir
+	cmd.Env = append(os.Environ(), "DATA="+dataDir, "HOME_DIR="+homeDir, "KEY_FILE="+keyFile)
+	out, err := cmd.CombinedOutput()
+	Ok(t, err)
+	Equals(t, []string{"bin is read-only", repoDir, "2"}, strings.Split(strings.TrimSpace(string(out)), "\n"))
+
+	written, err := os.ReadFile(filepath.Join(repoDir, "out"))
+	Ok(t, err)
+	Equals(t, "written\n", string(written))
+	// The sandbox's plugin cache is discarded.
+	_, err = os.Stat(filepath.Join(dataDir, "plugin-cache", "out"))
+	Assert(t, os.IsNotExist(err), "exp plugin cache not to be written to, got %v", err)
+	_, err = os.Stat(filepath.Join(dataDir, "atlantis.db"))
+	Ok(t, err)
+	key, err := os.ReadFile(keyFile)
+	Ok(t, err)
+	Equals(t, "key", string(key))
+}
+
+func TestNamespaceExecutor_CommandCantUnhide(t *testing.T) {
+	e, repoDir := newExecutor(t)
+
+	script := strings.Join([]string{
+		"umount -l $DATA 2>/dev/null || echo unmount failed",
+		"test -e $DATA/atlantis.db && echo db is visible",
+		"id -u",
+		"grep -E '^(CapPrm|CapEff|CapBnd|CapAmb|NoNewPrivs):' /proc/self/status",
+	}, "; ")
+	cmd := e.Command(&valid.Sandbox{}, repoDir, script)
+	cmd.Dir = repoDir
+	cmd.Env = append(os.Environ(), "DATA="+e.DataDir)
+	out, err := cmd.CombinedOutput()
+	Ok(t, err)
+	Equals(t, []string{
+		"unmount failed",
+		"1000",
+		"CapPrm:\t0000000000000000",
+		"CapEff:\t0000000000000000",
+		"CapBnd:\t0000000000000000",
+		"CapAmb:\t0000000000000000",
+		"NoNewPrivs:\t1",
+	}, strings.Split(strings.TrimSpace(string(out)), "\n"))
+}
+
+// TestNamespaceExecutor_CommandAsNonRoot runs the other tests again as a
+// non-root user since they run as the test's user.
+func TestNamespaceExecutor_CommandAsNonRoot(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("the tests already run as a non-root user")
+	}
+	// The dir and the copy of the test binary must be usable by the user.
+	dir, err := os.MkdirTemp("", "sandbox")
+	Ok(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) }) // nolint: errcheck
+	Ok(t, os.Chmod(dir, 0777))              // nolint: gosec
+	testBin, err := os.ReadFile(os.Args[0])
+	Ok(t, err)
+	binPath := filepath.Join(dir, "sandbox.test")
+	Ok(t, os.WriteFile(binPath, testBin, 0755)) // nolint: gosec
+
+	cmd := exec.Command(binPath, "-test.run", "^TestNamespaceExecutor_", "-test.v") // #nosec
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "TMPDIR="+dir)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Credential: &syscall.Credential{Uid: 65534, Gid: 65534}}
+	out, err := cmd.CombinedOutput()
+	Assert(t, err == nil, "exp tests to pass as a non-root user, got %s: %s", err, out)
+	if strings.Contains(string(out), "--- SKIP: TestNamespaceExecutor_Command (") {
+		t.Skipf("sandboxes aren't supported for non-root users here: %s", out)
+	}
+	for _, test := range []string{"TestNamespaceExecutor_Command", "TestNamespaceExecutor_CommandCantUnhide"} {
+		Assert(t, strings.Contains(string(out), "--- PASS: "+test+" ("), "exp %s to pass, got %s", test, out)
+	}
+}
+
+func TestNamespaceExecutor_CommandLimits(t *testing.T) {
+	e, repoDir := newExecutor(t)
+
+	cmd := e.Command(&valid.Sandbox{MaxFileSizeMB: 1, MaxOpenFiles: 64}, repoDir, "ulimit -n; head -c 2000000 /dev/zero > big")
+	cmd.Dir = repoDir
+	out, err := cmd.CombinedOutput()
+	Assert(t, err != nil, "exp writing a file over the limit to fail")
+	Equals(t, "64", strings.Split(string(out), "\n")[0])
+	info, err := os.Stat(filepath.Join(repoDir, "big"))
+	Ok(t, err)
+	Equals(t, int64(1024*1024), info.Size())
+}
+
+func TestNamespaceExecutor_CommandExitCode(t *testing.T) {
+	e, repoDir := newExecutor(t)
+
+	cmd := e.Command(&valid.Sandbox{}, repoDir, "exit 3")
+	err := cmd.Run()
+	exitErr, ok := err.(*exec.ExitError)
+	Assert(t, ok, "exp exit error, got %v", err)
+	Equals(t, 3, exitErr.ExitCode())
+}
+
+func TestNamespaceExecutor_CommandWithoutSandbox(t *testing.T) {
+	e := &sandbox.NamespaceExecutor{}
+	cmd := e.Command(nil, "", "echo hi")
+	Equals(t, []string{"sh", "-c", "echo hi"}, cmd.Args)
+}
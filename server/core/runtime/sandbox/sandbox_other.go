@@ -0,0 +1,25 @@
+//go:build !linux
+// +build !linux
+
+package sandbox
+
+import (
+	"os/exec"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+)
+
+var errUnsupported = errors.New("sandboxes are only supported on Linux")
+
+// setNamespaces is a no-op since namespaces are only supported on Linux. The
+// command fails when it runs instead.
+func setNamespaces(cmd *exec.Cmd) {}
+
+func runInit(opts Options, command []string) (int, error) {
+	return 0, errUnsupported
+}
+
+func execWithLimits(limits valid.Sandbox, command []string) error {
+	return errUnsupported
+}
@@ -0,0 +1,29 @@
+package runtime
+
+import (
+	"path/filepath"
+
+	"github.com/hashicorp/go-version"
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+// StateStepRunner runs a terraform state subcommand, ex. rm or mv, with the
+// addresses from the atlantis state comment.
+type StateStepRunner struct {
+	TerraformExecutor TerraformExec
+	DefaultTFVersion  *version.Version
+	// Subcommand is the terraform state subcommand to run, ex. rm.
+	Subcommand string
+}
+
+// Run runs terraform state in path. The comment args end with the addresses
+// to remove or move, which must come after any other flags.
+func (p *StateStepRunner) Run(ctx command.ProjectContext, extraArgs []string, path string, envs map[string]string) (string, error) {
+	tfVersion := p.DefaultTFVersion
+	if ctx.TerraformVersion != nil {
+		tfVersion = ctx.TerraformVersion
+	}
+
+	stateCmd := append(append([]string{"state", p.Subcommand}, extraArgs...), ctx.EscapedCommentArgs...)
+	return p.TerraformExecutor.RunCommandWithVersion(ctx, filepath.Clean(path), stateCmd, envs, tfVersion, ctx.Workspace)
+}
@@ -0,0 +1,61 @@
+package runtime_test
+
+import (
+	"testing"
+
+	version "github.com/hashicorp/go-version"
+	. "github.com/petergtz/pegomock"
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	"github.com/runatlantis/atlantis/server/core/terraform/mocks"
+	matchers2 "github.com/runatlantis/atlantis/server/core/terraform/mocks/matchers"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/mocks/matchers"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestStateStepRunner_Run(t *testing.T) {
+	cases := map[string]struct {
+		subcommand  string
+		commentArgs []string
+		expCmd      []string
+	}{
+		"rm": {
+			subcommand:  "rm",
+			commentArgs: []string{`\a\.\b`, `\c\.\d`},
+			expCmd:      []string{"state", "rm", `\a\.\b`, `\c\.\d`},
+		},
+		"mv": {
+			subcommand:  "mv",
+			commentArgs: []string{`\-\d\r\y\-\r\u\n`, `\a\.\b`, `\a\.\c`},
+			expCmd:      []string{"state", "mv", `\-\d\r\y\-\r\u\n`, `\a\.\b`, `\a\.\c`},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			ctx := command.ProjectContext{
+				Log:                logging.NewNoopLogger(t),
+				EscapedCommentArgs: c.commentArgs,
+				Workspace:          "staging",
+				RepoRelDir:         ".",
+			}
+			terraform := mocks.NewMockClient()
+			When(terraform.RunCommandWithVersion(matchers.AnyModelsProjectCommandContext(), AnyString(), AnyStringSlice(), matchers2.AnyMapOfStringToString(), matchers2.AnyPtrToGoVersionVersion(), AnyString())).
+				ThenReturn("Successfully done!", nil)
+			tfVersion, _ := version.NewVersion("1.3.0")
+			tmpDir, cleanup := TempDir(t)
+			defer cleanup()
+
+			s := &runtime.StateStepRunner{
+				TerraformExecutor: terraform,
+				DefaultTFVersion:  tfVersion,
+				Subcommand:        c.subcommand,
+			}
+			out, err := s.Run(ctx, nil, tmpDir, map[string]string(nil))
+			Ok(t, err)
+			Equals(t, "Successfully done!", out)
+			terraform.VerifyWasCalledOnce().RunCommandWithVersion(ctx, tmpDir, c.expCmd, map[string]string(nil), tfVersion, "staging")
+		})
+	}
+}
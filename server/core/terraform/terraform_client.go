@@ -31,6 +31,8 @@ import (
 	"github.com/mitchellh/go-homedir"
 	"github.com/pkg/errors"
 
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/runtime/common"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/terraform/ansi"
 	"github.com/runatlantis/atlantis/server/jobs"
@@ -297,7 +299,7 @@ func (c *DefaultClient) RunCommandWithVersion(ctx command.ProjectContext, path s
 		output = ansi.Strip(output)
 		return fmt.Sprintf("%s\n", output), err
 	}
-	tfCmd, cmd, err := c.prepCmd(ctx.Log, v, workspace, path, args)
+	tfCmd, cmd, err := c.prepCmd(ctx.Log, v, workspace, path, args, ctx.EnvPassthrough)
 	if err != nil {
 		return "", err
 	}
@@ -306,7 +308,7 @@ func (c *DefaultClient) RunCommandWithVersion(ctx command.ProjectContext, path s
 		envVars = append(envVars, fmt.Sprintf("%s=%s", key, val))
 	}
 	cmd.Env = envVars
-	out, err := cmd.CombinedOutput()
+	out, err := common.CombinedOutput(ctx.Context(), cmd)
 	if err != nil {
 		err = errors.Wrapf(err, "running %q in %q", tfCmd, path)
 		ctx.Log.Err(err.Error())
@@ -318,9 +320,10 @@ func (c *DefaultClient) RunCommandWithVersion(ctx command.ProjectContext, path s
 }
 
 // prepCmd builds a ready to execute command based on the version of terraform
-// v, and args. It returns a printable representation of the command that will
+// v, and args. Its environment only has the variables of the Atlantis process
+// that envPassthrough allows. It returns a printable representation of the command that will
 // be run and the actual command.
-func (c *DefaultClient) prepCmd(log logging.SimpleLogging, v *version.Version, workspace string, path string, args []string) (string, *exec.Cmd, error) {
+func (c *DefaultClient) prepCmd(log logging.SimpleLogging, v *version.Version, workspace string, path string, args []string, envPassthrough *valid.EnvPassthrough) (string, *exec.Cmd, error) {
 	if v == nil {
 		v = c.defaultVersion
 	}
@@ -353,8 +356,8 @@ func (c *DefaultClient) prepCmd(log logging.SimpleLogging, v *version.Version, w
 		envVars = append(envVars, fmt.Sprintf("TF_PLUGIN_CACHE_DIR=%s", c.terraformPluginCacheDir))
 	}
 	// Append current Atlantis process's environment variables, ex.
-	// AWS_ACCESS_KEY.
-	envVars = append(envVars, os.Environ()...)
+	// AWS_ACCESS_KEY, that the repo's env passthrough allows.
+	envVars = append(envVars, envPassthrough.Filter(os.Environ())...)
 	tfCmd := fmt.Sprintf("%s %s", binPath, strings.Join(args, " "))
 	cmd := exec.Command("sh", "-c", tfCmd)
 	cmd.Dir = path
@@ -390,7 +393,7 @@ func (c *DefaultClient) RunCommandAsync(ctx command.ProjectContext, path string,
 			close(inCh)
 		}()
 
-		tfCmd, cmd, err := c.prepCmd(ctx.Log, v, workspace, path, args)
+		tfCmd, cmd, err := c.prepCmd(ctx.Log, v, workspace, path, args, ctx.EnvPassthrough)
 		if err != nil {
 			ctx.Log.Err(err.Error())
 			outCh <- Line{Err: err}
@@ -406,6 +409,7 @@ func (c *DefaultClient) RunCommandAsync(ctx command.ProjectContext, path string,
 		cmd.Env = envVars
 
 		ctx.Log.Debug("starting %q in %q", tfCmd, path)
+		common.SetProcessGroup(cmd)
 		err = cmd.Start()
 		if err != nil {
 			err = errors.Wrapf(err, "running %q in %q", tfCmd, path)
@@ -413,6 +417,8 @@ func (c *DefaultClient) RunCommandAsync(ctx command.ProjectContext, path string,
 			outCh <- Line{Err: err}
 			return
 		}
+		// Stop the command if it's cancelled or times out.
+		stop := common.StopOnDone(ctx.Context(), cmd)
 
 		// If we get anything on inCh, write it to stdin.
 		// This function will exit when inCh is closed which we do in our defer.
@@ -458,6 +464,10 @@ func (c *DefaultClient) RunCommandAsync(ctx command.ProjectContext, path string,
 
 		// Wait for the command to complete.
 		err = cmd.Wait()
+		stop()
+		if err != nil && ctx.Context().Err() != nil {
+			err = ctx.Context().Err()
+		}
 
 		// We're done now. Send an error if there was one.
 		if err != nil {
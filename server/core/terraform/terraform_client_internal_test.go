@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	version "github.com/hashicorp/go-version"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
 	jobmocks "github.com/runatlantis/atlantis/server/jobs/mocks"
@@ -128,6 +129,41 @@ func TestDefaultClient_RunCommandWithVersion_EnvVars(t *testing.T) {
 	Equals(t, exp, out)
 }
 
+// Test that only the env vars the env passthrough allows are passed.
+func TestDefaultClient_RunCommandWithVersion_EnvPassthrough(t *testing.T) {
+	defer func(secrets []string) { valid.SecretEnvVars = secrets }(valid.SecretEnvVars)
+	valid.SecretEnvVars = []string{"ATLANTIS_GH_TOKEN"}
+	t.Setenv("ATLANTIS_GH_TOKEN", "token")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	v, err := version.NewVersion("0.11.11")
+	Ok(t, err)
+	tmp := t.TempDir()
+	client := &DefaultClient{
+		defaultVersion:          v,
+		overrideTF:              "echo",
+		projectCmdOutputHandler: jobmocks.NewMockProjectCommandOutputHandler(),
+	}
+	args := []string{
+		"token=$ATLANTIS_GH_TOKEN",
+		"region=$AWS_REGION",
+		"secret=$AWS_SECRET_ACCESS_KEY",
+	}
+
+	ctx := command.ProjectContext{
+		Log:       logging.NewNoopLogger(t),
+		Workspace: "default",
+	}
+	out, err := client.RunCommandWithVersion(ctx, tmp, args, map[string]string{}, nil, "default")
+	Ok(t, err)
+	Equals(t, "token= region=us-east-1 secret=secret\n", out)
+
+	ctx.EnvPassthrough = &valid.EnvPassthrough{Allow: []string{"AWS_REGION"}}
+	out, err = client.RunCommandWithVersion(ctx, tmp, args, map[string]string{}, nil, "default")
+	Ok(t, err)
+	Equals(t, "token= region=us-east-1 secret=\n", out)
+}
+
 // Test that it returns an error on error.
 func TestDefaultClient_RunCommandWithVersion_Error(t *testing.T) {
 	v, err := version.NewVersion("0.11.11")
@@ -18,7 +18,7 @@ func NewApplyCommandRunner(
 	autoMerger *AutoMerger,
 	pullUpdater *PullUpdater,
 	dbUpdater *DBUpdater,
-	db *db.BoltDB,
+	db db.Database,
 	parallelPoolSize int,
 	SilenceNoProjects bool,
 	silenceVCSStatusNoProjects bool,
@@ -44,7 +44,7 @@ func NewApplyCommandRunner(
 
 type ApplyCommandRunner struct {
 	DisableApplyAll      bool
-	DB                   *db.BoltDB
+	DB                   db.Database
 	locker               locking.ApplyLockChecker
 	vcsClient            vcs.Client
 	commitStatusUpdater  CommitStatusUpdater
@@ -140,7 +140,10 @@ func (a *ApplyCommandRunner) Run(ctx *command.Context, cmd *CommentCommand) {
 
 	// Only run commands in parallel if enabled
 	var result command.Result
-	if a.isParallelEnabled(projectCmds) {
+	if hasProjectDependencies(projectCmds) {
+		ctx.Log.Info("Running applies in dependency order")
+		result = runProjectCmdsWithDependencies(projectCmds, a.prjCmdRunner.Apply, a.isParallelEnabled(projectCmds), a.parallelPoolSize)
+	} else if a.isParallelEnabled(projectCmds) {
 		ctx.Log.Info("Running applies in parallel")
 		result = runProjectCmdsParallel(projectCmds, a.prjCmdRunner.Apply, a.parallelPoolSize)
 	} else {
@@ -1,8 +1,14 @@
 package events
 
 import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
 	"github.com/runatlantis/atlantis/server/core/config/raw"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/runtime"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
 )
@@ -17,7 +23,25 @@ type AggregateApplyRequirements struct {
 }
 
 func (a *AggregateApplyRequirements) ValidateProject(repoDir string, ctx command.ProjectContext) (failure string, err error) {
+	var analysis *models.PlanAnalysis
 	for _, req := range ctx.ApplyRequirements {
+		reqName, reqArg := raw.SplitApplyRequirement(req)
+		switch reqName {
+		case raw.MaxDestroysApplyRequirement, raw.NoReplaceApplyRequirement:
+			if analysis == nil {
+				// The plan is analyzed from the output of terraform show that's
+				// saved when planning.
+				analysis, err = runtime.AnalyzePlanFile(filepath.Join(repoDir, ctx.RepoRelDir, ctx.GetShowResultFileName()))
+				if err != nil {
+					ctx.Log.Warn("unable to analyze plan: %s", err)
+					return fmt.Sprintf("Unable to check the %s apply requirement because the plan couldn't be analyzed. Run plan again.", req), nil
+				}
+			}
+			if failure := planAnalysisFailure(reqName, reqArg, analysis); failure != "" {
+				return failure, nil
+			}
+			continue
+		}
 		switch req {
 		case raw.ApprovedApplyRequirement:
 			if !ctx.PullReqStatus.ApprovalStatus.IsApproved {
@@ -41,3 +65,54 @@ func (a *AggregateApplyRequirements) ValidateProject(repoDir string, ctx command
 	// Passed all apply requirements configured.
 	return "", nil
 }
+
+// withoutPlanApplyRequirements returns ctx without the max_destroys and
+// no_replace apply requirements, which check the plan being applied, for
+// commands that change the state without a plan like import.
+func withoutPlanApplyRequirements(ctx command.ProjectContext) command.ProjectContext {
+	var reqs []string
+	for _, req := range ctx.ApplyRequirements {
+		if !isPlanApplyRequirement(req) {
+			reqs = append(reqs, req)
+		}
+	}
+	ctx.ApplyRequirements = reqs
+	return ctx
+}
+
+// hasPlanApplyRequirements returns true if ctx has a max_destroys or
+// no_replace apply requirement, which need the plan to be analyzed.
+func hasPlanApplyRequirements(ctx command.ProjectContext) bool {
+	for _, req := range ctx.ApplyRequirements {
+		if isPlanApplyRequirement(req) {
+			return true
+		}
+	}
+	return false
+}
+
+func isPlanApplyRequirement(req string) bool {
+	switch reqName, _ := raw.SplitApplyRequirement(req); reqName {
+	case raw.MaxDestroysApplyRequirement, raw.NoReplaceApplyRequirement:
+		return true
+	}
+	return false
+}
+
+// planAnalysisFailure returns why the plan doesn't meet the max_destroys or
+// no_replace apply requirement, or an empty string if it does.
+func planAnalysisFailure(reqName string, reqArg string, analysis *models.PlanAnalysis) string {
+	switch reqName {
+	case raw.MaxDestroysApplyRequirement:
+		// Validated when parsing the config.
+		maxDestroys, _ := strconv.Atoi(reqArg)
+		if destroys := analysis.NumDestroys(); destroys > maxDestroys {
+			return fmt.Sprintf("Plan must destroy at most %d resources before running apply, but it destroys or replaces %d.", maxDestroys, destroys)
+		}
+	case raw.NoReplaceApplyRequirement:
+		if changes := analysis.ChangesForType(reqArg); changes.Replaces > 0 {
+			return fmt.Sprintf("Plan must not replace resources of type %s before running apply, but it replaces: %s.", reqArg, strings.Join(changes.ReplacedAddresses, ", "))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package events_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestAggregateApplyRequirements_ValidateProject_PlanAnalysis(t *testing.T) {
+	showOutput := `{
+  "resource_changes": [
+    {"address": "aws_instance.removed", "mode": "managed", "type": "aws_instance", "change": {"actions": ["delete"]}},
+    {"address": "aws_db_instance.main", "mode": "managed", "type": "aws_db_instance", "change": {"actions": ["delete", "create"]}}
+  ]
+}`
+	cases := []struct {
+		description string
+		reqs        []string
+		noShowFile  bool
+		expFailure  string
+	}{
+		{
+			description: "under max destroys",
+			reqs:        []string{"max_destroys:2"},
+		},
+		{
+			description: "over max destroys",
+			reqs:        []string{"max_destroys:1"},
+			expFailure:  "Plan must destroy at most 1 resources before running apply, but it destroys or replaces 2.",
+		},
+		{
+			description: "replaced resource type",
+			reqs:        []string{"no_replace:aws_instance", "no_replace:aws_db_instance"},
+			expFailure:  "Plan must not replace resources of type aws_db_instance before running apply, but it replaces: aws_db_instance.main.",
+		},
+		{
+			description: "plan wasn't analyzed",
+			reqs:        []string{"no_replace:aws_db_instance"},
+			noShowFile:  true,
+			expFailure:  "Unable to check the no_replace:aws_db_instance apply requirement because the plan couldn't be analyzed. Run plan again.",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			repoDir := t.TempDir()
+			ctx := command.ProjectContext{
+				Log:               logging.NewNoopLogger(t),
+				ApplyRequirements: c.reqs,
+				RepoRelDir:        "project",
+				Workspace:         "default",
+			}
+			Ok(t, os.MkdirAll(filepath.Join(repoDir, "project"), 0700))
+			if !c.noShowFile {
+				Ok(t, os.WriteFile(filepath.Join(repoDir, "project", ctx.GetShowResultFileName()), []byte(showOutput), 0600))
+			}
+
+			a := &events.AggregateApplyRequirements{}
+			failure, err := a.ValidateProject(repoDir, ctx)
+			Ok(t, err)
+			Equals(t, c.expFailure, failure)
+		})
+	}
+}
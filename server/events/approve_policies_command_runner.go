@@ -3,8 +3,10 @@ package events
 import (
 	"fmt"
 
+	"github.com/pkg/errors"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/vcs"
 )
 
 func NewApprovePoliciesCommandRunner(
@@ -13,10 +15,12 @@ func NewApprovePoliciesCommandRunner(
 	prjCommandRunner ProjectApprovePoliciesCommandRunner,
 	pullUpdater *PullUpdater,
 	dbUpdater *DBUpdater,
+	vcsClient vcs.Client,
 	SilenceNoProjects bool,
 	silenceVCSStatusNoProjects bool,
 ) *ApprovePoliciesCommandRunner {
 	return &ApprovePoliciesCommandRunner{
+		vcsClient:                  vcsClient,
 		commitStatusUpdater:        commitStatusUpdater,
 		prjCmdBuilder:              prjCommandBuilder,
 		prjCmdRunner:               prjCommandRunner,
@@ -28,6 +32,7 @@ func NewApprovePoliciesCommandRunner(
 }
 
 type ApprovePoliciesCommandRunner struct {
+	vcsClient           vcs.Client
 	commitStatusUpdater CommitStatusUpdater
 	pullUpdater         *PullUpdater
 	dbUpdater           *DBUpdater
@@ -88,10 +93,29 @@ func (a *ApprovePoliciesCommandRunner) Run(ctx *command.Context, cmd *CommentCom
 }
 
 func (a *ApprovePoliciesCommandRunner) buildApprovePolicyCommandResults(ctx *command.Context, prjCmds []command.ProjectContext) (result command.Result) {
-	// Check if vcs user is in the owner list of the PolicySets. All projects
-	// share the same Owners list at this time so no reason to iterate over each
-	// project.
-	if len(prjCmds) > 0 && !prjCmds[0].PolicySets.IsOwner(ctx.User.Username) {
+	if len(prjCmds) == 0 {
+		return
+	}
+
+	// All projects share the same policy sets at this time so no reason to
+	// iterate over each project.
+	policySets := prjCmds[0].PolicySets
+
+	// Only look up the user's teams if teams own any of the policy sets
+	// since it costs an API call.
+	var userTeams []string
+	if len(policySets.OwnerTeams()) > 0 {
+		var err error
+		userTeams, err = a.vcsClient.GetTeamNamesForUser(ctx.Pull.BaseRepo, ctx.User)
+		if err != nil {
+			result.Error = errors.Wrapf(err, "getting teams for user %s", ctx.User.Username)
+			return
+		}
+	}
+
+	// Check if vcs user can approve any of the PolicySets. Whether the user
+	// owns the failing policy sets is checked per project.
+	if !policySets.IsOwner(ctx.User.Username, userTeams) && !policySets.IsAnyPolicySetOwner(ctx.User.Username, userTeams) {
 		result.Error = fmt.Errorf("contact policy owners to approve failing policies")
 		return
 	}
@@ -99,6 +123,7 @@ func (a *ApprovePoliciesCommandRunner) buildApprovePolicyCommandResults(ctx *com
 	var prjResults []command.ProjectResult
 
 	for _, prjCmd := range prjCmds {
+		prjCmd.UserTeams = userTeams
 		prjResult := a.prjCmdRunner.ApprovePolicies(prjCmd)
 		prjResults = append(prjResults, prjResult)
 	}
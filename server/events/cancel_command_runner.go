@@ -0,0 +1,62 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/vcs"
+)
+
+func NewCancelCommandRunner(
+	runningCommands *RunningCommands,
+	jobMessageSender JobMessageSender,
+	vcsClient vcs.Client,
+) *CancelCommandRunner {
+	return &CancelCommandRunner{
+		runningCommands:  runningCommands,
+		jobMessageSender: jobMessageSender,
+		vcsClient:        vcsClient,
+	}
+}
+
+// CancelCommandRunner runs atlantis cancel. It stops the pull request's
+// running project commands, or only those for the project, dir or workspace
+// in the comment.
+type CancelCommandRunner struct {
+	runningCommands  *RunningCommands
+	jobMessageSender JobMessageSender
+	vcsClient        vcs.Client
+}
+
+func (c *CancelCommandRunner) Run(
+	ctx *command.Context,
+	cmd *CommentCommand,
+) {
+	cancelled := c.runningCommands.Cancel(ctx.Pull, cmd.ProjectName, cmd.RepoRelDir, cmd.Workspace, ctx.User)
+
+	var projects []string
+	for _, projCtx := range cancelled {
+		ctx.Log.Info("cancelled %s for dir %q workspace %q", projCtx.CommandName.String(), projCtx.RepoRelDir, projCtx.Workspace)
+		// The job is completed once its steps have stopped, which can take
+		// up to the kill grace period.
+		c.jobMessageSender.Send(projCtx, fmt.Sprintf("Cancelled by @%s.", ctx.User.Username), false)
+
+		project := fmt.Sprintf("dir: `%s` workspace: `%s`", projCtx.RepoRelDir, projCtx.Workspace)
+		if projCtx.ProjectName != "" {
+			project = fmt.Sprintf("project: `%s` %s", projCtx.ProjectName, project)
+		}
+		projects = append(projects, fmt.Sprintf("* `%s` for %s", projCtx.CommandName.String(), project))
+	}
+
+	vcsMessage := "There are no running commands to cancel for this pull request."
+	if cmd.IsForSpecificProject() {
+		vcsMessage = "There are no running commands to cancel that match the comment's project, dir or workspace."
+	}
+	if len(projects) > 0 {
+		vcsMessage = fmt.Sprintf("Cancelled %d command(s):\n\n%s", len(projects), strings.Join(projects, "\n"))
+	}
+	if err := c.vcsClient.CreateComment(ctx.Pull.BaseRepo, ctx.Pull.Num, vcsMessage, command.Cancel.String()); err != nil {
+		ctx.Log.Err("unable to comment: %s", err)
+	}
+}
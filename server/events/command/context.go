@@ -15,6 +15,9 @@ const (
 
 	// Commands that are triggered by comments (ie. atlantis plan)
 	CommentTrigger
+
+	// Commands that are triggered by the API (ie. POST /api/plan)
+	APITrigger
 )
 
 // Context represents the context of a command that should be executed
@@ -20,6 +20,14 @@ const (
 	Autoplan
 	// Version is a command to run terraform version.
 	Version
+	// Import is a command to run terraform import.
+	Import
+	// StateRm is a command to run terraform state rm.
+	StateRm
+	// StateMv is a command to run terraform state mv.
+	StateMv
+	// Cancel is a command to cancel the running commands of a pull request.
+	Cancel
 	// Adding more? Don't forget to update String() below
 )
 
@@ -44,6 +52,14 @@ func (c Name) String() string {
 		return "approve_policies"
 	case Version:
 		return "version"
+	case Import:
+		return "import"
+	case StateRm:
+		return "state_rm"
+	case StateMv:
+		return "state_mv"
+	case Cancel:
+		return "cancel"
 	}
 	return ""
 }
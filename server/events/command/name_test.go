@@ -30,3 +30,28 @@ func TestUnlockCommand_String(t *testing.T) {
 
 	Equals(t, "unlock", uc.String())
 }
+
+func TestImportCommand_String(t *testing.T) {
+	uc := command.Import
+
+	Equals(t, "import", uc.String())
+}
+
+func TestStateRmCommand_String(t *testing.T) {
+	uc := command.StateRm
+
+	Equals(t, "state_rm", uc.String())
+	Equals(t, "State Rm", uc.TitleString())
+}
+
+func TestStateMvCommand_String(t *testing.T) {
+	uc := command.StateMv
+
+	Equals(t, "state_mv", uc.String())
+}
+
+func TestCancelCommand_String(t *testing.T) {
+	uc := command.Cancel
+
+	Equals(t, "cancel", uc.String())
+}
@@ -1,8 +1,10 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
@@ -71,12 +73,22 @@ type ProjectContext struct {
 	// Steps are the sequence of commands we need to run for this project and this
 	// stage.
 	Steps []valid.Step
+	// Timeout is how long Steps can run for in total before they're
+	// cancelled. It's 0 if there's no timeout.
+	Timeout time.Duration
+	// Ctx is done when the command is cancelled, either with atlantis cancel
+	// or because it timed out. Use Context() to read it since it isn't always
+	// set.
+	Ctx context.Context
 	// TerraformVersion is the version of terraform we should use when executing
 	// commands for this project. This can be set to nil in which case we will
 	// use the default Atlantis terraform version.
 	TerraformVersion *version.Version
 	// Configuration metadata for a given project.
 	User models.User
+	// UserTeams are the VCS teams or groups User belongs to. It's only set
+	// when running approve_policies and teams own policy sets.
+	UserTeams []string
 	// Verbose is true when the user would like verbose output.
 	Verbose bool
 	// Workspace is the Terraform workspace this project is in. It will always
@@ -85,8 +97,25 @@ type ProjectContext struct {
 	// PolicySets represent the policies that are run on the plan as part of the
 	// policy check stage
 	PolicySets valid.PolicySets
+	// PolicySetTarget is the name of the policy set to approve when running
+	// approve_policies. If empty, all failing policy sets the user owns are
+	// approved.
+	PolicySetTarget string
+	// ProjectPolicyStatus is the result of the last policy check for each
+	// policy set, prior to this command.
+	ProjectPolicyStatus []models.PolicySetStatus
+	// DependsOn are the names of the projects that must run successfully
+	// before this project.
+	DependsOn []string
 	// DeleteSourceBranchOnMerge will attempt to allow a branch to be deleted when merged (AzureDevOps & GitLab Support Only)
 	DeleteSourceBranchOnMerge bool
+	// EnvPassthrough is which of the Atlantis process's environment variables
+	// are passed to Terraform and run steps. If nil, all of them except
+	// Atlantis' secrets are.
+	EnvPassthrough *valid.EnvPassthrough
+	// Sandbox is the sandbox run steps run in or nil if they don't run in
+	// one.
+	Sandbox *valid.Sandbox
 	// UUID for atlantis logs
 	JobID string
 }
@@ -97,6 +126,14 @@ func (p ProjectContext) SetScope(scope string) {
 	p.Scope = p.Scope.SubScope(scope) //nolint
 }
 
+// Context returns Ctx or, if it isn't set, a context that's never done.
+func (p ProjectContext) Context() context.Context {
+	if p.Ctx == nil {
+		return context.Background()
+	}
+	return p.Ctx
+}
+
 // GetShowResultFileName returns the filename (not the path) to store the tf show result
 func (p ProjectContext) GetShowResultFileName() string {
 	if p.ProjectName == "" {
@@ -106,6 +143,16 @@ func (p ProjectContext) GetShowResultFileName() string {
 	return fmt.Sprintf("%s-%s.json", projName, p.Workspace)
 }
 
+// GetPolicyCheckResultFileName returns the filename (not the path) to store
+// the structured policy check results.
+func (p ProjectContext) GetPolicyCheckResultFileName() string {
+	if p.ProjectName == "" {
+		return fmt.Sprintf("%s-policyout.json", p.Workspace)
+	}
+	projName := strings.Replace(p.ProjectName, "/", planfileSlashReplace, -1)
+	return fmt.Sprintf("%s-%s-policyout.json", projName, p.Workspace)
+}
+
 // Gets a unique identifier for the current pull request as a single string
 func (p ProjectContext) PullInfo() string {
 	normalizedOwner := strings.ReplaceAll(p.BaseRepo.Owner, "/", "-")
@@ -15,7 +15,15 @@ type ProjectResult struct {
 	PolicyCheckSuccess *models.PolicyCheckSuccess
 	ApplySuccess       string
 	VersionSuccess     string
+	ImportSuccess      *models.ImportSuccess
+	StateSuccess       *models.StateSuccess
 	ProjectName        string
+	// PolicyStatus is the result of each policy set for policy check and
+	// approve_policies results.
+	PolicyStatus []models.PolicySetStatus
+	// PolicySetResults are the structured results of each policy set for
+	// policy check results.
+	PolicySetResults []models.PolicySetResult
 }
 
 // CommitStatus returns the vcs commit status of this project result.
@@ -59,7 +67,17 @@ func (p ProjectResult) PlanStatus() models.ProjectPlanStatus {
 	panic("PlanStatus() missing a combination")
 }
 
+// PlanChangeCounts returns the number of resources the plan changes or nil if
+// this isn't a successful plan or the plan couldn't be analyzed.
+func (p ProjectResult) PlanChangeCounts() *models.PlanChangeCounts {
+	if p.PlanSuccess == nil || p.PlanSuccess.Analysis == nil {
+		return nil
+	}
+	counts := p.PlanSuccess.Analysis.Counts()
+	return &counts
+}
+
 // IsSuccessful returns true if this project result had no errors.
 func (p ProjectResult) IsSuccessful() bool {
-	return p.PlanSuccess != nil || p.PolicyCheckSuccess != nil || p.ApplySuccess != ""
+	return p.PlanSuccess != nil || p.PolicyCheckSuccess != nil || p.ApplySuccess != "" || p.ImportSuccess != nil || p.StateSuccess != nil
 }
@@ -32,6 +32,18 @@ func TestProjectResult_IsSuccessful(t *testing.T) {
 			},
 			true,
 		},
+		"import success": {
+			command.ProjectResult{
+				ImportSuccess: &models.ImportSuccess{},
+			},
+			true,
+		},
+		"state success": {
+			command.ProjectResult{
+				StateSuccess: &models.StateSuccess{},
+			},
+			true,
+		},
 		"failure": {
 			command.ProjectResult{
 				Failure: "failure",
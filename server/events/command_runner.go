@@ -16,6 +16,7 @@ package events
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/google/go-github/v31/github"
 	"github.com/mcdafydd/go-azuredevops/azuredevops"
@@ -24,6 +25,7 @@ import (
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/vcs"
+	"github.com/runatlantis/atlantis/server/events/vcs/gitea"
 	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/runatlantis/atlantis/server/metrics"
 	"github.com/runatlantis/atlantis/server/recovery"
@@ -62,6 +64,14 @@ type AzureDevopsPullGetter interface {
 	GetPullRequest(repo models.Repo, pullNum int) (*azuredevops.GitPullRequest, error)
 }
 
+//go:generate pegomock generate -m --use-experimental-model-gen --package mocks -o mocks/mock_gitea_pull_getter.go GiteaPullGetter
+
+// GiteaPullGetter makes API calls to get pull requests.
+type GiteaPullGetter interface {
+	// GetPullRequest gets the pull request with id pullNum for the repo.
+	GetPullRequest(repo models.Repo, pullNum int) (*gitea.PullRequest, error)
+}
+
 //go:generate pegomock generate -m --use-experimental-model-gen --package mocks -o mocks/mock_gitlab_merge_request_getter.go GitlabMergeRequestGetter
 
 // GitlabMergeRequestGetter makes API calls to get merge requests.
@@ -96,6 +106,7 @@ type DefaultCommandRunner struct {
 	GithubPullGetter         GithubPullGetter
 	AzureDevopsPullGetter    AzureDevopsPullGetter
 	GitlabMergeRequestGetter GitlabMergeRequestGetter
+	GiteaPullGetter          GiteaPullGetter
 	DisableAutoplan          bool
 	EventParser              EventParsing
 	Logger                   logging.SimpleLogging
@@ -162,6 +173,19 @@ func (c *DefaultCommandRunner) RunAutoplanCommand(baseRepo models.Repo, headRepo
 		return
 	}
 
+	// Autoplan runs plan on behalf of the pull request's author so it's
+	// skipped if they aren't allowed to run plan themselves. Someone who is
+	// allowed can still comment `atlantis plan`.
+	ok, _, err := c.checkUserPermissions(baseRepo, user, &CommentCommand{Name: command.Plan})
+	if err != nil {
+		log.Err("Unable to check user permissions: %s", err)
+		return
+	}
+	if !ok {
+		log.Info("Skipping autoplan because user @%s does not have permissions to execute 'plan' command.", user.Username)
+		return
+	}
+
 	err = c.PreWorkflowHooksCommandRunner.RunPreHooks(ctx)
 
 	if err != nil {
@@ -181,28 +205,61 @@ func (c *DefaultCommandRunner) RunAutoplanCommand(baseRepo models.Repo, headRepo
 
 // commentUserDoesNotHavePermissions comments on the pull request that the user
 // is not allowed to execute the command.
-func (c *DefaultCommandRunner) commentUserDoesNotHavePermissions(baseRepo models.Repo, pullNum int, user models.User, cmd *CommentCommand) {
+func (c *DefaultCommandRunner) commentUserDoesNotHavePermissions(baseRepo models.Repo, pullNum int, user models.User, cmd *CommentCommand, permission *valid.CommandPermission) {
 	errMsg := fmt.Sprintf("```\nError: User @%s does not have permissions to execute '%s' command.\n```", user.Username, cmd.Name.String())
+	if permission != nil {
+		errMsg += "\n" + commandPermissionDescription(cmd.Name.String(), permission)
+	}
 	if err := c.VCSClient.CreateComment(baseRepo, pullNum, errMsg, ""); err != nil {
 		c.Logger.Err("unable to comment on pull request: %s", err)
 	}
 }
 
-// checkUserPermissions checks if the user has permissions to execute the command
-func (c *DefaultCommandRunner) checkUserPermissions(repo models.Repo, user models.User, cmd *CommentCommand) (bool, error) {
-	if c.TeamAllowlistChecker == nil || !c.TeamAllowlistChecker.HasRules() {
-		// allowlist restriction is not enabled
-		return true, nil
+// commandPermissionDescription describes who can run cmdName.
+func commandPermissionDescription(cmdName string, permission *valid.CommandPermission) string {
+	var allowed []string
+	if len(permission.Users) > 0 {
+		allowed = append(allowed, fmt.Sprintf("users: `%s`", strings.Join(permission.Users, "`, `")))
 	}
-	teams, err := c.VCSClient.GetTeamNamesForUser(repo, user)
-	if err != nil {
-		return false, err
+	if len(permission.Teams) > 0 {
+		allowed = append(allowed, fmt.Sprintf("teams: `%s`", strings.Join(permission.Teams, "`, `")))
 	}
-	ok := c.TeamAllowlistChecker.IsCommandAllowedForAnyTeam(teams, cmd.Name.String())
-	if !ok {
-		return false, nil
+	if len(allowed) == 0 {
+		return fmt.Sprintf("The `%s` command is disabled for this repo.", cmdName)
 	}
-	return true, nil
+	return fmt.Sprintf("In this repo, `%s` can only be run by %s.", cmdName, strings.Join(allowed, " and "))
+}
+
+// checkUserPermissions checks if the user has permissions to execute the
+// command. If the command is restricted by the repo's permissions in the
+// server-side repo config and the user isn't allowed to run it, it also
+// returns those permissions.
+func (c *DefaultCommandRunner) checkUserPermissions(repo models.Repo, user models.User, cmd *CommentCommand) (bool, *valid.CommandPermission, error) {
+	if cmd == nil {
+		return true, nil, nil
+	}
+	allowlistEnabled := c.TeamAllowlistChecker != nil && c.TeamAllowlistChecker.HasRules()
+	permission := c.GlobalCfg.CommandPermission(repo.ID(), cmd.Name.String())
+	if !allowlistEnabled && permission == nil {
+		// no restrictions are enabled
+		return true, nil, nil
+	}
+
+	var teams []string
+	if allowlistEnabled || (permission != nil && len(permission.Teams) > 0) {
+		var err error
+		teams, err = c.VCSClient.GetTeamNamesForUser(repo, user)
+		if err != nil {
+			return false, nil, err
+		}
+	}
+	if allowlistEnabled && !c.TeamAllowlistChecker.IsCommandAllowedForAnyTeam(teams, cmd.Name.String()) {
+		return false, nil, nil
+	}
+	if permission != nil && !permission.IsAllowed(user.Username, teams) {
+		return false, permission, nil
+	}
+	return true, nil, nil
 }
 
 // RunCommentCommand executes the command.
@@ -231,13 +288,13 @@ func (c *DefaultCommandRunner) RunCommentCommand(baseRepo models.Repo, maybeHead
 	defer timer.Stop()
 
 	// Check if the user who commented has the permissions to execute the 'plan' or 'apply' commands
-	ok, err := c.checkUserPermissions(baseRepo, user, cmd)
+	ok, permission, err := c.checkUserPermissions(baseRepo, user, cmd)
 	if err != nil {
 		c.Logger.Err("Unable to check user permissions: %s", err)
 		return
 	}
 	if !ok {
-		c.commentUserDoesNotHavePermissions(baseRepo, pullNum, user, cmd)
+		c.commentUserDoesNotHavePermissions(baseRepo, pullNum, user, cmd, permission)
 		return
 	}
 
@@ -325,6 +382,21 @@ func (c *DefaultCommandRunner) getAzureDevopsData(baseRepo models.Repo, pullNum
 	return pull, headRepo, nil
 }
 
+func (c *DefaultCommandRunner) getGiteaData(baseRepo models.Repo, pullNum int) (models.PullRequest, models.Repo, error) {
+	if c.GiteaPullGetter == nil {
+		return models.PullRequest{}, models.Repo{}, errors.New("atlantis not configured to support Gitea")
+	}
+	giteaPull, err := c.GiteaPullGetter.GetPullRequest(baseRepo, pullNum)
+	if err != nil {
+		return models.PullRequest{}, models.Repo{}, errors.Wrap(err, "making pull request API call to Gitea")
+	}
+	pull, _, headRepo, err := c.EventParser.ParseGiteaPull(giteaPull)
+	if err != nil {
+		return pull, headRepo, errors.Wrap(err, "extracting required fields from comment data")
+	}
+	return pull, headRepo, nil
+}
+
 func (c *DefaultCommandRunner) buildLogger(repoFullName string, pullNum int) logging.SimpleLogging {
 
 	return c.Logger.WithHistory(
@@ -358,6 +430,8 @@ func (c *DefaultCommandRunner) ensureValidRepoMetadata(
 		pull = *maybePull
 	case models.AzureDevops:
 		pull, headRepo, err = c.getAzureDevopsData(baseRepo, pullNum)
+	case models.Gitea:
+		pull, headRepo, err = c.getGiteaData(baseRepo, pullNum)
 	default:
 		err = errors.New("Unknown VCS type–this is a bug")
 	}
@@ -93,6 +93,7 @@ func TestPlanUpdateCommitStatus(t *testing.T) {
 		expStatus     models.CommitStatus
 		expNumSuccess int
 		expNumTotal   int
+		expChanges    *models.PlanChangeCounts
 	}{
 		"single plan success": {
 			cmd: command.Plan,
@@ -129,6 +130,28 @@ func TestPlanUpdateCommitStatus(t *testing.T) {
 			expNumSuccess: 3,
 			expNumTotal:   4,
 		},
+		"plan changes are summed": {
+			cmd: command.Plan,
+			pullStatus: models.PullStatus{
+				Projects: []models.ProjectStatus{
+					{
+						Status:      models.PlannedPlanStatus,
+						PlanChanges: &models.PlanChangeCounts{Add: 2, Destroy: 1},
+					},
+					{
+						Status: models.ErroredPlanStatus,
+					},
+					{
+						Status:      models.PlannedPlanStatus,
+						PlanChanges: &models.PlanChangeCounts{Add: 1, Import: 1},
+					},
+				},
+			},
+			expStatus:     models.FailedCommitStatus,
+			expNumSuccess: 2,
+			expNumTotal:   3,
+			expChanges:    &models.PlanChangeCounts{Add: 3, Destroy: 1, Import: 1},
+		},
 	}
 
 	for name, c := range cases {
@@ -144,6 +167,7 @@ func TestPlanUpdateCommitStatus(t *testing.T) {
 			Equals(t, c.cmd, csu.CalledCommand)
 			Equals(t, c.expNumSuccess, csu.CalledNumSuccess)
 			Equals(t, c.expNumTotal, csu.CalledNumTotal)
+			Equals(t, c.expChanges, csu.CalledChanges)
 		})
 	}
 }
@@ -155,6 +179,7 @@ type MockCSU struct {
 	CalledCommand    command.Name
 	CalledNumSuccess int
 	CalledNumTotal   int
+	CalledChanges    *models.PlanChangeCounts
 }
 
 func (m *MockCSU) UpdateCombinedCount(repo models.Repo, pull models.PullRequest, status models.CommitStatus, command command.Name, numSuccess int, numTotal int) error {
@@ -166,9 +191,16 @@ func (m *MockCSU) UpdateCombinedCount(repo models.Repo, pull models.PullRequest,
 	m.CalledNumTotal = numTotal
 	return nil
 }
+func (m *MockCSU) UpdateCombinedPlanCount(repo models.Repo, pull models.PullRequest, status models.CommitStatus, numSuccess int, numTotal int, changes *models.PlanChangeCounts) error {
+	m.CalledChanges = changes
+	return m.UpdateCombinedCount(repo, pull, status, command.Plan, numSuccess, numTotal)
+}
 func (m *MockCSU) UpdateCombined(repo models.Repo, pull models.PullRequest, status models.CommitStatus, command command.Name) error {
 	return nil
 }
 func (m *MockCSU) UpdateProject(ctx command.ProjectContext, cmdName command.Name, status models.CommitStatus, url string) error {
 	return nil
 }
+func (m *MockCSU) UpdateProjectQueued(ctx command.ProjectContext, cmdName command.Name, position int, url string) error {
+	return nil
+}
@@ -46,6 +46,7 @@ var eventParsing *mocks.MockEventParsing
 var azuredevopsGetter *mocks.MockAzureDevopsPullGetter
 var githubGetter *mocks.MockGithubPullGetter
 var gitlabGetter *mocks.MockGitlabMergeRequestGetter
+var giteaGetter *mocks.MockGiteaPullGetter
 var ch events.DefaultCommandRunner
 var workingDir events.WorkingDir
 var pendingPlanFinder *mocks.MockPendingPlanFinder
@@ -76,6 +77,7 @@ func setup(t *testing.T) *vcsmocks.MockClient {
 	githubGetter = mocks.NewMockGithubPullGetter()
 	gitlabGetter = mocks.NewMockGitlabMergeRequestGetter()
 	azuredevopsGetter = mocks.NewMockAzureDevopsPullGetter()
+	giteaGetter = mocks.NewMockGiteaPullGetter()
 	logger = logging.NewNoopLogger(t)
 	projectCommandRunner = mocks.NewMockProjectCommandRunner()
 	workingDir = mocks.NewMockWorkingDir()
@@ -159,6 +161,7 @@ func setup(t *testing.T) *vcsmocks.MockClient {
 		projectCommandRunner,
 		pullUpdater,
 		dbUpdater,
+		vcsClient,
 		SilenceNoProjects,
 		false,
 	)
@@ -177,12 +180,31 @@ func setup(t *testing.T) *vcsmocks.MockClient {
 		SilenceNoProjects,
 	)
 
+	importCommandRunner := events.NewImportCommandRunner(
+		pullUpdater,
+		dbUpdater,
+		projectCommandBuilder,
+		projectCommandRunner,
+		pullReqStatusFetcher,
+	)
+
+	stateCommandRunner := events.NewStateCommandRunner(
+		pullUpdater,
+		dbUpdater,
+		projectCommandBuilder,
+		projectCommandRunner,
+		pullReqStatusFetcher,
+	)
+
 	commentCommandRunnerByCmd := map[command.Name]events.CommentCommandRunner{
 		command.Plan:            planCommandRunner,
 		command.Apply:           applyCommandRunner,
 		command.ApprovePolicies: approvePoliciesCommandRunner,
 		command.Unlock:          unlockCommandRunner,
 		command.Version:         versionCommandRunner,
+		command.Import:          importCommandRunner,
+		command.StateRm:         stateCommandRunner,
+		command.StateMv:         stateCommandRunner,
 	}
 
 	preWorkflowHooksCommandRunner = mocks.NewMockPreWorkflowHooksCommandRunner()
@@ -203,6 +225,7 @@ func setup(t *testing.T) *vcsmocks.MockClient {
 		GithubPullGetter:               githubGetter,
 		GitlabMergeRequestGetter:       gitlabGetter,
 		AzureDevopsPullGetter:          azuredevopsGetter,
+		GiteaPullGetter:                giteaGetter,
 		Logger:                         logger,
 		StatsScope:                     scope,
 		GlobalCfg:                      globalCfg,
@@ -241,6 +264,16 @@ func TestRunCommentCommand_GitlabMergeRequestErr(t *testing.T) {
 	vcsClient.VerifyWasCalledOnce().CreateComment(fixtures.GitlabRepo, fixtures.Pull.Num, "`Error: making merge request API call to GitLab: err`", "")
 }
 
+func TestRunCommentCommand_GiteaPullErr(t *testing.T) {
+	t.Log("if getting the gitea pull request fails an error should be logged")
+	vcsClient := setup(t)
+	giteaRepo := fixtures.GithubRepo
+	giteaRepo.VCSHost.Type = models.Gitea
+	When(giteaGetter.GetPullRequest(giteaRepo, fixtures.Pull.Num)).ThenReturn(nil, errors.New("err"))
+	ch.RunCommentCommand(giteaRepo, &giteaRepo, nil, fixtures.User, fixtures.Pull.Num, nil)
+	vcsClient.VerifyWasCalledOnce().CreateComment(giteaRepo, fixtures.Pull.Num, "`Error: making pull request API call to Gitea: err`", "")
+}
+
 func TestRunCommentCommand_GithubPullParseErr(t *testing.T) {
 	t.Log("if parsing the returned github pull request fails an error should be logged")
 	vcsClient := setup(t)
@@ -288,6 +321,66 @@ func TestRunCommentCommand_TeamAllowListChecker(t *testing.T) {
 	})
 }
 
+func TestRunCommentCommand_RepoPermissions(t *testing.T) {
+	permissions := map[string]valid.CommandPermission{
+		"apply":  {Users: []string{"admin"}, Teams: []string{"platform"}},
+		"unlock": {},
+	}
+
+	t.Run("unrestricted command", func(t *testing.T) {
+		vcsClient := setup(t)
+		ch.GlobalCfg.Repos = append(ch.GlobalCfg.Repos, valid.Repo{ID: fixtures.GithubRepo.ID(), Permissions: permissions})
+		var pull github.PullRequest
+		modelPull := models.PullRequest{
+			BaseRepo: fixtures.GithubRepo,
+			State:    models.OpenPullState,
+		}
+		When(githubGetter.GetPullRequest(fixtures.GithubRepo, fixtures.Pull.Num)).ThenReturn(&pull, nil)
+		When(eventParsing.ParseGithubPull(&pull)).ThenReturn(modelPull, modelPull.BaseRepo, fixtures.GithubRepo, nil)
+
+		ch.RunCommentCommand(fixtures.GithubRepo, nil, nil, fixtures.User, fixtures.Pull.Num, &events.CommentCommand{Name: command.Plan})
+		vcsClient.VerifyWasCalled(Never()).GetTeamNamesForUser(fixtures.GithubRepo, fixtures.User)
+		vcsClient.VerifyWasCalledOnce().CreateComment(fixtures.GithubRepo, modelPull.Num, "Ran Plan for 0 projects:\n\n\n\n", "plan")
+	})
+
+	t.Run("allowed team", func(t *testing.T) {
+		vcsClient := setup(t)
+		ch.GlobalCfg.Repos = append(ch.GlobalCfg.Repos, valid.Repo{ID: fixtures.GithubRepo.ID(), Permissions: permissions})
+		When(vcsClient.GetTeamNamesForUser(fixtures.GithubRepo, fixtures.User)).ThenReturn([]string{"Platform"}, nil)
+		var pull github.PullRequest
+		modelPull := models.PullRequest{
+			BaseRepo: fixtures.GithubRepo,
+			State:    models.OpenPullState,
+		}
+		When(githubGetter.GetPullRequest(fixtures.GithubRepo, fixtures.Pull.Num)).ThenReturn(&pull, nil)
+		When(eventParsing.ParseGithubPull(&pull)).ThenReturn(modelPull, modelPull.BaseRepo, fixtures.GithubRepo, nil)
+
+		ch.RunCommentCommand(fixtures.GithubRepo, nil, nil, fixtures.User, fixtures.Pull.Num, &events.CommentCommand{Name: command.Apply})
+		vcsClient.VerifyWasCalledOnce().CreateComment(fixtures.GithubRepo, modelPull.Num, "Ran Apply for 0 projects:\n\n\n\n", "apply")
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		vcsClient := setup(t)
+		ch.GlobalCfg.Repos = append(ch.GlobalCfg.Repos, valid.Repo{ID: fixtures.GithubRepo.ID(), Permissions: permissions})
+		When(vcsClient.GetTeamNamesForUser(fixtures.GithubRepo, fixtures.User)).ThenReturn([]string{"dev"}, nil)
+
+		ch.RunCommentCommand(fixtures.GithubRepo, nil, nil, fixtures.User, fixtures.Pull.Num, &events.CommentCommand{Name: command.Apply})
+		vcsClient.VerifyWasCalledOnce().CreateComment(fixtures.GithubRepo, fixtures.Pull.Num,
+			"```\nError: User @lkysow does not have permissions to execute 'apply' command.\n```\nIn this repo, `apply` can only be run by users: `admin` and teams: `platform`.", "")
+		githubGetter.VerifyWasCalled(Never()).GetPullRequest(fixtures.GithubRepo, fixtures.Pull.Num)
+	})
+
+	t.Run("disabled command", func(t *testing.T) {
+		vcsClient := setup(t)
+		ch.GlobalCfg.Repos = append(ch.GlobalCfg.Repos, valid.Repo{ID: fixtures.GithubRepo.ID(), Permissions: permissions})
+
+		ch.RunCommentCommand(fixtures.GithubRepo, nil, nil, fixtures.User, fixtures.Pull.Num, &events.CommentCommand{Name: command.Unlock})
+		vcsClient.VerifyWasCalled(Never()).GetTeamNamesForUser(fixtures.GithubRepo, fixtures.User)
+		vcsClient.VerifyWasCalledOnce().CreateComment(fixtures.GithubRepo, fixtures.Pull.Num,
+			"```\nError: User @lkysow does not have permissions to execute 'unlock' command.\n```\nThe `unlock` command is disabled for this repo.", "")
+	})
+}
+
 func TestRunCommentCommand_ForkPRDisabled(t *testing.T) {
 	t.Log("if a command is run on a forked pull request and this is disabled atlantis should" +
 		" comment saying that this is not allowed")
@@ -529,6 +622,42 @@ func TestRunUnlockCommandFail_VCSComment(t *testing.T) {
 
 // Test that if one plan fails and we are using automerge, that
 // we delete the plans.
+func TestRunAutoplanCommand_RepoPermissions(t *testing.T) {
+	permissions := map[string]valid.CommandPermission{
+		"plan": {Teams: []string{"platform"}},
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		vcsClient := setup(t)
+		ch.GlobalCfg.Repos = append(ch.GlobalCfg.Repos, valid.Repo{ID: fixtures.GithubRepo.ID(), Permissions: permissions})
+		When(vcsClient.GetTeamNamesForUser(fixtures.GithubRepo, fixtures.User)).ThenReturn([]string{"platform"}, nil)
+		fixtures.Pull.BaseRepo = fixtures.GithubRepo
+		ch.RunAutoplanCommand(fixtures.GithubRepo, fixtures.GithubRepo, fixtures.Pull, fixtures.User)
+		projectCommandBuilder.VerifyWasCalledOnce().BuildAutoplanCommands(matchers.AnyPtrToEventsCommandContext())
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		vcsClient := setup(t)
+		ch.GlobalCfg.Repos = append(ch.GlobalCfg.Repos, valid.Repo{ID: fixtures.GithubRepo.ID(), Permissions: permissions})
+		When(vcsClient.GetTeamNamesForUser(fixtures.GithubRepo, fixtures.User)).ThenReturn([]string{"dev"}, nil)
+		fixtures.Pull.BaseRepo = fixtures.GithubRepo
+		ch.RunAutoplanCommand(fixtures.GithubRepo, fixtures.GithubRepo, fixtures.Pull, fixtures.User)
+		projectCommandBuilder.VerifyWasCalled(Never()).BuildAutoplanCommands(matchers.AnyPtrToEventsCommandContext())
+		vcsClient.VerifyWasCalled(Never()).CreateComment(matchers.AnyModelsRepo(), AnyInt(), AnyString(), AnyString())
+	})
+
+	t.Run("denied by team allowlist", func(t *testing.T) {
+		vcsClient := setup(t)
+		checker, err := events.NewTeamAllowlistChecker("platform:plan")
+		Ok(t, err)
+		ch.TeamAllowlistChecker = checker
+		When(vcsClient.GetTeamNamesForUser(fixtures.GithubRepo, fixtures.User)).ThenReturn([]string{"dev"}, nil)
+		fixtures.Pull.BaseRepo = fixtures.GithubRepo
+		ch.RunAutoplanCommand(fixtures.GithubRepo, fixtures.GithubRepo, fixtures.Pull, fixtures.User)
+		projectCommandBuilder.VerifyWasCalled(Never()).BuildAutoplanCommands(matchers.AnyPtrToEventsCommandContext())
+	})
+}
+
 func TestRunAutoplanCommand_DeletePlans(t *testing.T) {
 	setup(t)
 	tmp, cleanup := TempDir(t)
@@ -677,6 +806,80 @@ func TestApprovedPoliciesUpdateFailedPolicyStatus(t *testing.T) {
 	)
 }
 
+func TestApprovedPoliciesTeamOwners(t *testing.T) {
+	t.Log("if \"atlantis approve_policies\" is run by a member of a team that owns the policies they are approved.")
+	cases := []struct {
+		description string
+		userTeams   []string
+		expApproved bool
+	}{
+		{
+			description: "member of owning team",
+			userTeams:   []string{"dev", "security"},
+			expApproved: true,
+		},
+		{
+			description: "not a member of owning team",
+			userTeams:   []string{"dev"},
+			expApproved: false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			vcsClient := setup(t)
+			tmp, cleanup := TempDir(t)
+			defer cleanup()
+			boltDB, err := db.New(tmp)
+			Ok(t, err)
+			dbUpdater.DB = boltDB
+
+			pull := &github.PullRequest{
+				State: github.String("open"),
+			}
+			modelPull := models.PullRequest{
+				BaseRepo: fixtures.GithubRepo,
+				State:    models.OpenPullState,
+				Num:      fixtures.Pull.Num,
+			}
+			When(githubGetter.GetPullRequest(fixtures.GithubRepo, fixtures.Pull.Num)).ThenReturn(pull, nil)
+			When(eventParsing.ParseGithubPull(pull)).ThenReturn(modelPull, modelPull.BaseRepo, fixtures.GithubRepo, nil)
+			When(vcsClient.GetTeamNamesForUser(fixtures.GithubRepo, fixtures.User)).ThenReturn(c.userTeams, nil)
+
+			When(projectCommandBuilder.BuildApprovePoliciesCommands(matchers.AnyPtrToEventsCommandContext(), matchers.AnyPtrToEventsCommentCommand())).ThenReturn([]command.ProjectContext{
+				{
+					CommandName: command.ApprovePolicies,
+					PolicySets: valid.PolicySets{
+						Owners: valid.PolicyOwners{
+							Teams: []string{"security"},
+						},
+					},
+				},
+			}, nil)
+
+			When(workingDir.GetPullDir(fixtures.GithubRepo, fixtures.Pull)).ThenReturn(tmp, nil)
+			var approvedTeams []string
+			When(projectCommandRunner.ApprovePolicies(matchers.AnyModelsProjectCommandContext())).Then(func(params []Param) ReturnValues {
+				approvedTeams = params[0].(command.ProjectContext).UserTeams
+				return ReturnValues{
+					command.ProjectResult{
+						Command:            command.PolicyCheck,
+						PolicyCheckSuccess: &models.PolicyCheckSuccess{},
+					},
+				}
+			})
+
+			ch.RunCommentCommand(fixtures.GithubRepo, &fixtures.GithubRepo, &fixtures.Pull, fixtures.User, fixtures.Pull.Num, &events.CommentCommand{Name: command.ApprovePolicies})
+			vcsClient.VerifyWasCalledOnce().GetTeamNamesForUser(fixtures.GithubRepo, fixtures.User)
+			if c.expApproved {
+				projectCommandRunner.VerifyWasCalledOnce().ApprovePolicies(matchers.AnyModelsProjectCommandContext())
+				Equals(t, c.userTeams, approvedTeams)
+			} else {
+				projectCommandRunner.VerifyWasCalled(Never()).ApprovePolicies(matchers.AnyModelsProjectCommandContext())
+			}
+		})
+	}
+}
+
 func TestApplyMergeablityWhenPolicyCheckFails(t *testing.T) {
 	t.Log("if \"atlantis apply\" is run with failing policy check then apply is not performed")
 	setup(t)
@@ -792,6 +995,53 @@ func TestRunApply_DiscardedProjects(t *testing.T) {
 	vcsClient.VerifyWasCalled(Never()).MergePull(matchers.AnyModelsPullRequest(), matchers.AnyModelsPullRequestOptions())
 }
 
+func TestRunImportCommand_DiscardsPlans(t *testing.T) {
+	t.Log("if \"atlantis import\" succeeds the plan of the project should be discarded")
+	setup(t)
+	tmp, cleanup := TempDir(t)
+	defer cleanup()
+	boltDB, err := db.New(tmp)
+	Ok(t, err)
+	dbUpdater.DB = boltDB
+	ch.PullStatusFetcher = boltDB
+	pull := fixtures.Pull
+	pull.BaseRepo = fixtures.GithubRepo
+	_, err = boltDB.UpdatePullWithResults(pull, []command.ProjectResult{
+		{
+			Command:    command.Plan,
+			RepoRelDir: ".",
+			Workspace:  "default",
+			PlanSuccess: &models.PlanSuccess{
+				TerraformOutput: "tf-output",
+				LockURL:         "lock-url",
+			},
+		},
+	})
+	Ok(t, err)
+	ghPull := &github.PullRequest{
+		State: github.String("open"),
+	}
+	cmd := &events.CommentCommand{Name: command.Import, Args: []string{"aws_instance.web", "i-1234"}}
+	projCtx := command.ProjectContext{CommandName: command.Import, RepoRelDir: ".", Workspace: "default"}
+	When(githubGetter.GetPullRequest(fixtures.GithubRepo, fixtures.Pull.Num)).ThenReturn(ghPull, nil)
+	When(eventParsing.ParseGithubPull(ghPull)).ThenReturn(pull, pull.BaseRepo, fixtures.GithubRepo, nil)
+	When(projectCommandBuilder.BuildImportCommands(matchers.AnyPtrToEventsCommandContext(), matchers.AnyPtrToEventsCommentCommand())).
+		ThenReturn([]command.ProjectContext{projCtx}, nil)
+	When(projectCommandRunner.Import(projCtx)).ThenReturn(command.ProjectResult{
+		Command:       command.Import,
+		RepoRelDir:    ".",
+		Workspace:     "default",
+		ImportSuccess: &models.ImportSuccess{Output: "Import successful!"},
+	})
+
+	ch.RunCommentCommand(fixtures.GithubRepo, &fixtures.GithubRepo, &pull, fixtures.User, fixtures.Pull.Num, cmd)
+
+	projectCommandRunner.VerifyWasCalledOnce().Import(projCtx)
+	status, err := boltDB.GetPullStatus(pull)
+	Ok(t, err)
+	Equals(t, models.DiscardedPlanStatus, status.Projects[0].Status)
+}
+
 func TestRunCommentCommand_DrainOngoing(t *testing.T) {
 	t.Log("if drain is ongoing then a message should be displayed")
 	vcsClient := setup(t)
@@ -41,7 +41,12 @@ const (
 	autoMergeDisabledFlagShort = ""
 	verboseFlagLong            = "verbose"
 	verboseFlagShort           = ""
+	policySetFlagLong          = "policy-set"
+	policySetFlagShort         = ""
 	atlantisExecutable         = "atlantis"
+	stateCommand               = "state"
+	stateRmSubcommand          = "rm"
+	stateMvSubcommand          = "mv"
 )
 
 // multiLineRegex is used to ignore multi-line comments since those aren't valid
@@ -78,6 +83,7 @@ type CommentParser struct {
 	GitlabUser      string
 	BitbucketUser   string
 	AzureDevopsUser string
+	GiteaUser       string
 	ApplyDisabled   bool
 }
 
@@ -99,9 +105,10 @@ type CommentParseResult struct {
 // - The initial "executable" name, 'run' or 'atlantis' or '@GithubUser'
 //   where GithubUser is the API user Atlantis is running as.
 // - Then a command: 'plan', 'apply', 'unlock', 'version, 'approve_policies',
-//   or 'help'.
-// - Then optional flags, then an optional separator '--' followed by optional
-//   extra flags to be appended to the terraform plan/apply command.
+//   'import', 'state rm', 'state mv', 'cancel' or 'help'.
+// - Then optional flags, then the arguments of import and state commands,
+//   then an optional separator '--' followed by optional extra flags to be
+//   appended to the terraform command.
 //
 // Examples:
 // - atlantis help
@@ -112,6 +119,9 @@ type CommentParseResult struct {
 // - atlantis unlock
 // - atlantis version
 // - atlantis approve_policies
+// - atlantis import -d dir aws_instance.web i-1234
+// - atlantis state mv -p project aws_instance.web aws_instance.app
+// - atlantis cancel -p project
 //
 func (e *CommentParser) Parse(comment string, vcsHost models.VCSHostType) CommentParseResult {
 	if multiLineRegex.MatchString(comment) {
@@ -142,6 +152,8 @@ func (e *CommentParser) Parse(comment string, vcsHost models.VCSHostType) Commen
 		vcsUser = e.BitbucketUser
 	case models.AzureDevops:
 		vcsUser = e.AzureDevopsUser
+	case models.Gitea:
+		vcsUser = e.GiteaUser
 	}
 	executableNames := []string{"run", atlantisExecutable, "@" + vcsUser}
 	if !e.stringInSlice(args[0], executableNames) {
@@ -170,14 +182,30 @@ func (e *CommentParser) Parse(comment string, vcsHost models.VCSHostType) Commen
 		return CommentParseResult{CommentResponse: e.HelpComment(e.ApplyDisabled)}
 	}
 
-	// Need to have a plan, apply, approve_policy or unlock at this point.
-	if !e.stringInSlice(cmd, []string{command.Plan.String(), command.Apply.String(), command.Unlock.String(), command.ApprovePolicies.String(), command.Version.String()}) {
+	// Need to have a plan, apply, approve_policy, unlock, version, import,
+	// state or cancel at this point.
+	if !e.stringInSlice(cmd, []string{command.Plan.String(), command.Apply.String(), command.Unlock.String(), command.ApprovePolicies.String(), command.Version.String(), command.Import.String(), stateCommand, command.Cancel.String()}) {
 		return CommentParseResult{CommentResponse: fmt.Sprintf("```\nError: unknown command %q.\nRun 'atlantis --help' for usage.\n```", cmd)}
 	}
 
+	// It's safe to use [2:] because we know there's at least 2 elements in args.
+	flagArgs := args[2:]
+	usageName := cmd
+	// State commands have a subcommand, ex. atlantis state rm, which is
+	// parsed like the rest of the commands after joining it to the command.
+	if cmd == stateCommand {
+		if len(args) < 3 || !e.stringInSlice(args[2], []string{stateRmSubcommand, stateMvSubcommand}) {
+			return CommentParseResult{CommentResponse: StateUsage}
+		}
+		cmd = fmt.Sprintf("%s_%s", stateCommand, args[2])
+		usageName = fmt.Sprintf("%s %s", stateCommand, args[2])
+		flagArgs = args[3:]
+	}
+
 	var workspace string
 	var dir string
 	var project string
+	var policySet string
 	var verbose, autoMergeDisabled bool
 	var flagSet *pflag.FlagSet
 	var name command.Name
@@ -205,6 +233,7 @@ func (e *CommentParser) Parse(comment string, vcsHost models.VCSHostType) Commen
 		name = command.ApprovePolicies
 		flagSet = pflag.NewFlagSet(command.ApprovePolicies.String(), pflag.ContinueOnError)
 		flagSet.SetOutput(io.Discard)
+		flagSet.StringVarP(&policySet, policySetFlagLong, policySetFlagShort, "", "Approve only this policy set. Refers to the name of the policy set configured in the server's repos.yaml.")
 		flagSet.BoolVarP(&verbose, verboseFlagLong, verboseFlagShort, false, "Append Atlantis log to comment.")
 	case command.Unlock.String():
 		name = command.Unlock
@@ -217,21 +246,46 @@ func (e *CommentParser) Parse(comment string, vcsHost models.VCSHostType) Commen
 		flagSet.StringVarP(&dir, dirFlagLong, dirFlagShort, "", "Which directory to run version in relative to root of repo, ex. 'child/dir'.")
 		flagSet.StringVarP(&project, projectFlagLong, projectFlagShort, "", fmt.Sprintf("Print the version for this project. Refers to the name of the project configured in %s.", config.AtlantisYAMLFilename))
 		flagSet.BoolVarP(&verbose, verboseFlagLong, verboseFlagShort, false, "Append Atlantis log to comment.")
+	case command.Import.String():
+		name = command.Import
+		flagSet = pflag.NewFlagSet(command.Import.String(), pflag.ContinueOnError)
+		flagSet.SetOutput(io.Discard)
+		flagSet.StringVarP(&workspace, workspaceFlagLong, workspaceFlagShort, "", "Switch to this Terraform workspace before importing.")
+		flagSet.StringVarP(&dir, dirFlagLong, dirFlagShort, "", "Which directory to run import in relative to root of repo, ex. 'child/dir'.")
+		flagSet.StringVarP(&project, projectFlagLong, projectFlagShort, "", fmt.Sprintf("Which project to run import for. Refers to the name of the project configured in %s. Cannot be used at same time as workspace or dir flags.", config.AtlantisYAMLFilename))
+		flagSet.BoolVarP(&verbose, verboseFlagLong, verboseFlagShort, false, "Append Atlantis log to comment.")
+	case command.StateRm.String(), command.StateMv.String():
+		name = command.StateRm
+		if cmd == command.StateMv.String() {
+			name = command.StateMv
+		}
+		flagSet = pflag.NewFlagSet(cmd, pflag.ContinueOnError)
+		flagSet.SetOutput(io.Discard)
+		flagSet.StringVarP(&workspace, workspaceFlagLong, workspaceFlagShort, "", "Switch to this Terraform workspace before changing the state.")
+		flagSet.StringVarP(&dir, dirFlagLong, dirFlagShort, "", "Which directory to change the state in relative to root of repo, ex. 'child/dir'.")
+		flagSet.StringVarP(&project, projectFlagLong, projectFlagShort, "", fmt.Sprintf("Which project to change the state for. Refers to the name of the project configured in %s. Cannot be used at same time as workspace or dir flags.", config.AtlantisYAMLFilename))
+		flagSet.BoolVarP(&verbose, verboseFlagLong, verboseFlagShort, false, "Append Atlantis log to comment.")
+	case command.Cancel.String():
+		name = command.Cancel
+		flagSet = pflag.NewFlagSet(command.Cancel.String(), pflag.ContinueOnError)
+		flagSet.SetOutput(io.Discard)
+		flagSet.StringVarP(&workspace, workspaceFlagLong, workspaceFlagShort, "", "Cancel the running commands for this Terraform workspace.")
+		flagSet.StringVarP(&dir, dirFlagLong, dirFlagShort, "", "Cancel the running commands for this directory, relative to root of repo, ex. 'child/dir'.")
+		flagSet.StringVarP(&project, projectFlagLong, projectFlagShort, "", fmt.Sprintf("Cancel the running commands for this project. Refers to the name of the project configured in %s. Cannot be used at same time as workspace or dir flags.", config.AtlantisYAMLFilename))
 	default:
 		return CommentParseResult{CommentResponse: fmt.Sprintf("Error: unknown command %q – this is a bug", cmd)}
 	}
 
 	// Now parse the flags.
-	// It's safe to use [2:] because we know there's at least 2 elements in args.
-	err = flagSet.Parse(args[2:])
+	err = flagSet.Parse(flagArgs)
 	if err == pflag.ErrHelp {
-		return CommentParseResult{CommentResponse: fmt.Sprintf("```\nUsage of %s:\n%s\n```", cmd, flagSet.FlagUsagesWrapped(usagesCols))}
+		return CommentParseResult{CommentResponse: fmt.Sprintf("```\nUsage of %s:\n%s\n```", usageName, flagSet.FlagUsagesWrapped(usagesCols))}
 	}
 	if err != nil {
 		if cmd == command.Unlock.String() {
 			return CommentParseResult{CommentResponse: UnlockUsage}
 		}
-		return CommentParseResult{CommentResponse: e.errMarkdown(err.Error(), cmd, flagSet)}
+		return CommentParseResult{CommentResponse: e.errMarkdown(err.Error(), usageName, flagSet)}
 	}
 
 	var unusedArgs []string
@@ -240,8 +294,28 @@ func (e *CommentParser) Parse(comment string, vcsHost models.VCSHostType) Commen
 	} else {
 		unusedArgs = flagSet.Args()[0:flagSet.ArgsLenAtDash()]
 	}
+	// Import and state commands take positional arguments, ex. the address
+	// and ID to import.
+	var positionalArgs []string
+	switch name {
+	case command.Import:
+		if len(unusedArgs) != 2 {
+			return CommentParseResult{CommentResponse: e.errMarkdown(fmt.Sprintf("import requires exactly two arguments, ADDRESS and ID, got %d", len(unusedArgs)), usageName, flagSet)}
+		}
+		positionalArgs, unusedArgs = unusedArgs, nil
+	case command.StateRm:
+		if len(unusedArgs) < 1 {
+			return CommentParseResult{CommentResponse: e.errMarkdown("state rm requires at least one ADDRESS argument", usageName, flagSet)}
+		}
+		positionalArgs, unusedArgs = unusedArgs, nil
+	case command.StateMv:
+		if len(unusedArgs) != 2 {
+			return CommentParseResult{CommentResponse: e.errMarkdown(fmt.Sprintf("state mv requires exactly two arguments, SOURCE and DESTINATION, got %d", len(unusedArgs)), usageName, flagSet)}
+		}
+		positionalArgs, unusedArgs = unusedArgs, nil
+	}
 	if len(unusedArgs) > 0 {
-		return CommentParseResult{CommentResponse: e.errMarkdown(fmt.Sprintf("unknown argument(s) – %s", strings.Join(unusedArgs, " ")), cmd, flagSet)}
+		return CommentParseResult{CommentResponse: e.errMarkdown(fmt.Sprintf("unknown argument(s) – %s", strings.Join(unusedArgs, " ")), usageName, flagSet)}
 	}
 
 	var extraArgs []string
@@ -251,14 +325,14 @@ func (e *CommentParser) Parse(comment string, vcsHost models.VCSHostType) Commen
 
 	dir, err = e.validateDir(dir)
 	if err != nil {
-		return CommentParseResult{CommentResponse: e.errMarkdown(err.Error(), cmd, flagSet)}
+		return CommentParseResult{CommentResponse: e.errMarkdown(err.Error(), usageName, flagSet)}
 	}
 
 	// Use the same validation that Terraform uses: https://git.io/vxGhU. Plus
 	// we also don't allow '..'. We don't want the workspace to contain a path
 	// since we create files based on the name.
 	if workspace != url.PathEscape(workspace) || strings.Contains(workspace, "..") {
-		return CommentParseResult{CommentResponse: e.errMarkdown(fmt.Sprintf("invalid workspace: %q", workspace), cmd, flagSet)}
+		return CommentParseResult{CommentResponse: e.errMarkdown(fmt.Sprintf("invalid workspace: %q", workspace), usageName, flagSet)}
 	}
 
 	// If project is specified, dir or workspace should not be set. Since we
@@ -268,11 +342,14 @@ func (e *CommentParser) Parse(comment string, vcsHost models.VCSHostType) Commen
 	// an error.
 	if project != "" && (workspace != "" || dir != "") {
 		err := fmt.Sprintf("cannot use -%s/--%s at same time as -%s/--%s or -%s/--%s", projectFlagShort, projectFlagLong, dirFlagShort, dirFlagLong, workspaceFlagShort, workspaceFlagLong)
-		return CommentParseResult{CommentResponse: e.errMarkdown(err, cmd, flagSet)}
+		return CommentParseResult{CommentResponse: e.errMarkdown(err, usageName, flagSet)}
 	}
 
+	commentCmd := NewCommentCommand(dir, extraArgs, name, verbose, autoMergeDisabled, workspace, project)
+	commentCmd.PolicySet = policySet
+	commentCmd.Args = positionalArgs
 	return CommentParseResult{
-		Command: NewCommentCommand(dir, extraArgs, name, verbose, autoMergeDisabled, workspace, project),
+		Command: commentCmd,
 	}
 }
 
@@ -409,8 +486,18 @@ Commands:
   unlock   Removes all atlantis locks and discards all plans for this PR.
            To unlock a specific plan you can use the Atlantis UI.
   approve_policies
-           Approves all current policy checking failures for the PR.
+           Approves all current policy checking failures for the PR that you own.
+           To only approve a specific policy set, use the --policy-set flag.
   version  Print the output of 'terraform version'
+  import ADDRESS ID
+           Runs 'terraform import' for the project and discards its plan.
+           To import into a specific project, use the -d, -w and -p flags.
+  state rm ADDRESS...
+           Runs 'terraform state rm' for the project and discards its plan.
+  state mv SOURCE DESTINATION
+           Runs 'terraform state mv' for the project and discards its plan.
+  cancel   Stops the running commands for this pull request.
+           To only cancel a specific project, use the -d, -w and -p flags.
   help     View help.
 
 Flags:
@@ -423,6 +510,18 @@ Use "atlantis [command] --help" for more information about a command.` +
 // someone runs a command with terraform instead of atlantis.
 var DidYouMeanAtlantisComment = "Did you mean to use `atlantis` instead of `terraform`?"
 
+// StateUsage is the comment we add to the pull request when someone runs
+// `atlantis state` without a valid subcommand.
+var StateUsage = "`Usage of state:`\n\n ```cmake\n" +
+	`atlantis state <subcommand> [options] ADDRESS... -- [terraform options]
+
+Subcommands:
+  rm ADDRESS...            Runs 'terraform state rm' for the given addresses.
+  mv SOURCE DESTINATION    Runs 'terraform state mv' to move SOURCE to DESTINATION.
+
+Use the -d, -w and -p flags to choose the project.` +
+	"\n```"
+
 // UnlockUsage is the comment we add to the pull request when someone runs
 // `atlantis unlock` with flags.
 
@@ -731,8 +731,18 @@ Commands:
   unlock   Removes all atlantis locks and discards all plans for this PR.
            To unlock a specific plan you can use the Atlantis UI.
   approve_policies
-           Approves all current policy checking failures for the PR.
+           Approves all current policy checking failures for the PR that you own.
+           To only approve a specific policy set, use the --policy-set flag.
   version  Print the output of 'terraform version'
+  import ADDRESS ID
+           Runs 'terraform import' for the project and discards its plan.
+           To import into a specific project, use the -d, -w and -p flags.
+  state rm ADDRESS...
+           Runs 'terraform state rm' for the project and discards its plan.
+  state mv SOURCE DESTINATION
+           Runs 'terraform state mv' for the project and discards its plan.
+  cancel   Stops the running commands for this pull request.
+           To only cancel a specific project, use the -d, -w and -p flags.
   help     View help.
 
 Flags:
@@ -760,8 +770,18 @@ Commands:
   unlock   Removes all atlantis locks and discards all plans for this PR.
            To unlock a specific plan you can use the Atlantis UI.
   approve_policies
-           Approves all current policy checking failures for the PR.
+           Approves all current policy checking failures for the PR that you own.
+           To only approve a specific policy set, use the --policy-set flag.
   version  Print the output of 'terraform version'
+  import ADDRESS ID
+           Runs 'terraform import' for the project and discards its plan.
+           To import into a specific project, use the -d, -w and -p flags.
+  state rm ADDRESS...
+           Runs 'terraform state rm' for the project and discards its plan.
+  state mv SOURCE DESTINATION
+           Runs 'terraform state mv' for the project and discards its plan.
+  cancel   Stops the running commands for this pull request.
+           To only cancel a specific project, use the -d, -w and -p flags.
   help     View help.
 
 Flags:
@@ -785,6 +805,7 @@ func TestParse_VCSUsername(t *testing.T) {
 		GitlabUser:      "gl",
 		BitbucketUser:   "bb",
 		AzureDevopsUser: "ad",
+		GiteaUser:       "gt",
 	}
 	cases := []struct {
 		vcs  models.VCSHostType
@@ -810,6 +831,10 @@ func TestParse_VCSUsername(t *testing.T) {
 			vcs:  models.AzureDevops,
 			user: "ad",
 		},
+		{
+			vcs:  models.Gitea,
+			user: "gt",
+		},
 	}
 
 	for _, c := range cases {
@@ -820,6 +845,108 @@ func TestParse_VCSUsername(t *testing.T) {
 	}
 }
 
+func TestParse_ApprovePoliciesPolicySet(t *testing.T) {
+	r := commentParser.Parse("atlantis approve_policies --policy-set security", models.Github)
+	Equals(t, "", r.CommentResponse)
+	Equals(t, command.ApprovePolicies, r.Command.Name)
+	Equals(t, "security", r.Command.PolicySet)
+
+	r = commentParser.Parse("atlantis approve_policies", models.Github)
+	Equals(t, "", r.CommentResponse)
+	Equals(t, "", r.Command.PolicySet)
+}
+
+func TestParse_ImportAndState(t *testing.T) {
+	cases := []struct {
+		comment     string
+		expName     command.Name
+		expDir      string
+		expWS       string
+		expProject  string
+		expArgs     []string
+		expFlags    []string
+		expResponse string
+	}{
+		{
+			comment: "atlantis import aws_instance.web i-1234",
+			expName: command.Import,
+			expArgs: []string{"aws_instance.web", "i-1234"},
+		},
+		{
+			comment:  "atlantis import -d dir -w staging 'aws_instance.web[\"a\"]' i-1234 -- -var-file=prod.tfvars",
+			expName:  command.Import,
+			expDir:   "dir",
+			expWS:    "staging",
+			expArgs:  []string{`aws_instance.web["a"]`, "i-1234"},
+			expFlags: []string{"-var-file=prod.tfvars"},
+		},
+		{
+			comment:    "atlantis state rm -p project aws_instance.web aws_instance.app",
+			expName:    command.StateRm,
+			expProject: "project",
+			expArgs:    []string{"aws_instance.web", "aws_instance.app"},
+		},
+		{
+			comment: "atlantis state mv aws_instance.web aws_instance.app",
+			expName: command.StateMv,
+			expArgs: []string{"aws_instance.web", "aws_instance.app"},
+		},
+		{
+			comment:     "atlantis import aws_instance.web",
+			expResponse: "```\nError: import requires exactly two arguments, ADDRESS and ID, got 1.\nUsage of import:\n",
+		},
+		{
+			comment:     "atlantis state rm -d dir",
+			expResponse: "```\nError: state rm requires at least one ADDRESS argument.\nUsage of state rm:\n",
+		},
+		{
+			comment:     "atlantis state mv aws_instance.web",
+			expResponse: "```\nError: state mv requires exactly two arguments, SOURCE and DESTINATION, got 1.\nUsage of state mv:\n",
+		},
+		{
+			comment:     "atlantis state",
+			expResponse: events.StateUsage,
+		},
+		{
+			comment:     "atlantis state list",
+			expResponse: events.StateUsage,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.comment, func(t *testing.T) {
+			r := commentParser.Parse(c.comment, models.Github)
+			if c.expResponse != "" {
+				Assert(t, strings.HasPrefix(r.CommentResponse, c.expResponse), "expected response to start with %q, got %q", c.expResponse, r.CommentResponse)
+				return
+			}
+			Equals(t, "", r.CommentResponse)
+			Equals(t, c.expName, r.Command.Name)
+			Equals(t, c.expDir, r.Command.RepoRelDir)
+			Equals(t, c.expWS, r.Command.Workspace)
+			Equals(t, c.expProject, r.Command.ProjectName)
+			Equals(t, c.expArgs, r.Command.Args)
+			Equals(t, c.expFlags, r.Command.Flags)
+		})
+	}
+}
+
+func TestParse_Cancel(t *testing.T) {
+	r := commentParser.Parse("atlantis cancel", models.Github)
+	Equals(t, "", r.CommentResponse)
+	Equals(t, command.Cancel, r.Command.Name)
+	Equals(t, "", r.Command.ProjectName)
+
+	r = commentParser.Parse("atlantis cancel -p project", models.Github)
+	Equals(t, "", r.CommentResponse)
+	Equals(t, command.Cancel, r.Command.Name)
+	Equals(t, "project", r.Command.ProjectName)
+
+	r = commentParser.Parse("atlantis cancel -d dir -w staging", models.Github)
+	Equals(t, "", r.CommentResponse)
+	Equals(t, "dir", r.Command.RepoRelDir)
+	Equals(t, "staging", r.Command.Workspace)
+}
+
 var PlanUsage = `Usage of plan:
   -d, --dir string         Which directory to run plan in relative to root of repo,
                            ex. 'child/dir'.
@@ -842,7 +969,9 @@ var ApplyUsage = `Usage of apply:
 `
 
 var ApprovePolicyUsage = `Usage of approve_policies:
-      --verbose   Append Atlantis log to comment.
+      --policy-set string   Approve only this policy set. Refers to the name of the
+                            policy set configured in the server's repos.yaml.
+      --verbose             Append Atlantis log to comment.
 `
 var UnlockUsage = "`Usage of unlock:`\n\n ```cmake\n" +
 	`atlantis unlock	
@@ -33,9 +33,17 @@ type CommitStatusUpdater interface {
 	// UpdateCombinedCount updates the combined status to reflect the
 	// numSuccess out of numTotal.
 	UpdateCombinedCount(repo models.Repo, pull models.PullRequest, status models.CommitStatus, cmdName command.Name, numSuccess int, numTotal int) error
+	// UpdateCombinedPlanCount updates the combined plan status to reflect the
+	// numSuccess out of numTotal and, if changes isn't nil, the number of
+	// resources the plans change.
+	UpdateCombinedPlanCount(repo models.Repo, pull models.PullRequest, status models.CommitStatus, numSuccess int, numTotal int, changes *models.PlanChangeCounts) error
 	// UpdateProject sets the commit status for the project represented by
 	// ctx.
 	UpdateProject(ctx command.ProjectContext, cmdName command.Name, status models.CommitStatus, url string) error
+	// UpdateProjectQueued sets the commit status for the project represented
+	// by ctx to pending with its position in the queue of commands waiting
+	// to run.
+	UpdateProjectQueued(ctx command.ProjectContext, cmdName command.Name, position int, url string) error
 }
 
 // DefaultCommitStatusUpdater implements CommitStatusUpdater.
@@ -76,12 +84,17 @@ func (d *DefaultCommitStatusUpdater) UpdateCombinedCount(repo models.Repo, pull
 	return d.Client.UpdateStatus(repo, pull, status, src, fmt.Sprintf("%d/%d projects %s successfully.", numSuccess, numTotal, cmdVerb), "")
 }
 
-func (d *DefaultCommitStatusUpdater) UpdateProject(ctx command.ProjectContext, cmdName command.Name, status models.CommitStatus, url string) error {
-	projectID := ctx.ProjectName
-	if projectID == "" {
-		projectID = fmt.Sprintf("%s/%s", ctx.RepoRelDir, ctx.Workspace)
+func (d *DefaultCommitStatusUpdater) UpdateCombinedPlanCount(repo models.Repo, pull models.PullRequest, status models.CommitStatus, numSuccess int, numTotal int, changes *models.PlanChangeCounts) error {
+	if changes == nil {
+		return d.UpdateCombinedCount(repo, pull, status, command.Plan, numSuccess, numTotal)
 	}
-	src := fmt.Sprintf("%s/%s: %s", d.StatusName, cmdName.String(), projectID)
+	src := fmt.Sprintf("%s/%s", d.StatusName, command.Plan.String())
+	descrip := fmt.Sprintf("%d/%d projects planned successfully: %s.", numSuccess, numTotal, changes)
+	return d.Client.UpdateStatus(repo, pull, status, src, descrip, "")
+}
+
+func (d *DefaultCommitStatusUpdater) UpdateProject(ctx command.ProjectContext, cmdName command.Name, status models.CommitStatus, url string) error {
+	src := d.projectSrc(ctx, cmdName)
 	var descripWords string
 	switch status {
 	case models.PendingCommitStatus:
@@ -95,3 +108,19 @@ func (d *DefaultCommitStatusUpdater) UpdateProject(ctx command.ProjectContext, c
 	descrip := fmt.Sprintf("%s %s", strings.Title(cmdName.String()), descripWords)
 	return d.Client.UpdateStatus(ctx.BaseRepo, ctx.Pull, status, src, descrip, url)
 }
+
+func (d *DefaultCommitStatusUpdater) UpdateProjectQueued(ctx command.ProjectContext, cmdName command.Name, position int, url string) error {
+	src := d.projectSrc(ctx, cmdName)
+	descrip := fmt.Sprintf("%s queued (position %d)...", strings.Title(cmdName.String()), position)
+	return d.Client.UpdateStatus(ctx.BaseRepo, ctx.Pull, models.PendingCommitStatus, src, descrip, url)
+}
+
+// projectSrc returns the source of the commit status of cmdName for the
+// project represented by ctx.
+func (d *DefaultCommitStatusUpdater) projectSrc(ctx command.ProjectContext, cmdName command.Name) string {
+	projectID := ctx.ProjectName
+	if projectID == "" {
+		projectID = fmt.Sprintf("%s/%s", ctx.RepoRelDir, ctx.Workspace)
+	}
+	return fmt.Sprintf("%s/%s: %s", d.StatusName, cmdName.String(), projectID)
+}
@@ -77,6 +77,50 @@ func TestUpdateCombined(t *testing.T) {
 	}
 }
 
+func TestUpdateCombinedPlanCount(t *testing.T) {
+	cases := []struct {
+		status     models.CommitStatus
+		numSuccess int
+		numTotal   int
+		changes    *models.PlanChangeCounts
+		expDescrip string
+	}{
+		{
+			status:     models.SuccessCommitStatus,
+			numSuccess: 2,
+			numTotal:   2,
+			changes:    nil,
+			expDescrip: "2/2 projects planned successfully.",
+		},
+		{
+			status:     models.SuccessCommitStatus,
+			numSuccess: 2,
+			numTotal:   2,
+			changes:    &models.PlanChangeCounts{Add: 3, Destroy: 1},
+			expDescrip: "2/2 projects planned successfully: 3 to add, 1 to destroy.",
+		},
+		{
+			status:     models.FailedCommitStatus,
+			numSuccess: 1,
+			numTotal:   2,
+			changes:    &models.PlanChangeCounts{},
+			expDescrip: "1/2 projects planned successfully: no changes.",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.expDescrip, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			client := mocks.NewMockClient()
+			s := events.DefaultCommitStatusUpdater{Client: client, StatusName: "atlantis"}
+			err := s.UpdateCombinedPlanCount(models.Repo{}, models.PullRequest{}, c.status, c.numSuccess, c.numTotal, c.changes)
+			Ok(t, err)
+
+			client.VerifyWasCalledOnce().UpdateStatus(models.Repo{}, models.PullRequest{}, c.status, "atlantis/plan", c.expDescrip, "")
+		})
+	}
+}
+
 func TestUpdateCombinedCount(t *testing.T) {
 	cases := []struct {
 		status     models.CommitStatus
@@ -242,6 +286,22 @@ func TestDefaultCommitStatusUpdater_UpdateProject(t *testing.T) {
 	}
 }
 
+// Test that queued commands show their position in the queue.
+func TestDefaultCommitStatusUpdater_UpdateProjectQueued(t *testing.T) {
+	RegisterMockTestingT(t)
+	client := mocks.NewMockClient()
+	s := events.DefaultCommitStatusUpdater{Client: client, StatusName: "atlantis"}
+	err := s.UpdateProjectQueued(command.ProjectContext{
+		RepoRelDir: ".",
+		Workspace:  "default",
+	},
+		command.Apply,
+		3,
+		"url")
+	Ok(t, err)
+	client.VerifyWasCalledOnce().UpdateStatus(models.Repo{}, models.PullRequest{}, models.PendingCommitStatus, "atlantis/apply: ./default", "Apply queued (position 3)...", "url")
+}
+
 // Test that we can set the status name.
 func TestDefaultCommitStatusUpdater_UpdateProjectCustomStatusName(t *testing.T) {
 	RegisterMockTestingT(t)
@@ -0,0 +1,186 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/uber-go/tally"
+)
+
+// ConcurrencyLimiter limits how many plans and applies run at a time across
+// all pull requests, and per repo. Commands over the limits wait in a FIFO
+// queue. A command whose repo is at its limit doesn't hold up the commands of
+// other repos queued behind it. A nil *ConcurrencyLimiter doesn't limit
+// anything.
+type ConcurrencyLimiter struct {
+	// Limit is how many commands can run at a time. 0 means no limit.
+	Limit int
+	// RepoLimit is how many commands of each repo can run at a time. 0 means
+	// no limit.
+	RepoLimit int
+
+	mu      sync.Mutex
+	running int
+	// repoRunning is the number of running commands of each repo.
+	repoRunning map[string]int
+	queue       []*queuedCommand
+
+	queued   tally.Gauge
+	waitTime tally.Timer
+}
+
+type queuedCommand struct {
+	repo string
+	// start is closed when the command can run.
+	start chan struct{}
+	// position is the command's position in the queue, starting at 1.
+	position int
+	// moved is signalled when position changes.
+	moved chan struct{}
+}
+
+// NewConcurrencyLimiter returns a limiter that reports the depth of its queue
+// and how long commands wait in it to scope.
+func NewConcurrencyLimiter(limit int, repoLimit int, scope tally.Scope) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{
+		Limit:       limit,
+		RepoLimit:   repoLimit,
+		repoRunning: make(map[string]int),
+		queued:      scope.Gauge("queued"),
+		waitTime:    scope.Timer("wait_time"),
+	}
+}
+
+// Wait blocks until the command described by ctx can run. If it has to wait,
+// onQueued is called with its position in the queue, starting at 1, and again
+// each time the position changes as commands ahead of it start or are
+// stopped. It returns a function that must be called once the command
+// finishes, or an error if ctx is done before the command's turn comes.
+func (l *ConcurrencyLimiter) Wait(ctx command.ProjectContext, onQueued func(position int)) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	repo := ctx.BaseRepo.FullName
+	start := time.Now()
+
+	cmd := &queuedCommand{repo: repo, start: make(chan struct{}), moved: make(chan struct{}, 1)}
+	l.mu.Lock()
+	l.queue = append(l.queue, cmd)
+	l.startQueuedLocked()
+	position := cmd.position
+	l.mu.Unlock()
+	select {
+	case <-cmd.start:
+		l.waitTime.Record(0)
+		return l.doneFunc(repo), nil
+	default:
+	}
+
+	ctx.Log.Info("waiting for a free slot to run %s, position %d in the queue", ctx.CommandName.String(), position)
+	onQueued(position)
+
+wait:
+	for {
+		select {
+		case <-cmd.start:
+			l.waitTime.Record(time.Since(start))
+			return l.doneFunc(repo), nil
+		case <-cmd.moved:
+			l.mu.Lock()
+			moved := cmd.position
+			l.mu.Unlock()
+			// The command may have started since it moved.
+			if moved != position && moved > 0 {
+				position = moved
+				onQueued(position)
+			}
+		case <-ctx.Context().Done():
+			break wait
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, queued := range l.queue {
+		if queued == cmd {
+			l.queue = append(l.queue[:i], l.queue[i+1:]...)
+			// Commands behind this one may be able to run now, ex. if
+			// this one's repo was at its limit.
+			l.startQueuedLocked()
+			return nil, errors.Wrapf(ctx.Context().Err(), "%s was stopped while waiting in the queue", ctx.CommandName.String())
+		}
+	}
+	// The command's turn came at the same time as it was stopped.
+	return l.doneFunc(repo), nil
+}
+
+// Queued returns the number of commands waiting in the queue.
+func (l *ConcurrencyLimiter) Queued() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.queue)
+}
+
+func (l *ConcurrencyLimiter) canRunLocked(repo string) bool {
+	return (l.Limit <= 0 || l.running < l.Limit) &&
+		(l.RepoLimit <= 0 || l.repoRunning[repo] < l.RepoLimit)
+}
+
+func (l *ConcurrencyLimiter) startLocked(repo string) {
+	l.running++
+	l.repoRunning[repo]++
+}
+
+// startQueuedLocked starts the queued commands that can run, in the order
+// they were queued, and tells the commands that are still queued if their
+// position changed.
+func (l *ConcurrencyLimiter) startQueuedLocked() {
+	remaining := l.queue[:0]
+	for _, cmd := range l.queue {
+		if !l.canRunLocked(cmd.repo) {
+			remaining = append(remaining, cmd)
+			continue
+		}
+		l.startLocked(cmd.repo)
+		cmd.position = 0
+		close(cmd.start)
+	}
+	l.queue = remaining
+	for i, cmd := range l.queue {
+		if cmd.position == i+1 {
+			continue
+		}
+		moved := cmd.position != 0
+		cmd.position = i + 1
+		if moved {
+			select {
+			case cmd.moved <- struct{}{}:
+			default:
+				// The command hasn't read its last move yet and will
+				// read the new position.
+			}
+		}
+	}
+	l.queued.Update(float64(len(l.queue)))
+}
+
+func (l *ConcurrencyLimiter) doneFunc(repo string) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.running--
+			l.repoRunning[repo]--
+			if l.repoRunning[repo] <= 0 {
+				delete(l.repoRunning, repo)
+			}
+			l.startQueuedLocked()
+		})
+	}
+}
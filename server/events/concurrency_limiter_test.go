@@ -0,0 +1,141 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+	"github.com/uber-go/tally"
+)
+
+func TestConcurrencyLimiter_Nil(t *testing.T) {
+	var l *events.ConcurrencyLimiter
+	release, err := l.Wait(limiterCtx(t, "owner/repo"), func(int) { t.Fatal("unexpected queue") })
+	Ok(t, err)
+	release()
+	Equals(t, 0, l.Queued())
+}
+
+func TestConcurrencyLimiter_FIFO(t *testing.T) {
+	scope := tally.NewTestScope("", nil)
+	l := events.NewConcurrencyLimiter(1, 0, scope)
+
+	release, err := l.Wait(limiterCtx(t, "owner/repo"), func(int) { t.Fatal("unexpected queue") })
+	Ok(t, err)
+
+	first := waitAsync(t, l, limiterCtx(t, "owner/repo"))
+	Equals(t, 1, <-first.positions)
+	second := waitAsync(t, l, limiterCtx(t, "owner/other"))
+	Equals(t, 2, <-second.positions)
+	Equals(t, 2, l.Queued())
+	Equals(t, 2.0, scope.Snapshot().Gauges()["queued+"].Value())
+
+	release()
+	firstRelease := <-first.started
+	// The second command is told it moved up.
+	Equals(t, 1, <-second.positions)
+	assertNotStarted(t, second)
+	Equals(t, 1, l.Queued())
+
+	firstRelease()
+	(<-second.started)()
+	Equals(t, 0, l.Queued())
+	Equals(t, 0.0, scope.Snapshot().Gauges()["queued+"].Value())
+	Equals(t, 3, len(scope.Snapshot().Timers()["wait_time+"].Values()))
+}
+
+func TestConcurrencyLimiter_RepoLimit(t *testing.T) {
+	l := events.NewConcurrencyLimiter(2, 1, tally.NoopScope)
+
+	release, err := l.Wait(limiterCtx(t, "owner/repo"), func(int) { t.Fatal("unexpected queue") })
+	Ok(t, err)
+	sameRepo := waitAsync(t, l, limiterCtx(t, "owner/repo"))
+	Equals(t, 1, <-sameRepo.positions)
+
+	// Commands of other repos aren't held up by the queued command.
+	otherRelease, err := l.Wait(limiterCtx(t, "owner/other"), func(int) { t.Fatal("unexpected queue") })
+	Ok(t, err)
+	// The global limit is reached.
+	third := waitAsync(t, l, limiterCtx(t, "owner/third"))
+	Equals(t, 2, <-third.positions)
+
+	otherRelease()
+	(<-third.started)()
+	assertNotStarted(t, sameRepo)
+	// Its position didn't change.
+	Equals(t, 0, len(sameRepo.positions))
+
+	release()
+	(<-sameRepo.started)()
+}
+
+func TestConcurrencyLimiter_CancelQueued(t *testing.T) {
+	l := events.NewConcurrencyLimiter(1, 0, tally.NoopScope)
+	release, err := l.Wait(limiterCtx(t, "owner/repo"), func(int) {})
+	Ok(t, err)
+
+	cmdCtx, cancel := context.WithCancel(context.Background())
+	ctx := limiterCtx(t, "owner/repo")
+	ctx.Ctx = cmdCtx
+	cancelled := waitAsync(t, l, ctx)
+	<-cancelled.positions
+	next := waitAsync(t, l, limiterCtx(t, "owner/repo"))
+	Equals(t, 2, <-next.positions)
+
+	cancel()
+	ErrEquals(t, "plan was stopped while waiting in the queue: context canceled", <-cancelled.errs)
+	Equals(t, 1, <-next.positions)
+	Equals(t, 1, l.Queued())
+
+	release()
+	(<-next.started)()
+	Equals(t, 0, l.Queued())
+}
+
+type limiterWait struct {
+	positions chan int
+	started   chan func()
+	errs      chan error
+}
+
+// waitAsync calls l.Wait with ctx in a goroutine. The positions the command is
+// queued at, the release func once it starts and its error are sent to the
+// returned channels.
+func waitAsync(t *testing.T, l *events.ConcurrencyLimiter, ctx command.ProjectContext) limiterWait {
+	w := limiterWait{
+		positions: make(chan int, 10),
+		started:   make(chan func(), 1),
+		errs:      make(chan error, 1),
+	}
+	go func() {
+		release, err := l.Wait(ctx, func(position int) { w.positions <- position })
+		if err != nil {
+			w.errs <- err
+			return
+		}
+		w.started <- release
+	}()
+	return w
+}
+
+func assertNotStarted(t *testing.T, w limiterWait) {
+	t.Helper()
+	select {
+	case <-w.started:
+		t.Fatal("expected the command to still be queued")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func limiterCtx(t *testing.T, repo string) command.ProjectContext {
+	return command.ProjectContext{
+		CommandName: command.Plan,
+		BaseRepo:    models.Repo{FullName: repo},
+		Log:         logging.NewNoopLogger(t),
+	}
+}
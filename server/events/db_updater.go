@@ -7,7 +7,7 @@ import (
 )
 
 type DBUpdater struct {
-	DB *db.BoltDB
+	DB db.Database
 }
 
 func (c *DBUpdater) updateDB(ctx *command.Context, pull models.PullRequest, results []command.ProjectResult) (models.PullStatus, error) {
@@ -25,3 +25,25 @@ func (c *DBUpdater) updateDB(ctx *command.Context, pull models.PullRequest, resu
 	ctx.Log.Debug("updating DB with pull results")
 	return c.DB.UpdatePullWithResults(pull, filtered)
 }
+
+// discardPlans marks the unapplied plans of the projects that results
+// successfully changed the state of as discarded since they no longer match
+// the state.
+func (c *DBUpdater) discardPlans(ctx *command.Context, results []command.ProjectResult) {
+	if ctx.PullStatus == nil {
+		return
+	}
+	for _, r := range results {
+		if !r.IsSuccessful() {
+			continue
+		}
+		for _, project := range ctx.PullStatus.Projects {
+			if project.RepoRelDir != r.RepoRelDir || project.Workspace != r.Workspace || !project.Status.HasUnappliedPlan() {
+				continue
+			}
+			if err := c.DB.UpdateProjectStatus(ctx.Pull, r.Workspace, r.RepoRelDir, models.DiscardedPlanStatus); err != nil {
+				ctx.Log.Err("unable to discard plan for dir %q workspace %q: %s", r.RepoRelDir, r.Workspace, err)
+			}
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/runatlantis/atlantis/server/core/db"
 	"github.com/runatlantis/atlantis/server/core/locking"
 	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/webhooks"
 	"github.com/runatlantis/atlantis/server/logging"
 )
 
@@ -21,7 +22,9 @@ type DefaultDeleteLockCommand struct {
 	Logger           logging.SimpleLogging
 	WorkingDir       WorkingDir
 	WorkingDirLocker WorkingDirLocker
-	DB               *db.BoltDB
+	DB               db.Database
+	// Webhooks is sent lock_released events. It may be nil.
+	Webhooks WebhooksSender
 }
 
 // DeleteLock handles deleting the lock at id
@@ -35,6 +38,7 @@ func (l *DefaultDeleteLockCommand) DeleteLock(id string) (*models.ProjectLock, e
 	}
 
 	l.deleteWorkingDir(*lock)
+	sendWebhook(l.Webhooks, l.Logger, lockWebhookResult(webhooks.LockReleasedEvent, *lock))
 	return lock, nil
 }
 
@@ -53,6 +57,7 @@ func (l *DefaultDeleteLockCommand) DeleteLocksByPull(repoFullName string, pullNu
 	for i := 0; i < numLocks; i++ {
 		lock := locks[i]
 		l.deleteWorkingDir(lock)
+		sendWebhook(l.Webhooks, l.Logger, lockWebhookResult(webhooks.LockReleasedEvent, lock))
 	}
 
 	return numLocks, nil
@@ -0,0 +1,357 @@
+package events
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-version"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/db"
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	"github.com/runatlantis/atlantis/server/core/terraform"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/vcs"
+	"github.com/runatlantis/atlantis/server/events/webhooks"
+	"github.com/runatlantis/atlantis/server/jobs"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/metrics"
+	"github.com/uber-go/tally"
+)
+
+const (
+	// driftDetectionPullNum is the pull request number drift detection uses
+	// for its working dir and job output since it doesn't run for a pull
+	// request.
+	driftDetectionPullNum = 0
+	// driftDetectionWorkspace is the name of the working dir drift detection
+	// clones the repo into.
+	driftDetectionWorkspace = "drift-detection"
+	// planDriftExitCode is the exit code of terraform plan -detailed-exitcode
+	// when there are changes.
+	planDriftExitCode = 2
+)
+
+//go:generate pegomock generate -m --use-experimental-model-gen --package mocks -o mocks/mock_drift_issue_creator.go DriftIssueCreator
+
+// DriftIssueCreator opens issues for repos whose projects drifted.
+type DriftIssueCreator interface {
+	CreateIssue(repo models.Repo, title string, body string) error
+}
+
+// DriftDetector checks the projects in a repo for drift, meaning changes to
+// the real infrastructure that aren't in the Terraform code, by planning
+// them. It records the result in the DB and notifies users about projects
+// that have drifted since the last check.
+type DriftDetector struct {
+	WorkingDir        WorkingDir
+	WorkingDirLocker  WorkingDirLocker
+	ParserValidator   *config.ParserValidator
+	GlobalCfg         valid.GlobalCfg
+	TerraformExecutor terraform.Client
+	OutputHandler     jobs.ProjectCommandOutputHandler
+	JobURLGenerator   jobs.ProjectJobURLGenerator
+	DB                db.Database
+	Parser            EventParsing
+	VCSClient         vcs.Client
+	// DefaultTFVersion is used for projects that don't set terraform_version.
+	DefaultTFVersion *version.Version
+	// VCSHostTypes maps the hostnames of the configured VCS hosts to their
+	// type so repos can be cloned from their id.
+	VCSHostTypes map[string]models.VCSHostType
+	// IssueCreator opens issues for repos that have create_issue set. It's
+	// nil if GitHub isn't configured.
+	IssueCreator DriftIssueCreator
+	// Webhooks is sent drift_detected events. It may be nil.
+	Webhooks WebhooksSender
+	Logger   logging.SimpleLogging
+	Scope    tally.Scope
+}
+
+// Detect checks the projects in the repo with repoID for drift and saves the
+// result.
+func (d *DriftDetector) Detect(repoID string, cfg valid.DriftDetection) error {
+	scope := d.Scope.SubScope("drift_detection")
+	executionTime := scope.Timer(metrics.ExecutionTimeMetric).Start()
+	defer executionTime.Stop()
+
+	err := d.detect(repoID, cfg, scope)
+	if err != nil {
+		scope.Counter(metrics.ExecutionErrorMetric).Inc(1)
+		return errors.Wrapf(err, "detecting drift in %s", repoID)
+	}
+	scope.Counter(metrics.ExecutionSuccessMetric).Inc(1)
+	return nil
+}
+
+func (d *DriftDetector) detect(repoID string, cfg valid.DriftDetection, scope tally.Scope) error {
+	repo, err := d.repo(repoID)
+	if err != nil {
+		return err
+	}
+	log := d.Logger.WithHistory("repository", repo.FullName, "branch", cfg.Branch)
+
+	unlock, err := d.WorkingDirLocker.TryLock(repo.FullName, driftDetectionPullNum, driftDetectionWorkspace, DefaultRepoRelDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// HeadCommit is set to the branch so that the repo is always cloned
+	// again rather than reusing an old clone.
+	pull := models.PullRequest{
+		Num:        driftDetectionPullNum,
+		BaseRepo:   repo,
+		BaseBranch: cfg.Branch,
+		HeadBranch: cfg.Branch,
+		HeadCommit: cfg.Branch,
+	}
+	repoDir, _, err := d.WorkingDir.Clone(log, repo, pull, driftDetectionWorkspace)
+	if err != nil {
+		return errors.Wrap(err, "cloning repo")
+	}
+	defer func() {
+		if err := d.WorkingDir.DeleteForWorkspace(repo, pull, driftDetectionWorkspace); err != nil {
+			log.Warn("deleting drift detection working dir: %s", err)
+		}
+	}()
+
+	hasRepoCfg, err := d.ParserValidator.HasRepoCfg(repoDir)
+	if err != nil {
+		return err
+	}
+	if !hasRepoCfg {
+		return fmt.Errorf("drift detection requires projects to be defined in an %s file", config.AtlantisYAMLFilename)
+	}
+	repoCfg, err := d.ParserValidator.ParseRepoCfg(repoDir, d.GlobalCfg, repoID)
+	if err != nil {
+		return errors.Wrapf(err, "parsing %s", config.AtlantisYAMLFilename)
+	}
+
+	status := models.DriftStatus{
+		RepoFullName: repo.FullName,
+		Hostname:     repo.VCSHost.Hostname,
+		Branch:       cfg.Branch,
+	}
+	for _, proj := range repoCfg.Projects {
+		status.Projects = append(status.Projects, d.detectProject(log, pull, repoDir, repoID, proj, repoCfg))
+	}
+	status.CheckedAt = time.Now()
+
+	previous, err := d.previousStatus(status)
+	if err != nil {
+		return err
+	}
+	if err := d.DB.SetDriftStatus(status); err != nil {
+		return errors.Wrap(err, "saving drift status")
+	}
+
+	repoScope := scope.Tagged(map[string]string{"repo": repo.FullName})
+	repoScope.Gauge("drifted_projects").Update(float64(len(status.DriftedProjects())))
+	errored := 0
+	for _, p := range status.Projects {
+		if p.Error != "" {
+			errored++
+		}
+	}
+	repoScope.Gauge("errored_projects").Update(float64(errored))
+
+	newlyDrifted := newlyDriftedProjects(previous, status)
+	log.Info("checked %d projects for drift, %d drifted, %d newly drifted", len(status.Projects), len(status.DriftedProjects()), len(newlyDrifted))
+	d.notify(log, repo, cfg, status, newlyDrifted)
+	return nil
+}
+
+// repo returns the repo with repoID, ex. github.com/runatlantis/atlantis.
+func (d *DriftDetector) repo(repoID string) (models.Repo, error) {
+	slash := strings.Index(repoID, "/")
+	if slash == -1 {
+		return models.Repo{}, fmt.Errorf("invalid repo id %q", repoID)
+	}
+	hostname, fullName := repoID[:slash], repoID[slash+1:]
+	vcsHostType, ok := d.VCSHostTypes[hostname]
+	if !ok {
+		return models.Repo{}, fmt.Errorf("no VCS host is configured for %q", hostname)
+	}
+	cloneURL, err := d.VCSClient.GetCloneURL(vcsHostType, fullName)
+	if err != nil {
+		return models.Repo{}, errors.Wrap(err, "getting clone url")
+	}
+	return d.Parser.ParseAPIPlanRequest(vcsHostType, fullName, cloneURL)
+}
+
+// detectProject plans proj in its workspace and returns whether it has
+// drifted. The extra_args of the init and plan steps of the project's plan
+// workflow are used but its other steps, ex. run steps, aren't.
+func (d *DriftDetector) detectProject(log logging.SimpleLogging, pull models.PullRequest, repoDir string, repoID string, proj valid.Project, repoCfg valid.RepoCfg) models.ProjectDriftStatus {
+	status := models.ProjectDriftStatus{
+		ProjectName: proj.GetName(),
+		RepoRelDir:  proj.Dir,
+		Workspace:   proj.Workspace,
+	}
+	ctx := command.ProjectContext{
+		Log:         log.WithHistory("dir", proj.Dir, "workspace", proj.Workspace),
+		Scope:       d.Scope,
+		Pull:        pull,
+		BaseRepo:    pull.BaseRepo,
+		HeadRepo:    pull.BaseRepo,
+		ProjectName: proj.GetName(),
+		RepoRelDir:  proj.Dir,
+		Workspace:   proj.Workspace,
+		JobID:       uuid.New().String(),
+	}
+
+	// The output of the previous check is kept until this one starts so
+	// that it can be viewed from the job url.
+	d.OutputHandler.CleanUp(jobs.PullInfo{
+		PullNum:     pull.Num,
+		Repo:        pull.BaseRepo.Name,
+		ProjectName: ctx.ProjectName,
+		Workspace:   ctx.Workspace,
+	})
+	defer d.OutputHandler.Send(ctx, "", true)
+	if d.JobURLGenerator != nil {
+		if url, err := d.JobURLGenerator.GenerateProjectJobURL(ctx); err == nil {
+			status.JobURL = url
+		}
+	}
+
+	mergedCfg := d.GlobalCfg.MergeProjectCfg(log, repoID, proj, repoCfg)
+	var initExtraArgs, planExtraArgs []string
+	for _, step := range mergedCfg.Workflow.Plan.Steps {
+		switch step.StepName {
+		case "init":
+			initExtraArgs = append(initExtraArgs, step.ExtraArgs...)
+		case "plan":
+			planExtraArgs = append(planExtraArgs, step.ExtraArgs...)
+		}
+	}
+	tfVersion := d.DefaultTFVersion
+	if proj.TerraformVersion != nil {
+		tfVersion = proj.TerraformVersion
+	}
+
+	absPath := filepath.Join(repoDir, proj.Dir)
+	initCmd := append([]string{"init", "-input=false"}, initExtraArgs...)
+	if _, err := d.TerraformExecutor.RunCommandWithVersion(ctx, absPath, initCmd, nil, tfVersion, proj.Workspace); err != nil {
+		status.Error = errors.Wrap(err, "running init").Error()
+		return status
+	}
+	if err := runtime.SwitchWorkspace(d.TerraformExecutor, ctx, absPath, tfVersion, nil); err != nil {
+		status.Error = errors.Wrap(err, "selecting workspace").Error()
+		return status
+	}
+	// The plan doesn't lock the state so that it can't block applies.
+	planCmd := append([]string{"plan", "-input=false", "-lock=false", "-detailed-exitcode"}, planExtraArgs...)
+	// Like the plan step, include env/{workspace}.tfvars if it exists.
+	envFile := filepath.Join(absPath, "env", proj.Workspace+".tfvars")
+	if _, err := os.Stat(envFile); err == nil {
+		planCmd = append(planCmd, "-var-file", envFile)
+	}
+	out, err := d.TerraformExecutor.RunCommandWithVersion(ctx, absPath, planCmd, nil, tfVersion, proj.Workspace)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != planDriftExitCode {
+			status.Error = errors.Wrap(err, "running plan").Error()
+			return status
+		}
+		status.Drifted = true
+	}
+	status.Summary = (&models.PlanSuccess{TerraformOutput: out}).Summary()
+	return status
+}
+
+// previousStatus returns the last drift status for status's repo or nil if
+// it hasn't been checked before.
+func (d *DriftDetector) previousStatus(status models.DriftStatus) (*models.DriftStatus, error) {
+	statuses, err := d.DB.ListDriftStatuses()
+	if err != nil {
+		return nil, errors.Wrap(err, "listing drift statuses")
+	}
+	for i, s := range statuses {
+		if s.RepoFullName == status.RepoFullName && s.Hostname == status.Hostname {
+			return &statuses[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// newlyDriftedProjects returns the projects that drifted in current but
+// hadn't drifted in previous so that users are only notified once.
+func newlyDriftedProjects(previous *models.DriftStatus, current models.DriftStatus) []models.ProjectDriftStatus {
+	previouslyDrifted := make(map[string]bool)
+	if previous != nil {
+		for _, p := range previous.DriftedProjects() {
+			previouslyDrifted[driftProjectKey(p)] = true
+		}
+	}
+	var drifted []models.ProjectDriftStatus
+	for _, p := range current.DriftedProjects() {
+		if !previouslyDrifted[driftProjectKey(p)] {
+			drifted = append(drifted, p)
+		}
+	}
+	return drifted
+}
+
+func driftProjectKey(p models.ProjectDriftStatus) string {
+	return fmt.Sprintf("%s/%s/%s", p.ProjectName, p.RepoRelDir, p.Workspace)
+}
+
+// notify sends drift_detected webhooks for the newly drifted projects and
+// opens an issue for them if configured.
+func (d *DriftDetector) notify(log logging.SimpleLogging, repo models.Repo, cfg valid.DriftDetection, status models.DriftStatus, newlyDrifted []models.ProjectDriftStatus) {
+	if len(newlyDrifted) == 0 {
+		return
+	}
+	for _, p := range newlyDrifted {
+		sendWebhook(d.Webhooks, log, webhooks.EventResult{
+			Event:       webhooks.DriftDetectedEvent,
+			Repo:        repo,
+			Workspace:   p.Workspace,
+			Directory:   p.RepoRelDir,
+			ProjectName: p.ProjectName,
+			Status:      webhooks.DriftedStatus,
+			JobURL:      p.JobURL,
+		})
+	}
+
+	if !cfg.CreateIssue {
+		return
+	}
+	if repo.VCSHost.Type != models.Github || d.IssueCreator == nil {
+		log.Warn("not opening an issue for drift: issues are only supported on GitHub")
+		return
+	}
+	title := fmt.Sprintf("Atlantis detected drift in %s", status.Branch)
+	if err := d.IssueCreator.CreateIssue(repo, title, renderDriftIssue(status, newlyDrifted)); err != nil {
+		log.Err("opening issue for drift: %s", err)
+	}
+}
+
+func renderDriftIssue(status models.DriftStatus, drifted []models.ProjectDriftStatus) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Atlantis found changes when planning these projects on `%s`, which means the infrastructure no longer matches the code:\n\n", status.Branch)
+	for _, p := range drifted {
+		fmt.Fprintf(&b, "- dir: `%s` workspace: `%s`", p.RepoRelDir, p.Workspace)
+		if p.ProjectName != "" {
+			fmt.Fprintf(&b, " project: `%s`", p.ProjectName)
+		}
+		if p.Summary != "" {
+			fmt.Fprintf(&b, ": %s", strings.TrimSpace(p.Summary))
+		}
+		if p.JobURL != "" {
+			fmt.Fprintf(&b, " ([output](%s))", p.JobURL)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\nApply the code again or update it to match the changes.")
+	return b.String()
+}
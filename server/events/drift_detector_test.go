@@ -0,0 +1,209 @@
+package events_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	. "github.com/petergtz/pegomock"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/db"
+	tfmocks "github.com/runatlantis/atlantis/server/core/terraform/mocks"
+	tfmatchers "github.com/runatlantis/atlantis/server/core/terraform/mocks/matchers"
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/mocks"
+	"github.com/runatlantis/atlantis/server/events/mocks/matchers"
+	"github.com/runatlantis/atlantis/server/events/models"
+	vcsmocks "github.com/runatlantis/atlantis/server/events/vcs/mocks"
+	"github.com/runatlantis/atlantis/server/events/webhooks"
+	"github.com/runatlantis/atlantis/server/jobs"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+	"github.com/uber-go/tally"
+)
+
+func TestDriftDetector_Detect(t *testing.T) {
+	RegisterMockTestingT(t)
+	repoDir := t.TempDir()
+	Ok(t, os.WriteFile(filepath.Join(repoDir, "atlantis.yaml"), []byte(`version: 3
+projects:
+- dir: drifted
+- dir: unchanged
+  workspace: staging
+`), 0600))
+	dataDir := t.TempDir()
+	database, err := db.New(dataDir)
+	Ok(t, err)
+
+	repo := models.Repo{
+		FullName: "owner/repo",
+		Owner:    "owner",
+		Name:     "repo",
+		VCSHost:  models.VCSHost{Hostname: "github.com", Type: models.Github},
+	}
+	vcsClient := vcsmocks.NewMockClient()
+	When(vcsClient.GetCloneURL(models.Github, "owner/repo")).ThenReturn("https://github.com/owner/repo.git", nil)
+	parser := mocks.NewMockEventParsing()
+	When(parser.ParseAPIPlanRequest(models.Github, "owner/repo", "https://github.com/owner/repo.git")).ThenReturn(repo, nil)
+	workingDir := mocks.NewMockWorkingDir()
+	When(workingDir.Clone(matchers.AnyPtrToLoggingSimpleLogger(), matchers.AnyModelsRepo(), matchers.AnyModelsPullRequest(), AnyString())).ThenReturn(repoDir, false, nil)
+
+	// Plan exits with 2 when there are changes.
+	driftErr := exec.Command("sh", "-c", "exit 2").Run()
+	tfClient := tfmocks.NewMockClient()
+	When(tfClient.RunCommandWithVersion(matchers.AnyModelsProjectCommandContext(), AnyString(), tfmatchers.AnySliceOfString(), tfmatchers.AnyMapOfStringToString(), tfmatchers.AnyPtrToGoVersionVersion(), AnyString())).
+		Then(func(params []Param) ReturnValues {
+			path, args := params[1].(string), params[2].([]string)
+			if args[0] == "plan" && path == filepath.Join(repoDir, "drifted") {
+				return ReturnValues{"Plan: 1 to add, 0 to change, 0 to destroy.", errors.Wrap(driftErr, "running terraform plan")}
+			}
+			if args[0] == "plan" {
+				return ReturnValues{"No changes. Your infrastructure matches the configuration.", nil}
+			}
+			return ReturnValues{"", nil}
+		})
+	issueCreator := mocks.NewMockDriftIssueCreator()
+	webhooksSender := mocks.NewMockWebhooksSender()
+
+	detector := &events.DriftDetector{
+		WorkingDir:        workingDir,
+		WorkingDirLocker:  events.NewDefaultWorkingDirLocker(),
+		ParserValidator:   &config.ParserValidator{},
+		GlobalCfg:         valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{AllowRepoCfg: true}),
+		TerraformExecutor: tfClient,
+		DefaultTFVersion:  version.Must(version.NewVersion("1.1.0")),
+		OutputHandler:     &jobs.NoopProjectOutputHandler{},
+		DB:                database,
+		Parser:            parser,
+		VCSClient:         vcsClient,
+		VCSHostTypes:      map[string]models.VCSHostType{"github.com": models.Github},
+		IssueCreator:      issueCreator,
+		Webhooks:          webhooksSender,
+		Logger:            logging.NewNoopLogger(t),
+		Scope:             tally.NewTestScope("", nil),
+	}
+	cfg := valid.DriftDetection{Branch: "main", CreateIssue: true}
+	Ok(t, detector.Detect("github.com/owner/repo", cfg))
+
+	statuses, err := database.ListDriftStatuses()
+	Ok(t, err)
+	Equals(t, 1, len(statuses))
+	Equals(t, "owner/repo", statuses[0].RepoFullName)
+	Equals(t, "main", statuses[0].Branch)
+	Equals(t, []models.ProjectDriftStatus{
+		{RepoRelDir: "drifted", Workspace: "default", Drifted: true, Summary: "Plan: 1 to add, 0 to change, 0 to destroy."},
+		{RepoRelDir: "unchanged", Workspace: "staging", Summary: "No changes. Your infrastructure matches the configuration."},
+	}, statuses[0].Projects)
+
+	_, _, pull, _ := workingDir.VerifyWasCalledOnce().Clone(matchers.AnyPtrToLoggingSimpleLogger(), matchers.AnyModelsRepo(), matchers.AnyModelsPullRequest(), AnyString()).GetCapturedArguments()
+	Equals(t, "main", pull.HeadBranch)
+	workingDir.VerifyWasCalledOnce().DeleteForWorkspace(repo, pull, "drift-detection")
+	issueCreator.VerifyWasCalledOnce().CreateIssue(matchers.EqModelsRepo(repo), EqString("Atlantis detected drift in main"), AnyString())
+	webhooksSender.VerifyWasCalledOnce().Send(matchers.AnyLoggingSimpleLogging(), matchers.EqWebhooksEventResult(webhooks.EventResult{
+		Event:     webhooks.DriftDetectedEvent,
+		Repo:      repo,
+		Workspace: "default",
+		Directory: "drifted",
+		Status:    webhooks.DriftedStatus,
+	}))
+
+	// Projects that are still drifted don't notify again.
+	Ok(t, detector.Detect("github.com/owner/repo", cfg))
+	issueCreator.VerifyWasCalledOnce().CreateIssue(matchers.EqModelsRepo(repo), AnyString(), AnyString())
+	webhooksSender.VerifyWasCalledOnce().Send(matchers.AnyLoggingSimpleLogging(), matchers.AnyWebhooksEventResult())
+}
+
+// Projects are planned in their workspace with their plan workflow's
+// extra_args.
+func TestDriftDetector_DetectWorkspace(t *testing.T) {
+	RegisterMockTestingT(t)
+	repoDir := t.TempDir()
+	Ok(t, os.WriteFile(filepath.Join(repoDir, "atlantis.yaml"), []byte(`version: 3
+projects:
+- dir: .
+  workspace: staging
+  workflow: custom
+workflows:
+  custom:
+    plan:
+      steps:
+      - init:
+          extra_args: ["-upgrade"]
+      - plan:
+          extra_args: ["-parallelism=5"]
+`), 0600))
+	Ok(t, os.Mkdir(filepath.Join(repoDir, "env"), 0700))
+	envFile := filepath.Join(repoDir, "env", "staging.tfvars")
+	Ok(t, os.WriteFile(envFile, nil, 0600))
+	database, err := db.New(t.TempDir())
+	Ok(t, err)
+
+	repo := models.Repo{
+		FullName: "owner/repo",
+		Owner:    "owner",
+		Name:     "repo",
+		VCSHost:  models.VCSHost{Hostname: "github.com", Type: models.Github},
+	}
+	vcsClient := vcsmocks.NewMockClient()
+	When(vcsClient.GetCloneURL(models.Github, "owner/repo")).ThenReturn("https://github.com/owner/repo.git", nil)
+	parser := mocks.NewMockEventParsing()
+	When(parser.ParseAPIPlanRequest(models.Github, "owner/repo", "https://github.com/owner/repo.git")).ThenReturn(repo, nil)
+	workingDir := mocks.NewMockWorkingDir()
+	When(workingDir.Clone(matchers.AnyPtrToLoggingSimpleLogger(), matchers.AnyModelsRepo(), matchers.AnyModelsPullRequest(), AnyString())).ThenReturn(repoDir, false, nil)
+
+	var cmds [][]string
+	tfClient := tfmocks.NewMockClient()
+	When(tfClient.RunCommandWithVersion(matchers.AnyModelsProjectCommandContext(), AnyString(), tfmatchers.AnySliceOfString(), tfmatchers.AnyMapOfStringToString(), tfmatchers.AnyPtrToGoVersionVersion(), AnyString())).
+		Then(func(params []Param) ReturnValues {
+			args := params[2].([]string)
+			cmds = append(cmds, args)
+			if args[0] == "workspace" && args[1] == "show" {
+				return ReturnValues{"default\n", nil}
+			}
+			return ReturnValues{"No changes. Your infrastructure matches the configuration.", nil}
+		})
+
+	detector := &events.DriftDetector{
+		WorkingDir:        workingDir,
+		WorkingDirLocker:  events.NewDefaultWorkingDirLocker(),
+		ParserValidator:   &config.ParserValidator{},
+		GlobalCfg:         valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{AllowRepoCfg: true}),
+		TerraformExecutor: tfClient,
+		DefaultTFVersion:  version.Must(version.NewVersion("1.1.0")),
+		OutputHandler:     &jobs.NoopProjectOutputHandler{},
+		DB:                database,
+		Parser:            parser,
+		VCSClient:         vcsClient,
+		VCSHostTypes:      map[string]models.VCSHostType{"github.com": models.Github},
+		Logger:            logging.NewNoopLogger(t),
+		Scope:             tally.NewTestScope("", nil),
+	}
+	Ok(t, detector.Detect("github.com/owner/repo", valid.DriftDetection{Branch: "main"}))
+
+	Equals(t, [][]string{
+		{"init", "-input=false", "-upgrade"},
+		{"workspace", "show"},
+		{"workspace", "select", "staging"},
+		{"plan", "-input=false", "-lock=false", "-detailed-exitcode", "-parallelism=5", "-var-file", envFile},
+	}, cmds)
+	statuses, err := database.ListDriftStatuses()
+	Ok(t, err)
+	Equals(t, []models.ProjectDriftStatus{
+		{RepoRelDir: ".", Workspace: "staging", Summary: "No changes. Your infrastructure matches the configuration."},
+	}, statuses[0].Projects)
+}
+
+func TestDriftDetector_UnknownHost(t *testing.T) {
+	RegisterMockTestingT(t)
+	detector := &events.DriftDetector{
+		VCSHostTypes: map[string]models.VCSHostType{"github.com": models.Github},
+		Logger:       logging.NewNoopLogger(t),
+		Scope:        tally.NewTestScope("", nil),
+	}
+	err := detector.Detect("gitlab.com/owner/repo", valid.DriftDetection{Branch: "main"})
+	ErrEquals(t, `detecting drift in gitlab.com/owner/repo: no VCS host is configured for "gitlab.com"`, err)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/vcs/bitbucketcloud"
 	"github.com/runatlantis/atlantis/server/events/vcs/bitbucketserver"
+	"github.com/runatlantis/atlantis/server/events/vcs/gitea"
 	"github.com/xanzy/go-gitlab"
 	"gopkg.in/go-playground/validator.v9"
 )
@@ -103,6 +104,12 @@ type CommentCommand struct {
 	// project specified in an atlantis.yaml file.
 	// If empty then the comment specified no project.
 	ProjectName string
+	// PolicySet is the name of the policy set to approve when running
+	// approve_policies. If empty then the comment specified no policy set.
+	PolicySet string
+	// Args are the positional arguments of import and state commands,
+	// ex. the address and ID in atlantis import ADDRESS ID.
+	Args []string
 }
 
 // IsForSpecificProject returns true if the command is for a specific dir, workspace
@@ -281,6 +288,36 @@ type EventParsing interface {
 	// ParseAzureDevopsRepo parses the response from the Azure DevOps API endpoint that
 	// returns a repo into the Atlantis model.
 	ParseAzureDevopsRepo(adRepo *azuredevops.GitRepository) (models.Repo, error)
+
+	// ParseGiteaPullEvent parses Gitea pull request events.
+	// pull is the parsed pull request.
+	// pullEventType is the type of event, for example opened/closed.
+	// baseRepo is the repo the pull request will be merged into.
+	// headRepo is the repo the pull request branch is from.
+	// user is the user that triggered the event.
+	ParseGiteaPullEvent(event gitea.PullRequestEvent) (
+		pull models.PullRequest, pullEventType models.PullRequestEventType,
+		baseRepo models.Repo, headRepo models.Repo, user models.User, err error)
+
+	// ParseGiteaIssueCommentEvent parses Gitea pull request comment events.
+	// baseRepo is the repo that the pull request will be merged into.
+	// user is the user that left the comment.
+	// pullNum is the number of the pull request that triggered the webhook.
+	// comment is the comment that triggered the event.
+	ParseGiteaIssueCommentEvent(event gitea.IssueCommentEvent) (
+		baseRepo models.Repo, user models.User, pullNum int, comment string, err error)
+
+	// ParseGiteaPull parses the response from the Gitea API endpoint (not
+	// from a webhook) that returns a pull request.
+	// pull is the parsed pull request.
+	// baseRepo is the repo the pull request will be merged into.
+	// headRepo is the repo the pull request branch is from.
+	ParseGiteaPull(giteaPull *gitea.PullRequest) (
+		pull models.PullRequest, baseRepo models.Repo, headRepo models.Repo, err error)
+
+	// ParseAPIPlanRequest parses a plan or apply request made to the API
+	// into the repo that the command should be run against.
+	ParseAPIPlanRequest(vcsHostType models.VCSHostType, repoFullName string, cloneURL string) (models.Repo, error)
 }
 
 // EventParser parses VCS events.
@@ -295,6 +332,8 @@ type EventParser struct {
 	BitbucketServerURL string
 	AzureDevopsToken   string
 	AzureDevopsUser    string
+	GiteaUser          string
+	GiteaToken         string
 }
 
 // GetBitbucketCloudPullEventType returns the type of the pull request
@@ -538,6 +577,22 @@ func (e *EventParser) ParseGithubRepo(ghRepo *github.Repository) (models.Repo, e
 	return models.NewRepo(models.Github, ghRepo.GetFullName(), ghRepo.GetCloneURL(), e.GithubUser, e.GithubToken)
 }
 
+// ParseAPIPlanRequest parses a plan or apply request made to the API into
+// the repo that the command should be run against using the credentials
+// configured for vcsHostType.
+// See EventParsing for return value docs.
+func (e *EventParser) ParseAPIPlanRequest(vcsHostType models.VCSHostType, repoFullName string, cloneURL string) (models.Repo, error) {
+	switch vcsHostType {
+	case models.Github:
+		return models.NewRepo(vcsHostType, repoFullName, cloneURL, e.GithubUser, e.GithubToken)
+	case models.Gitlab:
+		return models.NewRepo(vcsHostType, repoFullName, cloneURL, e.GitlabUser, e.GitlabToken)
+	case models.Gitea:
+		return models.NewRepo(vcsHostType, repoFullName, cloneURL, e.GiteaUser, e.GiteaToken)
+	}
+	return models.Repo{}, fmt.Errorf("not implemented")
+}
+
 // ParseGitlabMergeRequestEvent parses GitLab merge request events.
 // pull is the parsed merge request.
 // See EventParsing for return value docs.
@@ -890,3 +945,85 @@ func (e *EventParser) ParseAzureDevopsRepo(adRepo *azuredevops.GitRepository) (m
 	fullName := fmt.Sprintf("%s/%s/%s", owner, project, repo)
 	return models.NewRepo(models.AzureDevops, fullName, cloneURL, e.AzureDevopsUser, e.AzureDevopsToken)
 }
+
+// ParseGiteaPullEvent parses Gitea pull request events.
+// See EventParsing for return value docs.
+func (e *EventParser) ParseGiteaPullEvent(event gitea.PullRequestEvent) (pull models.PullRequest, pullEventType models.PullRequestEventType, baseRepo models.Repo, headRepo models.Repo, user models.User, err error) {
+	if err = validator.New().Struct(event); err != nil {
+		err = errors.Wrap(err, "event was missing fields")
+		return
+	}
+	pull, baseRepo, headRepo, err = e.ParseGiteaPull(event.PullRequest)
+	if err != nil {
+		return
+	}
+
+	switch *event.Action {
+	case gitea.PullOpenedAction, gitea.PullReopenedAction:
+		pullEventType = models.OpenedPullEvent
+	case gitea.PullSynchronizedAction:
+		pullEventType = models.UpdatedPullEvent
+	case gitea.PullClosedAction:
+		pullEventType = models.ClosedPullEvent
+	default:
+		pullEventType = models.OtherPullEvent
+	}
+	user = models.User{Username: *event.Sender.Login}
+	return
+}
+
+// ParseGiteaIssueCommentEvent parses Gitea pull request comment events.
+// See EventParsing for return value docs.
+func (e *EventParser) ParseGiteaIssueCommentEvent(event gitea.IssueCommentEvent) (baseRepo models.Repo, user models.User, pullNum int, comment string, err error) {
+	if err = validator.New().Struct(event); err != nil {
+		err = errors.Wrap(err, "event was missing fields")
+		return
+	}
+	baseRepo, err = e.parseGiteaRepo(event.Repository)
+	if err != nil {
+		return
+	}
+	user = models.User{Username: *event.Sender.Login}
+	pullNum = *event.Issue.Number
+	comment = *event.Comment.Body
+	return
+}
+
+// ParseGiteaPull parses the response from the Gitea API endpoint (not
+// from a webhook) that returns a pull request.
+// See EventParsing for return value docs.
+func (e *EventParser) ParseGiteaPull(pull *gitea.PullRequest) (pullModel models.PullRequest, baseRepo models.Repo, headRepo models.Repo, err error) {
+	if err = validator.New().Struct(pull); err != nil {
+		err = errors.Wrap(err, "pull request was missing fields")
+		return
+	}
+	baseRepo, err = e.parseGiteaRepo(pull.Base.Repository)
+	if err != nil {
+		return
+	}
+	headRepo, err = e.parseGiteaRepo(pull.Head.Repository)
+	if err != nil {
+		return
+	}
+
+	pullState := models.ClosedPullState
+	if *pull.State == "open" {
+		pullState = models.OpenPullState
+	}
+
+	pullModel = models.PullRequest{
+		Author:     *pull.User.Login,
+		HeadBranch: *pull.Head.Ref,
+		HeadCommit: *pull.Head.SHA,
+		URL:        *pull.HTMLURL,
+		Num:        *pull.Number,
+		State:      pullState,
+		BaseRepo:   baseRepo,
+		BaseBranch: *pull.Base.Ref,
+	}
+	return
+}
+
+func (e *EventParser) parseGiteaRepo(repo *gitea.Repository) (models.Repo, error) {
+	return models.NewRepo(models.Gitea, *repo.FullName, *repo.CloneURL, e.GiteaUser, e.GiteaToken)
+}
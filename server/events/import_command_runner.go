@@ -0,0 +1,35 @@
+package events
+
+import (
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/vcs"
+)
+
+func NewImportCommandRunner(
+	pullUpdater *PullUpdater,
+	dbUpdater *DBUpdater,
+	prjCmdBuilder ProjectImportCommandBuilder,
+	prjCmdRunner ProjectImportCommandRunner,
+	pullReqStatusFetcher vcs.PullReqStatusFetcher,
+) *ImportCommandRunner {
+	return &ImportCommandRunner{
+		pullUpdater:          pullUpdater,
+		dbUpdater:            dbUpdater,
+		prjCmdBuilder:        prjCmdBuilder,
+		prjCmdRunner:         prjCmdRunner,
+		pullReqStatusFetcher: pullReqStatusFetcher,
+	}
+}
+
+// ImportCommandRunner runs atlantis import.
+type ImportCommandRunner struct {
+	pullUpdater          *PullUpdater
+	dbUpdater            *DBUpdater
+	prjCmdBuilder        ProjectImportCommandBuilder
+	prjCmdRunner         ProjectImportCommandRunner
+	pullReqStatusFetcher vcs.PullReqStatusFetcher
+}
+
+func (i *ImportCommandRunner) Run(ctx *command.Context, cmd *CommentCommand) {
+	runStateChangeCmd(ctx, cmd, i.pullUpdater, i.dbUpdater, i.pullReqStatusFetcher, i.prjCmdBuilder.BuildImportCommands, i.prjCmdRunner.Import)
+}
@@ -30,6 +30,9 @@ var (
 	policyCheckCommandTitle     = command.PolicyCheck.TitleString()
 	approvePoliciesCommandTitle = command.ApprovePolicies.TitleString()
 	versionCommandTitle         = command.Version.TitleString()
+	importCommandTitle          = command.Import.TitleString()
+	stateRmCommandTitle         = command.StateRm.TitleString()
+	stateMvCommandTitle         = command.StateMv.TitleString()
 	// maxUnwrappedLines is the maximum number of lines the Terraform output
 	// can be before we wrap it in an expandable template.
 	maxUnwrappedLines = 12
@@ -96,6 +99,30 @@ type projectResultTmplData struct {
 	RepoRelDir  string
 	ProjectName string
 	Rendered    string
+	// PlanChanges is the number of resources the project's plan changes. It's
+	// nil if the project wasn't planned or the plan couldn't be analyzed.
+	PlanChanges *models.PlanChangeCounts
+}
+
+// HasPlanChanges returns true if the plan of any of the results was analyzed.
+func (r resultData) HasPlanChanges() bool {
+	for _, result := range r.Results {
+		if result.PlanChanges != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// TotalPlanChanges returns the sum of the resource changes of all the plans.
+func (r resultData) TotalPlanChanges() models.PlanChangeCounts {
+	var total models.PlanChangeCounts
+	for _, result := range r.Results {
+		if result.PlanChanges != nil {
+			total = total.Plus(*result.PlanChanges)
+		}
+	}
+	return total
 }
 
 // Render formats the data into a markdown string.
@@ -159,6 +186,7 @@ func (m *MarkdownRenderer) renderProjectResults(results []command.ProjectResult,
 			} else {
 				resultData.Rendered = m.renderTemplate(planSuccessUnwrappedTmpl, planSuccessData{PlanSuccess: *result.PlanSuccess, PlanWasDeleted: common.PlansDeleted, DisableApply: common.DisableApply, DisableRepoLocking: common.DisableRepoLocking, EnableDiffMarkdownFormat: common.EnableDiffMarkdownFormat})
 			}
+			resultData.PlanChanges = result.PlanChangeCounts()
 			numPlanSuccesses++
 		} else if result.PolicyCheckSuccess != nil {
 			if m.shouldUseWrappedTmpl(vcsHost, result.PolicyCheckSuccess.PolicyCheckOutput) {
@@ -180,9 +208,16 @@ func (m *MarkdownRenderer) renderProjectResults(results []command.ProjectResult,
 				resultData.Rendered = m.renderTemplate(versionUnwrappedSuccessTmpl, struct{ Output string }{result.VersionSuccess})
 			}
 			numVersionSuccesses++
+		} else if result.ImportSuccess != nil {
+			resultData.Rendered = m.renderStateChange(vcsHost, result.ImportSuccess.Output, result.ImportSuccess.RePlanCmd)
+		} else if result.StateSuccess != nil {
+			resultData.Rendered = m.renderStateChange(vcsHost, result.StateSuccess.Output, result.StateSuccess.RePlanCmd)
 		} else {
 			resultData.Rendered = "Found no template. This is a bug!"
 		}
+		if len(result.PolicySetResults) > 0 {
+			resultData.Rendered = strings.TrimSpace(m.renderTemplate(policySetResultsTmpl, result.PolicySetResults)) + "\n\n" + resultData.Rendered
+		}
 		resultsTmplData = append(resultsTmplData, resultData)
 	}
 
@@ -200,14 +235,20 @@ func (m *MarkdownRenderer) renderProjectResults(results []command.ProjectResult,
 		tmpl = singleProjectVersionSuccessTmpl
 	case len(resultsTmplData) == 1 && common.Command == versionCommandTitle && numVersionSuccesses == 0:
 		tmpl = singleProjectVersionUnsuccessfulTmpl
-	case len(resultsTmplData) == 1 && common.Command == applyCommandTitle:
+	case len(resultsTmplData) == 1 && common.Command == applyCommandTitle,
+		len(resultsTmplData) == 1 && common.Command == importCommandTitle,
+		len(resultsTmplData) == 1 && common.Command == stateRmCommandTitle,
+		len(resultsTmplData) == 1 && common.Command == stateMvCommandTitle:
 		tmpl = singleProjectApplyTmpl
 	case common.Command == planCommandTitle,
 		common.Command == policyCheckCommandTitle:
 		tmpl = multiProjectPlanTmpl
 	case common.Command == approvePoliciesCommandTitle:
 		tmpl = approveAllProjectsTmpl
-	case common.Command == applyCommandTitle:
+	case common.Command == applyCommandTitle,
+		common.Command == importCommandTitle,
+		common.Command == stateRmCommandTitle,
+		common.Command == stateMvCommandTitle:
 		tmpl = multiProjectApplyTmpl
 	case common.Command == versionCommandTitle:
 		tmpl = multiProjectVersionTmpl
@@ -217,6 +258,19 @@ func (m *MarkdownRenderer) renderProjectResults(results []command.ProjectResult,
 	return m.renderTemplate(tmpl, resultData{resultsTmplData, common})
 }
 
+// renderStateChange renders the output of an import or state command that
+// changed the state of a project.
+func (m *MarkdownRenderer) renderStateChange(vcsHost models.VCSHostType, output string, rePlanCmd string) string {
+	tmpl := stateChangeUnwrappedSuccessTmpl
+	if m.shouldUseWrappedTmpl(vcsHost, output) {
+		tmpl = stateChangeWrappedSuccessTmpl
+	}
+	return m.renderTemplate(tmpl, struct {
+		Output    string
+		RePlanCmd string
+	}{output, rePlanCmd})
+}
+
 // shouldUseWrappedTmpl returns true if we should use the wrapped markdown
 // templates that collapse the output to make the comment smaller on initial
 // load. Some VCS providers or versions of VCS providers don't support this
@@ -271,9 +325,10 @@ var approveAllProjectsTmpl = template.Must(template.New("").Funcs(sprig.TxtFuncM
 		"{{end}}\n" + logTmpl))
 var multiProjectPlanTmpl = template.Must(template.New("").Funcs(sprig.TxtFuncMap()).Parse(
 	"Ran {{.Command}} for {{ len .Results }} projects:\n\n" +
+		"{{ if .HasPlanChanges }}" + planOverviewTmpl + "{{ else }}" +
 		"{{ range $result := .Results }}" +
 		"1. {{ if $result.ProjectName }}project: `{{$result.ProjectName}}` {{ end }}dir: `{{$result.RepoRelDir}}` workspace: `{{$result.Workspace}}`\n" +
-		"{{end}}\n" +
+		"{{end}}{{end}}\n" +
 		"{{ $disableApplyAll := .DisableApplyAll }}{{ range $i, $result := .Results }}" +
 		"### {{add $i 1}}. {{ if $result.ProjectName }}project: `{{$result.ProjectName}}` {{ end }}dir: `{{$result.RepoRelDir}}` workspace: `{{$result.Workspace}}`\n" +
 		"{{$result.Rendered}}\n\n" +
@@ -283,6 +338,18 @@ var multiProjectPlanTmpl = template.Must(template.New("").Funcs(sprig.TxtFuncMap
 		"    * `atlantis unlock`" +
 		"{{end}}{{end}}" +
 		logTmpl))
+
+// planOverviewTmpl is a table of the number of resources each project's plan
+// changes so reviewers can triage many projects without unfolding each plan.
+// Projects whose plans failed or couldn't be analyzed have no counts.
+var planOverviewTmpl = "| Project | Add | Change | Destroy | Import |\n" +
+	"|---------|-----|--------|---------|--------|\n" +
+	"{{ range $result := .Results }}" +
+	"| {{ if $result.ProjectName }}project: `{{$result.ProjectName}}` {{ end }}dir: `{{$result.RepoRelDir}}` workspace: `{{$result.Workspace}}` | " +
+	"{{ with $result.PlanChanges }}{{.Add}} | {{.Change}} | {{.Destroy}} | {{.Import}}{{ else }}- | - | - | -{{ end }} |\n" +
+	"{{end}}" +
+	"{{ $total := .TotalPlanChanges }}| **Total** | {{$total.Add}} | {{$total.Change}} | {{$total.Destroy}} | {{$total.Import}} |\n"
+
 var multiProjectApplyTmpl = template.Must(template.New("").Funcs(sprig.TxtFuncMap()).Parse(
 	"Ran {{.Command}} for {{ len .Results }} projects:\n\n" +
 		"{{ range $result := .Results }}" +
@@ -304,13 +371,15 @@ var multiProjectVersionTmpl = template.Must(template.New("").Funcs(sprig.TxtFunc
 		"---\n{{end}}" +
 		logTmpl))
 var planSuccessUnwrappedTmpl = template.Must(template.New("").Parse(
-	"```diff\n" +
+	planAnalysisTmpl +
+		"```diff\n" +
 		"{{ if .EnableDiffMarkdownFormat }}{{.DiffMarkdownFormattedTerraformOutput}}{{else}}{{.TerraformOutput}}{{end}}\n" +
 		"```\n\n" + planNextSteps +
 		"{{ if .HasDiverged }}\n\n:warning: The branch we're merging into is ahead, it is recommended to pull new commits first.{{end}}"))
 
 var planSuccessWrappedTmpl = template.Must(template.New("").Parse(
-	"<details><summary>Show Output</summary>\n\n" +
+	planAnalysisTmpl +
+		"<details><summary>Show Output</summary>\n\n" +
 		"```diff\n" +
 		"{{ if .EnableDiffMarkdownFormat }}{{.DiffMarkdownFormattedTerraformOutput}}{{else}}{{.TerraformOutput}}{{end}}\n" +
 		"```\n\n" +
@@ -319,6 +388,15 @@ var planSuccessWrappedTmpl = template.Must(template.New("").Parse(
 		"{{.PlanSummary}}" +
 		"{{ if .HasDiverged }}\n\n:warning: The branch we're merging into is ahead, it is recommended to pull new commits first.{{end}}"))
 
+// planAnalysisTmpl is a table of the changes to each resource type in the
+// plan. It's rendered above the plan output so it's visible when the output is
+// folded.
+var planAnalysisTmpl = "{{ if .Analysis }}{{ if .Analysis.HasChanges }}" +
+	"| Resource Type | Create | Update | Delete | Replace | Import |\n" +
+	"|---------------|--------|--------|--------|---------|--------|\n" +
+	"{{ range .Analysis.Changes }}| `{{.Type}}` | {{.Creates}} | {{.Updates}} | {{.Deletes}} | {{.Replaces}} | {{.Imports}} |\n{{ end }}\n" +
+	"{{ end }}{{ end }}"
+
 var policyCheckSuccessUnwrappedTmpl = template.Must(template.New("").Parse(
 	"```diff\n" +
 		"{{.PolicyCheckOutput}}\n" +
@@ -334,6 +412,19 @@ var policyCheckSuccessWrappedTmpl = template.Must(template.New("").Parse(
 		"</details>" +
 		"{{ if .HasDiverged }}\n\n:warning: The branch we're merging into is ahead, it is recommended to pull new commits first.{{end}}"))
 
+// policySetResultsTmpl summarizes the structured policy check results of a
+// project in a table followed by the message of each failed or warning rule.
+var policySetResultsTmpl = template.Must(template.New("").Parse(
+	"| Policy Set | Status | Passed | Warnings | Failures | Exceptions |\n" +
+		"|------------|--------|--------|----------|----------|------------|\n" +
+		"{{ range . }}| `{{.PolicySetName}}` | " +
+		"{{ if .Error }}:x: errored{{ else if not .Passed }}:x: failed{{ else if .NumWarnings }}:warning: passed with warnings{{ else }}:white_check_mark: passed{{ end }} | " +
+		"{{.NumSuccesses}} | {{.NumWarnings}} | {{.NumFailures}} | {{.NumExceptions}} |\n{{ end }}" +
+		"{{ range . }}{{ $policySet := .PolicySetName }}{{ range .Results }}{{ $namespace := .Namespace }}" +
+		"{{ range .Failures }}\n* :x: `{{$policySet}}` `{{$namespace}}{{ if .Rule }}.{{.Rule}}{{ end }}`: {{.Message}}{{ end }}" +
+		"{{ range .Warnings }}\n* :warning: `{{$policySet}}` `{{$namespace}}{{ if .Rule }}.{{.Rule}}{{ end }}`: {{.Message}}{{ end }}" +
+		"{{ end }}{{ end }}"))
+
 // policyCheckNextSteps are instructions appended after successful plans as to what
 // to do next.
 var policyCheckNextSteps = "* :arrow_forward: To **apply** this plan, comment:\n" +
@@ -360,6 +451,19 @@ var applyWrappedSuccessTmpl = template.Must(template.New("").Parse(
 		"{{.Output}}\n" +
 		"```\n" +
 		"</details>"))
+var stateChangeUnwrappedSuccessTmpl = template.Must(template.New("").Parse(
+	"```diff\n" +
+		"{{.Output}}\n" +
+		"```\n\n" + stateChangeNextStepsTmpl))
+var stateChangeWrappedSuccessTmpl = template.Must(template.New("").Parse(
+	"<details><summary>Show Output</summary>\n\n" +
+		"```diff\n" +
+		"{{.Output}}\n" +
+		"```\n" +
+		"</details>\n\n" + stateChangeNextStepsTmpl))
+var stateChangeNextStepsTmpl = ":put_litter_in_its_place: Any plan for this project was discarded since the state changed.\n\n" +
+	"* :repeat: To **plan** this project again, comment:\n" +
+	"    * `{{.RePlanCmd}}`"
 var versionUnwrappedSuccessTmpl = template.Must(template.New("").Parse("```\n{{.Output}}```"))
 var versionWrappedSuccessTmpl = template.Must(template.New("").Parse(
 	"<details><summary>Show Output</summary>\n\n" +
@@ -166,6 +166,88 @@ $$$diff
 terraform-output
 $$$
 
+* :arrow_forward: To **apply** this plan, comment:
+    * $atlantis apply -d path -w workspace$
+* :put_litter_in_its_place: To **delete** this plan click [here](lock-url)
+* :repeat: To **plan** this project again, comment:
+    * $atlantis plan -d path -w workspace$
+
+---
+* :fast_forward: To **apply** all unapplied plans from this pull request, comment:
+    * $atlantis apply$
+* :put_litter_in_its_place: To delete all plans and locks for the PR, comment:
+    * $atlantis unlock$
+`,
+		},
+		{
+			"single successful plan with analysis",
+			command.Plan,
+			[]command.ProjectResult{
+				{
+					PlanSuccess: &models.PlanSuccess{
+						TerraformOutput: "terraform-output",
+						LockURL:         "lock-url",
+						RePlanCmd:       "atlantis plan -d path -w workspace",
+						ApplyCmd:        "atlantis apply -d path -w workspace",
+						Analysis: &models.PlanAnalysis{
+							Changes: []models.ResourceTypeChanges{
+								{Type: "aws_db_instance", Replaces: 1, ReplacedAddresses: []string{"aws_db_instance.main"}},
+								{Type: "aws_instance", Creates: 2, Updates: 1, Deletes: 1},
+							},
+						},
+					},
+					Workspace:  "workspace",
+					RepoRelDir: "path",
+				},
+			},
+			models.Github,
+			`Ran Plan for dir: $path$ workspace: $workspace$
+
+| Resource Type | Create | Update | Delete | Replace | Import |
+|---------------|--------|--------|--------|---------|--------|
+| $aws_db_instance$ | 0 | 0 | 0 | 1 | 0 |
+| $aws_instance$ | 2 | 1 | 1 | 0 | 0 |
+
+$$$diff
+terraform-output
+$$$
+
+* :arrow_forward: To **apply** this plan, comment:
+    * $atlantis apply -d path -w workspace$
+* :put_litter_in_its_place: To **delete** this plan click [here](lock-url)
+* :repeat: To **plan** this project again, comment:
+    * $atlantis plan -d path -w workspace$
+
+---
+* :fast_forward: To **apply** all unapplied plans from this pull request, comment:
+    * $atlantis apply$
+* :put_litter_in_its_place: To delete all plans and locks for the PR, comment:
+    * $atlantis unlock$
+`,
+		},
+		{
+			"single successful plan without changes",
+			command.Plan,
+			[]command.ProjectResult{
+				{
+					PlanSuccess: &models.PlanSuccess{
+						TerraformOutput: "terraform-output",
+						LockURL:         "lock-url",
+						RePlanCmd:       "atlantis plan -d path -w workspace",
+						ApplyCmd:        "atlantis apply -d path -w workspace",
+						Analysis:        &models.PlanAnalysis{},
+					},
+					Workspace:  "workspace",
+					RepoRelDir: "path",
+				},
+			},
+			models.Github,
+			`Ran Plan for dir: $path$ workspace: $workspace$
+
+$$$diff
+terraform-output
+$$$
+
 * :arrow_forward: To **apply** this plan, comment:
     * $atlantis apply -d path -w workspace$
 * :put_litter_in_its_place: To **delete** this plan click [here](lock-url)
@@ -287,6 +369,115 @@ $$$
     * $atlantis apply$
 * :put_litter_in_its_place: To delete all plans and locks for the PR, comment:
     * $atlantis unlock$
+`,
+		},
+		{
+			"single successful policy check with policy set results",
+			command.PolicyCheck,
+			[]command.ProjectResult{
+				{
+					PolicyCheckSuccess: &models.PolicyCheckSuccess{
+						PolicyCheckOutput: "policy-output",
+						LockURL:           "lock-url",
+						RePlanCmd:         "atlantis plan -d path -w workspace",
+						ApplyCmd:          "atlantis apply -d path -w workspace",
+					},
+					PolicySetResults: []models.PolicySetResult{
+						{
+							PolicySetName: "security",
+							Passed:        true,
+							Results: []models.PolicyNamespaceResult{
+								{
+									Namespace: "main",
+									Successes: 2,
+									Warnings:  []models.PolicyRuleMessage{{Rule: "warn", Message: "tags are missing"}},
+								},
+							},
+						},
+						{
+							PolicySetName: "cost",
+							Passed:        true,
+							Results:       []models.PolicyNamespaceResult{{Namespace: "main", Successes: 1}},
+						},
+					},
+					Workspace:   "workspace",
+					RepoRelDir:  "path",
+					ProjectName: "projectname",
+				},
+			},
+			models.Github,
+			`Ran Policy Check for project: $projectname$ dir: $path$ workspace: $workspace$
+
+| Policy Set | Status | Passed | Warnings | Failures | Exceptions |
+|------------|--------|--------|----------|----------|------------|
+| $security$ | :warning: passed with warnings | 2 | 1 | 0 | 0 |
+| $cost$ | :white_check_mark: passed | 1 | 0 | 0 | 0 |
+
+* :warning: $security$ $main.warn$: tags are missing
+
+$$$diff
+policy-output
+$$$
+
+* :arrow_forward: To **apply** this plan, comment:
+    * $atlantis apply -d path -w workspace$
+* :put_litter_in_its_place: To **delete** this plan click [here](lock-url)
+* :repeat: To re-run policies **plan** this project again by commenting:
+    * $atlantis plan -d path -w workspace$
+
+---
+* :fast_forward: To **apply** all unapplied plans from this pull request, comment:
+    * $atlantis apply$
+* :put_litter_in_its_place: To delete all plans and locks for the PR, comment:
+    * $atlantis unlock$
+`,
+		},
+		{
+			"single failed policy check with policy set results",
+			command.PolicyCheck,
+			[]command.ProjectResult{
+				{
+					Error: errors.New("some policy sets did not pass: security"),
+					PolicySetResults: []models.PolicySetResult{
+						{
+							PolicySetName: "security",
+							Passed:        false,
+							Results: []models.PolicyNamespaceResult{
+								{
+									Namespace: "main",
+									Failures:  []models.PolicyRuleMessage{{Rule: "deny", Message: "null resources cannot be created"}},
+								},
+							},
+						},
+						{
+							PolicySetName: "cost",
+							Passed:        false,
+							Error:         "Error: loading policies",
+						},
+					},
+					Workspace:  "workspace",
+					RepoRelDir: "path",
+				},
+			},
+			models.Github,
+			`Ran Policy Check for dir: $path$ workspace: $workspace$
+
+| Policy Set | Status | Passed | Warnings | Failures | Exceptions |
+|------------|--------|--------|----------|----------|------------|
+| $security$ | :x: failed | 0 | 0 | 1 | 0 |
+| $cost$ | :x: errored | 0 | 0 | 0 | 0 |
+
+* :x: $security$ $main.deny$: null resources cannot be created
+
+**Policy Check Error**
+$$$
+some policy sets did not pass: security
+$$$
+* :heavy_check_mark: To **approve** failing policies an authorized approver can comment:
+    * $atlantis approve_policies$
+* :repeat: Or, address the policy failure by modifying the codebase and re-planning.
+
+
 `,
 		},
 		{
@@ -383,6 +574,102 @@ $$$
 * :repeat: To **plan** this project again, comment:
     * $atlantis plan -d path2 -w workspace$
 
+---
+* :fast_forward: To **apply** all unapplied plans from this pull request, comment:
+    * $atlantis apply$
+* :put_litter_in_its_place: To delete all plans and locks for the PR, comment:
+    * $atlantis unlock$
+`,
+		},
+		{
+			"multiple plans with analysis",
+			command.Plan,
+			[]command.ProjectResult{
+				{
+					Workspace:  "workspace",
+					RepoRelDir: "path",
+					PlanSuccess: &models.PlanSuccess{
+						TerraformOutput: "terraform-output",
+						LockURL:         "lock-url",
+						ApplyCmd:        "atlantis apply -d path -w workspace",
+						RePlanCmd:       "atlantis plan -d path -w workspace",
+						Analysis: &models.PlanAnalysis{
+							Changes: []models.ResourceTypeChanges{
+								{Type: "aws_instance", Creates: 2, Replaces: 1, ReplacedAddresses: []string{"aws_instance.web"}},
+							},
+						},
+					},
+				},
+				{
+					Workspace:   "workspace",
+					RepoRelDir:  "path2",
+					ProjectName: "projectname",
+					PlanSuccess: &models.PlanSuccess{
+						TerraformOutput: "terraform-output2",
+						LockURL:         "lock-url2",
+						ApplyCmd:        "atlantis apply -d path2 -w workspace",
+						RePlanCmd:       "atlantis plan -d path2 -w workspace",
+						Analysis: &models.PlanAnalysis{
+							Changes: []models.ResourceTypeChanges{
+								{Type: "aws_s3_bucket", Updates: 1, Imports: 1},
+							},
+						},
+					},
+				},
+				{
+					Workspace:  "workspace",
+					RepoRelDir: "path3",
+					Error:      errors.New("error"),
+				},
+			},
+			models.Github,
+			`Ran Plan for 3 projects:
+
+| Project | Add | Change | Destroy | Import |
+|---------|-----|--------|---------|--------|
+| dir: $path$ workspace: $workspace$ | 3 | 0 | 1 | 0 |
+| project: $projectname$ dir: $path2$ workspace: $workspace$ | 0 | 1 | 0 | 1 |
+| dir: $path3$ workspace: $workspace$ | - | - | - | - |
+| **Total** | 3 | 1 | 1 | 1 |
+
+### 1. dir: $path$ workspace: $workspace$
+| Resource Type | Create | Update | Delete | Replace | Import |
+|---------------|--------|--------|--------|---------|--------|
+| $aws_instance$ | 2 | 0 | 0 | 1 | 0 |
+
+$$$diff
+terraform-output
+$$$
+
+* :arrow_forward: To **apply** this plan, comment:
+    * $atlantis apply -d path -w workspace$
+* :put_litter_in_its_place: To **delete** this plan click [here](lock-url)
+* :repeat: To **plan** this project again, comment:
+    * $atlantis plan -d path -w workspace$
+
+---
+### 2. project: $projectname$ dir: $path2$ workspace: $workspace$
+| Resource Type | Create | Update | Delete | Replace | Import |
+|---------------|--------|--------|--------|---------|--------|
+| $aws_s3_bucket$ | 0 | 1 | 0 | 0 | 1 |
+
+$$$diff
+terraform-output2
+$$$
+
+* :arrow_forward: To **apply** this plan, comment:
+    * $atlantis apply -d path2 -w workspace$
+* :put_litter_in_its_place: To **delete** this plan click [here](lock-url2)
+* :repeat: To **plan** this project again, comment:
+    * $atlantis plan -d path2 -w workspace$
+
+---
+### 3. dir: $path3$ workspace: $workspace$
+**Plan Error**
+$$$
+error
+$$$
+
 ---
 * :fast_forward: To **apply** all unapplied plans from this pull request, comment:
     * $atlantis apply$
@@ -487,6 +774,86 @@ $$$
 
 ---
 
+`,
+		},
+		{
+			"single successful import",
+			command.Import,
+			[]command.ProjectResult{
+				{
+					RepoRelDir: "path",
+					Workspace:  "workspace",
+					ImportSuccess: &models.ImportSuccess{
+						Output:    "import-output",
+						RePlanCmd: "atlantis plan -d path -w workspace",
+					},
+				},
+			},
+			models.Github,
+			`Ran Import for dir: $path$ workspace: $workspace$
+
+$$$diff
+import-output
+$$$
+
+:put_litter_in_its_place: Any plan for this project was discarded since the state changed.
+
+* :repeat: To **plan** this project again, comment:
+    * $atlantis plan -d path -w workspace$
+
+`,
+		},
+		{
+			"multiple successful state rm",
+			command.StateRm,
+			[]command.ProjectResult{
+				{
+					RepoRelDir:  "path",
+					Workspace:   "workspace",
+					ProjectName: "projectname",
+					StateSuccess: &models.StateSuccess{
+						Output:    "state-output",
+						RePlanCmd: "atlantis plan -p projectname",
+					},
+				},
+				{
+					RepoRelDir: "path2",
+					Workspace:  "workspace",
+					StateSuccess: &models.StateSuccess{
+						Output:    "state-output2",
+						RePlanCmd: "atlantis plan -d path2 -w workspace",
+					},
+				},
+			},
+			models.Github,
+			`Ran State Rm for 2 projects:
+
+1. project: $projectname$ dir: $path$ workspace: $workspace$
+1. dir: $path2$ workspace: $workspace$
+
+### 1. project: $projectname$ dir: $path$ workspace: $workspace$
+$$$diff
+state-output
+$$$
+
+:put_litter_in_its_place: Any plan for this project was discarded since the state changed.
+
+* :repeat: To **plan** this project again, comment:
+    * $atlantis plan -p projectname$
+
+---
+### 2. dir: $path2$ workspace: $workspace$
+$$$diff
+state-output2
+$$$
+
+:put_litter_in_its_place: Any plan for this project was discarded since the state changed.
+
+* :repeat: To **plan** this project again, comment:
+    * $atlantis plan -d path2 -w workspace$
+
+---
+
 `,
 		},
 		{
@@ -72,6 +72,36 @@ func (mock *MockCommitStatusUpdater) UpdateProject(ctx command.ProjectContext, c
 	return ret0
 }
 
+func (mock *MockCommitStatusUpdater) UpdateCombinedPlanCount(_param0 models.Repo, _param1 models.PullRequest, _param2 models.CommitStatus, _param3 int, _param4 int, _param5 *models.PlanChangeCounts) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockCommitStatusUpdater().")
+	}
+	params := []pegomock.Param{_param0, _param1, _param2, _param3, _param4, _param5}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("UpdateCombinedPlanCount", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(error)
+		}
+	}
+	return ret0
+}
+
+func (mock *MockCommitStatusUpdater) UpdateProjectQueued(_param0 command.ProjectContext, _param1 command.Name, _param2 int, _param3 string) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockCommitStatusUpdater().")
+	}
+	params := []pegomock.Param{_param0, _param1, _param2, _param3}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("UpdateProjectQueued", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(error)
+		}
+	}
+	return ret0
+}
+
 func (mock *MockCommitStatusUpdater) VerifyWasCalledOnce() *VerifierMockCommitStatusUpdater {
 	return &VerifierMockCommitStatusUpdater{
 		mock:                   mock,
@@ -233,3 +263,89 @@ func (c *MockCommitStatusUpdater_UpdateProject_OngoingVerification) GetAllCaptur
 	}
 	return
 }
+
+func (verifier *VerifierMockCommitStatusUpdater) UpdateCombinedPlanCount(_param0 models.Repo, _param1 models.PullRequest, _param2 models.CommitStatus, _param3 int, _param4 int, _param5 *models.PlanChangeCounts) *MockCommitStatusUpdater_UpdateCombinedPlanCount_OngoingVerification {
+	params := []pegomock.Param{_param0, _param1, _param2, _param3, _param4, _param5}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "UpdateCombinedPlanCount", params, verifier.timeout)
+	return &MockCommitStatusUpdater_UpdateCombinedPlanCount_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockCommitStatusUpdater_UpdateCombinedPlanCount_OngoingVerification struct {
+	mock              *MockCommitStatusUpdater
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockCommitStatusUpdater_UpdateCombinedPlanCount_OngoingVerification) GetCapturedArguments() (models.Repo, models.PullRequest, models.CommitStatus, int, int, *models.PlanChangeCounts) {
+	_param0, _param1, _param2, _param3, _param4, _param5 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1], _param1[len(_param1)-1], _param2[len(_param2)-1], _param3[len(_param3)-1], _param4[len(_param4)-1], _param5[len(_param5)-1]
+}
+
+func (c *MockCommitStatusUpdater_UpdateCombinedPlanCount_OngoingVerification) GetAllCapturedArguments() (_param0 []models.Repo, _param1 []models.PullRequest, _param2 []models.CommitStatus, _param3 []int, _param4 []int, _param5 []*models.PlanChangeCounts) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]models.Repo, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(models.Repo)
+		}
+		_param1 = make([]models.PullRequest, len(c.methodInvocations))
+		for u, param := range params[1] {
+			_param1[u] = param.(models.PullRequest)
+		}
+		_param2 = make([]models.CommitStatus, len(c.methodInvocations))
+		for u, param := range params[2] {
+			_param2[u] = param.(models.CommitStatus)
+		}
+		_param3 = make([]int, len(c.methodInvocations))
+		for u, param := range params[3] {
+			_param3[u] = param.(int)
+		}
+		_param4 = make([]int, len(c.methodInvocations))
+		for u, param := range params[4] {
+			_param4[u] = param.(int)
+		}
+		_param5 = make([]*models.PlanChangeCounts, len(c.methodInvocations))
+		for u, param := range params[5] {
+			_param5[u] = param.(*models.PlanChangeCounts)
+		}
+	}
+	return
+}
+
+func (verifier *VerifierMockCommitStatusUpdater) UpdateProjectQueued(_param0 command.ProjectContext, _param1 command.Name, _param2 int, _param3 string) *MockCommitStatusUpdater_UpdateProjectQueued_OngoingVerification {
+	params := []pegomock.Param{_param0, _param1, _param2, _param3}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "UpdateProjectQueued", params, verifier.timeout)
+	return &MockCommitStatusUpdater_UpdateProjectQueued_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockCommitStatusUpdater_UpdateProjectQueued_OngoingVerification struct {
+	mock              *MockCommitStatusUpdater
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockCommitStatusUpdater_UpdateProjectQueued_OngoingVerification) GetCapturedArguments() (command.ProjectContext, command.Name, int, string) {
+	_param0, _param1, _param2, _param3 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1], _param1[len(_param1)-1], _param2[len(_param2)-1], _param3[len(_param3)-1]
+}
+
+func (c *MockCommitStatusUpdater_UpdateProjectQueued_OngoingVerification) GetAllCapturedArguments() (_param0 []command.ProjectContext, _param1 []command.Name, _param2 []int, _param3 []string) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]command.ProjectContext, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(command.ProjectContext)
+		}
+		_param1 = make([]command.Name, len(c.methodInvocations))
+		for u, param := range params[1] {
+			_param1[u] = param.(command.Name)
+		}
+		_param2 = make([]int, len(c.methodInvocations))
+		for u, param := range params[2] {
+			_param2[u] = param.(int)
+		}
+		_param3 = make([]string, len(c.methodInvocations))
+		for u, param := range params[3] {
+			_param3[u] = param.(string)
+		}
+	}
+	return
+}
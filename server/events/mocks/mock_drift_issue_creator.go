@@ -0,0 +1,114 @@
+// Code generated by pegomock. DO NOT EDIT.
+// Source: github.com/runatlantis/atlantis/server/events (interfaces: DriftIssueCreator)
+
+package mocks
+
+import (
+	"reflect"
+	"time"
+
+	pegomock "github.com/petergtz/pegomock"
+	models "github.com/runatlantis/atlantis/server/events/models"
+)
+
+type MockDriftIssueCreator struct {
+	fail func(message string, callerSkip ...int)
+}
+
+func NewMockDriftIssueCreator(options ...pegomock.Option) *MockDriftIssueCreator {
+	mock := &MockDriftIssueCreator{}
+	for _, option := range options {
+		option.Apply(mock)
+	}
+	return mock
+}
+
+func (mock *MockDriftIssueCreator) SetFailHandler(fh pegomock.FailHandler) { mock.fail = fh }
+func (mock *MockDriftIssueCreator) FailHandler() pegomock.FailHandler      { return mock.fail }
+
+func (mock *MockDriftIssueCreator) CreateIssue(_param0 models.Repo, _param1 string, _param2 string) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockDriftIssueCreator().")
+	}
+	params := []pegomock.Param{_param0, _param1, _param2}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("CreateIssue", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(error)
+		}
+	}
+	return ret0
+}
+
+func (mock *MockDriftIssueCreator) VerifyWasCalledOnce() *VerifierMockDriftIssueCreator {
+	return &VerifierMockDriftIssueCreator{
+		mock:                   mock,
+		invocationCountMatcher: pegomock.Times(1),
+	}
+}
+
+func (mock *MockDriftIssueCreator) VerifyWasCalled(invocationCountMatcher pegomock.InvocationCountMatcher) *VerifierMockDriftIssueCreator {
+	return &VerifierMockDriftIssueCreator{
+		mock:                   mock,
+		invocationCountMatcher: invocationCountMatcher,
+	}
+}
+
+func (mock *MockDriftIssueCreator) VerifyWasCalledInOrder(invocationCountMatcher pegomock.InvocationCountMatcher, inOrderContext *pegomock.InOrderContext) *VerifierMockDriftIssueCreator {
+	return &VerifierMockDriftIssueCreator{
+		mock:                   mock,
+		invocationCountMatcher: invocationCountMatcher,
+		inOrderContext:         inOrderContext,
+	}
+}
+
+func (mock *MockDriftIssueCreator) VerifyWasCalledEventually(invocationCountMatcher pegomock.InvocationCountMatcher, timeout time.Duration) *VerifierMockDriftIssueCreator {
+	return &VerifierMockDriftIssueCreator{
+		mock:                   mock,
+		invocationCountMatcher: invocationCountMatcher,
+		timeout:                timeout,
+	}
+}
+
+type VerifierMockDriftIssueCreator struct {
+	mock                   *MockDriftIssueCreator
+	invocationCountMatcher pegomock.InvocationCountMatcher
+	inOrderContext         *pegomock.InOrderContext
+	timeout                time.Duration
+}
+
+func (verifier *VerifierMockDriftIssueCreator) CreateIssue(_param0 models.Repo, _param1 string, _param2 string) *MockDriftIssueCreator_CreateIssue_OngoingVerification {
+	params := []pegomock.Param{_param0, _param1, _param2}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "CreateIssue", params, verifier.timeout)
+	return &MockDriftIssueCreator_CreateIssue_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockDriftIssueCreator_CreateIssue_OngoingVerification struct {
+	mock              *MockDriftIssueCreator
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockDriftIssueCreator_CreateIssue_OngoingVerification) GetCapturedArguments() (models.Repo, string, string) {
+	_param0, _param1, _param2 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1], _param1[len(_param1)-1], _param2[len(_param2)-1]
+}
+
+func (c *MockDriftIssueCreator_CreateIssue_OngoingVerification) GetAllCapturedArguments() (_param0 []models.Repo, _param1 []string, _param2 []string) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]models.Repo, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(models.Repo)
+		}
+		_param1 = make([]string, len(c.methodInvocations))
+		for u, param := range params[1] {
+			_param1[u] = param.(string)
+		}
+		_param2 = make([]string, len(c.methodInvocations))
+		for u, param := range params[2] {
+			_param2[u] = param.(string)
+		}
+	}
+	return
+}
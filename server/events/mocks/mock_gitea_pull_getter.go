@@ -0,0 +1,115 @@
+// Code generated by pegomock. DO NOT EDIT.
+// Source: github.com/runatlantis/atlantis/server/events (interfaces: GiteaPullGetter)
+
+package mocks
+
+import (
+	"reflect"
+	"time"
+
+	pegomock "github.com/petergtz/pegomock"
+	models "github.com/runatlantis/atlantis/server/events/models"
+	gitea "github.com/runatlantis/atlantis/server/events/vcs/gitea"
+)
+
+type MockGiteaPullGetter struct {
+	fail func(message string, callerSkip ...int)
+}
+
+func NewMockGiteaPullGetter(options ...pegomock.Option) *MockGiteaPullGetter {
+	mock := &MockGiteaPullGetter{}
+	for _, option := range options {
+		option.Apply(mock)
+	}
+	return mock
+}
+
+func (mock *MockGiteaPullGetter) SetFailHandler(fh pegomock.FailHandler) { mock.fail = fh }
+func (mock *MockGiteaPullGetter) FailHandler() pegomock.FailHandler      { return mock.fail }
+
+func (mock *MockGiteaPullGetter) GetPullRequest(repo models.Repo, pullNum int) (*gitea.PullRequest, error) {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockGiteaPullGetter().")
+	}
+	params := []pegomock.Param{repo, pullNum}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("GetPullRequest", params, []reflect.Type{reflect.TypeOf((**gitea.PullRequest)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 *gitea.PullRequest
+	var ret1 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(*gitea.PullRequest)
+		}
+		if result[1] != nil {
+			ret1 = result[1].(error)
+		}
+	}
+	return ret0, ret1
+}
+
+func (mock *MockGiteaPullGetter) VerifyWasCalledOnce() *VerifierMockGiteaPullGetter {
+	return &VerifierMockGiteaPullGetter{
+		mock:                   mock,
+		invocationCountMatcher: pegomock.Times(1),
+	}
+}
+
+func (mock *MockGiteaPullGetter) VerifyWasCalled(invocationCountMatcher pegomock.InvocationCountMatcher) *VerifierMockGiteaPullGetter {
+	return &VerifierMockGiteaPullGetter{
+		mock:                   mock,
+		invocationCountMatcher: invocationCountMatcher,
+	}
+}
+
+func (mock *MockGiteaPullGetter) VerifyWasCalledInOrder(invocationCountMatcher pegomock.InvocationCountMatcher, inOrderContext *pegomock.InOrderContext) *VerifierMockGiteaPullGetter {
+	return &VerifierMockGiteaPullGetter{
+		mock:                   mock,
+		invocationCountMatcher: invocationCountMatcher,
+		inOrderContext:         inOrderContext,
+	}
+}
+
+func (mock *MockGiteaPullGetter) VerifyWasCalledEventually(invocationCountMatcher pegomock.InvocationCountMatcher, timeout time.Duration) *VerifierMockGiteaPullGetter {
+	return &VerifierMockGiteaPullGetter{
+		mock:                   mock,
+		invocationCountMatcher: invocationCountMatcher,
+		timeout:                timeout,
+	}
+}
+
+type VerifierMockGiteaPullGetter struct {
+	mock                   *MockGiteaPullGetter
+	invocationCountMatcher pegomock.InvocationCountMatcher
+	inOrderContext         *pegomock.InOrderContext
+	timeout                time.Duration
+}
+
+func (verifier *VerifierMockGiteaPullGetter) GetPullRequest(repo models.Repo, pullNum int) *MockGiteaPullGetter_GetPullRequest_OngoingVerification {
+	params := []pegomock.Param{repo, pullNum}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "GetPullRequest", params, verifier.timeout)
+	return &MockGiteaPullGetter_GetPullRequest_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockGiteaPullGetter_GetPullRequest_OngoingVerification struct {
+	mock              *MockGiteaPullGetter
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockGiteaPullGetter_GetPullRequest_OngoingVerification) GetCapturedArguments() (models.Repo, int) {
+	repo, pullNum := c.GetAllCapturedArguments()
+	return repo[len(repo)-1], pullNum[len(pullNum)-1]
+}
+
+func (c *MockGiteaPullGetter_GetPullRequest_OngoingVerification) GetAllCapturedArguments() (_param0 []models.Repo, _param1 []int) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]models.Repo, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(models.Repo)
+		}
+		_param1 = make([]int, len(c.methodInvocations))
+		for u, param := range params[1] {
+			_param1[u] = param.(int)
+		}
+	}
+	return
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/runatlantis/atlantis/server/logging"
 
 	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
 )
 
 type PullReqStatus struct {
@@ -296,6 +297,7 @@ const (
 	BitbucketCloud
 	BitbucketServer
 	AzureDevops
+	Gitea
 )
 
 func (h VCSHostType) String() string {
@@ -310,6 +312,8 @@ func (h VCSHostType) String() string {
 		return "BitbucketServer"
 	case AzureDevops:
 		return "AzureDevops"
+	case Gitea:
+		return "Gitea"
 	}
 	return "<missing String() implementation>"
 }
@@ -343,6 +347,108 @@ type PlanSuccess struct {
 	// branch we're merging into has been updated since we cloned and merged
 	// it.
 	HasDiverged bool
+	// Analysis is the analysis of the resource changes in the plan. It's nil
+	// if the plan couldn't be analyzed, ex. for remote plans.
+	Analysis *PlanAnalysis
+}
+
+// PlanAnalysis is an analysis of the resource changes in a plan.
+type PlanAnalysis struct {
+	// Changes are the changes to each resource type, sorted by type. Resource
+	// types without changes aren't included.
+	Changes []ResourceTypeChanges
+}
+
+// ResourceTypeChanges counts the changes to the resources of one type.
+type ResourceTypeChanges struct {
+	Type     string
+	Creates  int
+	Updates  int
+	Deletes  int
+	Replaces int
+	// Imports are the resources that will be imported. An imported resource
+	// can also be updated, in which case it's counted in Updates too.
+	Imports int
+	// ReplacedAddresses are the addresses of the replaced resources.
+	ReplacedAddresses []string
+}
+
+// HasChanges returns true if any resources are changed.
+func (p *PlanAnalysis) HasChanges() bool {
+	return len(p.Changes) > 0
+}
+
+// NumCreates returns the number of resources that will be created.
+func (p *PlanAnalysis) NumCreates() int {
+	n := 0
+	for _, c := range p.Changes {
+		n += c.Creates
+	}
+	return n
+}
+
+// NumUpdates returns the number of resources that will be updated in-place.
+func (p *PlanAnalysis) NumUpdates() int {
+	n := 0
+	for _, c := range p.Changes {
+		n += c.Updates
+	}
+	return n
+}
+
+// NumDeletes returns the number of resources that will be deleted.
+func (p *PlanAnalysis) NumDeletes() int {
+	n := 0
+	for _, c := range p.Changes {
+		n += c.Deletes
+	}
+	return n
+}
+
+// NumReplaces returns the number of resources that will be replaced.
+func (p *PlanAnalysis) NumReplaces() int {
+	n := 0
+	for _, c := range p.Changes {
+		n += c.Replaces
+	}
+	return n
+}
+
+// NumDestroys returns the number of resources that will be destroyed, which
+// includes the resources that will be replaced.
+func (p *PlanAnalysis) NumDestroys() int {
+	return p.NumDeletes() + p.NumReplaces()
+}
+
+// NumImports returns the number of resources that will be imported.
+func (p *PlanAnalysis) NumImports() int {
+	n := 0
+	for _, c := range p.Changes {
+		n += c.Imports
+	}
+	return n
+}
+
+// Counts returns the number of resources the plan adds, changes, destroys
+// and imports. Like Terraform, replaced resources are counted as both added
+// and destroyed.
+func (p *PlanAnalysis) Counts() PlanChangeCounts {
+	return PlanChangeCounts{
+		Add:     p.NumCreates() + p.NumReplaces(),
+		Change:  p.NumUpdates(),
+		Destroy: p.NumDestroys(),
+		Import:  p.NumImports(),
+	}
+}
+
+// ChangesForType returns the changes to resources of resourceType.
+func (p *PlanAnalysis) ChangesForType(resourceType string) ResourceTypeChanges {
+	for _, c := range p.Changes {
+		if c.Type == resourceType {
+			return c
+		}
+	}
+	return ResourceTypeChanges{Type: resourceType}
 }
 
 // Summary extracts one line summary of plan changes from TerraformOutput.
@@ -372,6 +478,48 @@ func (p PlanSuccess) DiffMarkdownFormattedTerraformOutput() string {
 	return formattedTerraformOutput
 }
 
+// PlanChangeCounts is the number of resources a plan adds, changes, destroys
+// and imports, like the "Plan:" line of terraform plan.
+type PlanChangeCounts struct {
+	Add     int
+	Change  int
+	Destroy int
+	Import  int
+}
+
+// Plus returns the sum of c and other.
+func (c PlanChangeCounts) Plus(other PlanChangeCounts) PlanChangeCounts {
+	return PlanChangeCounts{
+		Add:     c.Add + other.Add,
+		Change:  c.Change + other.Change,
+		Destroy: c.Destroy + other.Destroy,
+		Import:  c.Import + other.Import,
+	}
+}
+
+// String returns the non-zero counts, ex. "3 to add, 1 to destroy", or "no
+// changes".
+func (c PlanChangeCounts) String() string {
+	var parts []string
+	for _, count := range []struct {
+		n    int
+		verb string
+	}{
+		{c.Add, "add"},
+		{c.Change, "change"},
+		{c.Destroy, "destroy"},
+		{c.Import, "import"},
+	} {
+		if count.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d to %s", count.n, count.verb))
+		}
+	}
+	if len(parts) == 0 {
+		return "no changes"
+	}
+	return strings.Join(parts, ", ")
+}
+
 // PolicyCheckSuccess is the result of a successful policy check run.
 type PolicyCheckSuccess struct {
 	// PolicyCheckOutput is the output from policy check binary(conftest|opa)
@@ -388,6 +536,86 @@ type PolicyCheckSuccess struct {
 	HasDiverged bool
 }
 
+// ImportSuccess is the result of a successful import run.
+type ImportSuccess struct {
+	// Output is the output from Terraform of running import.
+	Output string
+	// RePlanCmd is the command that users should run to re-plan this project.
+	RePlanCmd string
+}
+
+// StateSuccess is the result of a successful state rm or state mv run.
+type StateSuccess struct {
+	// Output is the output from Terraform of running the state command.
+	Output string
+	// RePlanCmd is the command that users should run to re-plan this project.
+	RePlanCmd string
+}
+
+// PolicySetResult is the structured result of checking a plan against a
+// single policy set.
+type PolicySetResult struct {
+	PolicySetName string
+	// Passed is true if no policies failed. Warnings don't fail a policy set.
+	Passed bool
+	// Error is set if the policies couldn't be run, ex. if they don't compile.
+	Error string
+	// Results are the results for each policy namespace.
+	Results []PolicyNamespaceResult
+}
+
+// PolicyNamespaceResult is the result of the rules in one policy namespace.
+type PolicyNamespaceResult struct {
+	Namespace  string
+	Successes  int
+	Failures   []PolicyRuleMessage
+	Warnings   []PolicyRuleMessage
+	Exceptions []PolicyRuleMessage
+}
+
+// PolicyRuleMessage is a message returned by a policy rule.
+type PolicyRuleMessage struct {
+	// Rule is the name of the rule, ex. deny, if known.
+	Rule    string
+	Message string
+}
+
+// NumSuccesses returns the number of passed rules across all namespaces.
+func (p PolicySetResult) NumSuccesses() int {
+	n := 0
+	for _, r := range p.Results {
+		n += r.Successes
+	}
+	return n
+}
+
+// NumFailures returns the number of failures across all namespaces.
+func (p PolicySetResult) NumFailures() int {
+	n := 0
+	for _, r := range p.Results {
+		n += len(r.Failures)
+	}
+	return n
+}
+
+// NumWarnings returns the number of warnings across all namespaces.
+func (p PolicySetResult) NumWarnings() int {
+	n := 0
+	for _, r := range p.Results {
+		n += len(r.Warnings)
+	}
+	return n
+}
+
+// NumExceptions returns the number of exceptions across all namespaces.
+func (p PolicySetResult) NumExceptions() int {
+	n := 0
+	for _, r := range p.Results {
+		n += len(r.Exceptions)
+	}
+	return n
+}
+
 type VersionSuccess struct {
 	VersionOutput string
 }
@@ -411,6 +639,19 @@ func (p PullStatus) StatusCount(status ProjectPlanStatus) int {
 	return c
 }
 
+// PlanChangeCounts returns the total number of resources the latest plans of
+// the projects add, change, destroy and import. Discarded plans aren't
+// counted. ok is false if none of the plans could be analyzed.
+func (p PullStatus) PlanChangeCounts() (counts PlanChangeCounts, ok bool) {
+	for _, pr := range p.Projects {
+		if pr.PlanChanges != nil && pr.Status != DiscardedPlanStatus {
+			counts = counts.Plus(*pr.PlanChanges)
+			ok = true
+		}
+	}
+	return counts, ok
+}
+
 // ProjectStatus is the status of a specific project.
 type ProjectStatus struct {
 	Workspace   string
@@ -418,6 +659,67 @@ type ProjectStatus struct {
 	ProjectName string
 	// Status is the status of where this project is at in the planning cycle.
 	Status ProjectPlanStatus
+	// PolicyStatus is the result of the last policy check for each policy
+	// set, including approvals of failing policy sets.
+	PolicyStatus []PolicySetStatus
+	// PlanChanges is the number of resources the last plan changes. It's nil
+	// if the plan failed or couldn't be analyzed.
+	PlanChanges *PlanChangeCounts
+}
+
+// PolicySetStatus is the result of checking a project's plan against a
+// single policy set.
+type PolicySetStatus struct {
+	PolicySetName string
+	// Passed is true if the plan passed the policy set.
+	Passed bool
+	// Approvers are the users that approved the policy set after it failed.
+	Approvers []string
+}
+
+// IsApproved returns true if the policy set passed or was approved.
+func (p PolicySetStatus) IsApproved() bool {
+	return p.Passed || len(p.Approvers) > 0
+}
+
+// DriftStatus is the result of the last drift detection run for a repo.
+type DriftStatus struct {
+	// RepoFullName is the owner/repo name of the repo.
+	RepoFullName string
+	// Hostname is the hostname of the VCS host the repo is on.
+	Hostname string
+	// Branch is the branch that was checked.
+	Branch string
+	// CheckedAt is when the run finished.
+	CheckedAt time.Time
+	// Projects are the results for each project in the repo's atlantis.yaml.
+	Projects []ProjectDriftStatus
+}
+
+// ProjectDriftStatus is the result of checking a single project for drift.
+type ProjectDriftStatus struct {
+	ProjectName string
+	RepoRelDir  string
+	Workspace   string
+	// Drifted is true if planning the project found changes.
+	Drifted bool
+	// Summary is the plan's one line summary of the changes.
+	Summary string
+	// JobURL is the url to view the output of the check.
+	JobURL string
+	// Error is set if the project couldn't be checked.
+	Error string
+}
+
+// DriftedProjects returns the projects that drifted.
+func (d DriftStatus) DriftedProjects() []ProjectDriftStatus {
+	var drifted []ProjectDriftStatus
+	for _, p := range d.Projects {
+		if p.Drifted {
+			drifted = append(drifted, p)
+		}
+	}
+	return drifted
 }
 
 // ProjectPlanStatus is the status of where this project is at in the planning
@@ -470,6 +772,16 @@ func (p ProjectPlanStatus) String() string {
 	}
 }
 
+// HasUnappliedPlan returns true if the status is for a plan that was
+// generated but hasn't been applied yet.
+func (p ProjectPlanStatus) HasUnappliedPlan() bool {
+	switch p {
+	case PlannedPlanStatus, ErroredApplyStatus, ErroredPolicyCheckStatus, PassedPolicyCheckStatus:
+		return true
+	}
+	return false
+}
+
 // WorkflowHookCommandContext defines the context for a pre and post worklfow_hooks that will
 // be executed before workflows.
 type WorkflowHookCommandContext struct {
@@ -487,4 +799,11 @@ type WorkflowHookCommandContext struct {
 	User User
 	// Verbose is true when the user would like verbose output.
 	Verbose bool
+	// EnvPassthrough is which of the Atlantis process's environment variables
+	// are passed to the hooks. If nil, all of them except Atlantis' secrets
+	// are.
+	EnvPassthrough *valid.EnvPassthrough
+	// Sandbox is the sandbox the hooks run in or nil if they don't run in
+	// one.
+	Sandbox *valid.Sandbox
 }
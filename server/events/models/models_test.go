@@ -389,3 +389,45 @@ func TestPullStatus_StatusCount(t *testing.T) {
 	Equals(t, 1, ps.StatusCount(models.ErroredPolicyCheckStatus))
 	Equals(t, 1, ps.StatusCount(models.PassedPolicyCheckStatus))
 }
+
+func TestPullStatus_PlanChangeCounts(t *testing.T) {
+	ps := models.PullStatus{
+		Projects: []models.ProjectStatus{
+			{
+				Status: models.ErroredPlanStatus,
+			},
+		},
+	}
+	_, ok := ps.PlanChangeCounts()
+	Equals(t, false, ok)
+
+	ps.Projects = append(ps.Projects,
+		models.ProjectStatus{
+			Status:      models.PlannedPlanStatus,
+			PlanChanges: &models.PlanChangeCounts{Add: 2, Change: 1},
+		},
+		models.ProjectStatus{
+			Status:      models.AppliedPlanStatus,
+			PlanChanges: &models.PlanChangeCounts{Add: 1, Destroy: 1, Import: 3},
+		},
+	)
+	counts, ok := ps.PlanChangeCounts()
+	Equals(t, true, ok)
+	Equals(t, models.PlanChangeCounts{Add: 3, Change: 1, Destroy: 1, Import: 3}, counts)
+}
+
+func TestPlanChangeCounts_String(t *testing.T) {
+	cases := []struct {
+		counts models.PlanChangeCounts
+		exp    string
+	}{
+		{models.PlanChangeCounts{}, "no changes"},
+		{models.PlanChangeCounts{Add: 3, Destroy: 1}, "3 to add, 1 to destroy"},
+		{models.PlanChangeCounts{Add: 1, Change: 2, Destroy: 3, Import: 4}, "1 to add, 2 to change, 3 to destroy, 4 to import"},
+	}
+	for _, c := range cases {
+		t.Run(c.exp, func(t *testing.T) {
+			Equals(t, c.exp, c.counts.String())
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+// findModuleDirs returns the directories of the local modules called by the
+// Terraform configuration in projectDir, directly or through other local
+// modules. Only modules with a local source, ex. ../modules/network, are
+// followed. projectDir and the returned directories are relative to
+// absRepoDir. Modules outside of the repo are ignored.
+func findModuleDirs(log logging.SimpleLogging, absRepoDir string, projectDir string) []string {
+	var dirs []string
+	visited := make(map[string]bool)
+	var visit func(dir string)
+	visit = func(dir string) {
+		if visited[dir] {
+			return
+		}
+		visited[dir] = true
+
+		module, diags := tfconfig.LoadModule(filepath.Join(absRepoDir, dir))
+		if diags.HasErrors() {
+			// The module is still returned with whatever could be parsed.
+			log.Debug("parsing module at dir %q: %s", dir, diags.Error())
+		}
+		for _, call := range module.ModuleCalls {
+			if !isLocalModuleSource(call.Source) {
+				continue
+			}
+			moduleDir := filepath.Join(dir, call.Source)
+			if moduleDir == ".." || strings.HasPrefix(moduleDir, "../") {
+				log.Debug("ignoring module %q at %q since it is outside the repo", call.Name, call.Source)
+				continue
+			}
+			if !visited[moduleDir] {
+				dirs = append(dirs, moduleDir)
+			}
+			visit(moduleDir)
+		}
+	}
+	visit(filepath.Clean(projectDir))
+	return dirs
+}
+
+// isLocalModuleSource returns true if source is a path to a module on disk
+// rather than a registry or remote source. Terraform requires local paths to
+// start with ./ or ../ to tell them apart from registry addresses.
+func isLocalModuleSource(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")
+}
+
+// findTerraformDirs returns the directories in absRepoDir, relative to it,
+// that contain .tf files.
+func findTerraformDirs(absRepoDir string) ([]string, error) {
+	var dirs []string
+	seen := make(map[string]bool)
+	err := filepath.Walk(absRepoDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" || info.Name() == ".terraform" || info.Name() == ".terragrunt-cache" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".tf" {
+			return nil
+		}
+		relDir, err := filepath.Rel(absRepoDir, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		if !seen[relDir] {
+			seen[relDir] = true
+			dirs = append(dirs, relDir)
+		}
+		return nil
+	})
+	return dirs, err
+}
+
+// dirsOfFiles returns the set of directories that contain files. files are
+// relative to the repo root.
+func dirsOfFiles(files []string) map[string]bool {
+	dirs := make(map[string]bool)
+	for _, f := range files {
+		dirs[filepath.Dir(f)] = true
+	}
+	return dirs
+}
@@ -111,7 +111,10 @@ func (p *PlanCommandRunner) runAutoplan(ctx *command.Context) {
 
 	// Only run commands in parallel if enabled
 	var result command.Result
-	if p.isParallelEnabled(projectCmds) {
+	if hasProjectDependencies(projectCmds) {
+		ctx.Log.Info("Running plans in dependency order")
+		result = runProjectCmdsWithDependencies(projectCmds, p.prjCmdRunner.Plan, p.isParallelEnabled(projectCmds), p.parallelPoolSize)
+	} else if p.isParallelEnabled(projectCmds) {
 		ctx.Log.Info("Running plans in parallel")
 		result = runProjectCmdsParallel(projectCmds, p.prjCmdRunner.Plan, p.parallelPoolSize)
 	} else {
@@ -193,7 +196,10 @@ func (p *PlanCommandRunner) run(ctx *command.Context, cmd *CommentCommand) {
 
 	// Only run commands in parallel if enabled
 	var result command.Result
-	if p.isParallelEnabled(projectCmds) {
+	if hasProjectDependencies(projectCmds) {
+		ctx.Log.Info("Running plans in dependency order")
+		result = runProjectCmdsWithDependencies(projectCmds, p.prjCmdRunner.Plan, p.isParallelEnabled(projectCmds), p.parallelPoolSize)
+	} else if p.isParallelEnabled(projectCmds) {
 		ctx.Log.Info("Running applies in parallel")
 		result = runProjectCmdsParallel(projectCmds, p.prjCmdRunner.Plan, p.parallelPoolSize)
 	} else {
@@ -251,13 +257,20 @@ func (p *PlanCommandRunner) updateCommitStatus(ctx *command.Context, pullStatus
 		status = models.FailedCommitStatus
 	}
 
-	if err := p.commitStatusUpdater.UpdateCombinedCount(
+	// Include the resource counts of the plans in the status so reviewers
+	// can see what the pull request changes at a glance.
+	var changes *models.PlanChangeCounts
+	if counts, ok := pullStatus.PlanChangeCounts(); ok {
+		changes = &counts
+	}
+
+	if err := p.commitStatusUpdater.UpdateCombinedPlanCount(
 		ctx.Pull.BaseRepo,
 		ctx.Pull,
 		status,
-		command.Plan,
 		numSuccess,
 		len(pullStatus.Projects),
+		changes,
 	); err != nil {
 		ctx.Log.Warn("unable to update commit status: %s", err)
 	}
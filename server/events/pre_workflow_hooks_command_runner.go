@@ -61,12 +61,14 @@ func (w *DefaultPreWorkflowHooksCommandRunner) RunPreHooks(
 
 	err = w.runHooks(
 		models.WorkflowHookCommandContext{
-			BaseRepo: baseRepo,
-			HeadRepo: headRepo,
-			Log:      log,
-			Pull:     pull,
-			User:     user,
-			Verbose:  false,
+			BaseRepo:       baseRepo,
+			HeadRepo:       headRepo,
+			Log:            log,
+			Pull:           pull,
+			User:           user,
+			Verbose:        false,
+			EnvPassthrough: w.GlobalCfg.EnvPassthrough(baseRepo.ID()),
+			Sandbox:        w.GlobalCfg.Sandbox(baseRepo.ID()),
 		},
 		preWorkflowHooks, repoDir)
 
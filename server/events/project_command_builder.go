@@ -132,6 +132,20 @@ type ProjectVersionCommandBuilder interface {
 	BuildVersionCommands(ctx *command.Context, comment *CommentCommand) ([]command.ProjectContext, error)
 }
 
+type ProjectImportCommandBuilder interface {
+	// BuildImportCommands builds project Import commands for this ctx and
+	// comment. If comment doesn't specify a project then the command is for
+	// the root directory and default workspace.
+	BuildImportCommands(ctx *command.Context, comment *CommentCommand) ([]command.ProjectContext, error)
+}
+
+type ProjectStateCommandBuilder interface {
+	// BuildStateCommands builds project StateRm or StateMv commands for this
+	// ctx and comment. If comment doesn't specify a project then the command
+	// is for the root directory and default workspace.
+	BuildStateCommands(ctx *command.Context, comment *CommentCommand) ([]command.ProjectContext, error)
+}
+
 //go:generate pegomock generate -m --use-experimental-model-gen --package mocks -o mocks/mock_project_command_builder.go ProjectCommandBuilder
 
 // ProjectCommandBuilder builds commands that run on individual projects.
@@ -140,6 +154,8 @@ type ProjectCommandBuilder interface {
 	ProjectApplyCommandBuilder
 	ProjectApprovePoliciesCommandBuilder
 	ProjectVersionCommandBuilder
+	ProjectImportCommandBuilder
+	ProjectStateCommandBuilder
 }
 
 // DefaultProjectCommandBuilder implements ProjectCommandBuilder.
@@ -196,7 +212,14 @@ func (p *DefaultProjectCommandBuilder) BuildApplyCommands(ctx *command.Context,
 }
 
 func (p *DefaultProjectCommandBuilder) BuildApprovePoliciesCommands(ctx *command.Context, cmd *CommentCommand) ([]command.ProjectContext, error) {
-	return p.buildAllProjectCommands(ctx, cmd)
+	projCtxs, err := p.buildAllProjectCommands(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	for i := range projCtxs {
+		projCtxs[i].PolicySetTarget = cmd.PolicySet
+	}
+	return projCtxs, nil
 }
 
 func (p *DefaultProjectCommandBuilder) BuildVersionCommands(ctx *command.Context, cmd *CommentCommand) ([]command.ProjectContext, error) {
@@ -207,6 +230,16 @@ func (p *DefaultProjectCommandBuilder) BuildVersionCommands(ctx *command.Context
 	return pac, err
 }
 
+// See ProjectCommandBuilder.BuildImportCommands.
+func (p *DefaultProjectCommandBuilder) BuildImportCommands(ctx *command.Context, cmd *CommentCommand) ([]command.ProjectContext, error) {
+	return p.buildProjectStateCommand(ctx, cmd)
+}
+
+// See ProjectCommandBuilder.BuildStateCommands.
+func (p *DefaultProjectCommandBuilder) BuildStateCommands(ctx *command.Context, cmd *CommentCommand) ([]command.ProjectContext, error) {
+	return p.buildProjectStateCommand(ctx, cmd)
+}
+
 // buildPlanAllCommands builds plan contexts for all projects we determine were
 // modified in this ctx.
 func (p *DefaultProjectCommandBuilder) buildPlanAllCommands(ctx *command.Context, commentFlags []string, verbose bool) ([]command.ProjectContext, error) {
@@ -551,6 +584,58 @@ func (p *DefaultProjectCommandBuilder) buildProjectVersionCommand(ctx *command.C
 	)
 }
 
+// buildProjectStateCommand builds an import or state command for the single
+// project identified by cmd. Unlike apply, the repo is cloned if needed since
+// the state can be fixed before anything was planned.
+func (p *DefaultProjectCommandBuilder) buildProjectStateCommand(ctx *command.Context, cmd *CommentCommand) ([]command.ProjectContext, error) {
+	workspace := DefaultWorkspace
+	if cmd.Workspace != "" {
+		workspace = cmd.Workspace
+	}
+
+	var projCtx []command.ProjectContext
+	unlockFn, err := p.WorkingDirLocker.TryLock(ctx.Pull.BaseRepo.FullName, ctx.Pull.Num, workspace, DefaultRepoRelDir)
+	if err != nil {
+		return projCtx, err
+	}
+	defer unlockFn()
+
+	ctx.Log.Debug("cloning repository")
+	_, _, err = p.WorkingDir.Clone(ctx.Log, ctx.HeadRepo, ctx.Pull, workspace)
+	if err != nil {
+		return projCtx, err
+	}
+
+	// use the default repository workspace because it is the only one guaranteed to have an atlantis.yaml,
+	// other workspaces will not have the file if they are using pre_workflow_hooks to generate it dynamically
+	repoDir, err := p.WorkingDir.GetWorkingDir(ctx.Pull.BaseRepo, ctx.Pull, DefaultWorkspace)
+	if err != nil {
+		return projCtx, err
+	}
+
+	repoRelDir := DefaultRepoRelDir
+	if cmd.RepoRelDir != "" {
+		repoRelDir = cmd.RepoRelDir
+	}
+
+	// The flags come before the addresses since terraform doesn't allow
+	// options after them.
+	var commentArgs []string
+	commentArgs = append(commentArgs, cmd.Flags...)
+	commentArgs = append(commentArgs, cmd.Args...)
+
+	return p.buildProjectCommandCtx(
+		ctx,
+		cmd.Name,
+		cmd.ProjectName,
+		commentArgs,
+		repoDir,
+		repoRelDir,
+		workspace,
+		cmd.Verbose,
+	)
+}
+
 // buildProjectCommandCtx builds a context for a single or several projects identified
 // by the parameters.
 func (p *DefaultProjectCommandBuilder) buildProjectCommandCtx(ctx *command.Context,
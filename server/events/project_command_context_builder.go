@@ -94,15 +94,21 @@ func (cb *DefaultProjectCommandContextBuilder) BuildProjectContext(
 ) (projectCmds []command.ProjectContext) {
 	ctx.Log.Debug("Building project command context for %s", cmdName)
 
-	var steps []valid.Step
+	var stage valid.Stage
 	switch cmdName {
 	case command.Plan:
-		steps = prjCfg.Workflow.Plan.Steps
+		stage = prjCfg.Workflow.Plan
 	case command.Apply:
-		steps = prjCfg.Workflow.Apply.Steps
+		stage = prjCfg.Workflow.Apply
+	case command.Import:
+		stage = prjCfg.Workflow.Import
+	case command.StateRm:
+		stage = prjCfg.Workflow.StateRm
+	case command.StateMv:
+		stage = prjCfg.Workflow.StateMv
 	case command.Version:
 		// Setting statically since there will only be one step
-		steps = []valid.Step{{
+		stage.Steps = []valid.Step{{
 			StepName: "version",
 		}}
 	}
@@ -113,14 +119,21 @@ func (cb *DefaultProjectCommandContextBuilder) BuildProjectContext(
 		prjCfg.TerraformVersion = getTfVersion(ctx, filepath.Join(repoDir, prjCfg.RepoRelDir))
 	}
 
+	// The comment flags of import and state commands include their addresses
+	// so they can't be reused when planning again.
+	planCommentFlags := commentFlags
+	if cmdName == command.Import || cmdName == command.StateRm || cmdName == command.StateMv {
+		planCommentFlags = nil
+	}
+
 	projectCmdContext := newProjectCommandContext(
 		ctx,
 		cmdName,
 		cb.CommentBuilder.BuildApplyComment(prjCfg.RepoRelDir, prjCfg.Workspace, prjCfg.Name, prjCfg.AutoMergeDisabled),
-		cb.CommentBuilder.BuildPlanComment(prjCfg.RepoRelDir, prjCfg.Workspace, prjCfg.Name, commentFlags),
+		cb.CommentBuilder.BuildPlanComment(prjCfg.RepoRelDir, prjCfg.Workspace, prjCfg.Name, planCommentFlags),
 		cb.CommentBuilder.BuildVersionComment(prjCfg.RepoRelDir, prjCfg.Workspace, prjCfg.Name),
 		prjCfg,
-		steps,
+		stage,
 		prjCfg.PolicySets,
 		escapeArgs(commentFlags),
 		automerge,
@@ -173,7 +186,6 @@ func (cb *PolicyCheckProjectCommandContextBuilder) BuildProjectContext(
 
 	if cmdName == command.Plan {
 		ctx.Log.Debug("Building project command context for %s", command.PolicyCheck)
-		steps := prjCfg.Workflow.PolicyCheck.Steps
 
 		projectCmds = append(projectCmds, newProjectCommandContext(
 			ctx,
@@ -182,7 +194,7 @@ func (cb *PolicyCheckProjectCommandContextBuilder) BuildProjectContext(
 			cb.CommentBuilder.BuildPlanComment(prjCfg.RepoRelDir, prjCfg.Workspace, prjCfg.Name, commentFlags),
 			cb.CommentBuilder.BuildVersionComment(prjCfg.RepoRelDir, prjCfg.Workspace, prjCfg.Name),
 			prjCfg,
-			steps,
+			prjCfg.Workflow.PolicyCheck,
 			prjCfg.PolicySets,
 			escapeArgs(commentFlags),
 			automerge,
@@ -206,7 +218,7 @@ func newProjectCommandContext(ctx *command.Context,
 	planCmd string,
 	versionCmd string,
 	projCfg valid.MergedProjectCfg,
-	steps []valid.Step,
+	stage valid.Stage,
 	policySets valid.PolicySets,
 	escapedCommentArgs []string,
 	automergeEnabled bool,
@@ -219,6 +231,7 @@ func newProjectCommandContext(ctx *command.Context,
 ) command.ProjectContext {
 
 	var projectPlanStatus models.ProjectPlanStatus
+	var projectPolicyStatus []models.PolicySetStatus
 
 	if ctx.PullStatus != nil {
 		for _, project := range ctx.PullStatus.Projects {
@@ -226,11 +239,13 @@ func newProjectCommandContext(ctx *command.Context,
 			// if name is not used, let's match the directory
 			if projCfg.Name == "" && project.RepoRelDir == projCfg.RepoRelDir {
 				projectPlanStatus = project.Status
+				projectPolicyStatus = project.PolicyStatus
 				break
 			}
 
 			if projCfg.Name != "" && project.ProjectName == projCfg.Name {
 				projectPlanStatus = project.Status
+				projectPolicyStatus = project.PolicyStatus
 				break
 			}
 		}
@@ -247,11 +262,13 @@ func newProjectCommandContext(ctx *command.Context,
 		ParallelPlanEnabled:        parallelPlanEnabled,
 		ParallelPolicyCheckEnabled: parallelPlanEnabled,
 		AutoplanEnabled:            projCfg.AutoplanEnabled,
-		Steps:                      steps,
+		Steps:                      stage.Steps,
+		Timeout:                    stage.Timeout,
 		HeadRepo:                   ctx.HeadRepo,
 		Log:                        ctx.Log,
 		Scope:                      scope,
 		ProjectPlanStatus:          projectPlanStatus,
+		ProjectPolicyStatus:        projectPolicyStatus,
 		Pull:                       ctx.Pull,
 		ProjectName:                projCfg.Name,
 		ApplyRequirements:          projCfg.ApplyRequirements,
@@ -264,6 +281,9 @@ func newProjectCommandContext(ctx *command.Context,
 		Workspace:                  projCfg.Workspace,
 		PolicySets:                 policySets,
 		PullReqStatus:              pullStatus,
+		DependsOn:                  projCfg.DependsOn,
+		EnvPassthrough:             projCfg.EnvPassthrough,
+		Sandbox:                    projCfg.Sandbox,
 		JobID:                      uuid.New().String(),
 	}
 }
@@ -103,3 +103,29 @@ func TestProjectCommandContextBuilder_PullStatus(t *testing.T) {
 		assert.False(t, result[0].ParallelPlanEnabled)
 	})
 }
+
+func TestProjectCommandContextBuilder_Import(t *testing.T) {
+	RegisterMockTestingT(t)
+	mockCommentBuilder := mocks.NewMockCommentBuilder()
+	subject := events.DefaultProjectCommandContextBuilder{
+		CommentBuilder: mockCommentBuilder,
+	}
+	projCfg := valid.MergedProjectCfg{
+		RepoRelDir: "dir1",
+		Workspace:  "default",
+		Workflow: valid.Workflow{
+			Name:   valid.DefaultWorkflowName,
+			Import: valid.DefaultImportStage,
+		},
+	}
+	commandCtx := &command.Context{
+		Log: logging.NewNoopLogger(t),
+	}
+	When(mockCommentBuilder.BuildPlanComment("dir1", "default", "", []string(nil))).ThenReturn("atlantis plan -d dir1")
+
+	result := subject.BuildProjectContext(commandCtx, command.Import, projCfg, []string{"aws_instance.web", "i-1234"}, "some/dir", false, false, false, false, false)
+	assert.Equal(t, valid.DefaultImportStage.Steps, result[0].Steps)
+	assert.Equal(t, []string{`\a\w\s\_\i\n\s\t\a\n\c\e\.\w\e\b`, `\i\-\1\2\3\4`}, result[0].EscapedCommentArgs)
+	// The address and ID can't be reused when planning again.
+	assert.Equal(t, "atlantis plan -d dir1", result[0].RePlanCmd)
+}
@@ -1,10 +1,12 @@
 package events
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/remeh/sizedwaitgroup"
 	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
 )
 
 type prjCmdRunnerFunc func(ctx command.ProjectContext) command.ProjectResult
@@ -50,3 +52,117 @@ func runProjectCmds(
 	}
 	return command.Result{ProjectResults: results}
 }
+
+// hasProjectDependencies returns true if any of cmds depends on another
+// project.
+func hasProjectDependencies(cmds []command.ProjectContext) bool {
+	for _, cmd := range cmds {
+		if len(cmd.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// runProjectCmdsWithDependencies runs cmds in the order set by depends_on.
+// Commands are split into groups where each group only depends on earlier
+// groups. The commands in a group are run in parallel if parallel is true.
+// If a command fails, the commands that depend on it, directly or
+// indirectly, are skipped. Dependencies on projects that aren't in cmds are
+// ignored.
+func runProjectCmdsWithDependencies(
+	cmds []command.ProjectContext,
+	runnerFunc prjCmdRunnerFunc,
+	parallel bool,
+	poolSize int,
+) command.Result {
+	var results []command.ProjectResult
+	failed := make(map[string]bool)
+	for _, group := range groupProjectCmdsByDependencies(cmds) {
+		var toRun []command.ProjectContext
+		for _, cmd := range group {
+			var failedDep string
+			for _, dep := range cmd.DependsOn {
+				if failed[dep] {
+					failedDep = dep
+					break
+				}
+			}
+			if failedDep == "" {
+				toRun = append(toRun, cmd)
+				continue
+			}
+			cmd.Log.Info("skipping because dependency %q failed", failedDep)
+			results = append(results, command.ProjectResult{
+				Command:     cmd.CommandName,
+				RepoRelDir:  cmd.RepoRelDir,
+				Workspace:   cmd.Workspace,
+				ProjectName: cmd.ProjectName,
+				Failure:     fmt.Sprintf("Skipped because dependency %q failed.", failedDep),
+			})
+			failed[cmd.ProjectName] = true
+		}
+
+		var groupResult command.Result
+		if parallel {
+			groupResult = runProjectCmdsParallel(toRun, runnerFunc, poolSize)
+		} else {
+			groupResult = runProjectCmds(toRun, runnerFunc)
+		}
+		for _, res := range groupResult.ProjectResults {
+			if res.CommitStatus() == models.FailedCommitStatus {
+				failed[res.ProjectName] = true
+			}
+		}
+		results = append(results, groupResult.ProjectResults...)
+	}
+	return command.Result{ProjectResults: results}
+}
+
+// groupProjectCmdsByDependencies splits cmds into groups so that every
+// command only depends on commands in earlier groups. The order of cmds is
+// kept within each group.
+func groupProjectCmdsByDependencies(cmds []command.ProjectContext) [][]command.ProjectContext {
+	// Multiple commands can have the same project name if they're in
+	// different workspaces. A dependent waits for all of them.
+	remainingByName := make(map[string]int)
+	for _, cmd := range cmds {
+		if cmd.ProjectName != "" {
+			remainingByName[cmd.ProjectName]++
+		}
+	}
+
+	var groups [][]command.ProjectContext
+	remaining := cmds
+	for len(remaining) > 0 {
+		var group, next []command.ProjectContext
+		for _, cmd := range remaining {
+			ready := true
+			for _, dep := range cmd.DependsOn {
+				if remainingByName[dep] > 0 {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				group = append(group, cmd)
+			} else {
+				next = append(next, cmd)
+			}
+		}
+		// Cycles are caught when the config is validated but if one gets
+		// through we run the rest of the commands together rather than
+		// looping forever.
+		if len(group) == 0 {
+			group, next = next, nil
+		}
+		for _, cmd := range group {
+			if cmd.ProjectName != "" {
+				remainingByName[cmd.ProjectName]--
+			}
+		}
+		groups = append(groups, group)
+		remaining = next
+	}
+	return groups
+}
@@ -0,0 +1,138 @@
+package events
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestRunProjectCmdsWithDependencies(t *testing.T) {
+	logger := logging.NewNoopLogger(t)
+	newCmd := func(name string, dependsOn ...string) command.ProjectContext {
+		return command.ProjectContext{
+			CommandName: command.Apply,
+			ProjectName: name,
+			RepoRelDir:  name,
+			Workspace:   "default",
+			DependsOn:   dependsOn,
+			Log:         logger,
+		}
+	}
+
+	cases := []struct {
+		description string
+		cmds        []command.ProjectContext
+		failing     []string
+		expOrder    []string
+		expResults  map[string]string
+	}{
+		{
+			description: "runs dependencies first",
+			cmds: []command.ProjectContext{
+				newCmd("app", "network", "db"),
+				newCmd("db", "network"),
+				newCmd("network"),
+			},
+			expOrder: []string{"network", "db", "app"},
+			expResults: map[string]string{
+				"network": "",
+				"db":      "",
+				"app":     "",
+			},
+		},
+		{
+			description: "ignores dependencies that aren't being run",
+			cmds: []command.ProjectContext{
+				newCmd("app", "network"),
+			},
+			expOrder: []string{"app"},
+			expResults: map[string]string{
+				"app": "",
+			},
+		},
+		{
+			description: "skips dependents of failed projects",
+			cmds: []command.ProjectContext{
+				newCmd("network"),
+				newCmd("db", "network"),
+				newCmd("app", "db"),
+				newCmd("dns"),
+			},
+			failing:  []string{"network"},
+			expOrder: []string{"network", "dns"},
+			expResults: map[string]string{
+				"network": "failed",
+				"db":      `Skipped because dependency "network" failed.`,
+				"app":     `Skipped because dependency "db" failed.`,
+				"dns":     "",
+			},
+		},
+	}
+	for _, c := range cases {
+		for _, parallel := range []bool{false, true} {
+			t.Run(c.description, func(t *testing.T) {
+				var order []string
+				mux := &sync.Mutex{}
+				runner := func(ctx command.ProjectContext) command.ProjectResult {
+					mux.Lock()
+					order = append(order, ctx.ProjectName)
+					mux.Unlock()
+					res := command.ProjectResult{
+						Command:      ctx.CommandName,
+						ProjectName:  ctx.ProjectName,
+						ApplySuccess: "success",
+					}
+					for _, f := range c.failing {
+						if f == ctx.ProjectName {
+							res = command.ProjectResult{
+								Command:     ctx.CommandName,
+								ProjectName: ctx.ProjectName,
+								Failure:     "failed",
+							}
+						}
+					}
+					return res
+				}
+
+				result := runProjectCmdsWithDependencies(c.cmds, runner, parallel, 2)
+				if !parallel {
+					Equals(t, c.expOrder, order)
+				} else {
+					Equals(t, len(c.expOrder), len(order))
+				}
+
+				failures := make(map[string]string)
+				for _, res := range result.ProjectResults {
+					failures[res.ProjectName] = res.Failure
+				}
+				Equals(t, c.expResults, failures)
+			})
+		}
+	}
+}
+
+func TestGroupProjectCmdsByDependencies(t *testing.T) {
+	cmds := []command.ProjectContext{
+		{ProjectName: "app", DependsOn: []string{"db"}},
+		{ProjectName: "db", Workspace: "staging", DependsOn: []string{"network"}},
+		{ProjectName: "db", Workspace: "production", DependsOn: []string{"network"}},
+		{ProjectName: "network"},
+		{RepoRelDir: "unnamed"},
+	}
+	var names [][]string
+	for _, group := range groupProjectCmdsByDependencies(cmds) {
+		var groupNames []string
+		for _, cmd := range group {
+			groupNames = append(groupNames, cmd.ProjectName+cmd.Workspace+cmd.RepoRelDir)
+		}
+		names = append(names, groupNames)
+	}
+	Equals(t, [][]string{
+		{"network", "unnamed"},
+		{"dbstaging", "dbproduction"},
+		{"app"},
+	}, names)
+}
@@ -14,17 +14,23 @@
 package events
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/raw"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/runtime"
+	runtime_models "github.com/runatlantis/atlantis/server/core/runtime/models"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/webhooks"
+	"github.com/runatlantis/atlantis/server/jobs"
 	"github.com/runatlantis/atlantis/server/logging"
 )
 
@@ -77,7 +83,16 @@ type EnvStepRunner interface {
 // WebhooksSender sends webhook.
 type WebhooksSender interface {
 	// Send sends the webhook.
-	Send(log logging.SimpleLogging, res webhooks.ApplyResult) error
+	Send(log logging.SimpleLogging, res webhooks.EventResult) error
+}
+
+// sendWebhook sends res with sender. sender may be nil if there's nothing to
+// send webhooks with.
+func sendWebhook(sender WebhooksSender, log logging.SimpleLogging, res webhooks.EventResult) {
+	if sender == nil {
+		return
+	}
+	sender.Send(log, res) // nolint: errcheck
 }
 
 //go:generate pegomock generate -m --use-experimental-model-gen --package mocks -o mocks/mock_project_command_runner.go ProjectCommandRunner
@@ -107,6 +122,17 @@ type ProjectVersionCommandRunner interface {
 	Version(ctx command.ProjectContext) command.ProjectResult
 }
 
+type ProjectImportCommandRunner interface {
+	// Import runs terraform import for the project described by ctx.
+	Import(ctx command.ProjectContext) command.ProjectResult
+}
+
+type ProjectStateCommandRunner interface {
+	// State runs terraform state rm or state mv for the project described by
+	// ctx, depending on ctx.CommandName.
+	State(ctx command.ProjectContext) command.ProjectResult
+}
+
 // ProjectCommandRunner runs project commands. A project command is a command
 // for a specific TF project.
 type ProjectCommandRunner interface {
@@ -115,6 +141,15 @@ type ProjectCommandRunner interface {
 	ProjectPolicyCheckCommandRunner
 	ProjectApprovePoliciesCommandRunner
 	ProjectVersionCommandRunner
+	ProjectImportCommandRunner
+	ProjectStateCommandRunner
+}
+
+// StepsRunner runs the steps of a project command.
+type StepsRunner interface {
+	// RunSteps runs steps in absPath, the dir of the project described by
+	// ctx, and returns the output of each step that has any.
+	RunSteps(ctx command.ProjectContext, steps []valid.Step, absPath string) ([]string, error)
 }
 
 //go:generate pegomock generate -m --use-experimental-model-gen --package mocks -o mocks/mock_job_url_setter.go JobURLSetter
@@ -187,6 +222,9 @@ type DefaultProjectCommandRunner struct {
 	ApplyStepRunner            StepRunner
 	PolicyCheckStepRunner      StepRunner
 	VersionStepRunner          StepRunner
+	ImportStepRunner           StepRunner
+	StateRmStepRunner          StepRunner
+	StateMvStepRunner          StepRunner
 	RunStepRunner              CustomStepRunner
 	EnvStepRunner              EnvStepRunner
 	PullApprovedChecker        runtime.PullApprovedChecker
@@ -194,6 +232,26 @@ type DefaultProjectCommandRunner struct {
 	Webhooks                   WebhooksSender
 	WorkingDirLocker           WorkingDirLocker
 	AggregateApplyRequirements ApplyRequirement
+	// ProjectJobURLGenerator generates the job urls sent in webhooks. If nil,
+	// webhooks are sent without a job url.
+	ProjectJobURLGenerator jobs.ProjectJobURLGenerator
+	// RunningCommands tracks the commands whose steps are running so they
+	// can be cancelled. If nil, commands can't be cancelled.
+	RunningCommands *RunningCommands
+	// StepsRunner runs the steps of each command, ex. on remote workers. If
+	// nil, they're run in this process.
+	StepsRunner StepsRunner
+	// ConcurrencyLimiter limits how many plans and applies run at a time. If
+	// nil, they aren't limited.
+	ConcurrencyLimiter *ConcurrencyLimiter
+	// CommitStatusUpdater and JobMessageSender tell users that a plan or
+	// apply is waiting in the ConcurrencyLimiter's queue. Either can be nil.
+	CommitStatusUpdater CommitStatusUpdater
+	JobMessageSender    JobMessageSender
+	// EnablePlanSummary analyzes every plan to summarize the resources it
+	// changes in the plan comment and commit status. Otherwise plans are only
+	// analyzed for the max_destroys and no_replace apply requirements.
+	EnablePlanSummary bool
 }
 
 // Plan runs terraform plan for the project described by ctx.
@@ -212,10 +270,12 @@ func (p *DefaultProjectCommandRunner) Plan(ctx command.ProjectContext) command.P
 
 // PolicyCheck evaluates policies defined with Rego for the project described by ctx.
 func (p *DefaultProjectCommandRunner) PolicyCheck(ctx command.ProjectContext) command.ProjectResult {
-	policySuccess, failure, err := p.doPolicyCheck(ctx)
+	policySuccess, policyStatus, policySetResults, failure, err := p.doPolicyCheck(ctx)
 	return command.ProjectResult{
 		Command:            command.PolicyCheck,
 		PolicyCheckSuccess: policySuccess,
+		PolicyStatus:       policyStatus,
+		PolicySetResults:   policySetResults,
 		Error:              err,
 		Failure:            failure,
 		RepoRelDir:         ctx.RepoRelDir,
@@ -239,12 +299,13 @@ func (p *DefaultProjectCommandRunner) Apply(ctx command.ProjectContext) command.
 }
 
 func (p *DefaultProjectCommandRunner) ApprovePolicies(ctx command.ProjectContext) command.ProjectResult {
-	approvedOut, failure, err := p.doApprovePolicies(ctx)
+	approvedOut, policyStatus, failure, err := p.doApprovePolicies(ctx)
 	return command.ProjectResult{
 		Command:            command.PolicyCheck,
 		Failure:            failure,
 		Error:              err,
 		PolicyCheckSuccess: approvedOut,
+		PolicyStatus:       policyStatus,
 		RepoRelDir:         ctx.RepoRelDir,
 		Workspace:          ctx.Workspace,
 		ProjectName:        ctx.ProjectName,
@@ -264,17 +325,166 @@ func (p *DefaultProjectCommandRunner) Version(ctx command.ProjectContext) comman
 	}
 }
 
-func (p *DefaultProjectCommandRunner) doApprovePolicies(ctx command.ProjectContext) (*models.PolicyCheckSuccess, string, error) {
+// Import runs terraform import for the project described by ctx.
+func (p *DefaultProjectCommandRunner) Import(ctx command.ProjectContext) command.ProjectResult {
+	out, failure, err := p.doStateChange(ctx)
+	var importSuccess *models.ImportSuccess
+	if failure == "" && err == nil {
+		importSuccess = &models.ImportSuccess{
+			Output:    out,
+			RePlanCmd: ctx.RePlanCmd,
+		}
+	}
+	return command.ProjectResult{
+		Command:       command.Import,
+		Failure:       failure,
+		Error:         err,
+		ImportSuccess: importSuccess,
+		RepoRelDir:    ctx.RepoRelDir,
+		Workspace:     ctx.Workspace,
+		ProjectName:   ctx.ProjectName,
+	}
+}
 
-	// TODO: Make this a bit smarter
-	// without checking some sort of state that the policy check has indeed passed this is likely to cause issues
+// State runs terraform state rm or state mv for the project described by ctx.
+func (p *DefaultProjectCommandRunner) State(ctx command.ProjectContext) command.ProjectResult {
+	out, failure, err := p.doStateChange(ctx)
+	var stateSuccess *models.StateSuccess
+	if failure == "" && err == nil {
+		stateSuccess = &models.StateSuccess{
+			Output:    out,
+			RePlanCmd: ctx.RePlanCmd,
+		}
+	}
+	return command.ProjectResult{
+		Command:      ctx.CommandName,
+		Failure:      failure,
+		Error:        err,
+		StateSuccess: stateSuccess,
+		RepoRelDir:   ctx.RepoRelDir,
+		Workspace:    ctx.Workspace,
+		ProjectName:  ctx.ProjectName,
+	}
+}
+
+// jobURL returns the url of ctx's job or an empty string if it can't be
+// generated.
+func (p *DefaultProjectCommandRunner) jobURL(ctx command.ProjectContext) string {
+	if p.ProjectJobURLGenerator == nil {
+		return ""
+	}
+	url, err := p.ProjectJobURLGenerator.GenerateProjectJobURL(ctx)
+	if err != nil {
+		ctx.Log.Warn("generating job url: %s", err)
+	}
+	return url
+}
+
+// sendCommandWebhook sends the webhook for a command whose steps started at
+// start and ended with err. It's only sent once the steps ran so that ex. a
+// Slack webhook doesn't report that an apply failed when it didn't run
+// because the apply requirements weren't met.
+func (p *DefaultProjectCommandRunner) sendCommandWebhook(ctx command.ProjectContext, event string, start time.Time, err error) {
+	if p.Webhooks == nil {
+		return
+	}
+	status := webhooks.SuccessStatus
+	if err != nil {
+		status = webhooks.ErrorStatus
+	}
+	jobURL := p.jobURL(ctx)
+	sendWebhook(p.Webhooks, ctx.Log, webhooks.EventResult{
+		Event:       event,
+		Workspace:   ctx.Workspace,
+		User:        ctx.User,
+		Repo:        ctx.Pull.BaseRepo,
+		Pull:        ctx.Pull,
+		Success:     status == webhooks.SuccessStatus,
+		Directory:   ctx.RepoRelDir,
+		ProjectName: ctx.ProjectName,
+		Status:      status,
+		Duration:    time.Since(start),
+		JobURL:      jobURL,
+	})
+}
+
+// doApprovePolicies records ctx.User's approval of the failing policy sets
+// they own, or only of ctx.PolicySetTarget if set. The policy check only
+// passes once every failing policy set has been approved.
+func (p *DefaultProjectCommandRunner) doApprovePolicies(ctx command.ProjectContext) (*models.PolicyCheckSuccess, []models.PolicySetStatus, string, error) {
+	user := ctx.User.Username
+
+	// Policy checks run before we tracked each policy set don't have any
+	// status so we fall back to letting the top-level owners approve them.
+	if len(ctx.ProjectPolicyStatus) == 0 {
+		if ctx.PolicySetTarget != "" {
+			return nil, nil, fmt.Sprintf("Policy set %q has no policy check results, run plan again.", ctx.PolicySetTarget), nil
+		}
+		if !ctx.PolicySets.IsOwner(user, ctx.UserTeams) {
+			return nil, nil, "Contact policy owners to approve failing policies.", nil
+		}
+		return &models.PolicyCheckSuccess{
+			PolicyCheckOutput: "Policies approved",
+		}, nil, "", nil
+	}
 
+	var policyStatus []models.PolicySetStatus
+	var approved, notOwned, pending []string
+	targetFound := false
+	for _, status := range ctx.ProjectPolicyStatus {
+		// Copy the approvers so we don't modify the context.
+		status.Approvers = append([]string(nil), status.Approvers...)
+		isTarget := ctx.PolicySetTarget == "" || ctx.PolicySetTarget == status.PolicySetName
+		if isTarget {
+			targetFound = true
+		}
+		if isTarget && !status.Passed {
+			switch {
+			case !ctx.PolicySets.IsPolicySetOwner(status.PolicySetName, user, ctx.UserTeams):
+				notOwned = append(notOwned, status.PolicySetName)
+			case !containsFold(status.Approvers, user):
+				status.Approvers = append(status.Approvers, user)
+				approved = append(approved, status.PolicySetName)
+			default:
+				approved = append(approved, status.PolicySetName)
+			}
+		}
+		if !status.IsApproved() {
+			pending = append(pending, status.PolicySetName)
+		}
+		policyStatus = append(policyStatus, status)
+	}
+
+	if !targetFound {
+		return nil, ctx.ProjectPolicyStatus, fmt.Sprintf("Policy set %q was not checked for this project.", ctx.PolicySetTarget), nil
+	}
+	if len(approved) == 0 && len(notOwned) > 0 {
+		return nil, ctx.ProjectPolicyStatus, fmt.Sprintf("Contact policy owners to approve failing policy set(s): %s.", strings.Join(notOwned, ", ")), nil
+	}
+
+	var out string
+	if len(approved) > 0 {
+		out = fmt.Sprintf("Approved policy set(s): %s.", strings.Join(approved, ", "))
+	}
+	if len(pending) > 0 {
+		return nil, policyStatus, strings.TrimSpace(fmt.Sprintf("%s Policy set(s) still requiring approval from their owners: %s.", out, strings.Join(pending, ", "))), nil
+	}
 	return &models.PolicyCheckSuccess{
-		PolicyCheckOutput: "Policies approved",
-	}, "", nil
+		PolicyCheckOutput: strings.TrimSpace("Policies approved. " + out),
+	}, policyStatus, "", nil
+}
+
+// containsFold returns true if strs contains s, ignoring case.
+func containsFold(strs []string, s string) bool {
+	for _, str := range strs {
+		if strings.EqualFold(str, s) {
+			return true
+		}
+	}
+	return false
 }
 
-func (p *DefaultProjectCommandRunner) doPolicyCheck(ctx command.ProjectContext) (*models.PolicyCheckSuccess, string, error) {
+func (p *DefaultProjectCommandRunner) doPolicyCheck(ctx command.ProjectContext) (*models.PolicyCheckSuccess, []models.PolicySetStatus, []models.PolicySetResult, string, error) {
 	// Acquire Atlantis lock for this repo/dir/workspace.
 	// This should already be acquired from the prior plan operation.
 	// if for some reason an unlock happens between the plan and policy check step
@@ -284,10 +494,10 @@ func (p *DefaultProjectCommandRunner) doPolicyCheck(ctx command.ProjectContext)
 	lockAttempt, err := p.Locker.TryLock(ctx.Log, ctx.Pull, ctx.User, ctx.Workspace, models.NewProject(ctx.Pull.BaseRepo.FullName, ctx.RepoRelDir))
 
 	if err != nil {
-		return nil, "", errors.Wrap(err, "acquiring lock")
+		return nil, nil, nil, "", errors.Wrap(err, "acquiring lock")
 	}
 	if !lockAttempt.LockAcquired {
-		return nil, lockAttempt.LockFailureReason, nil
+		return nil, nil, nil, lockAttempt.LockFailureReason, nil
 	}
 	ctx.Log.Debug("acquired lock for project")
 
@@ -296,7 +506,7 @@ func (p *DefaultProjectCommandRunner) doPolicyCheck(ctx command.ProjectContext)
 	// there is a small gap where we don't have the lock and if we can't get this here, we should just unlock the PR.
 	unlockFn, err := p.WorkingDirLocker.TryLock(ctx.Pull.BaseRepo.FullName, ctx.Pull.Num, ctx.Workspace, ctx.RepoRelDir)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, nil, "", err
 	}
 	defer unlockFn()
 
@@ -311,9 +521,9 @@ func (p *DefaultProjectCommandRunner) doPolicyCheck(ctx command.ProjectContext)
 		}
 
 		if os.IsNotExist(err) {
-			return nil, "", errors.New("project has not been cloned–did you run plan?")
+			return nil, nil, nil, "", errors.New("project has not been cloned–did you run plan?")
 		}
-		return nil, "", err
+		return nil, nil, nil, "", err
 	}
 	absPath := filepath.Join(repoDir, ctx.RepoRelDir)
 	if _, err = os.Stat(absPath); os.IsNotExist(err) {
@@ -323,14 +533,29 @@ func (p *DefaultProjectCommandRunner) doPolicyCheck(ctx command.ProjectContext)
 			ctx.Log.Err("error unlocking state after plan error: %v", unlockErr)
 		}
 
-		return nil, "", DirNotExistErr{RepoRelDir: ctx.RepoRelDir}
+		return nil, nil, nil, "", DirNotExistErr{RepoRelDir: ctx.RepoRelDir}
 	}
 
-	outputs, err := p.runSteps(ctx.Steps, ctx, absPath)
+	// Remove the results of a previous policy check so stale results aren't
+	// rendered if the policy check step doesn't run.
+	resultsPath := filepath.Join(absPath, ctx.GetPolicyCheckResultFileName())
+	if err := os.Remove(resultsPath); err != nil && !os.IsNotExist(err) {
+		return nil, nil, nil, "", errors.Wrap(err, "removing previous policy check results")
+	}
+
+	start := time.Now()
+	outputs, err := p.runSteps(ctx.Steps, ctx, absPath, false)
+	p.sendCommandWebhook(ctx, webhooks.PolicyCheckEvent, start, err)
+	policySetResults := readPolicySetResults(ctx, resultsPath)
 	if err != nil {
 		// Note: we are explicitly not unlocking the pr here since a failing policy check will require
 		// approval
-		return nil, "", fmt.Errorf("%s\n%s", err, strings.Join(outputs, "\n"))
+		var policySetsErr runtime_models.PolicySetsFailedError
+		var policyStatus []models.PolicySetStatus
+		if errors.As(err, &policySetsErr) {
+			policyStatus = p.policySetStatus(ctx, policySetsErr.PolicySets)
+		}
+		return nil, policyStatus, policySetResults, "", fmt.Errorf("%s\n%s", err, strings.Join(outputs, "\n"))
 	}
 
 	return &models.PolicyCheckSuccess{
@@ -342,7 +567,46 @@ func (p *DefaultProjectCommandRunner) doPolicyCheck(ctx command.ProjectContext)
 		// set this to false right now because we don't have this information
 		// TODO: refactor the templates in a sane way so we don't need this
 		HasDiverged: false,
-	}, "", nil
+	}, p.policySetStatus(ctx, nil), policySetResults, "", nil
+}
+
+// readPolicySetResults reads the structured policy check results written by
+// the policy check step. It returns nil if there are none, ex. if the step
+// didn't run or the workflow uses a custom policy check.
+func readPolicySetResults(ctx command.ProjectContext, resultsPath string) []models.PolicySetResult {
+	contents, err := os.ReadFile(resultsPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			ctx.Log.Warn("unable to read policy check results: %s", err)
+		}
+		return nil
+	}
+	var results []models.PolicySetResult
+	if err := json.Unmarshal(contents, &results); err != nil {
+		ctx.Log.Warn("unable to parse policy check results: %s", err)
+		return nil
+	}
+	return results
+}
+
+// policySetStatus returns the status of each of the project's policy sets
+// given the names of the policy sets that failed.
+func (p *DefaultProjectCommandRunner) policySetStatus(ctx command.ProjectContext, failed []string) []models.PolicySetStatus {
+	var policyStatus []models.PolicySetStatus
+	for _, policySet := range ctx.PolicySets.PolicySets {
+		passed := true
+		for _, f := range failed {
+			if f == policySet.Name {
+				passed = false
+				break
+			}
+		}
+		policyStatus = append(policyStatus, models.PolicySetStatus{
+			PolicySetName: policySet.Name,
+			Passed:        passed,
+		})
+	}
+	return policyStatus
 }
 
 func (p *DefaultProjectCommandRunner) doPlan(ctx command.ProjectContext) (*models.PlanSuccess, string, error) {
@@ -373,10 +637,15 @@ func (p *DefaultProjectCommandRunner) doPlan(ctx command.ProjectContext) (*model
 	}
 	projAbsPath := filepath.Join(repoDir, ctx.RepoRelDir)
 	if _, err = os.Stat(projAbsPath); os.IsNotExist(err) {
+		if unlockErr := lockAttempt.UnlockFn(); unlockErr != nil {
+			ctx.Log.Err("error unlocking state after plan error: %v", unlockErr)
+		}
 		return nil, "", DirNotExistErr{RepoRelDir: ctx.RepoRelDir}
 	}
 
-	outputs, err := p.runSteps(ctx.Steps, ctx, projAbsPath)
+	start := time.Now()
+	outputs, err := p.runSteps(ctx.Steps, ctx, projAbsPath, true)
+	p.sendCommandWebhook(ctx, webhooks.PlanEvent, start, err)
 
 	if err != nil {
 		if unlockErr := lockAttempt.UnlockFn(); unlockErr != nil {
@@ -391,9 +660,55 @@ func (p *DefaultProjectCommandRunner) doPlan(ctx command.ProjectContext) (*model
 		RePlanCmd:       ctx.RePlanCmd,
 		ApplyCmd:        ctx.ApplyCmd,
 		HasDiverged:     hasDiverged,
+		Analysis:        p.analyzePlan(ctx, projAbsPath),
 	}, "", nil
 }
 
+// analyzePlan analyzes the resource changes of the plan in projAbsPath if the
+// plan summary or the project's apply requirements need it. It uses the output
+// of the plan stage's show step or else runs terraform show, whose output is
+// kept for checking the apply requirements. It returns nil if the plan isn't
+// or can't be analyzed, ex. for remote plans.
+func (p *DefaultProjectCommandRunner) analyzePlan(ctx command.ProjectContext, projAbsPath string) *models.PlanAnalysis {
+	if p.ShowStepRunner == nil || !(p.EnablePlanSummary || hasPlanApplyRequirements(ctx)) {
+		return nil
+	}
+	showResultFile := filepath.Join(projAbsPath, ctx.GetShowResultFileName())
+	if !hasStep(ctx.Steps, raw.ShowStepName) {
+		// Remove the output of a previous plan so it's never analyzed instead
+		// of this plan.
+		if err := os.Remove(showResultFile); err != nil && !os.IsNotExist(err) {
+			ctx.Log.Warn("unable to remove previous terraform show output: %s", err)
+			return nil
+		}
+		// The show step is run like the plan's steps so it runs where the
+		// plan ran.
+		if _, err := p.runSteps([]valid.Step{{StepName: raw.ShowStepName}}, ctx, projAbsPath, false); err != nil {
+			ctx.Log.Warn("unable to analyze plan: %s", err)
+			return nil
+		}
+	}
+	analysis, err := runtime.AnalyzePlanFile(showResultFile)
+	if err != nil {
+		// The show step doesn't write any output for remote plans or
+		// versions of Terraform that don't support show -json.
+		if !os.IsNotExist(err) {
+			ctx.Log.Warn("unable to analyze plan: %s", err)
+		}
+		return nil
+	}
+	return analysis
+}
+
+func hasStep(steps []valid.Step, name string) bool {
+	for _, step := range steps {
+		if step.StepName == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *DefaultProjectCommandRunner) doApply(ctx command.ProjectContext) (applyOut string, failure string, err error) {
 	repoDir, err := p.WorkingDir.GetWorkingDir(ctx.Pull.BaseRepo, ctx.Pull, ctx.Workspace)
 	if err != nil {
@@ -419,17 +734,9 @@ func (p *DefaultProjectCommandRunner) doApply(ctx command.ProjectContext) (apply
 	}
 	defer unlockFn()
 
-	outputs, err := p.runSteps(ctx.Steps, ctx, absPath)
-
-	p.Webhooks.Send(ctx.Log, webhooks.ApplyResult{ // nolint: errcheck
-		Workspace: ctx.Workspace,
-		User:      ctx.User,
-		Repo:      ctx.Pull.BaseRepo,
-		Pull:      ctx.Pull,
-		Success:   err == nil,
-		Directory: ctx.RepoRelDir,
-	})
-
+	start := time.Now()
+	outputs, err := p.runSteps(ctx.Steps, ctx, absPath, true)
+	p.sendCommandWebhook(ctx, webhooks.ApplyEvent, start, err)
 	if err != nil {
 		return "", "", fmt.Errorf("%s\n%s", err, strings.Join(outputs, "\n"))
 	}
@@ -457,50 +764,202 @@ func (p *DefaultProjectCommandRunner) doVersion(ctx command.ProjectContext) (ver
 	}
 	defer unlockFn()
 
-	outputs, err := p.runSteps(ctx.Steps, ctx, absPath)
+	outputs, err := p.runSteps(ctx.Steps, ctx, absPath, false)
+	if err != nil {
+		return "", "", fmt.Errorf("%s\n%s", err, strings.Join(outputs, "\n"))
+	}
+
+	return strings.Join(outputs, "\n"), "", nil
+}
+
+// doStateChange runs the import or state steps for the project described by
+// ctx. Like plan, it takes the project lock so the state isn't changed while
+// another pull request has a plan for the project, and like apply, the apply
+// requirements must be met. Since any existing plan no longer matches the
+// state afterwards, it's deleted.
+func (p *DefaultProjectCommandRunner) doStateChange(ctx command.ProjectContext) (out string, failure string, err error) {
+	// Acquire Atlantis lock for this repo/dir/workspace.
+	lockAttempt, err := p.Locker.TryLock(ctx.Log, ctx.Pull, ctx.User, ctx.Workspace, models.NewProject(ctx.Pull.BaseRepo.FullName, ctx.RepoRelDir))
+	if err != nil {
+		return "", "", errors.Wrap(err, "acquiring lock")
+	}
+	if !lockAttempt.LockAcquired {
+		return "", lockAttempt.LockFailureReason, nil
+	}
+	ctx.Log.Debug("acquired lock for project")
+
+	// Acquire internal lock for the directory we're going to operate in.
+	unlockFn, err := p.WorkingDirLocker.TryLock(ctx.Pull.BaseRepo.FullName, ctx.Pull.Num, ctx.Workspace, ctx.RepoRelDir)
+	if err != nil {
+		return "", "", err
+	}
+	defer unlockFn()
+
+	// Clone is idempotent so okay to run even if the repo was already cloned.
+	repoDir, _, err := p.WorkingDir.Clone(ctx.Log, ctx.HeadRepo, ctx.Pull, ctx.Workspace)
+	if err != nil {
+		return "", "", err
+	}
+	absPath := filepath.Join(repoDir, ctx.RepoRelDir)
+	if _, err = os.Stat(absPath); os.IsNotExist(err) {
+		return "", "", DirNotExistErr{RepoRelDir: ctx.RepoRelDir}
+	}
+
+	// The state is changed without a plan so the apply requirements must be
+	// met like for an apply, except for the ones that check the plan.
+	failure, err = p.AggregateApplyRequirements.ValidateProject(repoDir, withoutPlanApplyRequirements(ctx))
+	if failure != "" || err != nil {
+		return "", failure, err
+	}
+
+	outputs, err := p.runSteps(ctx.Steps, ctx, absPath, false)
 	if err != nil {
 		return "", "", fmt.Errorf("%s\n%s", err, strings.Join(outputs, "\n"))
 	}
 
+	for _, file := range []string{runtime.GetPlanFilename(ctx.Workspace, ctx.ProjectName), ctx.GetShowResultFileName()} {
+		if err := os.Remove(filepath.Join(absPath, file)); err != nil && !os.IsNotExist(err) {
+			return "", "", errors.Wrapf(err, "deleting %s after the state changed", file)
+		}
+	}
 	return strings.Join(outputs, "\n"), "", nil
 }
 
-func (p *DefaultProjectCommandRunner) runSteps(steps []valid.Step, ctx command.ProjectContext, absPath string) ([]string, error) {
+// runSteps runs steps in absPath, where the project described by ctx is, with
+// StepsRunner if it's set. If limit is true, they first wait for their turn
+// in the ConcurrencyLimiter's queue. The steps are stopped if the command is
+// cancelled or if the stage or a step times out.
+func (p *DefaultProjectCommandRunner) runSteps(steps []valid.Step, ctx command.ProjectContext, absPath string, limit bool) ([]string, error) {
+	ctx, done := p.RunningCommands.Start(ctx)
+	defer done()
+
 	var outputs []string
+	var err error
+	if limit {
+		var release func()
+		release, err = p.waitForTurn(ctx)
+		if release != nil {
+			defer release()
+		}
+	}
+	if err == nil {
+		if p.StepsRunner != nil {
+			outputs, err = p.StepsRunner.RunSteps(ctx, steps, absPath)
+		} else {
+			outputs, err = p.RunSteps(ctx, steps, absPath)
+		}
+	}
+	if err != nil {
+		if user, ok := p.RunningCommands.cancelledBy(ctx.JobID); ok {
+			// The job is only completed now that the steps have stopped so
+			// that their last output is kept.
+			if p.JobMessageSender != nil {
+				p.JobMessageSender.Send(ctx, "", OperationComplete)
+			}
+			return outputs, fmt.Errorf("%s was cancelled by @%s", ctx.CommandName.String(), user.Username)
+		}
+	}
+	return outputs, err
+}
 
-	envs := make(map[string]string)
-	for _, step := range steps {
-		var out string
-		var err error
-		switch step.StepName {
-		case "init":
-			out, err = p.InitStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
-		case "plan":
-			out, err = p.PlanStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
-		case "show":
-			_, err = p.ShowStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
-		case "policy_check":
-			out, err = p.PolicyCheckStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
-		case "apply":
-			out, err = p.ApplyStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
-		case "version":
-			out, err = p.VersionStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
-		case "run":
-			out, err = p.RunStepRunner.Run(ctx, step.RunCommand, absPath, envs)
-		case "env":
-			out, err = p.EnvStepRunner.Run(ctx, step.RunCommand, step.EnvVarValue, absPath, envs)
-			envs[step.EnvVarName] = out
-			// We reset out to the empty string because we don't want it to
-			// be printed to the PR, it's solely to set the environment variable.
-			out = ""
+// waitForTurn waits for the command described by ctx to be allowed to run by
+// the ConcurrencyLimiter. While it's queued, its commit status and job output
+// show its position in the queue.
+func (p *DefaultProjectCommandRunner) waitForTurn(ctx command.ProjectContext) (func(), error) {
+	queued := false
+	release, err := p.ConcurrencyLimiter.Wait(ctx, func(position int) {
+		queued = true
+		if p.JobMessageSender != nil {
+			p.JobMessageSender.Send(ctx, fmt.Sprintf("Queued (position %d), waiting for other plans and applies to finish...", position), false)
+		}
+		if p.CommitStatusUpdater != nil {
+			if err := p.CommitStatusUpdater.UpdateProjectQueued(ctx, ctx.CommandName, position, p.jobURL(ctx)); err != nil {
+				ctx.Log.Warn("unable to update commit status: %s", err)
+			}
+		}
+	})
+	if err != nil || !queued {
+		return release, err
+	}
+	if p.CommitStatusUpdater != nil {
+		if err := p.CommitStatusUpdater.UpdateProject(ctx, ctx.CommandName, models.PendingCommitStatus, p.jobURL(ctx)); err != nil {
+			ctx.Log.Warn("unable to update commit status: %s", err)
 		}
+	}
+	return release, nil
+}
+
+// RunSteps runs steps in absPath in this process. They're stopped if ctx is
+// done or if the stage or a step times out.
+func (p *DefaultProjectCommandRunner) RunSteps(ctx command.ProjectContext, steps []valid.Step, absPath string) ([]string, error) {
+	stageCtx := ctx
+	if ctx.Timeout > 0 {
+		var cancel context.CancelFunc
+		stageCtx.Ctx, cancel = context.WithTimeout(ctx.Context(), ctx.Timeout)
+		defer cancel()
+	}
 
+	var outputs []string
+	envs := make(map[string]string)
+	for _, step := range steps {
+		out, err := p.runStep(step, stageCtx, absPath, envs)
 		if out != "" {
 			outputs = append(outputs, out)
 		}
-		if err != nil {
-			return outputs, err
+		if err == nil {
+			continue
+		}
+		if stageCtx.Context().Err() == context.DeadlineExceeded {
+			return outputs, fmt.Errorf("%s timed out after %s: %s", ctx.CommandName.String(), ctx.Timeout, err)
 		}
+		return outputs, err
 	}
 	return outputs, nil
 }
+
+// runStep runs a single step. If the step has a timeout, it's stopped once the
+// timeout passes.
+func (p *DefaultProjectCommandRunner) runStep(step valid.Step, ctx command.ProjectContext, absPath string, envs map[string]string) (out string, err error) {
+	if step.Timeout > 0 {
+		stageCtx := ctx.Context()
+		var cancel context.CancelFunc
+		ctx.Ctx, cancel = context.WithTimeout(stageCtx, step.Timeout)
+		defer func() {
+			// If the stage is done, it's reported by runSteps instead.
+			if err != nil && ctx.Ctx.Err() == context.DeadlineExceeded && stageCtx.Err() == nil {
+				err = fmt.Errorf("%s step timed out after %s: %s", step.StepName, step.Timeout, err)
+			}
+			cancel()
+		}()
+	}
+
+	switch step.StepName {
+	case "init":
+		out, err = p.InitStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
+	case "plan":
+		out, err = p.PlanStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
+	case "show":
+		_, err = p.ShowStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
+	case "policy_check":
+		out, err = p.PolicyCheckStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
+	case "apply":
+		out, err = p.ApplyStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
+	case "version":
+		out, err = p.VersionStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
+	case "import":
+		out, err = p.ImportStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
+	case "state_rm":
+		out, err = p.StateRmStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
+	case "state_mv":
+		out, err = p.StateMvStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
+	case "run":
+		out, err = p.RunStepRunner.Run(ctx, step.RunCommand, absPath, envs)
+	case "env":
+		out, err = p.EnvStepRunner.Run(ctx, step.RunCommand, step.EnvVarValue, absPath, envs)
+		envs[step.EnvVarName] = out
+		// We reset out to the empty string because we don't want it to
+		// be printed to the PR, it's solely to set the environment variable.
+		out = ""
+	}
+	return out, err
+}
@@ -14,15 +14,19 @@
 package events_test
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	. "github.com/petergtz/pegomock"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/runtime"
+	runtime_models "github.com/runatlantis/atlantis/server/core/runtime/models"
 	tmocks "github.com/runatlantis/atlantis/server/core/terraform/mocks"
 	"github.com/runatlantis/atlantis/server/events"
 	"github.com/runatlantis/atlantis/server/events/command"
@@ -30,8 +34,11 @@ import (
 	eventmocks "github.com/runatlantis/atlantis/server/events/mocks"
 	"github.com/runatlantis/atlantis/server/events/mocks/matchers"
 	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/webhooks"
+	jobmocks "github.com/runatlantis/atlantis/server/jobs/mocks"
 	"github.com/runatlantis/atlantis/server/logging"
 	. "github.com/runatlantis/atlantis/testing"
+	"github.com/uber-go/tally"
 )
 
 // Test that it runs the expected plan steps.
@@ -132,6 +139,226 @@ func TestDefaultProjectCommandRunner_Plan(t *testing.T) {
 	}
 }
 
+// Plans are only analyzed with an extra show step if the plan summary or the
+// apply requirements need it and the plan stage doesn't run show itself.
+func TestDefaultProjectCommandRunner_PlanAnalysis(t *testing.T) {
+	showOutput := `{"resource_changes": [{"address": "aws_instance.a", "mode": "managed", "type": "aws_instance", "change": {"actions": ["delete"]}}]}`
+	cases := []struct {
+		description       string
+		enablePlanSummary bool
+		applyRequirements []string
+		planStageShow     bool
+		expShowRuns       int
+		expAnalysis       bool
+	}{
+		{
+			description: "not needed",
+			expShowRuns: 0,
+		},
+		{
+			description:       "apply requirement without plan analysis",
+			applyRequirements: []string{"approved"},
+			expShowRuns:       0,
+		},
+		{
+			description:       "plan summary",
+			enablePlanSummary: true,
+			expShowRuns:       1,
+			expAnalysis:       true,
+		},
+		{
+			description:       "max_destroys",
+			applyRequirements: []string{"max_destroys:1"},
+			expShowRuns:       1,
+			expAnalysis:       true,
+		},
+		{
+			description:       "plan stage runs show",
+			enablePlanSummary: true,
+			planStageShow:     true,
+			expShowRuns:       1,
+			expAnalysis:       true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			mockPlan := mocks.NewMockStepRunner()
+			mockShow := mocks.NewMockStepRunner()
+			mockWorkingDir := mocks.NewMockWorkingDir()
+			mockLocker := mocks.NewMockProjectLocker()
+			runner := events.DefaultProjectCommandRunner{
+				Locker:            mockLocker,
+				LockURLGenerator:  mockURLGenerator{},
+				PlanStepRunner:    mockPlan,
+				ShowStepRunner:    mockShow,
+				WorkingDir:        mockWorkingDir,
+				WorkingDirLocker:  events.NewDefaultWorkingDirLocker(),
+				EnablePlanSummary: c.enablePlanSummary,
+			}
+
+			repoDir := t.TempDir()
+			When(mockWorkingDir.Clone(
+				matchers.AnyPtrToLoggingSimpleLogger(),
+				matchers.AnyModelsRepo(),
+				matchers.AnyModelsPullRequest(),
+				AnyString(),
+			)).ThenReturn(repoDir, false, nil)
+			When(mockLocker.TryLock(
+				matchers.AnyPtrToLoggingSimpleLogger(),
+				matchers.AnyModelsPullRequest(),
+				matchers.AnyModelsUser(),
+				AnyString(),
+				matchers.AnyModelsProject(),
+			)).ThenReturn(&events.TryLockResponse{LockAcquired: true, LockKey: "lock-key"}, nil)
+
+			steps := []valid.Step{{StepName: "plan"}}
+			if c.planStageShow {
+				steps = append(steps, valid.Step{StepName: "show"})
+			}
+			ctx := command.ProjectContext{
+				Log:               logging.NewNoopLogger(t),
+				Steps:             steps,
+				Workspace:         "default",
+				RepoRelDir:        ".",
+				ApplyRequirements: c.applyRequirements,
+			}
+			showFile := filepath.Join(repoDir, ctx.GetShowResultFileName())
+			When(mockPlan.Run(ctx, nil, repoDir, map[string]string{})).ThenReturn("plan", nil)
+			When(mockShow.Run(ctx, nil, repoDir, map[string]string{})).Then(func(_ []Param) ReturnValues {
+				Ok(t, os.WriteFile(showFile, []byte(showOutput), 0600))
+				return []ReturnValue{"", nil}
+			})
+
+			res := runner.Plan(ctx)
+			Assert(t, res.PlanSuccess != nil, "exp plan success, got %v", res.Error)
+			mockShow.VerifyWasCalled(Times(c.expShowRuns)).Run(ctx, nil, repoDir, map[string]string{})
+			Equals(t, c.expAnalysis, res.PlanSuccess.Analysis != nil)
+		})
+	}
+}
+
+func TestDefaultProjectCommandRunner_Import(t *testing.T) {
+	RegisterMockTestingT(t)
+	mockInit := mocks.NewMockStepRunner()
+	mockImport := mocks.NewMockStepRunner()
+	mockWorkingDir := mocks.NewMockWorkingDir()
+	mockLocker := mocks.NewMockProjectLocker()
+
+	runner := events.DefaultProjectCommandRunner{
+		Locker:           mockLocker,
+		LockURLGenerator: mockURLGenerator{},
+		InitStepRunner:   mockInit,
+		ImportStepRunner: mockImport,
+		WorkingDir:       mockWorkingDir,
+		WorkingDirLocker: events.NewDefaultWorkingDirLocker(),
+		AggregateApplyRequirements: &events.AggregateApplyRequirements{
+			WorkingDir: mockWorkingDir,
+		},
+	}
+
+	repoDir, cleanup := TempDir(t)
+	defer cleanup()
+	When(mockWorkingDir.Clone(
+		matchers.AnyPtrToLoggingSimpleLogger(),
+		matchers.AnyModelsRepo(),
+		matchers.AnyModelsPullRequest(),
+		AnyString(),
+	)).ThenReturn(repoDir, false, nil)
+	When(mockLocker.TryLock(
+		matchers.AnyPtrToLoggingSimpleLogger(),
+		matchers.AnyModelsPullRequest(),
+		matchers.AnyModelsUser(),
+		AnyString(),
+		matchers.AnyModelsProject(),
+	)).ThenReturn(&events.TryLockResponse{
+		LockAcquired: true,
+		LockKey:      "lock-key",
+	}, nil)
+
+	// The existing plan no longer matches the state after importing.
+	planFile := filepath.Join(repoDir, "default.tfplan")
+	Ok(t, os.WriteFile(planFile, nil, 0600))
+
+	ctx := command.ProjectContext{
+		Log:         logging.NewNoopLogger(t),
+		CommandName: command.Import,
+		Steps: []valid.Step{
+			{StepName: "init"},
+			{StepName: "import"},
+		},
+		Workspace:  "default",
+		RepoRelDir: ".",
+		RePlanCmd:  "atlantis plan -d .",
+	}
+	When(mockInit.Run(ctx, nil, repoDir, map[string]string{})).ThenReturn("", nil)
+	When(mockImport.Run(ctx, nil, repoDir, map[string]string{})).ThenReturn("Import successful!", nil)
+
+	res := runner.Import(ctx)
+	Ok(t, res.Error)
+	Equals(t, command.Import, res.Command)
+	Equals(t, &models.ImportSuccess{Output: "Import successful!", RePlanCmd: "atlantis plan -d ."}, res.ImportSuccess)
+	_, err := os.Stat(planFile)
+	Assert(t, os.IsNotExist(err), "exp plan file to be deleted")
+}
+
+// Test that state changes must meet the apply requirements, except the ones
+// that check the plan.
+func TestDefaultProjectCommandRunner_StateNotApproved(t *testing.T) {
+	RegisterMockTestingT(t)
+	mockStateRm := mocks.NewMockStepRunner()
+	mockWorkingDir := mocks.NewMockWorkingDir()
+	mockLocker := mocks.NewMockProjectLocker()
+
+	runner := events.DefaultProjectCommandRunner{
+		Locker:            mockLocker,
+		LockURLGenerator:  mockURLGenerator{},
+		StateRmStepRunner: mockStateRm,
+		WorkingDir:        mockWorkingDir,
+		WorkingDirLocker:  events.NewDefaultWorkingDirLocker(),
+		AggregateApplyRequirements: &events.AggregateApplyRequirements{
+			WorkingDir: mockWorkingDir,
+		},
+	}
+
+	repoDir, cleanup := TempDir(t)
+	defer cleanup()
+	When(mockWorkingDir.Clone(
+		matchers.AnyPtrToLoggingSimpleLogger(),
+		matchers.AnyModelsRepo(),
+		matchers.AnyModelsPullRequest(),
+		AnyString(),
+	)).ThenReturn(repoDir, false, nil)
+	When(mockLocker.TryLock(
+		matchers.AnyPtrToLoggingSimpleLogger(),
+		matchers.AnyModelsPullRequest(),
+		matchers.AnyModelsUser(),
+		AnyString(),
+		matchers.AnyModelsProject(),
+	)).ThenReturn(&events.TryLockResponse{
+		LockAcquired: true,
+		LockKey:      "lock-key",
+	}, nil)
+
+	ctx := command.ProjectContext{
+		Log:         logging.NewNoopLogger(t),
+		CommandName: command.StateRm,
+		Steps: []valid.Step{
+			{StepName: "state_rm"},
+		},
+		Workspace:  "default",
+		RepoRelDir: ".",
+		// There's no plan to analyze for max_destroys.
+		ApplyRequirements: []string{"max_destroys=0", "approved"},
+	}
+
+	res := runner.State(ctx)
+	Ok(t, res.Error)
+	Equals(t, "Pull request must be approved by at least one person other than the author before running apply.", res.Failure)
+	Assert(t, res.StateSuccess == nil, "exp state rm not to succeed")
+	mockStateRm.VerifyWasCalled(Never()).Run(matchers.AnyModelsProjectCommandContext(), AnyStringSlice(), AnyString(), matchers.AnyMapOfStringToString())
+}
+
 func TestProjectOutputWrapper(t *testing.T) {
 	RegisterMockTestingT(t)
 	ctx := command.ProjectContext{
@@ -276,16 +503,19 @@ func TestDefaultProjectCommandRunner_ApplyNotApproved(t *testing.T) {
 	Equals(t, "Pull request must be approved by at least one person other than the author before running apply.", res.Failure)
 }
 
-// Test that if mergeable is required and the PR isn't mergeable we give an error.
+// Test that if mergeable is required and the PR isn't mergeable we give an
+// error and don't send an apply webhook since nothing was applied.
 func TestDefaultProjectCommandRunner_ApplyNotMergeable(t *testing.T) {
 	RegisterMockTestingT(t)
 	mockWorkingDir := mocks.NewMockWorkingDir()
+	mockSender := mocks.NewMockWebhooksSender()
 	runner := &events.DefaultProjectCommandRunner{
 		WorkingDir:       mockWorkingDir,
 		WorkingDirLocker: events.NewDefaultWorkingDirLocker(),
 		AggregateApplyRequirements: &events.AggregateApplyRequirements{
 			WorkingDir: mockWorkingDir,
 		},
+		Webhooks: mockSender,
 	}
 	ctx := command.ProjectContext{
 		PullReqStatus: models.PullReqStatus{
@@ -299,6 +529,7 @@ func TestDefaultProjectCommandRunner_ApplyNotMergeable(t *testing.T) {
 
 	res := runner.Apply(ctx)
 	Equals(t, "Pull request must be mergeable before running apply.", res.Failure)
+	mockSender.VerifyWasCalled(Never()).Send(matchers.AnyLoggingSimpleLogging(), matchers.AnyWebhooksEventResult())
 }
 
 // Test that if undiverged is required and the PR is diverged we give an error.
@@ -528,6 +759,79 @@ func TestDefaultProjectCommandRunner_ApplyRunStepFailure(t *testing.T) {
 	Assert(t, res.ApplySuccess == "", "exp apply failure")
 
 	mockApply.VerifyWasCalledOnce().Run(ctx, nil, repoDir, expEnvs)
+	_, webhookRes := mockSender.VerifyWasCalledOnce().Send(matchers.AnyLoggingSimpleLogging(), matchers.AnyWebhooksEventResult()).GetCapturedArguments()
+	Equals(t, webhooks.ApplyEvent, webhookRes.Event)
+	Equals(t, webhooks.ErrorStatus, webhookRes.Status)
+	Equals(t, false, webhookRes.Success)
+}
+
+// Test that plan sends a webhook with the job url.
+func TestDefaultProjectCommandRunner_PlanWebhook(t *testing.T) {
+	RegisterMockTestingT(t)
+	mockInit := mocks.NewMockStepRunner()
+	mockPlan := mocks.NewMockStepRunner()
+	mockWorkingDir := mocks.NewMockWorkingDir()
+	mockLocker := mocks.NewMockProjectLocker()
+	mockSender := mocks.NewMockWebhooksSender()
+	mockJobURLGenerator := jobmocks.NewMockProjectJobURLGenerator()
+
+	runner := events.DefaultProjectCommandRunner{
+		Locker:                 mockLocker,
+		LockURLGenerator:       mockURLGenerator{},
+		InitStepRunner:         mockInit,
+		PlanStepRunner:         mockPlan,
+		WorkingDir:             mockWorkingDir,
+		WorkingDirLocker:       events.NewDefaultWorkingDirLocker(),
+		Webhooks:               mockSender,
+		ProjectJobURLGenerator: mockJobURLGenerator,
+	}
+	repoDir, cleanup := TempDir(t)
+	defer cleanup()
+	When(mockWorkingDir.Clone(
+		matchers.AnyPtrToLoggingSimpleLogger(),
+		matchers.AnyModelsRepo(),
+		matchers.AnyModelsPullRequest(),
+		AnyString(),
+	)).ThenReturn(repoDir, false, nil)
+	When(mockLocker.TryLock(
+		matchers.AnyPtrToLoggingSimpleLogger(),
+		matchers.AnyModelsPullRequest(),
+		matchers.AnyModelsUser(),
+		AnyString(),
+		matchers.AnyModelsProject(),
+	)).ThenReturn(&events.TryLockResponse{
+		LockAcquired: true,
+		LockKey:      "lock-key",
+	}, nil)
+
+	ctx := command.ProjectContext{
+		Log: logging.NewNoopLogger(t),
+		Steps: []valid.Step{
+			{StepName: "init"},
+			{StepName: "plan"},
+		},
+		Workspace:   "default",
+		RepoRelDir:  ".",
+		ProjectName: "project1",
+		User:        models.User{Username: "acme-user"},
+	}
+	expEnvs := map[string]string{}
+	When(mockInit.Run(ctx, nil, repoDir, expEnvs)).ThenReturn("init", nil)
+	When(mockPlan.Run(ctx, nil, repoDir, expEnvs)).ThenReturn("plan", nil)
+	When(mockJobURLGenerator.GenerateProjectJobURL(ctx)).ThenReturn("https://atlantis.example.com/jobs/1234", nil)
+
+	res := runner.Plan(ctx)
+	Assert(t, res.PlanSuccess != nil, "exp plan success")
+
+	_, webhookRes := mockSender.VerifyWasCalledOnce().Send(matchers.AnyLoggingSimpleLogging(), matchers.AnyWebhooksEventResult()).GetCapturedArguments()
+	Equals(t, webhooks.PlanEvent, webhookRes.Event)
+	Equals(t, webhooks.SuccessStatus, webhookRes.Status)
+	Equals(t, true, webhookRes.Success)
+	Equals(t, "project1", webhookRes.ProjectName)
+	Equals(t, ".", webhookRes.Directory)
+	Equals(t, "default", webhookRes.Workspace)
+	Equals(t, "acme-user", webhookRes.User.Username)
+	Equals(t, "https://atlantis.example.com/jobs/1234", webhookRes.JobURL)
 }
 
 // Test run and env steps. We don't use mocks for this test since we're
@@ -621,6 +925,399 @@ func TestDefaultProjectCommandRunner_RunEnvSteps(t *testing.T) {
 	Equals(t, "var=\n\nvar=value\n\ndynamic_var=dynamic_value\n\ndynamic_var=overridden\n", res.PlanSuccess.TerraformOutput)
 }
 
+// Test that steps are stopped when the step or stage times out or when the
+// command is cancelled.
+func TestDefaultProjectCommandRunner_StopSteps(t *testing.T) {
+	RegisterMockTestingT(t)
+	tfClient := tmocks.NewMockClient()
+	tfVersion, err := version.NewVersion("0.12.0")
+	Ok(t, err)
+	run := runtime.RunStepRunner{
+		TerraformExecutor: tfClient,
+		DefaultTFVersion:  tfVersion,
+	}
+	mockWorkingDir := mocks.NewMockWorkingDir()
+	mockLocker := mocks.NewMockProjectLocker()
+	mockJobMessageSender := mocks.NewMockJobMessageSender()
+	runningCommands := events.NewRunningCommands()
+
+	runner := events.DefaultProjectCommandRunner{
+		Locker:           mockLocker,
+		LockURLGenerator: mockURLGenerator{},
+		RunStepRunner:    &run,
+		WorkingDir:       mockWorkingDir,
+		WorkingDirLocker: events.NewDefaultWorkingDirLocker(),
+		RunningCommands:  runningCommands,
+		JobMessageSender: mockJobMessageSender,
+	}
+
+	repoDir, cleanup := TempDir(t)
+	defer cleanup()
+	When(mockWorkingDir.Clone(
+		matchers.AnyPtrToLoggingSimpleLogger(),
+		matchers.AnyModelsRepo(),
+		matchers.AnyModelsPullRequest(),
+		AnyString(),
+	)).ThenReturn(repoDir, false, nil)
+	When(mockLocker.TryLock(
+		matchers.AnyPtrToLoggingSimpleLogger(),
+		matchers.AnyModelsPullRequest(),
+		matchers.AnyModelsUser(),
+		AnyString(),
+		matchers.AnyModelsProject(),
+	)).ThenReturn(&events.TryLockResponse{
+		LockAcquired: true,
+		LockKey:      "lock-key",
+		UnlockFn:     func() error { return nil },
+	}, nil)
+
+	pull := models.PullRequest{Num: 1, BaseRepo: models.Repo{FullName: "owner/repo"}}
+	newCtx := func(stageTimeout time.Duration, stepTimeout time.Duration) command.ProjectContext {
+		return command.ProjectContext{
+			Log:         logging.NewNoopLogger(t),
+			CommandName: command.Plan,
+			JobID:       "job",
+			Pull:        pull,
+			Timeout:     stageTimeout,
+			Steps: []valid.Step{
+				{
+					StepName:   "run",
+					RunCommand: "sleep 30",
+					Timeout:    stepTimeout,
+				},
+			},
+			Workspace:  "default",
+			RepoRelDir: ".",
+		}
+	}
+
+	t.Run("step timeout", func(t *testing.T) {
+		res := runner.Plan(newCtx(0, 100*time.Millisecond))
+		ErrContains(t, "run step timed out after 100ms", res.Error)
+	})
+
+	t.Run("stage timeout", func(t *testing.T) {
+		res := runner.Plan(newCtx(100*time.Millisecond, time.Minute))
+		ErrContains(t, "plan timed out after 100ms", res.Error)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		results := make(chan command.ProjectResult)
+		go func() {
+			results <- runner.Plan(newCtx(0, 0))
+		}()
+		for i := 0; i < 100 && len(runningCommands.Cancel(pull, "", "", "", models.User{Username: "user"})) == 0; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		select {
+		case res := <-results:
+			ErrContains(t, "plan was cancelled by @user", res.Error)
+		case <-time.After(10 * time.Second):
+			t.Fatal("exp plan to be cancelled")
+		}
+		// The job is completed once the steps have stopped.
+		mockJobMessageSender.VerifyWasCalledOnce().Send(matchers.AnyModelsProjectCommandContext(), EqString(""), EqBool(true))
+	})
+}
+
+// Test that a plan waits in the concurrency limiter's queue and tells the user
+// its position as the commands ahead of it start.
+func TestDefaultProjectCommandRunner_QueuedPlan(t *testing.T) {
+	RegisterMockTestingT(t)
+	tfVersion, err := version.NewVersion("0.12.0")
+	Ok(t, err)
+	mockWorkingDir := mocks.NewMockWorkingDir()
+	mockLocker := mocks.NewMockProjectLocker()
+	mockStatusUpdater := mocks.NewMockCommitStatusUpdater()
+	mockJobMessageSender := mocks.NewMockJobMessageSender()
+	limiter := events.NewConcurrencyLimiter(1, 0, tally.NoopScope)
+
+	runner := events.DefaultProjectCommandRunner{
+		Locker:           mockLocker,
+		LockURLGenerator: mockURLGenerator{},
+		RunStepRunner: &runtime.RunStepRunner{
+			TerraformExecutor: tmocks.NewMockClient(),
+			DefaultTFVersion:  tfVersion,
+		},
+		WorkingDir:          mockWorkingDir,
+		WorkingDirLocker:    events.NewDefaultWorkingDirLocker(),
+		ConcurrencyLimiter:  limiter,
+		CommitStatusUpdater: mockStatusUpdater,
+		JobMessageSender:    mockJobMessageSender,
+	}
+
+	repoDir, cleanup := TempDir(t)
+	defer cleanup()
+	When(mockWorkingDir.Clone(
+		matchers.AnyPtrToLoggingSimpleLogger(),
+		matchers.AnyModelsRepo(),
+		matchers.AnyModelsPullRequest(),
+		AnyString(),
+	)).ThenReturn(repoDir, false, nil)
+	When(mockLocker.TryLock(
+		matchers.AnyPtrToLoggingSimpleLogger(),
+		matchers.AnyModelsPullRequest(),
+		matchers.AnyModelsUser(),
+		AnyString(),
+		matchers.AnyModelsProject(),
+	)).ThenReturn(&events.TryLockResponse{
+		LockAcquired: true,
+		LockKey:      "lock-key",
+		UnlockFn:     func() error { return nil },
+	}, nil)
+
+	ctx := command.ProjectContext{
+		Log:         logging.NewNoopLogger(t),
+		CommandName: command.Plan,
+		JobID:       "job",
+		BaseRepo:    models.Repo{FullName: "owner/repo"},
+		Pull:        models.PullRequest{Num: 1, BaseRepo: models.Repo{FullName: "owner/repo"}},
+		Steps:       []valid.Step{{StepName: "run", RunCommand: "echo planned"}},
+		Workspace:   "default",
+		RepoRelDir:  ".",
+	}
+
+	waitForQueued := func(n int) {
+		for i := 0; i < 100 && limiter.Queued() != n; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		Equals(t, n, limiter.Queued())
+	}
+
+	// Another command is running and one is queued ahead of the plan.
+	release, err := limiter.Wait(ctx, func(int) {})
+	Ok(t, err)
+	ahead := make(chan func())
+	go func() {
+		aheadRelease, _ := limiter.Wait(ctx, func(int) {})
+		ahead <- aheadRelease
+	}()
+	waitForQueued(1)
+	results := make(chan command.ProjectResult)
+	go func() {
+		results <- runner.Plan(ctx)
+	}()
+	waitForQueued(2)
+
+	release()
+	aheadRelease := <-ahead
+	// Wait for the plan to be told it moved up before letting it run.
+	for i := 0; i < 100; i++ {
+		_, _, positions, _ := mockStatusUpdater.VerifyWasCalled(AtLeast(0)).
+			UpdateProjectQueued(matchers.AnyModelsProjectCommandContext(), matchers.AnyModelsCommandName(), AnyInt(), AnyString()).
+			GetAllCapturedArguments()
+		if len(positions) == 2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	aheadRelease()
+
+	res := <-results
+	Ok(t, res.Error)
+	Equals(t, "planned\n", res.PlanSuccess.TerraformOutput)
+	mockJobMessageSender.VerifyWasCalledOnce().Send(ctx, "Queued (position 2), waiting for other plans and applies to finish...", false)
+	mockJobMessageSender.VerifyWasCalledOnce().Send(ctx, "Queued (position 1), waiting for other plans and applies to finish...", false)
+	mockStatusUpdater.VerifyWasCalledOnce().UpdateProjectQueued(ctx, command.Plan, 2, "")
+	mockStatusUpdater.VerifyWasCalledOnce().UpdateProjectQueued(ctx, command.Plan, 1, "")
+	mockStatusUpdater.VerifyWasCalledOnce().UpdateProject(ctx, command.Plan, models.PendingCommitStatus, "")
+}
+
+// Test that the structured results written by the policy check step are
+// returned and that results from a previous policy check are discarded.
+func TestDefaultProjectCommandRunner_PolicyCheckResults(t *testing.T) {
+	RegisterMockTestingT(t)
+	mockPolicyCheck := mocks.NewMockStepRunner()
+	mockWorkingDir := mocks.NewMockWorkingDir()
+	mockLocker := mocks.NewMockProjectLocker()
+
+	runner := events.DefaultProjectCommandRunner{
+		Locker:                mockLocker,
+		LockURLGenerator:      mockURLGenerator{},
+		PolicyCheckStepRunner: mockPolicyCheck,
+		WorkingDir:            mockWorkingDir,
+		WorkingDirLocker:      events.NewDefaultWorkingDirLocker(),
+	}
+
+	repoDir := t.TempDir()
+	When(mockWorkingDir.GetWorkingDir(matchers.AnyModelsRepo(), matchers.AnyModelsPullRequest(), AnyString())).ThenReturn(repoDir, nil)
+	When(mockLocker.TryLock(
+		matchers.AnyPtrToLoggingSimpleLogger(),
+		matchers.AnyModelsPullRequest(),
+		matchers.AnyModelsUser(),
+		AnyString(),
+		matchers.AnyModelsProject(),
+	)).ThenReturn(&events.TryLockResponse{
+		LockAcquired: true,
+		LockKey:      "lock-key",
+	}, nil)
+
+	ctx := command.ProjectContext{
+		Log: logging.NewNoopLogger(t),
+		Steps: []valid.Step{
+			{
+				StepName: "policy_check",
+			},
+		},
+		PolicySets: valid.PolicySets{
+			PolicySets: []valid.PolicySet{{Name: "security"}, {Name: "cost"}},
+		},
+		Workspace:  "default",
+		RepoRelDir: ".",
+	}
+	resultsPath := filepath.Join(repoDir, ctx.GetPolicyCheckResultFileName())
+	Ok(t, os.WriteFile(resultsPath, []byte(`[{"PolicySetName": "stale"}]`), 0600))
+
+	expResults := []models.PolicySetResult{
+		{
+			PolicySetName: "security",
+			Passed:        false,
+			Results: []models.PolicyNamespaceResult{{
+				Namespace: "main",
+				Failures:  []models.PolicyRuleMessage{{Rule: "deny", Message: "null resources cannot be created"}},
+			}},
+		},
+		{
+			PolicySetName: "cost",
+			Passed:        true,
+			Results:       []models.PolicyNamespaceResult{{Namespace: "main", Successes: 1}},
+		},
+	}
+	When(mockPolicyCheck.Run(ctx, nil, repoDir, map[string]string{})).Then(func(_ []Param) ReturnValues {
+		results, err := json.Marshal(expResults)
+		Ok(t, err)
+		Ok(t, os.WriteFile(resultsPath, results, 0600))
+		return ReturnValues{"policy output", runtime_models.PolicySetsFailedError{PolicySets: []string{"security"}}}
+	})
+
+	res := runner.PolicyCheck(ctx)
+	Assert(t, res.Error != nil, "exp policy check error")
+	Equals(t, expResults, res.PolicySetResults)
+	Equals(t, []models.PolicySetStatus{
+		{PolicySetName: "security", Passed: false},
+		{PolicySetName: "cost", Passed: true},
+	}, res.PolicyStatus)
+}
+
+func TestDefaultProjectCommandRunner_ApprovePolicies(t *testing.T) {
+	policySets := valid.PolicySets{
+		Owners: valid.PolicyOwners{
+			Users: []string{"global-owner"},
+		},
+		PolicySets: []valid.PolicySet{
+			{
+				Name: "security",
+				Owners: valid.PolicyOwners{
+					Users: []string{"security-owner"},
+				},
+			},
+			{
+				Name: "cost",
+			},
+		},
+	}
+	failingStatus := []models.PolicySetStatus{
+		{PolicySetName: "security", Passed: false},
+		{PolicySetName: "cost", Passed: false},
+	}
+
+	cases := []struct {
+		description  string
+		user         string
+		target       string
+		policyStatus []models.PolicySetStatus
+		expOut       string
+		expFailure   string
+		expStatus    []models.PolicySetStatus
+	}{
+		{
+			description:  "owner approves the policy sets they own",
+			user:         "security-owner",
+			policyStatus: failingStatus,
+			expFailure:   "Approved policy set(s): security. Policy set(s) still requiring approval from their owners: cost.",
+			expStatus: []models.PolicySetStatus{
+				{PolicySetName: "security", Passed: false, Approvers: []string{"security-owner"}},
+				{PolicySetName: "cost", Passed: false},
+			},
+		},
+		{
+			description: "all policy sets approved",
+			user:        "global-owner",
+			policyStatus: []models.PolicySetStatus{
+				{PolicySetName: "security", Passed: false, Approvers: []string{"security-owner"}},
+				{PolicySetName: "cost", Passed: false},
+			},
+			expOut: "Policies approved. Approved policy set(s): cost.",
+			expStatus: []models.PolicySetStatus{
+				{PolicySetName: "security", Passed: false, Approvers: []string{"security-owner"}},
+				{PolicySetName: "cost", Passed: false, Approvers: []string{"global-owner"}},
+			},
+		},
+		{
+			description:  "passing policy sets don't need approval",
+			user:         "global-owner",
+			policyStatus: []models.PolicySetStatus{{PolicySetName: "security", Passed: true}, {PolicySetName: "cost", Passed: false}},
+			expOut:       "Policies approved. Approved policy set(s): cost.",
+			expStatus: []models.PolicySetStatus{
+				{PolicySetName: "security", Passed: true},
+				{PolicySetName: "cost", Passed: false, Approvers: []string{"global-owner"}},
+			},
+		},
+		{
+			description:  "non owner can't approve",
+			user:         "someone-else",
+			policyStatus: failingStatus,
+			expFailure:   "Contact policy owners to approve failing policy set(s): security, cost.",
+			expStatus:    failingStatus,
+		},
+		{
+			description:  "policy set target",
+			user:         "global-owner",
+			target:       "security",
+			policyStatus: failingStatus,
+			expFailure:   "Contact policy owners to approve failing policy set(s): security.",
+			expStatus:    failingStatus,
+		},
+		{
+			description:  "unknown policy set target",
+			user:         "global-owner",
+			target:       "unknown",
+			policyStatus: failingStatus,
+			expFailure:   `Policy set "unknown" was not checked for this project.`,
+			expStatus:    failingStatus,
+		},
+		{
+			description: "no policy status falls back to top-level owners",
+			user:        "global-owner",
+			expOut:      "Policies approved",
+		},
+		{
+			description: "no policy status with non owner",
+			user:        "security-owner",
+			expFailure:  "Contact policy owners to approve failing policies.",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			runner := &events.DefaultProjectCommandRunner{}
+			res := runner.ApprovePolicies(command.ProjectContext{
+				User:                models.User{Username: c.user},
+				PolicySets:          policySets,
+				PolicySetTarget:     c.target,
+				ProjectPolicyStatus: c.policyStatus,
+			})
+			Ok(t, res.Error)
+			Equals(t, c.expFailure, res.Failure)
+			if c.expOut == "" {
+				Assert(t, res.PolicyCheckSuccess == nil, "exp no policy check success")
+			} else {
+				Equals(t, c.expOut, res.PolicyCheckSuccess.PolicyCheckOutput)
+			}
+			Equals(t, c.expStatus, res.PolicyStatus)
+		})
+	}
+}
+
 type mockURLGenerator struct{}
 
 func (m mockURLGenerator) GenerateLockURL(lockID string) string {
@@ -44,7 +44,12 @@ type ProjectFinder interface {
 var ignoredFilenameFragments = []string{"terraform.tfstate", "terraform.tfstate.backup", "tflint.hcl"}
 
 // DefaultProjectFinder implements ProjectFinder.
-type DefaultProjectFinder struct{}
+type DefaultProjectFinder struct {
+	// AutoplanModules is true if projects should also be considered modified
+	// when a file in a local module they call, directly or transitively, is
+	// modified.
+	AutoplanModules bool
+}
 
 // See ProjectFinder.DetermineProjects.
 func (p *DefaultProjectFinder) DetermineProjects(log logging.SimpleLogging, modifiedFiles []string, repoFullName string, absRepoDir string, autoplanFileList string) []models.Project {
@@ -64,6 +69,9 @@ func (p *DefaultProjectFinder) DetermineProjects(log logging.SimpleLogging, modi
 			dirs = append(dirs, projectDir)
 		}
 	}
+	if p.AutoplanModules {
+		dirs = p.addModuleProjects(log, dirs, modifiedTerraformFiles, absRepoDir)
+	}
 	uniqueDirs := p.unique(dirs)
 
 	// The list of modified files will include files that were deleted. We still
@@ -112,6 +120,7 @@ func (p *DefaultProjectFinder) DetermineProjectsViaConfig(log logging.SimpleLogg
 
 		// If any of the modified files matches the pattern then this project is
 		// considered modified.
+		modified := false
 		for _, file := range modifiedFiles {
 			match, err := pm.Matches(file)
 			if err != nil {
@@ -120,30 +129,109 @@ func (p *DefaultProjectFinder) DetermineProjectsViaConfig(log logging.SimpleLogg
 			}
 			if match {
 				log.Debug("file %q matched pattern", file)
-				// If we're checking using an atlantis.yaml file we downloaded
-				// directly from the repo (when doing a no-clone check) then
-				// absRepoDir will be empty. Since we didn't clone the repo
-				// yet we can't do this check. If there was a file modified
-				// in a deleted directory then when we finally do clone the repo
-				// we'll call this function again and then we'll detect the
-				// directory was deleted.
-				if absRepoDir != "" {
-					_, err := os.Stat(filepath.Join(absRepoDir, project.Dir))
-					if err == nil {
-						projects = append(projects, project)
-					} else {
-						log.Debug("project at dir %q not included because dir does not exist", project.Dir)
-					}
-				} else {
-					projects = append(projects, project)
-				}
+				modified = true
 				break
 			}
 		}
+		if !modified && p.AutoplanModules {
+			modified = p.modulesModified(log, project, modifiedFiles, absRepoDir)
+		}
+		if !modified {
+			continue
+		}
+
+		// If we're checking using an atlantis.yaml file we downloaded
+		// directly from the repo (when doing a no-clone check) then
+		// absRepoDir will be empty. Since we didn't clone the repo
+		// yet we can't do this check. If there was a file modified
+		// in a deleted directory then when we finally do clone the repo
+		// we'll call this function again and then we'll detect the
+		// directory was deleted.
+		if absRepoDir != "" {
+			_, err := os.Stat(filepath.Join(absRepoDir, project.Dir))
+			if err == nil {
+				projects = append(projects, project)
+			} else {
+				log.Debug("project at dir %q not included because dir does not exist", project.Dir)
+			}
+		} else {
+			projects = append(projects, project)
+		}
 	}
 	return projects, nil
 }
 
+// modulesModified returns true if any of modifiedFiles is in a local module
+// called by project.
+func (p *DefaultProjectFinder) modulesModified(log logging.SimpleLogging, project valid.Project, modifiedFiles []string, absRepoDir string) bool {
+	// Without the repo on disk we can't tell which modules the project calls.
+	// To make sure the repo is cloned we treat any modified .tf file as
+	// possibly being in one of its modules. The projects are determined
+	// again once the repo is cloned.
+	if absRepoDir == "" {
+		for _, file := range modifiedFiles {
+			if filepath.Ext(file) == ".tf" {
+				return true
+			}
+		}
+		return false
+	}
+
+	modifiedDirs := dirsOfFiles(p.removeIgnored(modifiedFiles))
+	for _, moduleDir := range findModuleDirs(log, absRepoDir, project.Dir) {
+		if modifiedDirs[moduleDir] {
+			log.Debug("module at dir %q called by project at dir %q was modified", moduleDir, project.Dir)
+			return true
+		}
+	}
+	return false
+}
+
+// addModuleProjects adds the projects in absRepoDir that call a local module
+// containing one of modifiedFiles to dirs. Directories in dirs that are
+// modules rather than projects are removed.
+func (p *DefaultProjectFinder) addModuleProjects(log logging.SimpleLogging, dirs []string, modifiedFiles []string, absRepoDir string) []string {
+	tfDirs, err := findTerraformDirs(absRepoDir)
+	if err != nil {
+		log.Warn("unable to find Terraform directories to check for modified modules: %s", err)
+		return dirs
+	}
+
+	moduleDirsByProject := make(map[string][]string)
+	isModule := make(map[string]bool)
+	for _, dir := range tfDirs {
+		moduleDirs := findModuleDirs(log, absRepoDir, dir)
+		moduleDirsByProject[dir] = moduleDirs
+		for _, moduleDir := range moduleDirs {
+			isModule[moduleDir] = true
+		}
+	}
+
+	var projectDirs []string
+	for _, dir := range dirs {
+		if isModule[dir] {
+			log.Debug("not planning dir %q since it is a module", dir)
+			continue
+		}
+		projectDirs = append(projectDirs, dir)
+	}
+
+	modifiedDirs := dirsOfFiles(modifiedFiles)
+	for _, dir := range tfDirs {
+		if isModule[dir] {
+			continue
+		}
+		for _, moduleDir := range moduleDirsByProject[dir] {
+			if modifiedDirs[moduleDir] {
+				log.Debug("module at dir %q called by project at dir %q was modified", moduleDir, dir)
+				projectDirs = append(projectDirs, dir)
+				break
+			}
+		}
+	}
+	return projectDirs
+}
+
 // filterToFileList filters out files not included in the file list
 func (p *DefaultProjectFinder) filterToFileList(log logging.SimpleLogging, files []string, fileList string) []string {
 	var filtered []string
@@ -168,6 +256,17 @@ func (p *DefaultProjectFinder) filterToFileList(log logging.SimpleLogging, files
 	return filtered
 }
 
+// removeIgnored returns files without the files we shouldn't trigger a plan on.
+func (p *DefaultProjectFinder) removeIgnored(files []string) []string {
+	var filtered []string
+	for _, f := range files {
+		if !p.shouldIgnore(f) {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
 // shouldIgnore returns true if we shouldn't trigger a plan on changes to this file.
 func (p *DefaultProjectFinder) shouldIgnore(fileName string) bool {
 	for _, s := range ignoredFilenameFragments {
@@ -514,3 +514,138 @@ func TestDefaultProjectFinder_DetermineProjectsViaConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultProjectFinder_AutoplanModules(t *testing.T) {
+	// Create dir structure:
+	// modules/
+	//   network/
+	//     main.tf (calls ../subnet)
+	//   subnet/
+	//     main.tf
+	//   dns/
+	//     main.tf
+	// shared/
+	//   vpc/
+	//     main.tf
+	// project1/
+	//   main.tf (calls ../modules/network and ../shared/vpc)
+	// project2/
+	//   main.tf (calls ../modules/dns and a registry module)
+	tmpDir, cleanup := DirStructure(t, map[string]interface{}{
+		"modules": map[string]interface{}{
+			"network": map[string]interface{}{
+				"main.tf": `module "subnet" {
+  source = "../subnet"
+}`,
+			},
+			"subnet": map[string]interface{}{
+				"main.tf": nil,
+			},
+			"dns": map[string]interface{}{
+				"main.tf": nil,
+			},
+		},
+		"shared": map[string]interface{}{
+			"vpc": map[string]interface{}{
+				"main.tf": nil,
+			},
+		},
+		"project1": map[string]interface{}{
+			"main.tf": `module "network" {
+  source = "../modules/network"
+}
+
+module "vpc" {
+  source = "../shared/vpc"
+}`,
+		},
+		"project2": map[string]interface{}{
+			"main.tf": `module "dns" {
+  source = "../modules/dns"
+}
+
+module "consul" {
+  source  = "hashicorp/consul/aws"
+  version = "0.1.0"
+}`,
+		},
+	})
+	defer cleanup()
+
+	cases := []struct {
+		description  string
+		modified     []string
+		expProjPaths []string
+	}{
+		{
+			description:  "project modified",
+			modified:     []string{"project1/main.tf"},
+			expProjPaths: []string{"project1"},
+		},
+		{
+			description:  "module modified",
+			modified:     []string{"modules/dns/main.tf"},
+			expProjPaths: []string{"project2"},
+		},
+		{
+			description:  "nested module modified",
+			modified:     []string{"modules/subnet/main.tf"},
+			expProjPaths: []string{"project1"},
+		},
+		{
+			description:  "module outside of modules dir isn't planned",
+			modified:     []string{"shared/vpc/main.tf"},
+			expProjPaths: []string{"project1"},
+		},
+		{
+			description:  "unused module modified",
+			modified:     []string{"modules/unused/main.tf"},
+			expProjPaths: nil,
+		},
+	}
+	finder := events.DefaultProjectFinder{AutoplanModules: true}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			projects := finder.DetermineProjects(logging.NewNoopLogger(t), c.modified, modifiedRepo, tmpDir, "**/*.tf")
+			var paths []string
+			for _, p := range projects {
+				paths = append(paths, p.Path)
+			}
+			Equals(t, c.expProjPaths, paths)
+		})
+
+		t.Run(c.description+" via config", func(t *testing.T) {
+			config := valid.RepoCfg{
+				Projects: []valid.Project{
+					{
+						Dir: "project1",
+						Autoplan: valid.Autoplan{
+							Enabled:      true,
+							WhenModified: []string{"*.tf"},
+						},
+					},
+					{
+						Dir: "project2",
+						Autoplan: valid.Autoplan{
+							Enabled:      true,
+							WhenModified: []string{"*.tf"},
+						},
+					},
+				},
+			}
+			projects, err := finder.DetermineProjectsViaConfig(logging.NewNoopLogger(t), c.modified, config, tmpDir)
+			Ok(t, err)
+			var paths []string
+			for _, p := range projects {
+				paths = append(paths, p.Dir)
+			}
+			Equals(t, c.expProjPaths, paths)
+
+			// Without the repo on disk any modified .tf file could be in a
+			// module.
+			projects, err = finder.DetermineProjectsViaConfig(logging.NewNoopLogger(t), c.modified, config, "")
+			Ok(t, err)
+			Equals(t, 2, len(projects))
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/runatlantis/atlantis/server/core/locking"
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/vcs"
+	"github.com/runatlantis/atlantis/server/events/webhooks"
 	"github.com/runatlantis/atlantis/server/logging"
 )
 
@@ -40,6 +41,8 @@ type ProjectLocker interface {
 type DefaultProjectLocker struct {
 	Locker    locking.Locker
 	VCSClient vcs.Client
+	// Webhooks is sent lock_acquired and lock_released events. It may be nil.
+	Webhooks WebhooksSender
 }
 
 // TryLockResponse is the result of trying to lock a project.
@@ -63,7 +66,15 @@ func (p *DefaultProjectLocker) TryLock(log logging.SimpleLogging, pull models.Pu
 	if err != nil {
 		return nil, err
 	}
-	if !lockAttempt.LockAcquired && lockAttempt.CurrLock.Pull.Num != pull.Num {
+	// Locks taken by API requests without a pull request all have pull
+	// number 0 so they're never treated as held by the same pull request.
+	if !lockAttempt.LockAcquired && lockAttempt.CurrLock.Pull.Num == 0 {
+		return &TryLockResponse{
+			LockAcquired:      false,
+			LockFailureReason: "This project is currently locked by a command run through the API. To continue, wait for that command to finish or delete the lock.",
+		}, nil
+	}
+	if !lockAttempt.LockAcquired && (pull.Num == 0 || lockAttempt.CurrLock.Pull.Num != pull.Num) {
 		link, err := p.VCSClient.MarkdownPullLink(lockAttempt.CurrLock.Pull)
 		if err != nil {
 			return nil, err
@@ -78,12 +89,40 @@ func (p *DefaultProjectLocker) TryLock(log logging.SimpleLogging, pull models.Pu
 		}, nil
 	}
 	log.Info("acquired lock with id %q", lockAttempt.LockKey)
+	// LockAcquired is only true if the lock didn't already exist so we don't
+	// send the webhook again each time the pull request is re-planned.
+	if lockAttempt.LockAcquired {
+		sendWebhook(p.Webhooks, log, lockWebhookResult(webhooks.LockAcquiredEvent, lockAttempt.CurrLock))
+	}
 	return &TryLockResponse{
 		LockAcquired: true,
 		UnlockFn: func() error {
-			_, err := p.Locker.Unlock(lockAttempt.LockKey)
-			return err
+			return UnlockProjectLock(p.Locker, p.Webhooks, log, lockAttempt.LockKey)
 		},
 		LockKey: lockAttempt.LockKey,
 	}, nil
 }
+
+// UnlockProjectLock releases the lock with key and sends a lock_released
+// webhook with sender if there was a lock. sender may be nil.
+func UnlockProjectLock(locker locking.Locker, sender WebhooksSender, log logging.SimpleLogging, key string) error {
+	lock, err := locker.Unlock(key)
+	if err == nil && lock != nil {
+		sendWebhook(sender, log, lockWebhookResult(webhooks.LockReleasedEvent, *lock))
+	}
+	return err
+}
+
+// lockWebhookResult returns the webhook result for event on lock.
+func lockWebhookResult(event string, lock models.ProjectLock) webhooks.EventResult {
+	return webhooks.EventResult{
+		Event:     event,
+		Workspace: lock.Workspace,
+		Repo:      lock.Pull.BaseRepo,
+		Pull:      lock.Pull,
+		User:      lock.User,
+		Success:   true,
+		Directory: lock.Project.Path,
+		Status:    webhooks.SuccessStatus,
+	}
+}
@@ -21,15 +21,18 @@ import (
 	"github.com/runatlantis/atlantis/server/core/locking"
 	"github.com/runatlantis/atlantis/server/core/locking/mocks"
 	"github.com/runatlantis/atlantis/server/events"
+	eventmocks "github.com/runatlantis/atlantis/server/events/mocks"
+	"github.com/runatlantis/atlantis/server/events/mocks/matchers"
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/vcs"
+	"github.com/runatlantis/atlantis/server/events/webhooks"
 	"github.com/runatlantis/atlantis/server/logging"
 	. "github.com/runatlantis/atlantis/testing"
 )
 
 func TestDefaultProjectLocker_TryLockWhenLocked(t *testing.T) {
 	var githubClient *vcs.GithubClient
-	mockClient := vcs.NewClientProxy(githubClient, nil, nil, nil, nil)
+	mockClient := vcs.NewClientProxy(githubClient, nil, nil, nil, nil, nil)
 	mockLocker := mocks.NewMockLocker()
 	locker := events.DefaultProjectLocker{
 		Locker:    mockLocker,
@@ -65,7 +68,7 @@ func TestDefaultProjectLocker_TryLockWhenLocked(t *testing.T) {
 func TestDefaultProjectLocker_TryLockWhenLockedSamePull(t *testing.T) {
 	RegisterMockTestingT(t)
 	var githubClient *vcs.GithubClient
-	mockClient := vcs.NewClientProxy(githubClient, nil, nil, nil, nil)
+	mockClient := vcs.NewClientProxy(githubClient, nil, nil, nil, nil, nil)
 	mockLocker := mocks.NewMockLocker()
 	locker := events.DefaultProjectLocker{
 		Locker:    mockLocker,
@@ -101,10 +104,40 @@ func TestDefaultProjectLocker_TryLockWhenLockedSamePull(t *testing.T) {
 	mockLocker.VerifyWasCalledOnce().Unlock(lockKey)
 }
 
+func TestDefaultProjectLocker_TryLockWhenLockedByAPI(t *testing.T) {
+	RegisterMockTestingT(t)
+	mockLocker := mocks.NewMockLocker()
+	locker := events.DefaultProjectLocker{
+		Locker: mockLocker,
+	}
+	expProject := models.Project{}
+	expWorkspace := "default"
+	// API requests without a pull request use pull number 0.
+	expPull := models.PullRequest{}
+	expUser := models.User{}
+
+	When(mockLocker.TryLock(expProject, expWorkspace, expPull, expUser)).ThenReturn(
+		locking.TryLockResponse{
+			LockAcquired: false,
+			CurrLock: models.ProjectLock{
+				Pull: models.PullRequest{},
+			},
+			LockKey: "key",
+		},
+		nil,
+	)
+	res, err := locker.TryLock(logging.NewNoopLogger(t), expPull, expUser, expWorkspace, expProject)
+	Ok(t, err)
+	Equals(t, &events.TryLockResponse{
+		LockAcquired:      false,
+		LockFailureReason: "This project is currently locked by a command run through the API. To continue, wait for that command to finish or delete the lock.",
+	}, res)
+}
+
 func TestDefaultProjectLocker_TryLockUnlocked(t *testing.T) {
 	RegisterMockTestingT(t)
 	var githubClient *vcs.GithubClient
-	mockClient := vcs.NewClientProxy(githubClient, nil, nil, nil, nil)
+	mockClient := vcs.NewClientProxy(githubClient, nil, nil, nil, nil, nil)
 	mockLocker := mocks.NewMockLocker()
 	locker := events.DefaultProjectLocker{
 		Locker:    mockLocker,
@@ -139,3 +172,50 @@ func TestDefaultProjectLocker_TryLockUnlocked(t *testing.T) {
 	Ok(t, err)
 	mockLocker.VerifyWasCalledOnce().Unlock(lockKey)
 }
+
+func TestDefaultProjectLocker_TryLockSendsWebhooks(t *testing.T) {
+	RegisterMockTestingT(t)
+	var githubClient *vcs.GithubClient
+	mockClient := vcs.NewClientProxy(githubClient, nil, nil, nil, nil, nil)
+	mockLocker := mocks.NewMockLocker()
+	mockSender := eventmocks.NewMockWebhooksSender()
+	locker := events.DefaultProjectLocker{
+		Locker:    mockLocker,
+		VCSClient: mockClient,
+		Webhooks:  mockSender,
+	}
+	expProject := models.Project{Path: "dir"}
+	expWorkspace := "default"
+	expPull := models.PullRequest{Num: 2}
+	expUser := models.User{Username: "acme-user"}
+	currLock := models.ProjectLock{
+		Project:   expProject,
+		Workspace: expWorkspace,
+		Pull:      expPull,
+		User:      expUser,
+	}
+	lockKey := "key"
+	When(mockLocker.TryLock(expProject, expWorkspace, expPull, expUser)).ThenReturn(
+		locking.TryLockResponse{
+			LockAcquired: true,
+			CurrLock:     currLock,
+			LockKey:      lockKey,
+		},
+		nil,
+	)
+	When(mockLocker.Unlock(lockKey)).ThenReturn(&currLock, nil)
+
+	res, err := locker.TryLock(logging.NewNoopLogger(t), expPull, expUser, expWorkspace, expProject)
+	Ok(t, err)
+	Ok(t, res.UnlockFn())
+
+	_, results := mockSender.VerifyWasCalled(Times(2)).Send(matchers.AnyLoggingSimpleLogging(), matchers.AnyWebhooksEventResult()).GetAllCapturedArguments()
+	Equals(t, webhooks.LockAcquiredEvent, results[0].Event)
+	Equals(t, webhooks.LockReleasedEvent, results[1].Event)
+	for _, r := range results {
+		Equals(t, "dir", r.Directory)
+		Equals(t, "default", r.Workspace)
+		Equals(t, 2, r.Pull.Num)
+		Equals(t, "acme-user", r.User.Username)
+	}
+}
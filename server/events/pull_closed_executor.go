@@ -29,6 +29,7 @@ import (
 	"github.com/runatlantis/atlantis/server/core/locking"
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/vcs"
+	"github.com/runatlantis/atlantis/server/events/webhooks"
 	"github.com/runatlantis/atlantis/server/jobs"
 )
 
@@ -54,9 +55,11 @@ type PullClosedExecutor struct {
 	VCSClient                vcs.Client
 	WorkingDir               WorkingDir
 	Logger                   logging.SimpleLogging
-	DB                       *db.BoltDB
+	DB                       db.Database
 	PullClosedTemplate       PullCleanupTemplate
 	LogStreamResourceCleaner ResourceCleaner
+	// Webhooks is sent lock_released and pull_closed events. It may be nil.
+	Webhooks WebhooksSender
 }
 
 type templatedProject struct {
@@ -116,6 +119,17 @@ func (p *PullClosedExecutor) CleanUpPull(repo models.Repo, pull models.PullReque
 		p.Logger.Err("deleting pull from db: %s", err)
 	}
 
+	for _, lock := range locks {
+		sendWebhook(p.Webhooks, p.Logger, lockWebhookResult(webhooks.LockReleasedEvent, lock))
+	}
+	sendWebhook(p.Webhooks, p.Logger, webhooks.EventResult{
+		Event:   webhooks.PullClosedEvent,
+		Repo:    repo,
+		Pull:    pull,
+		Success: true,
+		Status:  webhooks.SuccessStatus,
+	})
+
 	// If there are no locks then there's no need to comment.
 	if len(locks) == 0 {
 		return nil
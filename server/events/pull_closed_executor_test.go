@@ -32,6 +32,8 @@ import (
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/models/fixtures"
 	vcsmocks "github.com/runatlantis/atlantis/server/events/vcs/mocks"
+	"github.com/runatlantis/atlantis/server/events/webhooks"
+	"github.com/runatlantis/atlantis/server/logging"
 	loggermocks "github.com/runatlantis/atlantis/server/logging/mocks"
 	. "github.com/runatlantis/atlantis/testing"
 )
@@ -98,6 +100,43 @@ func TestCleanUpPullNoLocks(t *testing.T) {
 	cp.VerifyWasCalled(Never()).CreateComment(matchers.AnyModelsRepo(), AnyInt(), AnyString(), AnyString())
 }
 
+func TestCleanUpPullWebhooks(t *testing.T) {
+	t.Log("should send a lock_released webhook for each lock and then a pull_closed webhook")
+	RegisterMockTestingT(t)
+	w := mocks.NewMockWorkingDir()
+	l := lockmocks.NewMockLocker()
+	cp := vcsmocks.NewMockClient()
+	sender := mocks.NewMockWebhooksSender()
+	tmp, cleanup := TempDir(t)
+	defer cleanup()
+	db, err := db.New(tmp)
+	Ok(t, err)
+	pce := events.PullClosedExecutor{
+		Locker:     l,
+		VCSClient:  cp,
+		WorkingDir: w,
+		DB:         db,
+		Logger:     logging.NewNoopLogger(t),
+		Webhooks:   sender,
+	}
+	locks := []models.ProjectLock{
+		{
+			Project:   models.NewProject(fixtures.GithubRepo.FullName, "dir1"),
+			Workspace: "default",
+			Pull:      fixtures.Pull,
+		},
+	}
+	When(l.UnlockByPull(fixtures.GithubRepo.FullName, fixtures.Pull.Num)).ThenReturn(locks, nil)
+	err = pce.CleanUpPull(fixtures.GithubRepo, fixtures.Pull)
+	Ok(t, err)
+
+	_, results := sender.VerifyWasCalled(Times(2)).Send(matchers.AnyLoggingSimpleLogging(), matchers.AnyWebhooksEventResult()).GetAllCapturedArguments()
+	Equals(t, webhooks.LockReleasedEvent, results[0].Event)
+	Equals(t, "dir1", results[0].Directory)
+	Equals(t, webhooks.PullClosedEvent, results[1].Event)
+	Equals(t, fixtures.Pull.Num, results[1].Pull.Num)
+}
+
 func TestCleanUpPullComments(t *testing.T) {
 	t.Log("should comment correctly")
 	RegisterMockTestingT(t)
@@ -197,7 +236,7 @@ func TestCleanUpLogStreaming(t *testing.T) {
 
 		// Create Log streaming resources
 		prjCmdOutput := make(chan *jobs.ProjectCmdOutputLine)
-		prjCmdOutHandler := jobs.NewAsyncProjectCommandOutputHandler(prjCmdOutput, logger)
+		prjCmdOutHandler := jobs.NewAsyncProjectCommandOutputHandler(prjCmdOutput, logger, nil)
 		ctx := command.ProjectContext{
 			BaseRepo:    fixtures.GithubRepo,
 			Pull:        fixtures.Pull,
@@ -0,0 +1,108 @@
+package events
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+)
+
+// RunningCommands tracks the project commands that are running so they can be
+// cancelled with atlantis cancel. The zero value isn't usable, use
+// NewRunningCommands. A nil *RunningCommands doesn't track anything.
+type RunningCommands struct {
+	mu sync.Mutex
+	// commands are the running commands keyed by their job ID.
+	commands map[string]*runningCommand
+}
+
+type runningCommand struct {
+	ctx    command.ProjectContext
+	cancel context.CancelFunc
+	// cancelledBy is the user that cancelled the command. It's empty if the
+	// command hasn't been cancelled.
+	cancelledBy models.User
+}
+
+// NewRunningCommands returns an empty RunningCommands.
+func NewRunningCommands() *RunningCommands {
+	return &RunningCommands{
+		commands: make(map[string]*runningCommand),
+	}
+}
+
+// Start records that the command described by ctx is running. It returns a
+// copy of ctx whose Ctx is cancelled if the command is cancelled and a
+// function that must be called once the command finishes. If r is nil, ctx is
+// returned as is.
+func (r *RunningCommands) Start(ctx command.ProjectContext) (command.ProjectContext, func()) {
+	if r == nil {
+		return ctx, func() {}
+	}
+	cmdCtx, cancel := context.WithCancel(ctx.Context())
+	ctx.Ctx = cmdCtx
+
+	r.mu.Lock()
+	r.commands[ctx.JobID] = &runningCommand{ctx: ctx, cancel: cancel}
+	r.mu.Unlock()
+	return ctx, func() {
+		r.mu.Lock()
+		delete(r.commands, ctx.JobID)
+		r.mu.Unlock()
+		cancel()
+	}
+}
+
+// Cancel cancels the running commands of pull that match projectName,
+// repoRelDir and workspace. Empty filters match every command. It returns the
+// contexts of the cancelled commands.
+func (r *RunningCommands) Cancel(pull models.PullRequest, projectName string, repoRelDir string, workspace string, user models.User) []command.ProjectContext {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var cancelled []command.ProjectContext
+	for _, cmd := range r.commands {
+		ctx := cmd.ctx
+		if ctx.Pull.BaseRepo.FullName != pull.BaseRepo.FullName || ctx.Pull.Num != pull.Num {
+			continue
+		}
+		if (projectName != "" && ctx.ProjectName != projectName) ||
+			(repoRelDir != "" && ctx.RepoRelDir != repoRelDir) ||
+			(workspace != "" && ctx.Workspace != workspace) {
+			continue
+		}
+		// The command may take a while to stop so it could be cancelled
+		// twice.
+		if cmd.cancelledBy.Username != "" {
+			continue
+		}
+		cmd.cancelledBy = user
+		cmd.cancel()
+		cancelled = append(cancelled, ctx)
+	}
+	sort.Slice(cancelled, func(i, j int) bool {
+		return cancelled[i].RepoRelDir < cancelled[j].RepoRelDir ||
+			(cancelled[i].RepoRelDir == cancelled[j].RepoRelDir && cancelled[i].Workspace < cancelled[j].Workspace)
+	})
+	return cancelled
+}
+
+// cancelledBy returns the user that cancelled the command with jobID or false
+// if it wasn't cancelled.
+func (r *RunningCommands) cancelledBy(jobID string) (models.User, bool) {
+	if r == nil {
+		return models.User{}, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cmd, ok := r.commands[jobID]
+	if !ok || cmd.cancelledBy.Username == "" {
+		return models.User{}, false
+	}
+	return cmd.cancelledBy, true
+}
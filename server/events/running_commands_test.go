@@ -0,0 +1,65 @@
+package events_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestRunningCommands_Cancel(t *testing.T) {
+	repo := models.Repo{FullName: "owner/repo"}
+	pull := models.PullRequest{Num: 1, BaseRepo: repo}
+	otherPull := models.PullRequest{Num: 2, BaseRepo: repo}
+	user := models.User{Username: "user"}
+	running := events.NewRunningCommands()
+
+	start := func(jobID string, pull models.PullRequest, projectName string, dir string) command.ProjectContext {
+		ctx, _ := running.Start(command.ProjectContext{
+			JobID:       jobID,
+			Pull:        pull,
+			ProjectName: projectName,
+			RepoRelDir:  dir,
+			Workspace:   "default",
+		})
+		return ctx
+	}
+	prj1 := start("1", pull, "prj1", "dir1")
+	prj2 := start("2", pull, "prj2", "dir2")
+	other := start("3", otherPull, "prj1", "dir1")
+
+	Equals(t, 0, len(running.Cancel(pull, "unknown", "", "", user)))
+
+	cancelled := running.Cancel(pull, "prj2", "", "", user)
+	Equals(t, 1, len(cancelled))
+	Equals(t, "2", cancelled[0].JobID)
+	Assert(t, prj2.Context().Err() != nil, "exp prj2 to be cancelled")
+	Assert(t, prj1.Context().Err() == nil, "exp prj1 to still be running")
+
+	// Commands that were already cancelled aren't returned again.
+	cancelled = running.Cancel(pull, "", "", "", user)
+	Equals(t, 1, len(cancelled))
+	Equals(t, "1", cancelled[0].JobID)
+	Assert(t, prj1.Context().Err() != nil, "exp prj1 to be cancelled")
+	Assert(t, other.Context().Err() == nil, "exp the other pull's command to still be running")
+}
+
+func TestRunningCommands_Done(t *testing.T) {
+	pull := models.PullRequest{Num: 1, BaseRepo: models.Repo{FullName: "owner/repo"}}
+	running := events.NewRunningCommands()
+
+	ctx, done := running.Start(command.ProjectContext{JobID: "1", Pull: pull})
+	done()
+	Assert(t, ctx.Context().Err() != nil, "exp context to be cancelled once the command is done")
+	Equals(t, 0, len(running.Cancel(pull, "", "", "", models.User{Username: "user"})))
+}
+
+func TestRunningCommands_Nil(t *testing.T) {
+	var running *events.RunningCommands
+	ctx, done := running.Start(command.ProjectContext{JobID: "1"})
+	done()
+	Assert(t, ctx.Ctx == nil, "exp context to be unchanged")
+	Equals(t, 0, len(running.Cancel(models.PullRequest{}, "", "", "", models.User{})))
+}
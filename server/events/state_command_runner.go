@@ -0,0 +1,71 @@
+package events
+
+import (
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/vcs"
+)
+
+func NewStateCommandRunner(
+	pullUpdater *PullUpdater,
+	dbUpdater *DBUpdater,
+	prjCmdBuilder ProjectStateCommandBuilder,
+	prjCmdRunner ProjectStateCommandRunner,
+	pullReqStatusFetcher vcs.PullReqStatusFetcher,
+) *StateCommandRunner {
+	return &StateCommandRunner{
+		pullUpdater:          pullUpdater,
+		dbUpdater:            dbUpdater,
+		prjCmdBuilder:        prjCmdBuilder,
+		prjCmdRunner:         prjCmdRunner,
+		pullReqStatusFetcher: pullReqStatusFetcher,
+	}
+}
+
+// StateCommandRunner runs atlantis state rm and atlantis state mv.
+type StateCommandRunner struct {
+	pullUpdater          *PullUpdater
+	dbUpdater            *DBUpdater
+	prjCmdBuilder        ProjectStateCommandBuilder
+	prjCmdRunner         ProjectStateCommandRunner
+	pullReqStatusFetcher vcs.PullReqStatusFetcher
+}
+
+func (s *StateCommandRunner) Run(ctx *command.Context, cmd *CommentCommand) {
+	runStateChangeCmd(ctx, cmd, s.pullUpdater, s.dbUpdater, s.pullReqStatusFetcher, s.prjCmdBuilder.BuildStateCommands, s.prjCmdRunner.State)
+}
+
+// runStateChangeCmd runs a command that changes the state of projects, like
+// import or state rm. The commands run one at a time since they lock the
+// state. Afterwards the plans of the changed projects are discarded.
+func runStateChangeCmd(
+	ctx *command.Context,
+	cmd *CommentCommand,
+	pullUpdater *PullUpdater,
+	dbUpdater *DBUpdater,
+	pullReqStatusFetcher vcs.PullReqStatusFetcher,
+	buildCmds func(*command.Context, *CommentCommand) ([]command.ProjectContext, error),
+	runCmd func(command.ProjectContext) command.ProjectResult,
+) {
+	// The apply requirements are checked before the state is changed so the
+	// pull request's status is needed, see ApplyCommandRunner.
+	var err error
+	ctx.PullRequestStatus, err = pullReqStatusFetcher.FetchPullStatus(ctx.Pull.BaseRepo, ctx.Pull)
+	if err != nil {
+		ctx.Log.Warn("unable to get pull request status: %s. Continuing with mergeable and approved assumed false", err)
+	}
+
+	projectCmds, err := buildCmds(ctx, cmd)
+	if err != nil {
+		pullUpdater.updatePull(ctx, cmd, command.Result{Error: err})
+		return
+	}
+
+	if len(projectCmds) == 0 {
+		ctx.Log.Info("no projects to run %s in", cmd.Name.String())
+		return
+	}
+
+	result := runProjectCmds(projectCmds, runCmd)
+	dbUpdater.discardPlans(ctx, result.ProjectResults)
+	pullUpdater.updatePull(ctx, cmd, result)
+}
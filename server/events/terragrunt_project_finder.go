@@ -0,0 +1,379 @@
+package events
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// TerragruntConfigFilename is the name of Terragrunt config files.
+const TerragruntConfigFilename = "terragrunt.hcl"
+
+// terragruntSkipDirs are directories we don't look for Terragrunt configs in.
+var terragruntSkipDirs = map[string]bool{
+	".git":              true,
+	".terraform":        true,
+	".terragrunt-cache": true,
+}
+
+// TerragruntProjectFinder determines projects by parsing the Terragrunt
+// configs in the repo rather than by looking for .tf files. Each terragrunt.hcl
+// that isn't included by another config is a project. A project is modified if
+// a file in its directory, a file it includes or reads via
+// read_terragrunt_config, a file in a local Terraform source, or a project it
+// depends on was modified.
+// If the repo has no Terragrunt configs, it falls back to
+// DefaultProjectFinder.
+type TerragruntProjectFinder struct {
+	DefaultProjectFinder
+}
+
+// terragruntConfig holds the paths a Terragrunt config refers to. All paths
+// are absolute.
+type terragruntConfig struct {
+	// Files are the files that are included or read by the config.
+	Files []string
+	// Dependencies are the directories of the configs this config depends on
+	// via dependency and dependencies blocks.
+	Dependencies []string
+	// Sources are the directories of local Terraform sources.
+	Sources []string
+}
+
+// See ProjectFinder.DetermineProjects.
+func (p *TerragruntProjectFinder) DetermineProjects(log logging.SimpleLogging, modifiedFiles []string, repoFullName string, absRepoDir string, autoplanFileList string) []models.Project {
+	configs, err := p.parseTerragruntConfigs(log, absRepoDir)
+	if err != nil {
+		log.Warn("unable to parse Terragrunt configs, falling back to default project discovery: %s", err)
+		return p.DefaultProjectFinder.DetermineProjects(log, modifiedFiles, repoFullName, absRepoDir, autoplanFileList)
+	}
+	if len(configs) == 0 {
+		log.Debug("found no %s files, falling back to default project discovery", TerragruntConfigFilename)
+		return p.DefaultProjectFinder.DetermineProjects(log, modifiedFiles, repoFullName, absRepoDir, autoplanFileList)
+	}
+
+	filtered := p.filterToFileList(log, modifiedFiles, autoplanFileList)
+	modified := make(map[string]bool)
+	for _, f := range modifiedFiles {
+		if !p.shouldIgnore(f) {
+			modified[filepath.Join(absRepoDir, f)] = true
+		}
+	}
+
+	// dependents maps from a project directory to the projects that depend on
+	// it.
+	dependents := make(map[string][]string)
+	var modifiedDirs []string
+	for dir, cfg := range configs {
+		for _, dep := range cfg.Dependencies {
+			dependents[dep] = append(dependents[dep], dir)
+		}
+		if p.terragruntConfigModified(dir, cfg, filtered, modified, absRepoDir) {
+			modifiedDirs = append(modifiedDirs, dir)
+		}
+	}
+
+	// Projects that depend on a modified project need to be planned too since
+	// their inputs may have changed.
+	seen := make(map[string]bool)
+	for len(modifiedDirs) > 0 {
+		dir := modifiedDirs[0]
+		modifiedDirs = modifiedDirs[1:]
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		modifiedDirs = append(modifiedDirs, dependents[dir]...)
+	}
+
+	var relDirs []string
+	for dir := range seen {
+		relDir, err := filepath.Rel(absRepoDir, dir)
+		if err != nil {
+			continue
+		}
+		relDirs = append(relDirs, filepath.ToSlash(relDir))
+	}
+	sort.Strings(relDirs)
+
+	var projects []models.Project
+	for _, dir := range relDirs {
+		projects = append(projects, models.NewProject(repoFullName, dir))
+	}
+	log.Info("there are %d modified Terragrunt project(s) at path(s): %v",
+		len(projects), strings.Join(relDirs, ", "))
+	return projects
+}
+
+// terragruntConfigModified returns true if the config in the absolute
+// directory dir was modified directly, i.e. not through a dependency.
+// filtered are the modified files, relative to the repo root, that match the
+// autoplan file list. modified are all the modified files as absolute paths.
+func (p *TerragruntProjectFinder) terragruntConfigModified(dir string, cfg terragruntConfig, filtered []string, modified map[string]bool, absRepoDir string) bool {
+	for _, f := range filtered {
+		absFile := filepath.Join(absRepoDir, f)
+		if filepath.Dir(absFile) == dir {
+			return true
+		}
+		for _, src := range cfg.Sources {
+			if strings.HasPrefix(absFile, src+string(filepath.Separator)) {
+				return true
+			}
+		}
+	}
+	// Included and read files often don't match the autoplan file list, ex.
+	// _envcommon/vpc.hcl, so we check them against all modified files.
+	for _, f := range cfg.Files {
+		if modified[f] {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTerragruntConfigs parses the Terragrunt configs in absRepoDir. It
+// returns a map from the absolute directory of each config that isn't
+// included by another config to what that config refers to.
+func (p *TerragruntProjectFinder) parseTerragruntConfigs(log logging.SimpleLogging, absRepoDir string) (map[string]terragruntConfig, error) {
+	var configFiles []string
+	err := filepath.Walk(absRepoDir, func(pth string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if terragruntSkipDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Name() == TerragruntConfigFilename {
+			configFiles = append(configFiles, pth)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding %s files", TerragruntConfigFilename)
+	}
+
+	configs := make(map[string]terragruntConfig)
+	included := make(map[string]bool)
+	for _, configFile := range configFiles {
+		dir := filepath.Dir(configFile)
+		cfg := p.parseTerragruntConfig(log, configFile, dir, absRepoDir, make(map[string]bool))
+		for _, f := range cfg.Files {
+			included[f] = true
+		}
+		configs[dir] = cfg
+	}
+
+	// Configs that are included by other configs, ex. a root terragrunt.hcl
+	// with the remote state config, aren't projects themselves.
+	for _, configFile := range configFiles {
+		if included[configFile] {
+			delete(configs, filepath.Dir(configFile))
+		}
+	}
+	return configs, nil
+}
+
+// parseTerragruntConfig parses the Terragrunt config at absPath and any files
+// it includes or reads. Like Terragrunt, functions and relative paths are
+// evaluated relative to the directory of the config being planned, dir, even
+// in included files. visited tracks files already parsed to avoid cycles.
+// Expressions we can't evaluate, ex. ones that refer to locals, are skipped.
+func (p *TerragruntProjectFinder) parseTerragruntConfig(log logging.SimpleLogging, absPath string, dir string, absRepoDir string, visited map[string]bool) terragruntConfig {
+	var cfg terragruntConfig
+	if visited[absPath] {
+		return cfg
+	}
+	visited[absPath] = true
+
+	file, diags := hclparse.NewParser().ParseHCLFile(absPath)
+	if diags.HasErrors() {
+		log.Debug("unable to parse %q: %s", absPath, diags.Error())
+		return cfg
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return cfg
+	}
+
+	var reads []string
+	evalCtx := terragruntEvalContext(dir, absRepoDir, &reads)
+	evalPath := func(expr hcl.Expression) (string, bool) {
+		val, diags := expr.Value(evalCtx)
+		if diags.HasErrors() || !val.IsKnown() || val.IsNull() || val.Type() != cty.String {
+			log.Debug("unable to evaluate expression in %q: %s", absPath, diags.Error())
+			return "", false
+		}
+		return absTerragruntPath(dir, val.AsString()), true
+	}
+
+	var includes []string
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case "include":
+			if attr, ok := block.Body.Attributes["path"]; ok {
+				if include, ok := evalPath(attr.Expr); ok {
+					includes = append(includes, include)
+				}
+			}
+		case "dependency":
+			if attr, ok := block.Body.Attributes["config_path"]; ok {
+				if dep, ok := evalPath(attr.Expr); ok {
+					cfg.Dependencies = append(cfg.Dependencies, dep)
+				}
+			}
+		case "dependencies":
+			if attr, ok := block.Body.Attributes["paths"]; ok {
+				val, diags := attr.Expr.Value(evalCtx)
+				if diags.HasErrors() || !val.CanIterateElements() {
+					log.Debug("unable to evaluate dependencies.paths in %q: %s", absPath, diags.Error())
+					continue
+				}
+				for it := val.ElementIterator(); it.Next(); {
+					_, v := it.Element()
+					if v.IsKnown() && !v.IsNull() && v.Type() == cty.String {
+						cfg.Dependencies = append(cfg.Dependencies, absTerragruntPath(dir, v.AsString()))
+					}
+				}
+			}
+		case "terraform":
+			if attr, ok := block.Body.Attributes["source"]; ok {
+				val, diags := attr.Expr.Value(evalCtx)
+				if diags.HasErrors() || !val.IsKnown() || val.IsNull() || val.Type() != cty.String {
+					continue
+				}
+				if src, ok := localTerragruntSource(dir, val.AsString()); ok {
+					cfg.Sources = append(cfg.Sources, src)
+				}
+			}
+		case "locals":
+			// We only evaluate locals to find read_terragrunt_config calls.
+			for _, attr := range block.Body.Attributes {
+				attr.Expr.Value(evalCtx) // nolint: errcheck
+			}
+		}
+	}
+	for _, attr := range body.Attributes {
+		attr.Expr.Value(evalCtx) // nolint: errcheck
+	}
+
+	for _, f := range append(includes, reads...) {
+		cfg.Files = append(cfg.Files, f)
+		nested := p.parseTerragruntConfig(log, f, dir, absRepoDir, visited)
+		cfg.Files = append(cfg.Files, nested.Files...)
+		cfg.Dependencies = append(cfg.Dependencies, nested.Dependencies...)
+		cfg.Sources = append(cfg.Sources, nested.Sources...)
+	}
+	return cfg
+}
+
+// terragruntEvalContext returns the context used to evaluate Terragrunt
+// expressions for the config in dir. It supports the Terragrunt functions
+// commonly used to build paths. Paths passed to read_terragrunt_config are
+// appended to reads.
+func terragruntEvalContext(dir string, absRepoDir string, reads *[]string) *hcl.EvalContext {
+	stringFunc := func(impl func(args []string) (string, error)) function.Function {
+		return function.New(&function.Spec{
+			VarParam: &function.Parameter{Type: cty.String},
+			Type:     function.StaticReturnType(cty.String),
+			Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+				var strs []string
+				for _, a := range args {
+					strs = append(strs, a.AsString())
+				}
+				s, err := impl(strs)
+				if err != nil {
+					return cty.NilVal, err
+				}
+				return cty.StringVal(s), nil
+			},
+		})
+	}
+
+	return &hcl.EvalContext{
+		Functions: map[string]function.Function{
+			"find_in_parent_folders": stringFunc(func(args []string) (string, error) {
+				name := TerragruntConfigFilename
+				if len(args) > 0 {
+					name = args[0]
+				}
+				for cur := filepath.Dir(dir); strings.HasPrefix(cur, absRepoDir); cur = filepath.Dir(cur) {
+					candidate := filepath.Join(cur, name)
+					if _, err := os.Stat(candidate); err == nil {
+						return candidate, nil
+					}
+					if cur == absRepoDir {
+						break
+					}
+				}
+				if len(args) > 1 {
+					return args[1], nil
+				}
+				return "", fmt.Errorf("could not find %s in parent folders of %s", name, dir)
+			}),
+			"get_terragrunt_dir": stringFunc(func([]string) (string, error) {
+				return dir, nil
+			}),
+			"get_repo_root": stringFunc(func([]string) (string, error) {
+				return absRepoDir, nil
+			}),
+			"dirname": stringFunc(func(args []string) (string, error) {
+				if len(args) != 1 {
+					return "", errors.New("dirname takes one argument")
+				}
+				return filepath.Dir(args[0]), nil
+			}),
+			"read_terragrunt_config": function.New(&function.Spec{
+				Params:   []function.Parameter{{Type: cty.String}},
+				VarParam: &function.Parameter{Type: cty.DynamicPseudoType},
+				Type:     function.StaticReturnType(cty.DynamicPseudoType),
+				Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+					*reads = append(*reads, absTerragruntPath(dir, args[0].AsString()))
+					// We don't evaluate the config so its value is unknown.
+					return cty.DynamicVal, nil
+				},
+			}),
+		},
+	}
+}
+
+// absTerragruntPath returns pth as an absolute path. Relative paths are
+// relative to dir.
+func absTerragruntPath(dir string, pth string) string {
+	if filepath.IsAbs(pth) {
+		return filepath.Clean(pth)
+	}
+	return filepath.Join(dir, pth)
+}
+
+// localTerragruntSource returns the absolute directory of source if it's a
+// local path rather than a remote source like a git URL or registry module.
+func localTerragruntSource(dir string, source string) (string, bool) {
+	if strings.Contains(source, "::") || strings.Contains(source, "://") {
+		return "", false
+	}
+	if !filepath.IsAbs(source) && !strings.HasPrefix(source, "./") && !strings.HasPrefix(source, "../") {
+		return "", false
+	}
+	// A double slash separates the root of the source from the subdirectory
+	// of the module, ex. ../modules//vpc. The whole root is copied so changes
+	// anywhere in it can affect the module.
+	if i := strings.Index(source, "//"); i != -1 {
+		source = source[:i]
+	}
+	return path.Clean(absTerragruntPath(dir, source)), true
+}
@@ -0,0 +1,189 @@
+package events_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestTerragruntProjectFinder_DetermineProjects(t *testing.T) {
+	// Repo structure:
+	// terragrunt.hcl (root config included by every project)
+	// _envcommon/
+	//   vpc.hcl
+	// modules/
+	//   vpc/
+	//     main.tf
+	// dev/
+	//   env.hcl
+	//   vpc/
+	//     terragrunt.hcl (includes root and _envcommon/vpc.hcl, reads env.hcl)
+	//   app/
+	//     terragrunt.hcl (depends on ../vpc)
+	//   db/
+	//     terragrunt.hcl (depends on ../app via dependencies block)
+	// prod/
+	//   env.hcl
+	//   vpc/
+	//     terragrunt.hcl (includes root and _envcommon/vpc.hcl, reads env.hcl)
+	vpcConfig := `
+include "root" {
+  path = find_in_parent_folders()
+}
+
+include "envcommon" {
+  path = "${dirname(find_in_parent_folders())}/_envcommon/vpc.hcl"
+}
+`
+	repoDir, cleanup := DirStructure(t, map[string]interface{}{
+		"terragrunt.hcl": `
+remote_state {
+  backend = "s3"
+  config = {
+    key = "${path_relative_to_include()}/terraform.tfstate"
+  }
+}
+`,
+		"_envcommon": map[string]interface{}{
+			"vpc.hcl": `
+locals {
+  env_vars = read_terragrunt_config(find_in_parent_folders("env.hcl"))
+}
+
+terraform {
+  source = "${get_repo_root()}/modules//vpc"
+}
+`,
+		},
+		"modules": map[string]interface{}{
+			"vpc": map[string]interface{}{
+				"main.tf": nil,
+			},
+		},
+		"dev": map[string]interface{}{
+			"env.hcl": nil,
+			"vpc": map[string]interface{}{
+				"terragrunt.hcl": vpcConfig,
+			},
+			"app": map[string]interface{}{
+				"terragrunt.hcl": `
+include {
+  path = find_in_parent_folders()
+}
+
+terraform {
+  source = "git::https://github.com/runatlantis/modules.git//app?ref=v1.0.0"
+}
+
+dependency "vpc" {
+  config_path = "../vpc"
+}
+`,
+			},
+			"db": map[string]interface{}{
+				"terragrunt.hcl": `
+include {
+  path = find_in_parent_folders()
+}
+
+dependencies {
+  paths = ["../app"]
+}
+`,
+			},
+		},
+		"prod": map[string]interface{}{
+			"env.hcl": nil,
+			"vpc": map[string]interface{}{
+				"terragrunt.hcl": vpcConfig,
+			},
+		},
+	})
+	defer cleanup()
+
+	defaultAutoplanFileList := "**/*.tf,**/*.tfvars,**/*.tfvars.json,**/terragrunt.hcl,**/.terraform.lock.hcl"
+	cases := []struct {
+		description     string
+		files           []string
+		expProjectPaths []string
+	}{
+		{
+			"no modified files",
+			nil,
+			nil,
+		},
+		{
+			"modified leaf config with no dependents",
+			[]string{"prod/vpc/terragrunt.hcl"},
+			[]string{"prod/vpc"},
+		},
+		{
+			"modified leaf config plans its dependents",
+			[]string{"dev/vpc/terragrunt.hcl"},
+			[]string{"dev/app", "dev/db", "dev/vpc"},
+		},
+		{
+			"modified dependency only plans dependents",
+			[]string{"dev/app/terragrunt.hcl"},
+			[]string{"dev/app", "dev/db"},
+		},
+		{
+			"modified include plans every config that includes it",
+			[]string{"_envcommon/vpc.hcl"},
+			[]string{"dev/app", "dev/db", "dev/vpc", "prod/vpc"},
+		},
+		{
+			"modified root config plans every project",
+			[]string{"terragrunt.hcl"},
+			[]string{"dev/app", "dev/db", "dev/vpc", "prod/vpc"},
+		},
+		{
+			"modified file read via read_terragrunt_config",
+			[]string{"prod/env.hcl"},
+			[]string{"prod/vpc"},
+		},
+		{
+			"modified local source",
+			[]string{"modules/vpc/main.tf"},
+			[]string{"dev/app", "dev/db", "dev/vpc", "prod/vpc"},
+		},
+		{
+			"ignores files that don't match the autoplan file list",
+			[]string{"dev/db/README.md"},
+			nil,
+		},
+		{
+			"ignores files in .terragrunt-cache",
+			[]string{"dev/db/.terragrunt-cache/abc/main.tf"},
+			nil,
+		},
+	}
+	finder := events.TerragruntProjectFinder{}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			projects := finder.DetermineProjects(logging.NewNoopLogger(t), c.files, modifiedRepo, repoDir, defaultAutoplanFileList)
+			var paths []string
+			for _, p := range projects {
+				Equals(t, modifiedRepo, p.RepoFullName)
+				paths = append(paths, p.Path)
+			}
+			Equals(t, c.expProjectPaths, paths)
+		})
+	}
+}
+
+func TestTerragruntProjectFinder_DetermineProjectsFallback(t *testing.T) {
+	repoDir, cleanup := DirStructure(t, map[string]interface{}{
+		"project1": map[string]interface{}{
+			"main.tf": nil,
+		},
+	})
+	defer cleanup()
+
+	finder := events.TerragruntProjectFinder{}
+	projects := finder.DetermineProjects(logging.NewNoopLogger(t), []string{"project1/main.tf"}, modifiedRepo, repoDir, "**/*.tf")
+	Equals(t, 1, len(projects))
+	Equals(t, "project1", projects[0].Path)
+}
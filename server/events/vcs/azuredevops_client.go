@@ -379,9 +379,70 @@ func SplitAzureDevopsRepoFullName(repoFullName string) (owner string, project st
 	return repoFullName[:lastSlashIdx], "", repoFullName[lastSlashIdx+1:]
 }
 
+// azureDevopsTeamsMaxResults is the max number of teams, or members of a team,
+// returned by a single request.
+const azureDevopsTeamsMaxResults = 1000
+
+// azureDevopsTeam is a team in the Get Teams and Get Team Members responses.
+type azureDevopsTeam struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// azureDevopsTeamMember is a member in the Get Team Members response.
+type azureDevopsTeamMember struct {
+	Identity struct {
+		UniqueName string `json:"uniqueName"`
+	} `json:"identity"`
+}
+
 // GetTeamNamesForUser returns the names of the teams or groups that the user belongs to (in the organization the repository belongs to).
+// Azure DevOps teams belong to a project so only the teams of the
+// repository's project are returned.
 func (g *AzureDevopsClient) GetTeamNamesForUser(repo models.Repo, user models.User) ([]string, error) {
-	return nil, nil
+	owner, project, _ := SplitAzureDevopsRepoFullName(repo.FullName)
+
+	// The SDK's TeamsService doesn't support the project teams or team
+	// members endpoints.
+	var teams struct {
+		Value []azureDevopsTeam `json:"value"`
+	}
+	teamsURL := fmt.Sprintf("%s/_apis/projects/%s/teams?$top=%d&api-version=6.0", owner, url.PathEscape(project), azureDevopsTeamsMaxResults)
+	if err := g.get(teamsURL, &teams); err != nil {
+		return nil, errors.Wrapf(err, "listing teams in project %s", project)
+	}
+
+	var teamNames []string
+	for _, team := range teams.Value {
+		var members struct {
+			Value []azureDevopsTeamMember `json:"value"`
+		}
+		membersURL := fmt.Sprintf("%s/_apis/projects/%s/teams/%s/members?$top=%d&api-version=6.0", owner, url.PathEscape(project), team.ID, azureDevopsTeamsMaxResults)
+		if err := g.get(membersURL, &members); err != nil {
+			return nil, errors.Wrapf(err, "listing members of team %s", team.Name)
+		}
+		for _, member := range members.Value {
+			if strings.EqualFold(member.Identity.UniqueName, user.Username) {
+				teamNames = append(teamNames, team.Name)
+				break
+			}
+		}
+	}
+	return teamNames, nil
+}
+
+// get makes a GET request to the API path and decodes the JSON response into r.
+func (g *AzureDevopsClient) get(path string, r interface{}) error {
+	u, err := g.Client.BaseURL.Parse(path)
+	if err != nil {
+		return err
+	}
+	req, err := g.Client.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	_, err = g.Client.Execute(g.ctx, req, r)
+	return err
 }
 
 func (g *AzureDevopsClient) SupportsSingleFileDownload(repo models.Repo) bool {
@@ -410,3 +471,8 @@ func GitStatusContextFromSrc(src string) *azuredevops.GitStatusContext {
 		Genre: &genre,
 	}
 }
+
+// GetCloneURL returns the clone URL of the repo.
+func (g *AzureDevopsClient) GetCloneURL(VCSHostType models.VCSHostType, repo string) (string, error) {
+	return "", fmt.Errorf("not yet implemented")
+}
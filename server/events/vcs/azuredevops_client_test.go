@@ -555,6 +555,37 @@ func TestAzureDevopsClient_GetPullRequest(t *testing.T) {
 	})
 }
 
+func TestAzureDevopsClient_GetTeamNamesForUser(t *testing.T) {
+	testServer := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.RequestURI {
+			case "/owner/_apis/projects/project/teams?$top=1000&api-version=6.0":
+				w.Write([]byte(`{"value": [{"id": "team-1", "name": "Security"}, {"id": "team-2", "name": "Network"}], "count": 2}`)) // nolint: errcheck
+			case "/owner/_apis/projects/project/teams/team-1/members?$top=1000&api-version=6.0":
+				w.Write([]byte(`{"value": [{"identity": {"uniqueName": "someone@example.com"}}, {"identity": {"uniqueName": "User@example.com"}}], "count": 2}`)) // nolint: errcheck
+			case "/owner/_apis/projects/project/teams/team-2/members?$top=1000&api-version=6.0":
+				w.Write([]byte(`{"value": [{"identity": {"uniqueName": "someone@example.com"}}], "count": 1}`)) // nolint: errcheck
+			default:
+				t.Errorf("got unexpected request at %q", r.RequestURI)
+				http.Error(w, "not found", http.StatusNotFound)
+			}
+		}))
+	defer testServer.Close()
+	testServerURL, err := url.Parse(testServer.URL)
+	Ok(t, err)
+	client, err := vcs.NewAzureDevopsClient(testServerURL.Host, "user", "token")
+	Ok(t, err)
+	defer disableSSLVerification()()
+
+	teams, err := client.GetTeamNamesForUser(models.Repo{
+		FullName: "owner/project/repo",
+		Owner:    "owner",
+		Name:     "repo",
+	}, models.User{Username: "user@example.com"})
+	Ok(t, err)
+	Equals(t, []string{"Security"}, teams)
+}
+
 func TestAzureDevopsClient_MarkdownPullLink(t *testing.T) {
 	client, err := vcs.NewAzureDevopsClient("hostname", "user", "token")
 	Ok(t, err)
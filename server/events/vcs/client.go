@@ -45,4 +45,8 @@ type Client interface {
 	// if BaseRepo had one repo config file, its content will placed on the second return value
 	DownloadRepoConfigFile(pull models.PullRequest) (bool, []byte, error)
 	SupportsSingleFileDownload(repo models.Repo) bool
+	// GetCloneURL returns the URL that repo, a full name like owner/name,
+	// can be cloned from. It's used when a command isn't triggered by a
+	// webhook, for example by the API.
+	GetCloneURL(VCSHostType models.VCSHostType, repo string) (string, error)
 }
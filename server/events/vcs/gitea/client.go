@@ -0,0 +1,347 @@
+package gitea
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config"
+	"github.com/runatlantis/atlantis/server/events/models"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+// pageLimit is the number of items requested per page from paginated APIs.
+const pageLimit = 50
+
+type Client struct {
+	HTTPClient *http.Client
+	Token      string
+	// BaseURL is the URL of the Gitea server without the /api/v1 suffix,
+	// ex. https://gitea.com.
+	BaseURL string
+}
+
+// NewClient builds a Gitea client. hostname is the hostname of the Gitea
+// server, ex. gitea.com. We assume the server is served over HTTPS if
+// hostname doesn't have a scheme.
+func NewClient(httpClient *http.Client, hostname string, token string) (*Client, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	baseURL := hostname
+	if !strings.HasPrefix(hostname, "http://") && !strings.HasPrefix(hostname, "https://") {
+		baseURL = "https://" + baseURL
+	}
+	if _, err := url.Parse(baseURL); err != nil {
+		return nil, errors.Wrapf(err, "parsing URL %q", baseURL)
+	}
+	return &Client{
+		HTTPClient: httpClient,
+		Token:      token,
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+	}, nil
+}
+
+// GetModifiedFiles returns the names of files that were modified in the merge request
+// relative to the repo root, e.g. parent/child/file.txt.
+func (c *Client) GetModifiedFiles(repo models.Repo, pull models.PullRequest) ([]string, error) {
+	var files []string
+	// We'll only loop 1000 times as a safety measure.
+	maxLoops := 1000
+	for page := 1; page <= maxLoops; page++ {
+		path := fmt.Sprintf("%s/repos/%s/pulls/%d/files?page=%d&limit=%d", c.apiURL(), repo.FullName, pull.Num, page, pageLimit)
+		resp, err := c.makeRequest("GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+		var changed []ChangedFile
+		if err := json.Unmarshal(resp, &changed); err != nil {
+			return nil, errors.Wrapf(err, "Could not parse response %q", string(resp))
+		}
+		for _, f := range changed {
+			files = append(files, f.Filename)
+			// If the file was renamed, we'll want to run plan in the directory
+			// it was moved from as well.
+			if f.PreviousFilename != "" && f.PreviousFilename != f.Filename {
+				files = append(files, f.PreviousFilename)
+			}
+		}
+		if len(changed) < pageLimit {
+			break
+		}
+	}
+	return files, nil
+}
+
+// CreateComment creates a comment on the pull request.
+func (c *Client) CreateComment(repo models.Repo, pullNum int, comment string, command string) error {
+	bodyBytes, err := json.Marshal(map[string]string{"body": comment})
+	if err != nil {
+		return errors.Wrap(err, "json encoding")
+	}
+	// Pull requests are issues in Gitea so their comments are created through
+	// the issues API.
+	path := fmt.Sprintf("%s/repos/%s/issues/%d/comments", c.apiURL(), repo.FullName, pullNum)
+	_, err = c.makeRequest("POST", path, bytes.NewBuffer(bodyBytes))
+	return err
+}
+
+// HidePrevCommandComments is a no-op because Gitea's API can't hide or
+// minimize comments. Previous comments could only be deleted, which would
+// lose their output.
+func (c *Client) HidePrevCommandComments(repo models.Repo, pullNum int, command string) error {
+	return nil
+}
+
+// PullIsApproved returns true if the pull request was approved by someone
+// other than its author and that approval hasn't been dismissed or
+// superseded by a later review from the same user.
+func (c *Client) PullIsApproved(repo models.Repo, pull models.PullRequest) (approvalStatus models.ApprovalStatus, err error) {
+	var reviews []Review
+	// We'll only loop 1000 times as a safety measure.
+	maxLoops := 1000
+	for page := 1; page <= maxLoops; page++ {
+		path := fmt.Sprintf("%s/repos/%s/pulls/%d/reviews?page=%d&limit=%d", c.apiURL(), repo.FullName, pull.Num, page, pageLimit)
+		resp, err := c.makeRequest("GET", path, nil)
+		if err != nil {
+			return approvalStatus, err
+		}
+		var pageReviews []Review
+		if err := json.Unmarshal(resp, &pageReviews); err != nil {
+			return approvalStatus, errors.Wrapf(err, "Could not parse response %q", string(resp))
+		}
+		reviews = append(reviews, pageReviews...)
+		if len(pageReviews) < pageLimit {
+			break
+		}
+	}
+
+	// Reviews are returned oldest first so later reviews overwrite earlier
+	// ones. Comments don't change whether a user approved.
+	latest := make(map[string]Review)
+	for _, r := range reviews {
+		if r.User == nil || r.User.Login == nil || r.State == "COMMENT" {
+			continue
+		}
+		latest[*r.User.Login] = r
+	}
+	for login, r := range latest {
+		// Gitea allows the author to approve their own pull request. This
+		// defeats the purpose of approvals so we don't count that approval.
+		if login == pull.Author {
+			continue
+		}
+		if r.State == "APPROVED" && !r.Dismissed {
+			return models.ApprovalStatus{
+				IsApproved: true,
+				ApprovedBy: login,
+			}, nil
+		}
+	}
+	return approvalStatus, nil
+}
+
+// PullIsMergeable returns true if the pull request has no conflicts and can
+// be merged.
+func (c *Client) PullIsMergeable(repo models.Repo, pull models.PullRequest) (bool, error) {
+	giteaPull, err := c.GetPullRequest(repo, pull.Num)
+	if err != nil {
+		return false, err
+	}
+	return giteaPull.Mergeable, nil
+}
+
+// GetPullRequest returns the pull request.
+func (c *Client) GetPullRequest(repo models.Repo, num int) (*PullRequest, error) {
+	path := fmt.Sprintf("%s/repos/%s/pulls/%d", c.apiURL(), repo.FullName, num)
+	resp, err := c.makeRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var pull PullRequest
+	if err := json.Unmarshal(resp, &pull); err != nil {
+		return nil, errors.Wrapf(err, "Could not parse response %q", string(resp))
+	}
+	if err := validator.New().Struct(pull); err != nil {
+		return nil, errors.Wrapf(err, "API response %q was missing fields", string(resp))
+	}
+	return &pull, nil
+}
+
+// UpdateStatus updates the status of a commit.
+func (c *Client) UpdateStatus(repo models.Repo, pull models.PullRequest, status models.CommitStatus, src string, description string, url string) error {
+	giteaState := "failure"
+	switch status {
+	case models.PendingCommitStatus:
+		giteaState = "pending"
+	case models.SuccessCommitStatus:
+		giteaState = "success"
+	case models.FailedCommitStatus:
+		giteaState = "failure"
+	}
+
+	bodyBytes, err := json.Marshal(map[string]string{
+		"context":     src,
+		"target_url":  url,
+		"state":       giteaState,
+		"description": description,
+	})
+	if err != nil {
+		return errors.Wrap(err, "json encoding")
+	}
+	path := fmt.Sprintf("%s/repos/%s/statuses/%s", c.apiURL(), repo.FullName, pull.HeadCommit)
+	_, err = c.makeRequest("POST", path, bytes.NewBuffer(bodyBytes))
+	return err
+}
+
+// MergePull merges the pull request.
+func (c *Client) MergePull(pull models.PullRequest, pullOptions models.PullRequestOptions) error {
+	bodyBytes, err := json.Marshal(map[string]interface{}{
+		"Do":                        "merge",
+		"delete_branch_after_merge": pullOptions.DeleteSourceBranchOnMerge,
+	})
+	if err != nil {
+		return errors.Wrap(err, "json encoding")
+	}
+	path := fmt.Sprintf("%s/repos/%s/pulls/%d/merge", c.apiURL(), pull.BaseRepo.FullName, pull.Num)
+	_, err = c.makeRequest("POST", path, bytes.NewBuffer(bodyBytes))
+	return err
+}
+
+// MarkdownPullLink specifies the character used in a pull request comment.
+func (c *Client) MarkdownPullLink(pull models.PullRequest) (string, error) {
+	return fmt.Sprintf("#%d", pull.Num), nil
+}
+
+// GetTeamNamesForUser returns the names of the teams or groups that the user belongs to (in the organization the repository belongs to).
+// Gitea doesn't list the teams of another user so we list the teams of the
+// organization and check the user's membership of each.
+func (c *Client) GetTeamNamesForUser(repo models.Repo, user models.User) ([]string, error) {
+	var teams []Team
+	// We'll only loop 1000 times as a safety measure.
+	maxLoops := 1000
+	for page := 1; page <= maxLoops; page++ {
+		path := fmt.Sprintf("%s/orgs/%s/teams?page=%d&limit=%d", c.apiURL(), url.PathEscape(repo.Owner), page, pageLimit)
+		status, resp, err := c.doRequest("GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+		// Repos owned by users rather than organizations don't have teams.
+		if status == http.StatusNotFound {
+			return nil, nil
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("making request %q unexpected status code: %d, body: %s", "GET "+path, status, string(resp))
+		}
+		var pageTeams []Team
+		if err := json.Unmarshal(resp, &pageTeams); err != nil {
+			return nil, errors.Wrapf(err, "Could not parse response %q", string(resp))
+		}
+		teams = append(teams, pageTeams...)
+		if len(pageTeams) < pageLimit {
+			break
+		}
+	}
+
+	var teamNames []string
+	for _, team := range teams {
+		path := fmt.Sprintf("%s/teams/%d/members/%s", c.apiURL(), team.ID, url.PathEscape(user.Username))
+		status, resp, err := c.doRequest("GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+		switch status {
+		case http.StatusOK:
+			teamNames = append(teamNames, team.Name)
+		case http.StatusNotFound:
+		default:
+			return nil, fmt.Errorf("making request %q unexpected status code: %d, body: %s", "GET "+path, status, string(resp))
+		}
+	}
+	return teamNames, nil
+}
+
+func (c *Client) SupportsSingleFileDownload(models.Repo) bool {
+	return true
+}
+
+// DownloadRepoConfigFile return `atlantis.yaml` content from VCS (which support fetch a single file from repository)
+// The first return value indicate that repo contain atlantis.yaml or not
+// if BaseRepo had one repo config file, its content will placed on the second return value
+func (c *Client) DownloadRepoConfigFile(pull models.PullRequest) (bool, []byte, error) {
+	// The head commit is in the base repo even if the pull request is from a
+	// fork since Gitea keeps the heads of pull requests as refs/pull/<num>/head,
+	// whereas the head branch may only exist in the fork.
+	path := fmt.Sprintf("%s/repos/%s/raw/%s?ref=%s", c.apiURL(), pull.BaseRepo.FullName, config.AtlantisYAMLFilename, url.QueryEscape(pull.HeadCommit))
+	status, resp, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return true, []byte{}, err
+	}
+	if status == http.StatusNotFound {
+		return false, []byte{}, nil
+	}
+	if status != http.StatusOK {
+		return true, []byte{}, fmt.Errorf("making request %q unexpected status code: %d, body: %s", "GET "+path, status, string(resp))
+	}
+	return true, resp, nil
+}
+
+// GetCloneURL returns the clone URL of the repo.
+func (c *Client) GetCloneURL(VCSHostType models.VCSHostType, repo string) (string, error) {
+	path := fmt.Sprintf("%s/repos/%s", c.apiURL(), repo)
+	resp, err := c.makeRequest("GET", path, nil)
+	if err != nil {
+		return "", err
+	}
+	var repository Repository
+	if err := json.Unmarshal(resp, &repository); err != nil {
+		return "", errors.Wrapf(err, "Could not parse response %q", string(resp))
+	}
+	if err := validator.New().Struct(repository); err != nil {
+		return "", errors.Wrapf(err, "API response %q was missing fields", string(resp))
+	}
+	return *repository.CloneURL, nil
+}
+
+func (c *Client) apiURL() string {
+	return c.BaseURL + "/api/v1"
+}
+
+// doRequest makes the request and returns the status code and body of the
+// response.
+func (c *Client) doRequest(method string, path string, reqBody io.Reader) (int, []byte, error) {
+	req, err := http.NewRequest(method, path, reqBody)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "constructing request")
+	}
+	req.Header.Add("Authorization", "token "+c.Token)
+	if reqBody != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close() // nolint: errcheck
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, errors.Wrapf(err, "reading response from request %q", fmt.Sprintf("%s %s", method, path))
+	}
+	return resp.StatusCode, respBody, nil
+}
+
+func (c *Client) makeRequest(method string, path string, reqBody io.Reader) ([]byte, error) {
+	status, respBody, err := c.doRequest(method, path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK && status != http.StatusCreated {
+		return nil, fmt.Errorf("making request %q unexpected status code: %d, body: %s", fmt.Sprintf("%s %s", method, path), status, string(respBody))
+	}
+	return respBody, nil
+}
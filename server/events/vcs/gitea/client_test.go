@@ -0,0 +1,248 @@
+package gitea_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/vcs/gitea"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+var repo = models.Repo{
+	FullName: "runatlantis/atlantis-example",
+	Owner:    "runatlantis",
+	Name:     "atlantis-example",
+	VCSHost: models.VCSHost{
+		Type:     models.Gitea,
+		Hostname: "gitea.com",
+	},
+}
+
+// newTestClient returns a client pointed at a server that responds to the
+// requests in routes, keyed by request URI, with the contents of the files in
+// testdata.
+func newTestClient(t *testing.T, routes map[string]string) *gitea.Client {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Equals(t, "token token", r.Header.Get("Authorization"))
+		fixture, ok := routes[r.Method+" "+r.RequestURI]
+		if !ok {
+			t.Errorf("got unexpected request %s %q", r.Method, r.RequestURI)
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		if fixture == "" {
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		resp, err := os.ReadFile(filepath.Join("testdata", fixture))
+		Ok(t, err)
+		w.Write(resp) // nolint: errcheck
+	}))
+	t.Cleanup(testServer.Close)
+
+	client, err := gitea.NewClient(http.DefaultClient, testServer.URL, "token")
+	Ok(t, err)
+	return client
+}
+
+func TestNewClient_BaseURL(t *testing.T) {
+	cases := map[string]string{
+		"gitea.com":                  "https://gitea.com",
+		"https://gitea.example.com/": "https://gitea.example.com",
+		"http://localhost:3000":      "http://localhost:3000",
+	}
+	for hostname, exp := range cases {
+		t.Run(hostname, func(t *testing.T) {
+			client, err := gitea.NewClient(nil, hostname, "token")
+			Ok(t, err)
+			Equals(t, exp, client.BaseURL)
+		})
+	}
+}
+
+func TestClient_GetModifiedFiles(t *testing.T) {
+	client := newTestClient(t, map[string]string{
+		"GET /api/v1/repos/runatlantis/atlantis-example/pulls/1/files?page=1&limit=50": "pull-files.json",
+	})
+	files, err := client.GetModifiedFiles(repo, models.PullRequest{Num: 1})
+	Ok(t, err)
+	Equals(t, []string{"main.tf", "staging/main.tf", "dev/main.tf"}, files)
+}
+
+func TestClient_PullIsApproved(t *testing.T) {
+	cases := []struct {
+		fixture     string
+		expApproved bool
+	}{
+		{"reviews-approved.json", true},
+		{"reviews-changes-requested.json", false},
+		{"reviews-approval-dismissed.json", false},
+		{"reviews-approved-by-author.json", false},
+	}
+	for _, c := range cases {
+		t.Run(c.fixture, func(t *testing.T) {
+			client := newTestClient(t, map[string]string{
+				"GET /api/v1/repos/runatlantis/atlantis-example/pulls/1/reviews?page=1&limit=50": c.fixture,
+			})
+			approvalStatus, err := client.PullIsApproved(repo, models.PullRequest{Num: 1, Author: "lkysow"})
+			Ok(t, err)
+			Equals(t, c.expApproved, approvalStatus.IsApproved)
+		})
+	}
+}
+
+func TestClient_PullIsMergeable(t *testing.T) {
+	client := newTestClient(t, map[string]string{
+		"GET /api/v1/repos/runatlantis/atlantis-example/pulls/1": "pull.json",
+	})
+	mergeable, err := client.PullIsMergeable(repo, models.PullRequest{Num: 1})
+	Ok(t, err)
+	Equals(t, true, mergeable)
+}
+
+func TestClient_GetPullRequest(t *testing.T) {
+	client := newTestClient(t, map[string]string{
+		"GET /api/v1/repos/runatlantis/atlantis-example/pulls/1": "pull.json",
+	})
+	pull, err := client.GetPullRequest(repo, 1)
+	Ok(t, err)
+	Equals(t, 1, *pull.Number)
+	Equals(t, "b52b8e254e956654dcdb394d0ccba9199f420427", *pull.Head.SHA)
+	Equals(t, "lkysow/null-resource", *pull.Head.Ref)
+	Equals(t, "main", *pull.Base.Ref)
+	Equals(t, "https://gitea.com/runatlantis/atlantis-example.git", *pull.Base.Repository.CloneURL)
+}
+
+func TestClient_GetCloneURL(t *testing.T) {
+	client := newTestClient(t, map[string]string{
+		"GET /api/v1/repos/runatlantis/atlantis-example": "repo.json",
+	})
+	cloneURL, err := client.GetCloneURL(models.Gitea, "runatlantis/atlantis-example")
+	Ok(t, err)
+	Equals(t, "https://gitea.com/runatlantis/atlantis-example.git", cloneURL)
+}
+
+func TestClient_CreateComment(t *testing.T) {
+	var body map[string]string
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Equals(t, "POST /api/v1/repos/runatlantis/atlantis-example/issues/1/comments", r.Method+" "+r.RequestURI)
+		bodyBytes, err := io.ReadAll(r.Body)
+		Ok(t, err)
+		Ok(t, json.Unmarshal(bodyBytes, &body))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer testServer.Close()
+
+	client, err := gitea.NewClient(http.DefaultClient, testServer.URL, "token")
+	Ok(t, err)
+	Ok(t, client.CreateComment(repo, 1, "comment", ""))
+	Equals(t, map[string]string{"body": "comment"}, body)
+}
+
+func TestClient_UpdateStatus(t *testing.T) {
+	cases := []struct {
+		status   models.CommitStatus
+		expState string
+	}{
+		{models.PendingCommitStatus, "pending"},
+		{models.SuccessCommitStatus, "success"},
+		{models.FailedCommitStatus, "failure"},
+	}
+	for _, c := range cases {
+		t.Run(c.expState, func(t *testing.T) {
+			var body map[string]string
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Equals(t, "POST /api/v1/repos/runatlantis/atlantis-example/statuses/sha", r.Method+" "+r.RequestURI)
+				bodyBytes, err := io.ReadAll(r.Body)
+				Ok(t, err)
+				Ok(t, json.Unmarshal(bodyBytes, &body))
+				w.WriteHeader(http.StatusCreated)
+			}))
+			defer testServer.Close()
+
+			client, err := gitea.NewClient(http.DefaultClient, testServer.URL, "token")
+			Ok(t, err)
+			err = client.UpdateStatus(repo, models.PullRequest{HeadCommit: "sha"}, c.status, "atlantis/plan", "description", "https://atlantis.example.com")
+			Ok(t, err)
+			Equals(t, map[string]string{
+				"context":     "atlantis/plan",
+				"target_url":  "https://atlantis.example.com",
+				"state":       c.expState,
+				"description": "description",
+			}, body)
+		})
+	}
+}
+
+func TestClient_DownloadRepoConfigFile(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/api/v1/repos/runatlantis/atlantis-example/raw/atlantis.yaml?ref=has-config-sha":
+			w.Write([]byte("version: 3\n")) // nolint: errcheck
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer testServer.Close()
+
+	client, err := gitea.NewClient(http.DefaultClient, testServer.URL, "token")
+	Ok(t, err)
+	Equals(t, true, client.SupportsSingleFileDownload(repo))
+
+	hasConfig, content, err := client.DownloadRepoConfigFile(models.PullRequest{BaseRepo: repo, HeadBranch: "has-config", HeadCommit: "has-config-sha"})
+	Ok(t, err)
+	Equals(t, true, hasConfig)
+	Equals(t, "version: 3\n", string(content))
+
+	// The head branch of a pull request from a fork, ex. main, only exists in
+	// the fork but its head commit is also in the base repo.
+	hasConfig, content, err = client.DownloadRepoConfigFile(models.PullRequest{BaseRepo: repo, HeadBranch: "main", HeadCommit: "has-config-sha"})
+	Ok(t, err)
+	Equals(t, true, hasConfig)
+	Equals(t, "version: 3\n", string(content))
+
+	hasConfig, _, err = client.DownloadRepoConfigFile(models.PullRequest{BaseRepo: repo, HeadBranch: "no-config", HeadCommit: "no-config-sha"})
+	Ok(t, err)
+	Equals(t, false, hasConfig)
+}
+
+func TestClient_GetTeamNamesForUser(t *testing.T) {
+	teams, err := os.ReadFile(filepath.Join("testdata", "org-teams.json"))
+	Ok(t, err)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/api/v1/orgs/runatlantis/teams?page=1&limit=50":
+			w.Write(teams) // nolint: errcheck
+		case "/api/v1/teams/1/members/lkysow", "/api/v1/teams/3/members/lkysow":
+			w.Write([]byte(`{"id": 2, "login": "lkysow"}`)) // nolint: errcheck
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer testServer.Close()
+
+	client, err := gitea.NewClient(http.DefaultClient, testServer.URL, "token")
+	Ok(t, err)
+	names, err := client.GetTeamNamesForUser(repo, models.User{Username: "lkysow"})
+	Ok(t, err)
+	Equals(t, []string{"Owners", "security"}, names)
+
+	// Repos owned by users don't have teams.
+	names, err = client.GetTeamNamesForUser(models.Repo{FullName: "lkysow/example", Owner: "lkysow"}, models.User{Username: "lkysow"})
+	Ok(t, err)
+	Equals(t, 0, len(names))
+}
+
+func TestClient_MarkdownPullLink(t *testing.T) {
+	client, err := gitea.NewClient(nil, "gitea.com", "token")
+	Ok(t, err)
+	s, err := client.MarkdownPullLink(models.PullRequest{Num: 1})
+	Ok(t, err)
+	Equals(t, "#1", s)
+}
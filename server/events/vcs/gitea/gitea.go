@@ -0,0 +1,27 @@
+// Package gitea holds code for Gitea and Forgejo, its fork. Both have the same
+// API and webhooks.
+package gitea
+
+const DefaultHostname = "gitea.com"
+
+// Gitea sends these headers with every webhook. Forgejo sends them as well as
+// its own X-Forgejo-* headers.
+const (
+	EventHeader     = "X-Gitea-Event"
+	DeliveryHeader  = "X-Gitea-Delivery"
+	SignatureHeader = "X-Gitea-Signature"
+)
+
+// Values of the EventHeader header.
+const (
+	PullRequestHeader  = "pull_request"
+	IssueCommentHeader = "issue_comment"
+)
+
+// Actions of pull request events.
+const (
+	PullOpenedAction       = "opened"
+	PullReopenedAction     = "reopened"
+	PullSynchronizedAction = "synchronized"
+	PullClosedAction       = "closed"
+)
@@ -0,0 +1,74 @@
+package gitea
+
+// PullRequestEvent is the payload of the pull_request webhook.
+type PullRequestEvent struct {
+	Action      *string      `json:"action,omitempty" validate:"required"`
+	PullRequest *PullRequest `json:"pull_request,omitempty" validate:"required"`
+	Repository  *Repository  `json:"repository,omitempty" validate:"required"`
+	Sender      *User        `json:"sender,omitempty" validate:"required"`
+}
+
+// IssueCommentEvent is the payload of the issue_comment webhook. It's sent
+// for comments on both issues and pull requests.
+type IssueCommentEvent struct {
+	Action     *string     `json:"action,omitempty" validate:"required"`
+	Issue      *Issue      `json:"issue,omitempty" validate:"required"`
+	Comment    *Comment    `json:"comment,omitempty" validate:"required"`
+	Repository *Repository `json:"repository,omitempty" validate:"required"`
+	Sender     *User       `json:"sender,omitempty" validate:"required"`
+	IsPull     bool        `json:"is_pull"`
+}
+
+type PullRequest struct {
+	Number    *int        `json:"number,omitempty" validate:"required"`
+	HTMLURL   *string     `json:"html_url,omitempty" validate:"required"`
+	State     *string     `json:"state,omitempty" validate:"required"`
+	User      *User       `json:"user,omitempty" validate:"required"`
+	Head      *BranchMeta `json:"head,omitempty" validate:"required"`
+	Base      *BranchMeta `json:"base,omitempty" validate:"required"`
+	Mergeable bool        `json:"mergeable"`
+	Merged    bool        `json:"merged"`
+}
+
+type BranchMeta struct {
+	Ref        *string     `json:"ref,omitempty" validate:"required"`
+	SHA        *string     `json:"sha,omitempty" validate:"required"`
+	Repository *Repository `json:"repo,omitempty" validate:"required"`
+}
+
+type Repository struct {
+	FullName *string `json:"full_name,omitempty" validate:"required"`
+	CloneURL *string `json:"clone_url,omitempty" validate:"required"`
+}
+
+type User struct {
+	Login *string `json:"login,omitempty" validate:"required"`
+}
+
+type Issue struct {
+	Number *int `json:"number,omitempty" validate:"required"`
+}
+
+type Comment struct {
+	Body *string `json:"body,omitempty" validate:"required"`
+}
+
+// ChangedFile is a file changed by a pull request.
+type ChangedFile struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename"`
+}
+
+// Review is a review of a pull request.
+type Review struct {
+	State     string `json:"state"`
+	Dismissed bool   `json:"dismissed"`
+	Stale     bool   `json:"stale"`
+	User      *User  `json:"user,omitempty"`
+}
+
+// Team is a team of an organization.
+type Team struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
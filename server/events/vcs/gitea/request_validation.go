@@ -0,0 +1,30 @@
+package gitea
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// ValidateSignature validates that payload was signed with secretKey.
+// signature is the value of the SignatureHeader header which, unlike GitHub
+// and Bitbucket Server, is the hex encoded HMAC-SHA256 without a prefix.
+func ValidateSignature(payload []byte, signature string, secretKey []byte) error {
+	if signature == "" {
+		return errors.New("missing signature")
+	}
+	messageMAC, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("error decoding signature %q: %v", signature, err)
+	}
+	mac := hmac.New(sha256.New, secretKey)
+	// nolint: errcheck
+	mac.Write(payload)
+	if !hmac.Equal(messageMAC, mac.Sum(nil)) {
+		return errors.New("payload signature check failed")
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package gitea_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events/vcs/gitea"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestValidateSignature(t *testing.T) {
+	body := []byte(`{"action":"created","is_pull":true}`)
+	secret := []byte("mysecret")
+
+	err := gitea.ValidateSignature(body, "7047f16ac75bd313213d745a1f7fde8644ad918a07582674f2d0fd614bb6bd6a", secret)
+	Ok(t, err)
+}
+
+func TestValidateSignature_Invalid(t *testing.T) {
+	body := []byte(`{"action":"created","is_pull":true}`)
+	secret := []byte("mysecret")
+
+	err := gitea.ValidateSignature(body, "", secret)
+	ErrEquals(t, "missing signature", err)
+
+	err = gitea.ValidateSignature(body, "4b6dbd5f0d1e2a5a4b4e1b0d1f8b4ddf8e8d0b0e3c1b4c7d6f0c0b5b3c2a7e2e", secret)
+	ErrEquals(t, "payload signature check failed", err)
+
+	err = gitea.ValidateSignature(body, "7047f16ac75bd313213d745a1f7fde8644ad918a07582674f2d0fd614bb6bd6a", []byte("othersecret"))
+	ErrEquals(t, "payload signature check failed", err)
+
+	err = gitea.ValidateSignature(body, "not-hex", secret)
+	Assert(t, err != nil, "expected error decoding signature")
+}
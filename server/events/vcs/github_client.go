@@ -396,6 +396,16 @@ func (g *GithubClient) UpdateStatus(repo models.Repo, pull models.PullRequest, s
 	return err
 }
 
+// CreateIssue opens an issue in repo.
+func (g *GithubClient) CreateIssue(repo models.Repo, title string, body string) error {
+	g.logger.Debug("POST /repos/%v/%v/issues", repo.Owner, repo.Name)
+	_, _, err := g.client.Issues.Create(g.ctx, repo.Owner, repo.Name, &github.IssueRequest{
+		Title: &title,
+		Body:  &body,
+	})
+	return err
+}
+
 // MergePull merges the pull request.
 func (g *GithubClient) MergePull(pull models.PullRequest, pullOptions models.PullRequestOptions) error {
 	// Users can set their repo to disallow certain types of merging.
@@ -529,3 +539,16 @@ func (g *GithubClient) DownloadRepoConfigFile(pull models.PullRequest) (bool, []
 func (g *GithubClient) SupportsSingleFileDownload(repo models.Repo) bool {
 	return true
 }
+
+// GetCloneURL returns the clone URL of the repo.
+func (g *GithubClient) GetCloneURL(VCSHostType models.VCSHostType, repo string) (string, error) {
+	parts := strings.Split(repo, "/")
+	if len(parts) != 2 {
+		return "", fmt.Errorf("invalid repo %q, expected owner/name", repo)
+	}
+	repository, _, err := g.client.Repositories.Get(g.ctx, parts[0], parts[1])
+	if err != nil {
+		return "", err
+	}
+	return repository.GetCloneURL(), nil
+}
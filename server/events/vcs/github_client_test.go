@@ -389,6 +389,42 @@ func TestGithubClient_UpdateStatus(t *testing.T) {
 	}
 }
 
+func TestGithubClient_CreateIssue(t *testing.T) {
+	testServer := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.RequestURI {
+			case "/api/v3/repos/owner/repo/issues":
+				body, err := io.ReadAll(r.Body)
+				Ok(t, err)
+				Equals(t, `{"title":"title","body":"body"}`+"\n", string(body))
+				defer r.Body.Close() // nolint: errcheck
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"number":1}`)) // nolint: errcheck
+			default:
+				t.Errorf("got unexpected request at %q", r.RequestURI)
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+		}))
+
+	testServerURL, err := url.Parse(testServer.URL)
+	Ok(t, err)
+	client, err := vcs.NewGithubClient(testServerURL.Host, &vcs.GithubUserCredentials{"user", "pass"}, logging.NewNoopLogger(t))
+	Ok(t, err)
+	defer disableSSLVerification()()
+
+	err = client.CreateIssue(models.Repo{
+		FullName: "owner/repo",
+		Owner:    "owner",
+		Name:     "repo",
+		VCSHost: models.VCSHost{
+			Type:     models.Github,
+			Hostname: "github.com",
+		},
+	}, "title", "body")
+	Ok(t, err)
+}
+
 func TestGithubClient_PullIsApproved(t *testing.T) {
 	respTemplate := `[
 		{
@@ -43,6 +43,10 @@ type GitlabClient struct {
 	Client *gitlab.Client
 	// Version is set to the server version.
 	Version *version.Version
+	// ConfiguredGroups are the full paths of the groups that
+	// GetTeamNamesForUser checks the user's membership of, ex. the groups
+	// that own policy sets.
+	ConfiguredGroups []string
 }
 
 // commonMarkSupported is a version constraint that is true when this version of
@@ -356,8 +360,45 @@ func MustConstraint(constraint string) version.Constraints {
 }
 
 // GetTeamNamesForUser returns the names of the teams or groups that the user belongs to (in the organization the repository belongs to).
+// GitLab doesn't let us list the groups of another user so this returns the
+// ConfiguredGroups that the user is an active member of, either directly or
+// through a parent group.
 func (g *GitlabClient) GetTeamNamesForUser(repo models.Repo, user models.User) ([]string, error) {
-	return nil, nil
+	if len(g.ConfiguredGroups) == 0 {
+		return nil, nil
+	}
+
+	users, _, err := g.Client.Users.ListUsers(&gitlab.ListUsersOptions{Username: gitlab.String(user.Username)})
+	if err != nil {
+		return nil, errors.Wrapf(err, "looking up user %s", user.Username)
+	}
+	if len(users) != 1 {
+		return nil, fmt.Errorf("expected 1 user with username %s, found %d", user.Username, len(users))
+	}
+	userID := users[0].ID
+
+	var groupNames []string
+	for _, group := range g.ConfiguredGroups {
+		// go-gitlab doesn't support the endpoint that includes inherited members.
+		req, err := g.Client.NewRequest(http.MethodGet, fmt.Sprintf("groups/%s/members/all/%d", gitlab.PathEscape(group), userID), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		member := new(gitlab.GroupMember)
+		resp, err := g.Client.Do(req, member)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting membership of group %s", group)
+		}
+		// Members whose invitation or access request is pending aren't active.
+		if member.State != "" && member.State != "active" {
+			continue
+		}
+		groupNames = append(groupNames, group)
+	}
+	return groupNames, nil
 }
 
 // DownloadRepoConfigFile return `atlantis.yaml` content from VCS (which support fetch a single file from repository)
@@ -381,3 +422,12 @@ func (g *GitlabClient) DownloadRepoConfigFile(pull models.PullRequest) (bool, []
 func (g *GitlabClient) SupportsSingleFileDownload(repo models.Repo) bool {
 	return true
 }
+
+// GetCloneURL returns the clone URL of the repo.
+func (g *GitlabClient) GetCloneURL(VCSHostType models.VCSHostType, repo string) (string, error) {
+	project, _, err := g.Client.Projects.GetProject(repo, nil)
+	if err != nil {
+		return "", err
+	}
+	return project.HTTPURLToRepo, nil
+}
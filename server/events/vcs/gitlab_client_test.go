@@ -248,6 +248,51 @@ func TestGitlabClient_UpdateStatus(t *testing.T) {
 	}
 }
 
+func TestGitlabClient_GetTeamNamesForUser(t *testing.T) {
+	testServer := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.RequestURI {
+			case "/api/v4/users?username=lkysow":
+				w.Write([]byte(`[{"id": 123, "username": "lkysow"}]`)) // nolint: errcheck
+			case "/api/v4/groups/runatlantis%2Fsecurity/members/all/123":
+				w.Write([]byte(`{"id": 123, "username": "lkysow", "state": "active"}`)) // nolint: errcheck
+			case "/api/v4/groups/runatlantis%2Fpending/members/all/123":
+				w.Write([]byte(`{"id": 123, "username": "lkysow", "state": "awaiting"}`)) // nolint: errcheck
+			case "/api/v4/groups/runatlantis%2Fnetwork/members/all/123":
+				http.Error(w, `{"message": "404 Not found"}`, http.StatusNotFound)
+			case "/api/v4/":
+				// Rate limiter requests.
+				w.WriteHeader(http.StatusOK)
+			default:
+				t.Errorf("got unexpected request at %q", r.RequestURI)
+				http.Error(w, "not found", http.StatusNotFound)
+			}
+		}))
+	defer testServer.Close()
+
+	internalClient, err := gitlab.NewClient("token", gitlab.WithBaseURL(testServer.URL))
+	Ok(t, err)
+	client := &GitlabClient{
+		Client:           internalClient,
+		ConfiguredGroups: []string{"runatlantis/security", "runatlantis/pending", "runatlantis/network"},
+	}
+	repo := models.Repo{
+		FullName: "runatlantis/atlantis",
+		Owner:    "runatlantis",
+		Name:     "atlantis",
+	}
+
+	groups, err := client.GetTeamNamesForUser(repo, models.User{Username: "lkysow"})
+	Ok(t, err)
+	Equals(t, []string{"runatlantis/security"}, groups)
+
+	t.Log("no requests are made without configured groups")
+	client.ConfiguredGroups = nil
+	groups, err = client.GetTeamNamesForUser(repo, models.User{Username: "lkysow"})
+	Ok(t, err)
+	Equals(t, 0, len(groups))
+}
+
 func TestGitlabClient_MarkdownPullLink(t *testing.T) {
 	gitlabClientUnderTest = true
 	defer func() { gitlabClientUnderTest = false }()
@@ -0,0 +1,52 @@
+package webhooks
+
+import (
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+// asyncQueueSize is how many webhooks can wait to be sent before new ones
+// are dropped.
+const asyncQueueSize = 1000
+
+// AsyncSender sends webhooks with Sender in the background so that slow
+// receivers don't hold up the locks and commands the webhooks are sent for.
+// Webhooks are sent one at a time in the order Send is called.
+type AsyncSender struct {
+	Sender Sender
+	queue  chan asyncWebhook
+}
+
+type asyncWebhook struct {
+	log    logging.SimpleLogging
+	result EventResult
+}
+
+// NewAsyncSender returns an AsyncSender that sends webhooks with sender
+// until the process exits.
+func NewAsyncSender(sender Sender) *AsyncSender {
+	a := &AsyncSender{
+		Sender: sender,
+		queue:  make(chan asyncWebhook, asyncQueueSize),
+	}
+	go a.run()
+	return a
+}
+
+// Send queues the webhook to be sent. It never blocks: if too many webhooks
+// are waiting to be sent, the webhook is dropped with a warning.
+func (a *AsyncSender) Send(log logging.SimpleLogging, result EventResult) error {
+	select {
+	case a.queue <- asyncWebhook{log: log, result: result}:
+	default:
+		log.Warn("dropping %s webhook since %d webhooks are waiting to be sent", result.Event, asyncQueueSize)
+	}
+	return nil
+}
+
+func (a *AsyncSender) run() {
+	for w := range a.queue {
+		if err := a.Sender.Send(w.log, w.result); err != nil {
+			w.log.Warn("error sending webhook: %s", err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package webhooks_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/events/webhooks"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+// blockingSender records the events it's sent once unblock is closed.
+type blockingSender struct {
+	unblock chan struct{}
+	sent    chan string
+}
+
+func (b *blockingSender) Send(_ logging.SimpleLogging, result webhooks.EventResult) error {
+	<-b.unblock
+	b.sent <- result.Event
+	return nil
+}
+
+func TestAsyncSender_DoesNotBlock(t *testing.T) {
+	sender := &blockingSender{unblock: make(chan struct{}), sent: make(chan string, 2)}
+	async := webhooks.NewAsyncSender(sender)
+	logger := logging.NewNoopLogger(t)
+
+	// Send returns even though the receiver hasn't responded.
+	Ok(t, async.Send(logger, webhooks.EventResult{Event: webhooks.PlanEvent}))
+	Ok(t, async.Send(logger, webhooks.EventResult{Event: webhooks.LockReleasedEvent}))
+
+	close(sender.unblock)
+	for _, exp := range []string{webhooks.PlanEvent, webhooks.LockReleasedEvent} {
+		select {
+		case event := <-sender.sent:
+			Equals(t, exp, event)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s webhook", exp)
+		}
+	}
+}
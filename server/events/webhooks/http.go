@@ -0,0 +1,129 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+const (
+	// SignatureHeader is the header that contains the HMAC-SHA256 signature
+	// of the body, hex encoded and prefixed with "sha256=". It's only set if
+	// the webhook has a secret.
+	SignatureHeader = "X-Atlantis-Signature"
+	// EventHeader is the header that contains the event the webhook is for.
+	EventHeader = "X-Atlantis-Event"
+
+	httpTimeout = 10 * time.Second
+)
+
+// HTTPWebhook POSTs a JSON payload describing the event to a URL.
+type HTTPWebhook struct {
+	Client         *http.Client
+	Event          string
+	WorkspaceRegex *regexp.Regexp
+	RepoRegex      *regexp.Regexp
+	URL            string
+	// Secret is used to sign the payload. If empty, the payload isn't signed.
+	Secret string
+}
+
+// HTTPPayload is the JSON body sent by HTTPWebhook.
+type HTTPPayload struct {
+	Event           string  `json:"event"`
+	Repo            string  `json:"repo"`
+	VCSHostname     string  `json:"vcs_hostname"`
+	PullNum         int     `json:"pull_num"`
+	PullURL         string  `json:"pull_url"`
+	PullAuthor      string  `json:"pull_author"`
+	HeadCommit      string  `json:"head_commit"`
+	User            string  `json:"user"`
+	ProjectName     string  `json:"project_name"`
+	Directory       string  `json:"directory"`
+	Workspace       string  `json:"workspace"`
+	Status          string  `json:"status"`
+	Success         bool    `json:"success"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	JobURL          string  `json:"job_url"`
+}
+
+func NewHTTP(event string, workspaceRegex *regexp.Regexp, repoRegex *regexp.Regexp, url string, secret string) *HTTPWebhook {
+	return &HTTPWebhook{
+		Client:         &http.Client{Timeout: httpTimeout},
+		Event:          event,
+		WorkspaceRegex: workspaceRegex,
+		RepoRegex:      repoRegex,
+		URL:            url,
+		Secret:         secret,
+	}
+}
+
+// Send POSTs the webhook if the event matches and the workspace and repo
+// match the regexes.
+func (h *HTTPWebhook) Send(log logging.SimpleLogging, result EventResult) error {
+	if result.Event != h.Event || !h.WorkspaceRegex.MatchString(result.Workspace) || !h.RepoRegex.MatchString(result.Repo.FullName) {
+		return nil
+	}
+
+	// The payload is built field by field rather than serializing the result
+	// because the repo's clone URL contains the VCS credentials.
+	body, err := json.Marshal(HTTPPayload{
+		Event:           result.Event,
+		Repo:            result.Repo.FullName,
+		VCSHostname:     result.Repo.VCSHost.Hostname,
+		PullNum:         result.Pull.Num,
+		PullURL:         result.Pull.URL,
+		PullAuthor:      result.Pull.Author,
+		HeadCommit:      result.Pull.HeadCommit,
+		User:            result.User.Username,
+		ProjectName:     result.ProjectName,
+		Directory:       result.Directory,
+		Workspace:       result.Workspace,
+		Status:          result.Status,
+		Success:         result.Success,
+		DurationSeconds: result.Duration.Seconds(),
+		JobURL:          result.JobURL,
+	})
+	if err != nil {
+		return errors.Wrap(err, "serializing payload")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "creating request to %s", h.URL)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, result.Event)
+	if h.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign([]byte(h.Secret), body))
+	}
+
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "sending webhook to %s", h.URL)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body) // nolint: errcheck
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sending webhook to %s: got status code %d", h.URL, resp.StatusCode)
+	}
+	log.Debug("sent %s webhook to %s", result.Event, h.URL)
+	return nil
+}
+
+// Sign returns the value of SignatureHeader for body.
+func Sign(secret []byte, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body) // nolint: errcheck
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -9,26 +9,26 @@ import (
 	webhooks "github.com/runatlantis/atlantis/server/events/webhooks"
 )
 
-func AnyWebhooksApplyResult() webhooks.ApplyResult {
-	pegomock.RegisterMatcher(pegomock.NewAnyMatcher(reflect.TypeOf((*(webhooks.ApplyResult))(nil)).Elem()))
-	var nullValue webhooks.ApplyResult
+func AnyWebhooksEventResult() webhooks.EventResult {
+	pegomock.RegisterMatcher(pegomock.NewAnyMatcher(reflect.TypeOf((*(webhooks.EventResult))(nil)).Elem()))
+	var nullValue webhooks.EventResult
 	return nullValue
 }
 
-func EqWebhooksApplyResult(value webhooks.ApplyResult) webhooks.ApplyResult {
+func EqWebhooksEventResult(value webhooks.EventResult) webhooks.EventResult {
 	pegomock.RegisterMatcher(&pegomock.EqMatcher{Value: value})
-	var nullValue webhooks.ApplyResult
+	var nullValue webhooks.EventResult
 	return nullValue
 }
 
-func NotEqWebhooksApplyResult(value webhooks.ApplyResult) webhooks.ApplyResult {
+func NotEqWebhooksEventResult(value webhooks.EventResult) webhooks.EventResult {
 	pegomock.RegisterMatcher(&pegomock.NotEqMatcher{Value: value})
-	var nullValue webhooks.ApplyResult
+	var nullValue webhooks.EventResult
 	return nullValue
 }
 
-func WebhooksApplyResultThat(matcher pegomock.ArgumentMatcher) webhooks.ApplyResult {
+func WebhooksEventResultThat(matcher pegomock.ArgumentMatcher) webhooks.EventResult {
 	pegomock.RegisterMatcher(matcher)
-	var nullValue webhooks.ApplyResult
+	var nullValue webhooks.EventResult
 	return nullValue
 }
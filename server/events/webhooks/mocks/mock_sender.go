@@ -27,11 +27,11 @@ func NewMockSender(options ...pegomock.Option) *MockSender {
 func (mock *MockSender) SetFailHandler(fh pegomock.FailHandler) { mock.fail = fh }
 func (mock *MockSender) FailHandler() pegomock.FailHandler      { return mock.fail }
 
-func (mock *MockSender) Send(log logging.SimpleLogging, applyResult webhooks.ApplyResult) error {
+func (mock *MockSender) Send(log logging.SimpleLogging, eventResult webhooks.EventResult) error {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockSender().")
 	}
-	params := []pegomock.Param{log, applyResult}
+	params := []pegomock.Param{log, eventResult}
 	result := pegomock.GetGenericMockFrom(mock).Invoke("Send", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
 	var ret0 error
 	if len(result) != 0 {
@@ -79,8 +79,8 @@ type VerifierMockSender struct {
 	timeout                time.Duration
 }
 
-func (verifier *VerifierMockSender) Send(log logging.SimpleLogging, applyResult webhooks.ApplyResult) *MockSender_Send_OngoingVerification {
-	params := []pegomock.Param{log, applyResult}
+func (verifier *VerifierMockSender) Send(log logging.SimpleLogging, eventResult webhooks.EventResult) *MockSender_Send_OngoingVerification {
+	params := []pegomock.Param{log, eventResult}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "Send", params, verifier.timeout)
 	return &MockSender_Send_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
 }
@@ -90,21 +90,21 @@ type MockSender_Send_OngoingVerification struct {
 	methodInvocations []pegomock.MethodInvocation
 }
 
-func (c *MockSender_Send_OngoingVerification) GetCapturedArguments() (logging.SimpleLogging, webhooks.ApplyResult) {
-	log, applyResult := c.GetAllCapturedArguments()
-	return log[len(log)-1], applyResult[len(applyResult)-1]
+func (c *MockSender_Send_OngoingVerification) GetCapturedArguments() (logging.SimpleLogging, webhooks.EventResult) {
+	log, eventResult := c.GetAllCapturedArguments()
+	return log[len(log)-1], eventResult[len(eventResult)-1]
 }
 
-func (c *MockSender_Send_OngoingVerification) GetAllCapturedArguments() (_param0 []logging.SimpleLogging, _param1 []webhooks.ApplyResult) {
+func (c *MockSender_Send_OngoingVerification) GetAllCapturedArguments() (_param0 []logging.SimpleLogging, _param1 []webhooks.EventResult) {
 	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
 	if len(params) > 0 {
 		_param0 = make([]logging.SimpleLogging, len(c.methodInvocations))
 		for u, param := range params[0] {
 			_param0[u] = param.(logging.SimpleLogging)
 		}
-		_param1 = make([]webhooks.ApplyResult, len(c.methodInvocations))
+		_param1 = make([]webhooks.EventResult, len(c.methodInvocations))
 		for u, param := range params[1] {
-			_param1[u] = param.(webhooks.ApplyResult)
+			_param1[u] = param.(webhooks.EventResult)
 		}
 	}
 	return
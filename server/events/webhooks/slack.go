@@ -26,7 +26,10 @@ import (
 type SlackWebhook struct {
 	Client         SlackClient
 	WorkspaceRegex *regexp.Regexp
-	Channel        string
+	// RepoRegex is matched against the repo's full name. If nil, all repos
+	// match.
+	RepoRegex *regexp.Regexp
+	Channel   string
 }
 
 func NewSlack(r *regexp.Regexp, channel string, client SlackClient) (*SlackWebhook, error) {
@@ -49,10 +52,14 @@ func NewSlack(r *regexp.Regexp, channel string, client SlackClient) (*SlackWebho
 	}, nil
 }
 
-// Send sends the webhook to Slack if the workspace matches the regex.
-func (s *SlackWebhook) Send(log logging.SimpleLogging, applyResult ApplyResult) error {
-	if !s.WorkspaceRegex.MatchString(applyResult.Workspace) {
+// Send sends the webhook to Slack if the result is for an apply and the
+// workspace and repo match the regexes.
+func (s *SlackWebhook) Send(log logging.SimpleLogging, result EventResult) error {
+	if result.Event != ApplyEvent || !s.WorkspaceRegex.MatchString(result.Workspace) {
 		return nil
 	}
-	return s.Client.PostMessage(s.Channel, applyResult)
+	if s.RepoRegex != nil && !s.RepoRegex.MatchString(result.Repo.FullName) {
+		return nil
+	}
+	return s.Client.PostMessage(s.Channel, result)
 }
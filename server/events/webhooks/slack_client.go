@@ -31,7 +31,7 @@ type SlackClient interface {
 	AuthTest() error
 	TokenIsSet() bool
 	ChannelExists(channelName string) (bool, error)
-	PostMessage(channel string, applyResult ApplyResult) error
+	PostMessage(channel string, result EventResult) error
 }
 
 //go:generate pegomock generate -m --use-experimental-model-gen --package mocks -o mocks/mock_underlying_slack_client.go UnderlyingSlackClient
@@ -90,19 +90,19 @@ func (d *DefaultSlackClient) ChannelExists(channelName string) (bool, error) {
 	return false, nil
 }
 
-func (d *DefaultSlackClient) PostMessage(channel string, applyResult ApplyResult) error {
+func (d *DefaultSlackClient) PostMessage(channel string, result EventResult) error {
 	params := slack.NewPostMessageParameters()
-	params.Attachments = d.createAttachments(applyResult)
+	params.Attachments = d.createAttachments(result)
 	params.AsUser = true
 	params.EscapeText = false
 	_, _, err := d.Slack.PostMessage(channel, "", params)
 	return err
 }
 
-func (d *DefaultSlackClient) createAttachments(applyResult ApplyResult) []slack.Attachment {
+func (d *DefaultSlackClient) createAttachments(result EventResult) []slack.Attachment {
 	var colour string
 	var successWord string
-	if applyResult.Success {
+	if result.Success {
 		colour = slackSuccessColour
 		successWord = "succeeded"
 	} else {
@@ -110,8 +110,8 @@ func (d *DefaultSlackClient) createAttachments(applyResult ApplyResult) []slack.
 		successWord = "failed"
 	}
 
-	text := fmt.Sprintf("Apply %s for <%s|%s>", successWord, applyResult.Pull.URL, applyResult.Repo.FullName)
-	directory := applyResult.Directory
+	text := fmt.Sprintf("Apply %s for <%s|%s>", successWord, result.Pull.URL, result.Repo.FullName)
+	directory := result.Directory
 	// Since "." looks weird, replace it with "/" to make it clear this is the root.
 	if directory == "." {
 		directory = "/"
@@ -123,12 +123,12 @@ func (d *DefaultSlackClient) createAttachments(applyResult ApplyResult) []slack.
 		Fields: []slack.AttachmentField{
 			{
 				Title: "Workspace",
-				Value: applyResult.Workspace,
+				Value: result.Workspace,
 				Short: true,
 			},
 			{
 				Title: "User",
-				Value: applyResult.User.Username,
+				Value: result.User.Username,
 				Short: true,
 			},
 			{
@@ -37,7 +37,8 @@ func TestSend_PostMessage(t *testing.T) {
 		WorkspaceRegex: regex,
 		Channel:        channel,
 	}
-	result := webhooks.ApplyResult{
+	result := webhooks.EventResult{
+		Event:     webhooks.ApplyEvent,
 		Workspace: "production",
 	}
 
@@ -59,10 +60,55 @@ func TestSend_NoopSuccess(t *testing.T) {
 		WorkspaceRegex: regex,
 		Channel:        channel,
 	}
-	result := webhooks.ApplyResult{
+	result := webhooks.EventResult{
+		Event:     webhooks.ApplyEvent,
 		Workspace: "production",
 	}
 	err = hook.Send(logging.NewNoopLogger(t), result)
 	Ok(t, err)
 	client.VerifyWasCalled(Never()).PostMessage(channel, result)
 }
+
+func TestSend_NoopOtherEvents(t *testing.T) {
+	t.Log("Sending a hook for an event other than apply should succeed without posting")
+	RegisterMockTestingT(t)
+	client := mocks.NewMockSlackClient()
+	regex, err := regexp.Compile(".*")
+	Ok(t, err)
+
+	channel := "somechannel"
+	hook := webhooks.SlackWebhook{
+		Client:         client,
+		WorkspaceRegex: regex,
+		Channel:        channel,
+	}
+	result := webhooks.EventResult{
+		Event:     webhooks.PlanEvent,
+		Workspace: "production",
+	}
+	err = hook.Send(logging.NewNoopLogger(t), result)
+	Ok(t, err)
+	client.VerifyWasCalled(Never()).PostMessage(channel, result)
+}
+
+func TestSend_NoopNonMatchingRepo(t *testing.T) {
+	t.Log("Sending a hook with a non-matching repo regex should succeed without posting")
+	RegisterMockTestingT(t)
+	client := mocks.NewMockSlackClient()
+
+	channel := "somechannel"
+	hook := webhooks.SlackWebhook{
+		Client:         client,
+		WorkspaceRegex: regexp.MustCompile(".*"),
+		RepoRegex:      regexp.MustCompile("^runatlantis/"),
+		Channel:        channel,
+	}
+	result := webhooks.EventResult{
+		Event:     webhooks.ApplyEvent,
+		Workspace: "production",
+	}
+	result.Repo.FullName = "owner/repo"
+	err := hook.Send(logging.NewNoopLogger(t), result)
+	Ok(t, err)
+	client.VerifyWasCalled(Never()).PostMessage(channel, result)
+}
@@ -16,6 +16,8 @@ package webhooks
 import (
 	"fmt"
 	"regexp"
+	"strings"
+	"time"
 
 	"errors"
 
@@ -24,24 +26,57 @@ import (
 )
 
 const SlackKind = "slack"
+const HTTPKind = "http"
+
 const ApplyEvent = "apply"
+const PlanEvent = "plan"
+const PolicyCheckEvent = "policy_check"
+const LockAcquiredEvent = "lock_acquired"
+const LockReleasedEvent = "lock_released"
+const PullClosedEvent = "pull_closed"
+const DriftDetectedEvent = "drift_detected"
+
+// Events are all the events webhooks can be sent for.
+var Events = []string{PlanEvent, PolicyCheckEvent, ApplyEvent, LockAcquiredEvent, LockReleasedEvent, PullClosedEvent, DriftDetectedEvent}
+
+// Statuses of the command an EventResult is for.
+const (
+	SuccessStatus = "success"
+	ErrorStatus   = "error"
+	// DriftedStatus is the status of drift_detected events.
+	DriftedStatus = "drifted"
+)
 
 //go:generate pegomock generate -m --use-experimental-model-gen --package mocks -o mocks/mock_sender.go Sender
 
 // Sender sends webhooks.
 type Sender interface {
 	// Send sends the webhook (if the implementation thinks it should).
-	Send(log logging.SimpleLogging, applyResult ApplyResult) error
+	Send(log logging.SimpleLogging, result EventResult) error
 }
 
-// ApplyResult is the result of a terraform apply.
-type ApplyResult struct {
+// EventResult describes an event webhooks are sent for, ex. the result of a
+// terraform apply or a lock being released.
+type EventResult struct {
+	// Event is the event this result is for, ex. apply.
+	Event     string
 	Workspace string
 	Repo      models.Repo
 	Pull      models.PullRequest
 	User      models.User
 	Success   bool
 	Directory string
+	// ProjectName is the name of the project from atlantis.yaml. It's empty
+	// if the project isn't named.
+	ProjectName string
+	// Status is one of SuccessStatus, ErrorStatus or DriftedStatus.
+	Status string
+	// Duration is how long the command took. It's zero for events that
+	// aren't commands.
+	Duration time.Duration
+	// JobURL is the url to view the output of the command. It's empty for
+	// events that aren't commands.
+	JobURL string
 }
 
 // MultiWebhookSender sends multiple webhooks for each one it's configured for.
@@ -52,8 +87,11 @@ type MultiWebhookSender struct {
 type Config struct {
 	Event          string
 	WorkspaceRegex string
+	RepoRegex      string
 	Kind           string
 	Channel        string
+	URL            string
+	Secret         string
 }
 
 func NewMultiWebhookSender(configs []Config, client SlackClient) (*MultiWebhookSender, error) {
@@ -63,14 +101,21 @@ func NewMultiWebhookSender(configs []Config, client SlackClient) (*MultiWebhookS
 		if err != nil {
 			return nil, err
 		}
+		repoRegex, err := regexp.Compile(c.RepoRegex)
+		if err != nil {
+			return nil, err
+		}
 		if c.Kind == "" || c.Event == "" {
 			return nil, errors.New("must specify \"kind\" and \"event\" keys for webhooks")
 		}
-		if c.Event != ApplyEvent {
-			return nil, fmt.Errorf("\"event: %s\" not supported. Only \"event: %s\" is supported right now", c.Event, ApplyEvent)
+		if !isSupportedEvent(c.Event) {
+			return nil, fmt.Errorf("\"event: %s\" not supported. Supported events are: %s", c.Event, strings.Join(Events, ", "))
 		}
 		switch c.Kind {
 		case SlackKind:
+			if c.Event != ApplyEvent {
+				return nil, fmt.Errorf("\"event: %s\" not supported for \"kind: %s\". Only \"event: %s\" is supported right now", c.Event, SlackKind, ApplyEvent)
+			}
 			if !client.TokenIsSet() {
 				return nil, errors.New("must specify top-level \"slack-token\" if using a webhook of \"kind: slack\"")
 			}
@@ -81,9 +126,15 @@ func NewMultiWebhookSender(configs []Config, client SlackClient) (*MultiWebhookS
 			if err != nil {
 				return nil, err
 			}
+			slack.RepoRegex = repoRegex
 			webhooks = append(webhooks, slack)
+		case HTTPKind:
+			if c.URL == "" {
+				return nil, errors.New("must specify \"url\" if using a webhook of \"kind: http\"")
+			}
+			webhooks = append(webhooks, NewHTTP(c.Event, r, repoRegex, c.URL, c.Secret))
 		default:
-			return nil, fmt.Errorf("\"kind: %s\" not supported. Only \"kind: %s\" is supported right now", c.Kind, SlackKind)
+			return nil, fmt.Errorf("\"kind: %s\" not supported. Only \"kind: %s\" and \"kind: %s\" are supported right now", c.Kind, SlackKind, HTTPKind)
 		}
 	}
 
@@ -92,11 +143,20 @@ func NewMultiWebhookSender(configs []Config, client SlackClient) (*MultiWebhookS
 	}, nil
 }
 
+func isSupportedEvent(event string) bool {
+	for _, e := range Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
 // Send sends the webhook using its Webhooks.
-func (w *MultiWebhookSender) Send(log logging.SimpleLogging, result ApplyResult) error {
+func (w *MultiWebhookSender) Send(log logging.SimpleLogging, result EventResult) error {
 	for _, w := range w.Webhooks {
 		if err := w.Send(log, result); err != nil {
-			log.Warn("error sending slack webhook: %s", err)
+			log.Warn("error sending webhook: %s", err)
 		}
 	}
 	return nil
@@ -78,7 +78,21 @@ func TestNewWebhooksManager_UnsupportedEvent(t *testing.T) {
 	configs[0].Event = unsupportedEvent
 	_, err := webhooks.NewMultiWebhookSender(configs, client)
 	Assert(t, err != nil, "expected error")
-	Equals(t, "\"event: badevent\" not supported. Only \"event: apply\" is supported right now", err.Error())
+	Equals(t, "\"event: badevent\" not supported. Supported events are: plan, policy_check, apply, lock_acquired, lock_released, pull_closed, drift_detected", err.Error())
+}
+
+func TestNewWebhooksManager_UnsupportedSlackEvent(t *testing.T) {
+	t.Log("When given an event other than apply in a slack config, an error is returned")
+	RegisterMockTestingT(t)
+	client := mocks.NewMockSlackClient()
+	When(client.TokenIsSet()).ThenReturn(true)
+	When(client.ChannelExists(validChannel)).ThenReturn(true, nil)
+
+	configs := validConfigs()
+	configs[0].Event = webhooks.PlanEvent
+	_, err := webhooks.NewMultiWebhookSender(configs, client)
+	Assert(t, err != nil, "expected error")
+	Equals(t, "\"event: plan\" not supported for \"kind: slack\". Only \"event: apply\" is supported right now", err.Error())
 }
 
 func TestNewWebhooksManager_NoKind(t *testing.T) {
@@ -103,7 +117,50 @@ func TestNewWebhooksManager_UnsupportedKind(t *testing.T) {
 	configs[0].Kind = unsupportedKind
 	_, err := webhooks.NewMultiWebhookSender(configs, client)
 	Assert(t, err != nil, "expected error")
-	Equals(t, "\"kind: badkind\" not supported. Only \"kind: slack\" is supported right now", err.Error())
+	Equals(t, "\"kind: badkind\" not supported. Only \"kind: slack\" and \"kind: http\" are supported right now", err.Error())
+}
+
+func TestNewWebhooksManager_InvalidRepoRegex(t *testing.T) {
+	t.Log("When given an invalid repo regex in a config, an error is returned")
+	RegisterMockTestingT(t)
+	client := mocks.NewMockSlackClient()
+
+	configs := validConfigs()
+	configs[0].RepoRegex = "("
+	_, err := webhooks.NewMultiWebhookSender(configs, client)
+	Assert(t, err != nil, "expected error")
+	Assert(t, strings.Contains(err.Error(), "error parsing regexp"), "expected regex error")
+}
+
+func TestNewWebhooksManager_HTTPNoURL(t *testing.T) {
+	t.Log("When the url key is not specified in an http config, an error is returned")
+	RegisterMockTestingT(t)
+	client := mocks.NewMockSlackClient()
+
+	configs := []webhooks.Config{{
+		Event: webhooks.PlanEvent,
+		Kind:  webhooks.HTTPKind,
+	}}
+	_, err := webhooks.NewMultiWebhookSender(configs, client)
+	Assert(t, err != nil, "expected error")
+	Equals(t, "must specify \"url\" if using a webhook of \"kind: http\"", err.Error())
+}
+
+func TestNewWebhooksManager_HTTPSuccess(t *testing.T) {
+	t.Log("When there is a valid http config, function should succeed without a slack token")
+	RegisterMockTestingT(t)
+	client := mocks.NewMockSlackClient()
+
+	configs := []webhooks.Config{{
+		Event:     webhooks.LockReleasedEvent,
+		Kind:      webhooks.HTTPKind,
+		URL:       "https://example.com/hook",
+		RepoRegex: "^runatlantis/",
+	}}
+	m, err := webhooks.NewMultiWebhookSender(configs, client)
+	Ok(t, err)
+	Equals(t, 1, len(m.Webhooks)) // nolint: staticcheck
+	client.VerifyWasCalled(Never()).TokenIsSet()
 }
 
 func TestNewWebhooksManager_NoConfigSuccess(t *testing.T) {
@@ -158,7 +215,7 @@ func TestSend_SingleSuccess(t *testing.T) {
 		Webhooks: []webhooks.Sender{sender},
 	}
 	logger := logging.NewNoopLogger(t)
-	result := webhooks.ApplyResult{}
+	result := webhooks.EventResult{}
 	manager.Send(logger, result) // nolint: errcheck
 	sender.VerifyWasCalledOnce().Send(logger, result)
 }
@@ -175,7 +232,7 @@ func TestSend_MultipleSuccess(t *testing.T) {
 		Webhooks: []webhooks.Sender{senders[0], senders[1], senders[2]},
 	}
 	logger := logging.NewNoopLogger(t)
-	result := webhooks.ApplyResult{}
+	result := webhooks.EventResult{}
 	err := manager.Send(logger, result)
 	Ok(t, err)
 	for _, s := range senders {
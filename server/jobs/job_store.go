@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// StoredJob is a completed job and its output.
+type StoredJob struct {
+	Job
+	Output      []string
+	CompletedAt time.Time
+}
+
+//go:generate pegomock generate -m --use-experimental-model-gen --package mocks -o mocks/mock_job_store.go JobStore
+
+// JobStore persists the output of completed jobs so the job urls in pull
+// request comments keep working after Atlantis restarts.
+type JobStore interface {
+	// Write stores job, replacing any stored job with the same ID.
+	Write(job StoredJob) error
+	// Get returns the job with jobID or nil if it isn't stored.
+	Get(jobID string) (*StoredJob, error)
+	// DeleteCompletedBefore deletes the jobs that completed before t and
+	// returns how many were deleted.
+	DeleteCompletedBefore(t time.Time) (int, error)
+}
+
+// jobIDRegex matches valid job IDs. Job IDs are UUIDs but anything that
+// can't escape the store's directory or prefix is allowed.
+var jobIDRegex = regexp.MustCompile(`^[a-zA-Z0-9-]+$`)
+
+func validateJobID(jobID string) error {
+	if !jobIDRegex.MatchString(jobID) {
+		return fmt.Errorf("invalid job id %q", jobID)
+	}
+	return nil
+}
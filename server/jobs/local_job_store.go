@@ -0,0 +1,104 @@
+package jobs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const storedJobExt = ".json"
+
+// LocalJobStore stores each job as a JSON file in a directory on disk.
+type LocalJobStore struct {
+	dir string
+}
+
+// NewLocalJobStore returns a LocalJobStore that stores jobs in dir, creating
+// it if it doesn't exist.
+func NewLocalJobStore(dir string) (*LocalJobStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "creating job store dir %q", dir)
+	}
+	return &LocalJobStore{dir: dir}, nil
+}
+
+func (l *LocalJobStore) Write(job StoredJob) error {
+	if err := validateJobID(job.JobID); err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(job)
+	if err != nil {
+		return errors.Wrap(err, "serializing job")
+	}
+	// Write to a temporary file first so a partially written job is never
+	// read.
+	tmp, err := os.CreateTemp(l.dir, job.JobID+"-*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "creating job file")
+	}
+	defer os.Remove(tmp.Name()) // nolint: errcheck
+	if _, err := tmp.Write(bytes); err != nil {
+		tmp.Close() // nolint: errcheck
+		return errors.Wrapf(err, "writing job %s", job.JobID)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrapf(err, "writing job %s", job.JobID)
+	}
+	return errors.Wrapf(os.Rename(tmp.Name(), l.path(job.JobID)), "writing job %s", job.JobID)
+}
+
+func (l *LocalJobStore) Get(jobID string) (*StoredJob, error) {
+	if err := validateJobID(jobID); err != nil {
+		return nil, err
+	}
+	bytes, err := os.ReadFile(l.path(jobID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading job %s", jobID)
+	}
+	var job StoredJob
+	if err := json.Unmarshal(bytes, &job); err != nil {
+		return nil, errors.Wrapf(err, "deserializing job %s", jobID)
+	}
+	return &job, nil
+}
+
+// DeleteCompletedBefore uses the modification times of the job files since
+// jobs are written when they complete.
+func (l *LocalJobStore) DeleteCompletedBefore(t time.Time) (int, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return 0, errors.Wrap(err, "listing jobs")
+	}
+	deleted := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), storedJobExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return deleted, errors.Wrapf(err, "reading %s", entry.Name())
+		}
+		if !info.ModTime().Before(t) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(l.dir, entry.Name())); err != nil && !os.IsNotExist(err) {
+			return deleted, errors.Wrapf(err, "deleting %s", entry.Name())
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+func (l *LocalJobStore) path(jobID string) string {
+	return filepath.Join(l.dir, jobID+storedJobExt)
+}
@@ -0,0 +1,57 @@
+package jobs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/jobs"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestLocalJobStore(t *testing.T) {
+	tmp, cleanup := TempDir(t)
+	defer cleanup()
+	store, err := jobs.NewLocalJobStore(filepath.Join(tmp, "jobs"))
+	Ok(t, err)
+
+	job, err := store.Get("1234")
+	Ok(t, err)
+	Assert(t, job == nil, "exp no job")
+
+	stored := jobs.StoredJob{
+		Job: jobs.Job{
+			JobID: "1234",
+			JobInfo: jobs.JobInfo{
+				PullInfo:   jobs.PullInfo{PullNum: 1, Repo: "repo", ProjectName: "project", Workspace: "default"},
+				HeadCommit: "abc",
+			},
+			OperationComplete: true,
+		},
+		Output:      []string{"line 1", "line 2"},
+		CompletedAt: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	Ok(t, store.Write(stored))
+	job, err = store.Get("1234")
+	Ok(t, err)
+	Equals(t, stored, *job)
+
+	_, err = store.Get("../1234")
+	ErrEquals(t, `invalid job id "../1234"`, err)
+
+	// Only jobs that completed before the cutoff are deleted.
+	stored.JobID = "5678"
+	Ok(t, store.Write(stored))
+	old := time.Now().Add(-48 * time.Hour)
+	Ok(t, os.Chtimes(filepath.Join(tmp, "jobs", "1234.json"), old, old))
+	deleted, err := store.DeleteCompletedBefore(time.Now().Add(-24 * time.Hour))
+	Ok(t, err)
+	Equals(t, 1, deleted)
+	job, err = store.Get("1234")
+	Ok(t, err)
+	Assert(t, job == nil, "exp job to be deleted")
+	job, err = store.Get("5678")
+	Ok(t, err)
+	Assert(t, job != nil, "exp job to be kept")
+}
@@ -0,0 +1,198 @@
+// Code generated by pegomock. DO NOT EDIT.
+// Source: github.com/runatlantis/atlantis/server/jobs (interfaces: JobStore)
+
+package mocks
+
+import (
+	"reflect"
+	"time"
+
+	pegomock "github.com/petergtz/pegomock"
+	jobs "github.com/runatlantis/atlantis/server/jobs"
+)
+
+type MockJobStore struct {
+	fail func(message string, callerSkip ...int)
+}
+
+func NewMockJobStore(options ...pegomock.Option) *MockJobStore {
+	mock := &MockJobStore{}
+	for _, option := range options {
+		option.Apply(mock)
+	}
+	return mock
+}
+
+func (mock *MockJobStore) SetFailHandler(fh pegomock.FailHandler) { mock.fail = fh }
+func (mock *MockJobStore) FailHandler() pegomock.FailHandler      { return mock.fail }
+
+func (mock *MockJobStore) Write(_param0 jobs.StoredJob) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockJobStore().")
+	}
+	params := []pegomock.Param{_param0}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("Write", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(error)
+		}
+	}
+	return ret0
+}
+
+func (mock *MockJobStore) Get(_param0 string) (*jobs.StoredJob, error) {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockJobStore().")
+	}
+	params := []pegomock.Param{_param0}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("Get", params, []reflect.Type{reflect.TypeOf((**jobs.StoredJob)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 *jobs.StoredJob
+	var ret1 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(*jobs.StoredJob)
+		}
+		if result[1] != nil {
+			ret1 = result[1].(error)
+		}
+	}
+	return ret0, ret1
+}
+
+func (mock *MockJobStore) DeleteCompletedBefore(_param0 time.Time) (int, error) {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockJobStore().")
+	}
+	params := []pegomock.Param{_param0}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("DeleteCompletedBefore", params, []reflect.Type{reflect.TypeOf((*int)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 int
+	var ret1 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(int)
+		}
+		if result[1] != nil {
+			ret1 = result[1].(error)
+		}
+	}
+	return ret0, ret1
+}
+
+func (mock *MockJobStore) VerifyWasCalledOnce() *VerifierMockJobStore {
+	return &VerifierMockJobStore{
+		mock:                   mock,
+		invocationCountMatcher: pegomock.Times(1),
+	}
+}
+
+func (mock *MockJobStore) VerifyWasCalled(invocationCountMatcher pegomock.InvocationCountMatcher) *VerifierMockJobStore {
+	return &VerifierMockJobStore{
+		mock:                   mock,
+		invocationCountMatcher: invocationCountMatcher,
+	}
+}
+
+func (mock *MockJobStore) VerifyWasCalledInOrder(invocationCountMatcher pegomock.InvocationCountMatcher, inOrderContext *pegomock.InOrderContext) *VerifierMockJobStore {
+	return &VerifierMockJobStore{
+		mock:                   mock,
+		invocationCountMatcher: invocationCountMatcher,
+		inOrderContext:         inOrderContext,
+	}
+}
+
+func (mock *MockJobStore) VerifyWasCalledEventually(invocationCountMatcher pegomock.InvocationCountMatcher, timeout time.Duration) *VerifierMockJobStore {
+	return &VerifierMockJobStore{
+		mock:                   mock,
+		invocationCountMatcher: invocationCountMatcher,
+		timeout:                timeout,
+	}
+}
+
+type VerifierMockJobStore struct {
+	mock                   *MockJobStore
+	invocationCountMatcher pegomock.InvocationCountMatcher
+	inOrderContext         *pegomock.InOrderContext
+	timeout                time.Duration
+}
+
+func (verifier *VerifierMockJobStore) Write(_param0 jobs.StoredJob) *MockJobStore_Write_OngoingVerification {
+	params := []pegomock.Param{_param0}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "Write", params, verifier.timeout)
+	return &MockJobStore_Write_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockJobStore_Write_OngoingVerification struct {
+	mock              *MockJobStore
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockJobStore_Write_OngoingVerification) GetCapturedArguments() jobs.StoredJob {
+	_param0 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1]
+}
+
+func (c *MockJobStore_Write_OngoingVerification) GetAllCapturedArguments() (_param0 []jobs.StoredJob) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]jobs.StoredJob, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(jobs.StoredJob)
+		}
+	}
+	return
+}
+
+func (verifier *VerifierMockJobStore) Get(_param0 string) *MockJobStore_Get_OngoingVerification {
+	params := []pegomock.Param{_param0}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "Get", params, verifier.timeout)
+	return &MockJobStore_Get_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockJobStore_Get_OngoingVerification struct {
+	mock              *MockJobStore
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockJobStore_Get_OngoingVerification) GetCapturedArguments() string {
+	_param0 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1]
+}
+
+func (c *MockJobStore_Get_OngoingVerification) GetAllCapturedArguments() (_param0 []string) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]string, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(string)
+		}
+	}
+	return
+}
+
+func (verifier *VerifierMockJobStore) DeleteCompletedBefore(_param0 time.Time) *MockJobStore_DeleteCompletedBefore_OngoingVerification {
+	params := []pegomock.Param{_param0}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "DeleteCompletedBefore", params, verifier.timeout)
+	return &MockJobStore_DeleteCompletedBefore_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockJobStore_DeleteCompletedBefore_OngoingVerification struct {
+	mock              *MockJobStore
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockJobStore_DeleteCompletedBefore_OngoingVerification) GetCapturedArguments() time.Time {
+	_param0 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1]
+}
+
+func (c *MockJobStore_DeleteCompletedBefore_OngoingVerification) GetAllCapturedArguments() (_param0 []time.Time) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]time.Time, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(time.Time)
+		}
+	}
+	return
+}
@@ -82,6 +82,21 @@ func (mock *MockProjectCommandOutputHandler) Send(_param0 command.ProjectContext
 	pegomock.GetGenericMockFrom(mock).Invoke("Send", params, []reflect.Type{})
 }
 
+func (mock *MockProjectCommandOutputHandler) ListJobs() []jobs.Job {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockProjectCommandOutputHandler().")
+	}
+	params := []pegomock.Param{}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("ListJobs", params, []reflect.Type{reflect.TypeOf((*[]jobs.Job)(nil)).Elem()})
+	var ret0 []jobs.Job
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].([]jobs.Job)
+		}
+	}
+	return ret0
+}
+
 func (mock *MockProjectCommandOutputHandler) VerifyWasCalledOnce() *VerifierMockProjectCommandOutputHandler {
 	return &VerifierMockProjectCommandOutputHandler{
 		mock:                   mock,
@@ -286,3 +301,14 @@ func (c *MockProjectCommandOutputHandler_Send_OngoingVerification) GetAllCapture
 	}
 	return
 }
+
+func (verifier *VerifierMockProjectCommandOutputHandler) ListJobs() *MockProjectCommandOutputHandler_ListJobs_OngoingVerification {
+	params := []pegomock.Param{}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "ListJobs", params, verifier.timeout)
+	return &MockProjectCommandOutputHandler_ListJobs_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockProjectCommandOutputHandler_ListJobs_OngoingVerification struct {
+	mock              *MockProjectCommandOutputHandler
+	methodInvocations []pegomock.MethodInvocation
+}
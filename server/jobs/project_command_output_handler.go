@@ -2,6 +2,7 @@ package jobs
 
 import (
 	"sync"
+	"time"
 
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/logging"
@@ -24,6 +25,13 @@ type JobInfo struct {
 	HeadCommit string
 }
 
+// Job is a job that the output handler has received output for.
+type Job struct {
+	JobID string
+	JobInfo
+	OperationComplete bool
+}
+
 type ProjectCmdOutputLine struct {
 	JobID             string
 	JobInfo           JobInfo
@@ -46,6 +54,13 @@ type AsyncProjectCommandOutputHandler struct {
 
 	// Tracks all the jobs for a pull request which is used for clean up after a pull request is closed.
 	pullToJobMapping sync.Map
+
+	// Tracks the info of each job so that jobs can be listed.
+	jobInfos sync.Map
+
+	// jobStore persists completed jobs. It's nil if jobs are only kept in
+	// memory.
+	jobStore JobStore
 }
 
 //go:generate pegomock generate -m --use-experimental-model-gen --package mocks -o mocks/mock_project_command_output_handler.go ProjectCommandOutputHandler
@@ -68,11 +83,18 @@ type ProjectCommandOutputHandler interface {
 
 	// Cleans up resources for a pull
 	CleanUp(pullInfo PullInfo)
+
+	// ListJobs returns the jobs that haven't been cleaned up yet.
+	ListJobs() []Job
 }
 
+// NewAsyncProjectCommandOutputHandler returns a handler that keeps the output
+// of jobs in memory until their pull request is closed. If jobStore isn't nil,
+// completed jobs are also written to it.
 func NewAsyncProjectCommandOutputHandler(
 	projectCmdOutput chan *ProjectCmdOutputLine,
 	logger logging.SimpleLogging,
+	jobStore JobStore,
 ) ProjectCommandOutputHandler {
 	return &AsyncProjectCommandOutputHandler{
 		projectCmdOutput:     projectCmdOutput,
@@ -80,6 +102,7 @@ func NewAsyncProjectCommandOutputHandler(
 		receiverBuffers:      map[string]map[chan string]bool{},
 		projectOutputBuffers: map[string]OutputBuffer{},
 		pullToJobMapping:     sync.Map{},
+		jobStore:             jobStore,
 	}
 }
 
@@ -125,6 +148,7 @@ func (p *AsyncProjectCommandOutputHandler) Handle() {
 		value, _ := p.pullToJobMapping.Load(msg.JobInfo.PullInfo)
 		jobMapping := value.(map[string]bool)
 		jobMapping[msg.JobID] = true
+		p.jobInfos.Store(msg.JobID, msg.JobInfo)
 
 		// Forward new message to all receiver channels and output buffer
 		p.writeLogLine(msg.JobID, msg.Line)
@@ -139,8 +163,13 @@ func (p *AsyncProjectCommandOutputHandler) completeJob(jobID string) {
 		p.receiverBuffersLock.Unlock()
 	}()
 
-	// Update operation status to complete
-	if outputBuffer, ok := p.projectOutputBuffers[jobID]; ok {
+	// Update operation status to complete. A cancelled job can be completed
+	// twice, once when its steps stop and once when the command returns.
+	outputBuffer, ok := p.projectOutputBuffers[jobID]
+	if ok && outputBuffer.OperationComplete {
+		return
+	}
+	if ok {
 		outputBuffer.OperationComplete = true
 		p.projectOutputBuffers[jobID] = outputBuffer
 	}
@@ -150,8 +179,34 @@ func (p *AsyncProjectCommandOutputHandler) completeJob(jobID string) {
 		for ch := range openChannels {
 			close(ch)
 		}
+		delete(p.receiverBuffers, jobID)
+	}
+
+	if p.jobStore == nil || !ok {
+		return
+	}
+	info, ok := p.jobInfos.Load(jobID)
+	if !ok {
+		return
 	}
+	// Write the job in the background so slow stores don't hold up the output
+	// of other jobs. The buffer isn't appended to after the job completes so
+	// it's safe to share.
+	go p.storeJob(StoredJob{
+		Job: Job{
+			JobID:             jobID,
+			JobInfo:           info.(JobInfo),
+			OperationComplete: true,
+		},
+		Output:      outputBuffer.Buffer,
+		CompletedAt: time.Now(),
+	})
+}
 
+func (p *AsyncProjectCommandOutputHandler) storeJob(job StoredJob) {
+	if err := p.jobStore.Write(job); err != nil {
+		p.logger.Err("unable to store output of job %s: %s", job.JobID, err)
+	}
 }
 
 func (p *AsyncProjectCommandOutputHandler) addChan(ch chan string, jobID string) {
@@ -239,6 +294,8 @@ func (p *AsyncProjectCommandOutputHandler) CleanUp(pullInfo PullInfo) {
 			p.receiverBuffersLock.Lock()
 			delete(p.receiverBuffers, jobID)
 			p.receiverBuffersLock.Unlock()
+
+			p.jobInfos.Delete(jobID)
 		}
 
 		// Remove job mapping
@@ -246,6 +303,22 @@ func (p *AsyncProjectCommandOutputHandler) CleanUp(pullInfo PullInfo) {
 	}
 }
 
+func (p *AsyncProjectCommandOutputHandler) ListJobs() []Job {
+	var jobs []Job
+	p.projectOutputBuffersLock.RLock()
+	defer p.projectOutputBuffersLock.RUnlock()
+	p.jobInfos.Range(func(key, value interface{}) bool {
+		jobID := key.(string)
+		jobs = append(jobs, Job{
+			JobID:             jobID,
+			JobInfo:           value.(JobInfo),
+			OperationComplete: p.projectOutputBuffers[jobID].OperationComplete,
+		})
+		return true
+	})
+	return jobs
+}
+
 // NoopProjectOutputHandler is a mock that doesn't do anything
 type NoopProjectOutputHandler struct{}
 
@@ -264,3 +337,7 @@ func (p *NoopProjectOutputHandler) CleanUp(pullInfo PullInfo) {
 func (p *NoopProjectOutputHandler) IsKeyExists(key string) bool {
 	return false
 }
+
+func (p *NoopProjectOutputHandler) ListJobs() []Job {
+	return nil
+}
@@ -48,6 +48,7 @@ func createProjectCommandOutputHandler(t *testing.T) jobs.ProjectCommandOutputHa
 	prjCmdOutputHandler := jobs.NewAsyncProjectCommandOutputHandler(
 		prjCmdOutputChan,
 		logger,
+		nil,
 	)
 
 	go func() {
@@ -248,4 +249,102 @@ func TestProjectCommandOutputHandler(t *testing.T) {
 
 		assert.True(t, <-opComplete)
 	})
+
+	t.Run("list jobs until they're cleaned up", func(t *testing.T) {
+		projectOutputHandler := createProjectCommandOutputHandler(t)
+
+		projectOutputHandler.Send(ctx, Msg, false)
+		projectOutputHandler.Send(ctx, "", true)
+
+		// Wait for the handler to process the message
+		time.Sleep(10 * time.Millisecond)
+
+		Equals(t, []jobs.Job{
+			{
+				JobID: ctx.JobID,
+				JobInfo: jobs.JobInfo{
+					HeadCommit: ctx.Pull.HeadCommit,
+					PullInfo: jobs.PullInfo{
+						PullNum:     ctx.Pull.Num,
+						Repo:        ctx.BaseRepo.Name,
+						ProjectName: ctx.ProjectName,
+						Workspace:   ctx.Workspace,
+					},
+				},
+				OperationComplete: true,
+			},
+		}, projectOutputHandler.ListJobs())
+
+		projectOutputHandler.CleanUp(jobs.PullInfo{
+			PullNum:     ctx.Pull.Num,
+			Repo:        ctx.BaseRepo.Name,
+			ProjectName: ctx.ProjectName,
+			Workspace:   ctx.Workspace,
+		})
+		Equals(t, 0, len(projectOutputHandler.ListJobs()))
+	})
+}
+
+func TestProjectCommandOutputHandler_StoresCompletedJobs(t *testing.T) {
+	ctx := createTestProjectCmdContext(t)
+	tmp, cleanup := TempDir(t)
+	defer cleanup()
+	store, err := jobs.NewLocalJobStore(tmp)
+	Ok(t, err)
+	prjCmdOutputChan := make(chan *jobs.ProjectCmdOutputLine)
+	projectOutputHandler := jobs.NewAsyncProjectCommandOutputHandler(prjCmdOutputChan, logging.NewNoopLogger(t), store)
+	go projectOutputHandler.Handle()
+
+	projectOutputHandler.Send(ctx, "line 1", false)
+	projectOutputHandler.Send(ctx, "line 2", false)
+	projectOutputHandler.Send(ctx, "", true)
+
+	// The job is stored in the background.
+	var job *jobs.StoredJob
+	for i := 0; i < 100 && job == nil; i++ {
+		job, err = store.Get(ctx.JobID)
+		Ok(t, err)
+		time.Sleep(10 * time.Millisecond)
+	}
+	Assert(t, job != nil, "exp job to be stored")
+	Equals(t, []string{"line 1", "line 2"}, job.Output)
+	Equals(t, ctx.ProjectName, job.ProjectName)
+	Equals(t, ctx.Pull.Num, job.PullNum)
+	Equals(t, ctx.Pull.HeadCommit, job.HeadCommit)
+	Assert(t, job.OperationComplete, "exp job to be complete")
+
+	// The stored job outlives the in-memory output.
+	projectOutputHandler.CleanUp(jobs.PullInfo{
+		PullNum:     ctx.Pull.Num,
+		Repo:        ctx.BaseRepo.Name,
+		ProjectName: ctx.ProjectName,
+		Workspace:   ctx.Workspace,
+	})
+	Assert(t, !projectOutputHandler.IsKeyExists(ctx.JobID), "exp job to be cleaned up")
+	job, err = store.Get(ctx.JobID)
+	Ok(t, err)
+	Assert(t, job != nil, "exp job to still be stored")
+}
+
+func TestProjectCommandOutputHandler_CompleteTwice(t *testing.T) {
+	ctx := createTestProjectCmdContext(t)
+	prjCmdOutputChan := make(chan *jobs.ProjectCmdOutputLine)
+	projectOutputHandler := jobs.NewAsyncProjectCommandOutputHandler(prjCmdOutputChan, logging.NewNoopLogger(t), nil)
+	go projectOutputHandler.Handle()
+
+	ch := make(chan string, 10)
+	projectOutputHandler.Register(ctx.JobID, ch)
+	projectOutputHandler.Send(ctx, "line 1", false)
+
+	// Completing the job again, ex. after it's cancelled, doesn't close the
+	// receiver channels twice.
+	projectOutputHandler.Send(ctx, "", true)
+	projectOutputHandler.Send(ctx, "", true)
+	projectOutputHandler.Send(ctx, "line 2", false)
+
+	var received []string
+	for line := range ch {
+		received = append(received, line)
+	}
+	Equals(t, []string{"line 1"}, received)
 }
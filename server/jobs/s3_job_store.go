@@ -0,0 +1,147 @@
+package jobs
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/pkg/errors"
+)
+
+// S3JobStoreConfig configures an S3JobStore.
+type S3JobStoreConfig struct {
+	Bucket string
+	// Prefix is prepended to the key of each job, ex. atlantis/jobs.
+	Prefix string
+	// Region is the region of the bucket. If empty, the region is read from
+	// the environment like other AWS tools.
+	Region string
+	// Endpoint is the URL of an S3 compatible service, ex. MinIO. If empty,
+	// AWS S3 is used.
+	Endpoint string
+}
+
+// S3JobStore stores each job as a JSON object in an S3 compatible bucket.
+// Credentials are read from the environment like other AWS tools.
+type S3JobStore struct {
+	client s3iface.S3API
+	bucket string
+	prefix string
+}
+
+// NewS3JobStore returns an S3JobStore for the bucket in cfg.
+func NewS3JobStore(cfg S3JobStoreConfig) (*S3JobStore, error) {
+	awsCfg := aws.NewConfig()
+	if cfg.Region != "" {
+		awsCfg = awsCfg.WithRegion(cfg.Region)
+	}
+	if cfg.Endpoint != "" {
+		// S3 compatible services usually don't support virtual hosted-style
+		// URLs.
+		awsCfg = awsCfg.WithEndpoint(cfg.Endpoint).WithS3ForcePathStyle(true)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *awsCfg,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "creating aws session")
+	}
+	return &S3JobStore{
+		client: s3.New(sess),
+		bucket: cfg.Bucket,
+		prefix: strings.Trim(cfg.Prefix, "/"),
+	}, nil
+}
+
+func (s *S3JobStore) Write(job StoredJob) error {
+	if err := validateJobID(job.JobID); err != nil {
+		return err
+	}
+	body, err := json.Marshal(job)
+	if err != nil {
+		return errors.Wrap(err, "serializing job")
+	}
+	_, err = s.client.PutObject(&s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(s.key(job.JobID)),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String("application/json"),
+	})
+	return errors.Wrapf(err, "writing job %s", job.JobID)
+}
+
+func (s *S3JobStore) Get(jobID string) (*StoredJob, error) {
+	if err := validateJobID(jobID); err != nil {
+		return nil, err
+	}
+	out, err := s.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(jobID)),
+	})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading job %s", jobID)
+	}
+	defer out.Body.Close() // nolint: errcheck
+	body, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading job %s", jobID)
+	}
+	var job StoredJob
+	if err := json.Unmarshal(body, &job); err != nil {
+		return nil, errors.Wrapf(err, "deserializing job %s", jobID)
+	}
+	return &job, nil
+}
+
+// DeleteCompletedBefore uses the last modified times of the objects since
+// jobs are written when they complete.
+func (s *S3JobStore) DeleteCompletedBefore(t time.Time) (int, error) {
+	var keys []string
+	listPrefix := ""
+	if s.prefix != "" {
+		listPrefix = s.prefix + "/"
+	}
+	err := s.client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(listPrefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			key := aws.StringValue(obj.Key)
+			if strings.HasSuffix(key, storedJobExt) && aws.TimeValue(obj.LastModified).Before(t) {
+				keys = append(keys, key)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "listing jobs")
+	}
+
+	deleted := 0
+	for _, key := range keys {
+		if _, err := s.client.DeleteObject(&s3.DeleteObjectInput{
+			Bucket: aws.String(s.bucket),
+			Key:    aws.String(key),
+		}); err != nil {
+			return deleted, errors.Wrapf(err, "deleting %s", key)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+func (s *S3JobStore) key(jobID string) string {
+	return path.Join(s.prefix, jobID+storedJobExt)
+}
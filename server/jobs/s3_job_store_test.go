@@ -0,0 +1,164 @@
+package jobs_test
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/jobs"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+// fakeS3 is a minimal stand-in for an S3 compatible service like MinIO. It
+// supports the path-style object requests used by S3JobStore and doesn't
+// check signatures.
+type fakeS3 struct {
+	bucket  string
+	mu      sync.Mutex
+	objects map[string]fakeS3Object
+}
+
+type fakeS3Object struct {
+	body         []byte
+	lastModified time.Time
+}
+
+func newFakeS3(bucket string) *fakeS3 {
+	return &fakeS3{bucket: bucket, objects: map[string]fakeS3Object{}}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	bucket, key := path, ""
+	if i := strings.Index(path, "/"); i != -1 {
+		bucket, key = path[:i], path[i+1:]
+	}
+	if bucket != f.bucket {
+		f.writeErr(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		f.list(w, r.URL.Query().Get("prefix"))
+	case r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[key] = fakeS3Object{body: body, lastModified: time.Now()}
+	case r.Method == http.MethodGet:
+		obj, ok := f.objects[key]
+		if !ok {
+			f.writeErr(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Write(obj.body) // nolint: errcheck
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		f.writeErr(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, prefix string) {
+	type content struct {
+		Key          string
+		LastModified string
+		Size         int
+	}
+	result := struct {
+		XMLName     xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+		Name        string
+		Prefix      string
+		KeyCount    int
+		IsTruncated bool
+		Contents    []content
+	}{Name: f.bucket, Prefix: prefix}
+	for key, obj := range f.objects {
+		if strings.HasPrefix(key, prefix) {
+			result.Contents = append(result.Contents, content{
+				Key:          key,
+				LastModified: obj.lastModified.UTC().Format(time.RFC3339),
+				Size:         len(obj.body),
+			})
+		}
+	}
+	sort.Slice(result.Contents, func(i, j int) bool { return result.Contents[i].Key < result.Contents[j].Key })
+	result.KeyCount = len(result.Contents)
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(result) // nolint: errcheck
+}
+
+func (f *fakeS3) writeErr(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	xml.NewEncoder(w).Encode(struct { // nolint: errcheck
+		XMLName xml.Name `xml:"Error"`
+		Code    string
+	}{Code: code})
+}
+
+func TestS3JobStore(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "access-key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret-key")
+	fake := newFakeS3("atlantis")
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store, err := jobs.NewS3JobStore(jobs.S3JobStoreConfig{
+		Bucket:   "atlantis",
+		Prefix:   "/jobs/",
+		Region:   "us-east-1",
+		Endpoint: server.URL,
+	})
+	Ok(t, err)
+
+	job, err := store.Get("1234")
+	Ok(t, err)
+	Assert(t, job == nil, "exp no job")
+
+	stored := jobs.StoredJob{
+		Job: jobs.Job{
+			JobID: "1234",
+			JobInfo: jobs.JobInfo{
+				PullInfo:   jobs.PullInfo{PullNum: 1, Repo: "repo", ProjectName: "project", Workspace: "default"},
+				HeadCommit: "abc",
+			},
+			OperationComplete: true,
+		},
+		Output:      []string{"line 1", "line 2"},
+		CompletedAt: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	Ok(t, store.Write(stored))
+	_, ok := fake.objects["jobs/1234.json"]
+	Assert(t, ok, "exp job to be stored under the prefix")
+	job, err = store.Get("1234")
+	Ok(t, err)
+	Equals(t, stored, *job)
+
+	// Only jobs that completed before the cutoff are deleted.
+	stored.JobID = "5678"
+	Ok(t, store.Write(stored))
+	fake.mu.Lock()
+	old := fake.objects["jobs/1234.json"]
+	old.lastModified = time.Now().Add(-48 * time.Hour)
+	fake.objects["jobs/1234.json"] = old
+	fake.mu.Unlock()
+	deleted, err := store.DeleteCompletedBefore(time.Now().Add(-24 * time.Hour))
+	Ok(t, err)
+	Equals(t, 1, deleted)
+	job, err = store.Get("1234")
+	Ok(t, err)
+	Assert(t, job == nil, "exp job to be deleted")
+	job, err = store.Get("5678")
+	Ok(t, err)
+	Assert(t, job != nil, "exp job to be kept")
+}
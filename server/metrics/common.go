@@ -6,3 +6,9 @@ const (
 	ExecutionErrorMetric   = "execution_error"
 	ExecutionFailureMetric = "execution_failure"
 )
+
+// DurationHistogramBuckets are the upper bounds in seconds of the buckets
+// that durations are reported into when timers are histograms. They range
+// from 100ms to an hour to cover everything from a VCS API call to a long
+// terraform apply.
+var DurationHistogramBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600, 1200, 1800, 3600}
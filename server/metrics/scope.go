@@ -2,14 +2,17 @@ package metrics
 
 import (
 	"io"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/cactus/go-statsd-client/statsd"
+	prom "github.com/m3db/prometheus_client_golang/prometheus"
 	"github.com/pkg/errors"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/uber-go/tally"
+	"github.com/uber-go/tally/prometheus"
 	tallystatsd "github.com/uber-go/tally/statsd"
 )
 
@@ -28,11 +31,25 @@ func NewLoggingScope(logger logging.SimpleLogging, statsNamespace string) (tally
 	return scope, closer, nil
 }
 
-func NewScope(cfg valid.Metrics, logger logging.SimpleLogging, statsNamespace string) (tally.Scope, io.Closer, error) {
+// NewScope returns the root scope that all metrics are reported to. If cfg
+// configures Prometheus, the returned http.Handler serves the metrics to be
+// scraped, otherwise it's nil.
+func NewScope(cfg valid.Metrics, logger logging.SimpleLogging, statsNamespace string) (tally.Scope, http.Handler, io.Closer, error) {
+	if cfg.Prometheus != nil {
+		reporter := newPrometheusReporter(logger)
+		scope, closer := tally.NewRootScope(tally.ScopeOptions{
+			Prefix:          statsNamespace,
+			CachedReporter:  reporter,
+			Separator:       prometheus.DefaultSeparator,
+			SanitizeOptions: &prometheus.DefaultSanitizerOpts,
+		}, time.Second)
+		return scope, reporter.HTTPHandler(), closer, nil
+	}
+
 	reporter, err := newReporter(cfg, logger)
 
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "initializing stats reporter")
+		return nil, nil, nil, errors.Wrap(err, "initializing stats reporter")
 	}
 
 	scope, closer := tally.NewRootScope(tally.ScopeOptions{
@@ -40,7 +57,23 @@ func NewScope(cfg valid.Metrics, logger logging.SimpleLogging, statsNamespace st
 		Reporter: reporter,
 	}, time.Second)
 
-	return scope, closer, nil
+	return scope, nil, closer, nil
+}
+
+// newPrometheusReporter returns a reporter that serves metrics to Prometheus.
+// Timers are reported as histograms so that plan and apply durations can be
+// aggregated across servers.
+func newPrometheusReporter(logger logging.SimpleLogging) prometheus.Reporter {
+	return prometheus.NewReporter(prometheus.Options{
+		// Use our own registry rather than the global one so that metrics
+		// from different scopes don't collide.
+		Registerer:              prom.NewRegistry(),
+		DefaultTimerType:        prometheus.HistogramTimerType,
+		DefaultHistogramBuckets: DurationHistogramBuckets,
+		OnRegisterError: func(err error) {
+			logger.Warn("unable to register prometheus metric: %s", err)
+		},
+	})
 }
 
 func newReporter(cfg valid.Metrics, logger logging.SimpleLogging) (tally.StatsReporter, error) {
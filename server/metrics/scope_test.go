@@ -0,0 +1,44 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/metrics"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestNewScope_Prometheus(t *testing.T) {
+	scope, handler, closer, err := metrics.NewScope(valid.Metrics{
+		Prometheus: &valid.Prometheus{Endpoint: "/metrics"},
+	}, logging.NewNoopLogger(t), "atlantis")
+	Ok(t, err)
+	Assert(t, handler != nil, "expected a handler for prometheus")
+
+	planScope := scope.SubScope("project").SubScope("plan")
+	planScope.Counter(metrics.ExecutionSuccessMetric).Inc(1)
+	planScope.Timer(metrics.ExecutionTimeMetric).Record(90 * time.Second)
+	// Closing the scope flushes the counters to the reporter.
+	Ok(t, closer.Close())
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	Equals(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	Assert(t, strings.Contains(body, "atlantis_project_plan_execution_success 1\n"), "missing counter in:\n%s", body)
+	// Timers are histograms so durations land in buckets.
+	Assert(t, strings.Contains(body, "atlantis_project_plan_execution_time_bucket{le=\"60\"} 0\n"), "missing histogram in:\n%s", body)
+	Assert(t, strings.Contains(body, "atlantis_project_plan_execution_time_bucket{le=\"120\"} 1\n"), "missing histogram in:\n%s", body)
+}
+
+func TestNewScope_NoPrometheus(t *testing.T) {
+	_, handler, closer, err := metrics.NewScope(valid.Metrics{}, logging.NewNoopLogger(t), "atlantis")
+	Ok(t, err)
+	defer closer.Close() // nolint: errcheck
+	Assert(t, handler == nil, "expected no handler without prometheus")
+}
@@ -0,0 +1,83 @@
+package scheduled
+
+import (
+	"sync"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+// DriftDetector checks a repo for drift.
+type DriftDetector interface {
+	Detect(repoID string, cfg valid.DriftDetection) error
+}
+
+// DriftDetectionJob checks the repos that have drift detection configured
+// when their schedule is due. It should be run every minute.
+type DriftDetectionJob struct {
+	Detector DriftDetector
+	Repos    []valid.Repo
+	Logger   logging.SimpleLogging
+
+	// now is overridden in tests.
+	now func() time.Time
+	// nextRuns is when each repo should next be checked, by id.
+	nextRuns map[string]time.Time
+	// running is the ids of the repos that are being checked.
+	running   map[string]bool
+	runningMu sync.Mutex
+	wg        sync.WaitGroup
+}
+
+func NewDriftDetectionJob(detector DriftDetector, repos []valid.Repo, logger logging.SimpleLogging) *DriftDetectionJob {
+	return &DriftDetectionJob{
+		Detector: detector,
+		Repos:    repos,
+		Logger:   logger,
+		now:      time.Now,
+		nextRuns: make(map[string]time.Time),
+		running:  make(map[string]bool),
+	}
+}
+
+// Run starts checking the repos whose schedule is due. Checks run in the
+// background so that a slow repo doesn't delay the others. A repo is skipped
+// if its previous check is still running.
+func (j *DriftDetectionJob) Run() {
+	now := j.now()
+	for _, repo := range j.Repos {
+		next, ok := j.nextRuns[repo.ID]
+		if !ok || !now.Before(next) {
+			j.nextRuns[repo.ID] = repo.DriftDetection.Schedule.Next(now)
+		}
+		// The first run only schedules the repos.
+		if !ok || next.IsZero() || now.Before(next) {
+			continue
+		}
+
+		j.runningMu.Lock()
+		if j.running[repo.ID] {
+			j.runningMu.Unlock()
+			j.Logger.Warn("skipping drift detection for %s: the previous check is still running", repo.ID)
+			continue
+		}
+		j.running[repo.ID] = true
+		j.runningMu.Unlock()
+
+		j.wg.Add(1)
+		go j.detect(repo.ID, *repo.DriftDetection)
+	}
+}
+
+func (j *DriftDetectionJob) detect(repoID string, cfg valid.DriftDetection) {
+	defer j.wg.Done()
+	defer func() {
+		j.runningMu.Lock()
+		delete(j.running, repoID)
+		j.runningMu.Unlock()
+	}()
+	if err := j.Detector.Detect(repoID, cfg); err != nil {
+		j.Logger.Err(err.Error())
+	}
+}
@@ -0,0 +1,54 @@
+package scheduled
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+type fakeDriftDetector struct {
+	mu     sync.Mutex
+	checks []string
+}
+
+func (f *fakeDriftDetector) Detect(repoID string, cfg valid.DriftDetection) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.checks = append(f.checks, repoID)
+	return nil
+}
+
+func TestDriftDetectionJob_Run(t *testing.T) {
+	hourly, err := valid.ParseCronSchedule("@hourly")
+	Ok(t, err)
+	daily, err := valid.ParseCronSchedule("@daily")
+	Ok(t, err)
+	detector := &fakeDriftDetector{}
+	job := NewDriftDetectionJob(detector, []valid.Repo{
+		{ID: "github.com/owner/hourly", DriftDetection: &valid.DriftDetection{Schedule: hourly}},
+		{ID: "github.com/owner/daily", DriftDetection: &valid.DriftDetection{Schedule: daily}},
+	}, logging.NewNoopLogger(t))
+
+	now := time.Date(2022, 6, 15, 22, 30, 0, 0, time.UTC)
+	job.now = func() time.Time { return now }
+	run := func(at time.Time) []string {
+		now = at
+		detector.checks = nil
+		job.Run()
+		job.wg.Wait()
+		return detector.checks
+	}
+
+	// The first run only schedules the checks.
+	Equals(t, []string(nil), run(now))
+	Equals(t, []string(nil), run(now.Add(15*time.Minute)))
+	Equals(t, []string{"github.com/owner/hourly"}, run(time.Date(2022, 6, 15, 23, 0, 0, 0, time.UTC)))
+	Equals(t, []string(nil), run(time.Date(2022, 6, 15, 23, 1, 0, 0, time.UTC)))
+	// Runs that are late still check the repo.
+	Equals(t, 2, len(run(time.Date(2022, 6, 16, 0, 0, 30, 0, time.UTC))))
+	Equals(t, []string(nil), run(time.Date(2022, 6, 16, 0, 1, 0, 0, time.UTC)))
+}
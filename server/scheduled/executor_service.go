@@ -2,6 +2,8 @@ package scheduled
 
 import (
 	"context"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/jobs"
 	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/uber-go/tally"
 	"os"
@@ -16,11 +18,17 @@ type ExecutorService struct {
 
 	// jobs
 	runtimeStatsPublisher JobDefinition
+	jobStoreCleaner       *JobDefinition
+	driftDetection        *JobDefinition
 }
 
 func NewExecutorService(
 	statsScope tally.Scope,
 	log logging.SimpleLogging,
+	jobStore jobs.JobStore,
+	jobRetention time.Duration,
+	driftDetector DriftDetector,
+	driftDetectionRepos []valid.Repo,
 ) *ExecutorService {
 
 	scheduledScope := statsScope.SubScope("scheduled")
@@ -31,9 +39,32 @@ func NewExecutorService(
 		Period: 10 * time.Second,
 	}
 
+	// Jobs are only cleaned up if they're persisted.
+	var jobStoreCleanerJob *JobDefinition
+	if jobStore != nil {
+		jobStoreCleanerJob = &JobDefinition{
+			Job: &JobStoreCleaner{
+				Store:     jobStore,
+				Retention: jobRetention,
+				Logger:    log,
+			},
+			Period: time.Hour,
+		}
+	}
+
+	var driftDetectionJob *JobDefinition
+	if len(driftDetectionRepos) > 0 {
+		driftDetectionJob = &JobDefinition{
+			Job:    NewDriftDetectionJob(driftDetector, driftDetectionRepos, log),
+			Period: time.Minute,
+		}
+	}
+
 	return &ExecutorService{
 		log:                   log,
 		runtimeStatsPublisher: runtimeStatsPublisherJob,
+		jobStoreCleaner:       jobStoreCleanerJob,
+		driftDetection:        driftDetectionJob,
 	}
 }
 
@@ -50,6 +81,12 @@ func (s *ExecutorService) Run() {
 	var wg sync.WaitGroup
 
 	s.runScheduledJob(ctx, &wg, s.runtimeStatsPublisher)
+	if s.jobStoreCleaner != nil {
+		s.runScheduledJob(ctx, &wg, *s.jobStoreCleaner)
+	}
+	if s.driftDetection != nil {
+		s.runScheduledJob(ctx, &wg, *s.driftDetection)
+	}
 
 	interrupt := make(chan os.Signal, 1)
 
@@ -0,0 +1,27 @@
+package scheduled
+
+import (
+	"time"
+
+	"github.com/runatlantis/atlantis/server/jobs"
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+// JobStoreCleaner deletes jobs from a job store once they're older than the
+// retention period.
+type JobStoreCleaner struct {
+	Store     jobs.JobStore
+	Retention time.Duration
+	Logger    logging.SimpleLogging
+}
+
+func (c *JobStoreCleaner) Run() {
+	deleted, err := c.Store.DeleteCompletedBefore(time.Now().Add(-c.Retention))
+	if err != nil {
+		c.Logger.Err("deleting expired jobs from job store: %s", err)
+		return
+	}
+	if deleted > 0 {
+		c.Logger.Info("deleted %d expired jobs from job store", deleted)
+	}
+}
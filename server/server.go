@@ -35,6 +35,7 @@ import (
 	cfg "github.com/runatlantis/atlantis/server/core/config"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/db"
+	"github.com/runatlantis/atlantis/server/core/redis"
 	"github.com/runatlantis/atlantis/server/jobs"
 	"github.com/runatlantis/atlantis/server/metrics"
 	"github.com/runatlantis/atlantis/server/scheduled"
@@ -49,7 +50,9 @@ import (
 	"github.com/runatlantis/atlantis/server/controllers/websocket"
 	"github.com/runatlantis/atlantis/server/core/locking"
 	"github.com/runatlantis/atlantis/server/core/runtime"
+	"github.com/runatlantis/atlantis/server/core/runtime/common"
 	"github.com/runatlantis/atlantis/server/core/runtime/policy"
+	"github.com/runatlantis/atlantis/server/core/runtime/sandbox"
 	"github.com/runatlantis/atlantis/server/core/terraform"
 	"github.com/runatlantis/atlantis/server/events"
 	"github.com/runatlantis/atlantis/server/events/command"
@@ -57,9 +60,11 @@ import (
 	"github.com/runatlantis/atlantis/server/events/vcs"
 	"github.com/runatlantis/atlantis/server/events/vcs/bitbucketcloud"
 	"github.com/runatlantis/atlantis/server/events/vcs/bitbucketserver"
+	"github.com/runatlantis/atlantis/server/events/vcs/gitea"
 	"github.com/runatlantis/atlantis/server/events/webhooks"
 	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/runatlantis/atlantis/server/static"
+	"github.com/runatlantis/atlantis/server/workers"
 	"github.com/urfave/cli"
 	"github.com/urfave/negroni"
 )
@@ -81,6 +86,12 @@ const (
 	// terraformPluginCacheDir is the name of the dir inside our data dir
 	// where we tell terraform to cache plugins and modules.
 	TerraformPluginCacheDirName = "plugin-cache"
+	// PolicyCacheDirName is the name of the dir inside our data dir where
+	// we download policy sets with a remote source.
+	PolicyCacheDirName = "policies"
+	// JobStoreDirName is the name of the dir inside our data dir where we
+	// store the output of completed jobs when using the disk job store.
+	JobStoreDirName = "jobs"
 )
 
 // Server runs the Atlantis web server.
@@ -95,25 +106,31 @@ type Server struct {
 	Logger                         logging.SimpleLogging
 	StatsScope                     tally.Scope
 	StatsCloser                    io.Closer
-	Locker                         locking.Locker
-	ApplyLocker                    locking.ApplyLocker
-	VCSEventsController            *events_controllers.VCSEventsController
-	GithubAppController            *controllers.GithubAppController
-	LocksController                *controllers.LocksController
-	StatusController               *controllers.StatusController
-	JobsController                 *controllers.JobsController
-	IndexTemplate                  templates.TemplateWriter
-	LockDetailTemplate             templates.TemplateWriter
-	ProjectJobsTemplate            templates.TemplateWriter
-	ProjectJobsErrorTemplate       templates.TemplateWriter
-	SSLCertFile                    string
-	SSLKeyFile                     string
-	Drainer                        *events.Drainer
-	WebAuthentication              bool
-	WebUsername                    string
-	WebPassword                    string
-	ProjectCmdOutputHandler        jobs.ProjectCommandOutputHandler
-	ScheduledExecutorService       *scheduled.ExecutorService
+	// MetricsHandler serves metrics for Prometheus to scrape from
+	// MetricsEndpoint. It's nil unless Prometheus metrics are configured.
+	MetricsHandler           http.Handler
+	MetricsEndpoint          string
+	Locker                   locking.Locker
+	ApplyLocker              locking.ApplyLocker
+	VCSEventsController      *events_controllers.VCSEventsController
+	GithubAppController      *controllers.GithubAppController
+	LocksController          *controllers.LocksController
+	StatusController         *controllers.StatusController
+	APIController            *controllers.APIController
+	WorkersController        *controllers.WorkersController
+	JobsController           *controllers.JobsController
+	IndexTemplate            templates.TemplateWriter
+	LockDetailTemplate       templates.TemplateWriter
+	ProjectJobsTemplate      templates.TemplateWriter
+	ProjectJobsErrorTemplate templates.TemplateWriter
+	SSLCertFile              string
+	SSLKeyFile               string
+	Drainer                  *events.Drainer
+	WebAuthentication        bool
+	WebUsername              string
+	WebPassword              string
+	ProjectCmdOutputHandler  jobs.ProjectCommandOutputHandler
+	ScheduledExecutorService *scheduled.ExecutorService
 }
 
 // Config holds config for server that isn't passed in by the user.
@@ -134,11 +151,20 @@ type WebhookConfig struct {
 	// that is being modified for this event. If the regex matches, we'll
 	// send the webhook, ex. "production.*".
 	WorkspaceRegex string `mapstructure:"workspace-regex"`
+	// RepoRegex is a regex that is used to match against the full name of
+	// the repo for this event, ex. "runatlantis/.*".
+	RepoRegex string `mapstructure:"repo-regex"`
 	// Kind is the type of webhook we should send, ex. slack.
 	Kind string `mapstructure:"kind"`
 	// Channel is the channel to send this webhook to. It only applies to
 	// slack webhooks. Should be without '#'.
 	Channel string `mapstructure:"channel"`
+	// URL is the url to POST this webhook to. It only applies to http
+	// webhooks.
+	URL string `mapstructure:"url"`
+	// Secret is used to sign http webhooks. It only applies to http
+	// webhooks.
+	Secret string `mapstructure:"secret"`
 }
 
 // NewServer returns a new server. If there are issues starting the server or
@@ -150,6 +176,11 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 	if err != nil {
 		return nil, err
 	}
+	// Commands run by the same user, ex. run steps, can't read our secrets
+	// from /proc/<pid>/environ.
+	if err := common.SetNotDumpable(); err != nil {
+		logger.Warn("%s, run steps may be able to read the server's environment", err)
+	}
 
 	var supportedVCSHosts []models.VCSHostType
 	var githubClient vcs.IGithubClient
@@ -159,6 +190,11 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 	var bitbucketCloudClient *bitbucketcloud.Client
 	var bitbucketServerClient *bitbucketserver.Client
 	var azuredevopsClient *vcs.AzureDevopsClient
+	var giteaClient *gitea.Client
+	// driftIssueCreator is only set when GitHub is configured since it's the
+	// only host that supports opening issues for drift.
+	var driftIssueCreator events.DriftIssueCreator
+	driftHostTypes := make(map[string]models.VCSHostType)
 
 	policyChecksEnabled := false
 	if userConfig.EnablePolicyChecksFlag {
@@ -188,7 +224,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		}
 	}
 
-	statsScope, closer, err := metrics.NewScope(globalCfg.Metrics, logger, userConfig.StatsNamespace)
+	statsScope, metricsHandler, closer, err := metrics.NewScope(globalCfg.Metrics, logger, userConfig.StatsNamespace)
 
 	if err != nil {
 		return nil, errors.Wrapf(err, "instantiating metrics scope")
@@ -230,6 +266,8 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		}
 
 		githubClient = vcs.NewInstrumentedGithubClient(rawGithubClient, statsScope, logger)
+		driftIssueCreator = rawGithubClient
+		driftHostTypes[userConfig.GithubHostname] = models.Github
 	}
 	if userConfig.GitlabUser != "" {
 		supportedVCSHosts = append(supportedVCSHosts, models.Gitlab)
@@ -238,6 +276,10 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		if err != nil {
 			return nil, err
 		}
+		driftHostTypes[userConfig.GitlabHostname] = models.Gitlab
+		// GitLab can only check membership of specific groups so we check
+		// the groups that own policies or are allowed to run commands.
+		gitlabClient.ConfiguredGroups = append(globalCfg.PolicySets.OwnerTeams(), globalCfg.PermissionTeams()...)
 	}
 	if userConfig.BitbucketUser != "" {
 		if userConfig.BitbucketBaseURL == bitbucketcloud.BaseURL {
@@ -270,6 +312,16 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 			return nil, err
 		}
 	}
+	if userConfig.GiteaUser != "" {
+		supportedVCSHosts = append(supportedVCSHosts, models.Gitea)
+
+		var err error
+		giteaClient, err = gitea.NewClient(http.DefaultClient, userConfig.GiteaHostname, userConfig.GiteaToken)
+		if err != nil {
+			return nil, errors.Wrapf(err, "setting up Gitea client")
+		}
+		driftHostTypes[userConfig.GiteaHostname] = models.Gitea
+	}
 
 	if userConfig.WriteGitCreds {
 		home, err := homedir.Dir()
@@ -302,6 +354,17 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 				return nil, err
 			}
 		}
+		if userConfig.GiteaUser != "" {
+			// The Gitea hostname may include a scheme and port which don't
+			// belong in the credentials file.
+			giteaURL, err := url.Parse(giteaClient.BaseURL)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parsing Gitea URL %q", giteaClient.BaseURL)
+			}
+			if err := events.WriteGitCreds(userConfig.GiteaUser, userConfig.GiteaToken, giteaURL.Host, home, logger, false); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	var webhooksConfig []webhooks.Config
@@ -311,14 +374,18 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 			Event:          c.Event,
 			Kind:           c.Kind,
 			WorkspaceRegex: c.WorkspaceRegex,
+			RepoRegex:      c.RepoRegex,
+			URL:            c.URL,
+			Secret:         c.Secret,
 		}
 		webhooksConfig = append(webhooksConfig, config)
 	}
-	webhooksManager, err := webhooks.NewMultiWebhookSender(webhooksConfig, webhooks.NewSlackClient(userConfig.SlackToken))
+	multiWebhookSender, err := webhooks.NewMultiWebhookSender(webhooksConfig, webhooks.NewSlackClient(userConfig.SlackToken))
 	if err != nil {
 		return nil, errors.Wrap(err, "initializing webhooks")
 	}
-	vcsClient := vcs.NewClientProxy(githubClient, gitlabClient, bitbucketCloudClient, bitbucketServerClient, azuredevopsClient)
+	webhooksManager := webhooks.NewAsyncSender(multiWebhookSender)
+	vcsClient := vcs.NewClientProxy(githubClient, gitlabClient, bitbucketCloudClient, bitbucketServerClient, azuredevopsClient, giteaClient)
 	commitStatusUpdater := &events.DefaultCommitStatusUpdater{Client: vcsClient, StatusName: userConfig.VCSStatusName}
 
 	binDir, err := mkSubDir(userConfig.DataDir, BinDirName)
@@ -333,6 +400,12 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		return nil, err
 	}
 
+	policyCacheDir, err := mkSubDir(userConfig.DataDir, PolicyCacheDirName)
+
+	if err != nil {
+		return nil, err
+	}
+
 	parsedURL, err := ParseAtlantisURL(userConfig.AtlantisURL)
 	if err != nil {
 		return nil, errors.Wrapf(err,
@@ -348,6 +421,29 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		Underlying:                underlyingRouter,
 	}
 
+	var jobStore jobs.JobStore
+	switch userConfig.JobStore {
+	case "disk":
+		jobStoreDir := userConfig.JobStoreDir
+		if jobStoreDir == "" {
+			jobStoreDir = filepath.Join(userConfig.DataDir, JobStoreDirName)
+		}
+		jobStore, err = jobs.NewLocalJobStore(jobStoreDir)
+		if err != nil {
+			return nil, errors.Wrap(err, "initializing disk job store")
+		}
+	case "s3":
+		jobStore, err = jobs.NewS3JobStore(jobs.S3JobStoreConfig{
+			Bucket:   userConfig.JobStoreS3Bucket,
+			Prefix:   userConfig.JobStoreS3Prefix,
+			Region:   userConfig.JobStoreS3Region,
+			Endpoint: userConfig.JobStoreS3Endpoint,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "initializing s3 job store")
+		}
+	}
+
 	var projectCmdOutputHandler jobs.ProjectCommandOutputHandler
 	// When TFE is enabled log streaming is not necessary.
 
@@ -358,6 +454,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		projectCmdOutputHandler = jobs.NewAsyncProjectCommandOutputHandler(
 			projectCmdOutput,
 			logger,
+			jobStore,
 		)
 	}
 
@@ -388,18 +485,29 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		EnableDiffMarkdownFormat: userConfig.EnableDiffMarkdownFormat,
 	}
 
-	boltdb, err := db.New(userConfig.DataDir)
-	if err != nil {
-		return nil, err
+	var backend db.Database
+	switch userConfig.LockingDBType {
+	case "redis":
+		logger.Info("Utilizing Redis DB")
+		backend, err = redis.New(userConfig.RedisHost, userConfig.RedisPort, userConfig.RedisPassword, userConfig.RedisTLSEnabled, userConfig.RedisInsecureSkipVerify, userConfig.RedisDB)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		logger.Info("Utilizing BoltDB")
+		backend, err = db.New(userConfig.DataDir)
+		if err != nil {
+			return nil, err
+		}
 	}
 	var lockingClient locking.Locker
 	var applyLockingClient locking.ApplyLocker
 	if userConfig.DisableRepoLocking {
 		lockingClient = locking.NewNoOpLocker()
 	} else {
-		lockingClient = locking.NewClient(boltdb)
+		lockingClient = locking.NewClient(backend)
 	}
-	applyLockingClient = locking.NewApplyClient(boltdb, userConfig.DisableApply)
+	applyLockingClient = locking.NewApplyClient(backend, userConfig.DisableApply)
 	workingDirLocker := events.NewDefaultWorkingDirLocker()
 
 	var workingDir events.WorkingDir = &events.FileWorkspace{
@@ -421,13 +529,15 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 	projectLocker := &events.DefaultProjectLocker{
 		Locker:    lockingClient,
 		VCSClient: vcsClient,
+		Webhooks:  webhooksManager,
 	}
 	deleteLockCommand := &events.DefaultDeleteLockCommand{
 		Locker:           lockingClient,
 		Logger:           logger,
 		WorkingDir:       workingDir,
 		WorkingDirLocker: workingDirLocker,
-		DB:               boltdb,
+		DB:               backend,
+		Webhooks:         webhooksManager,
 	}
 
 	pullClosedExecutor := events.NewInstrumentedPullClosedExecutor(
@@ -437,10 +547,11 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 			Locker:                   lockingClient,
 			WorkingDir:               workingDir,
 			Logger:                   logger,
-			DB:                       boltdb,
+			DB:                       backend,
 			PullClosedTemplate:       &events.PullClosedEventTemplate{},
 			LogStreamResourceCleaner: projectCmdOutputHandler,
 			VCSClient:                vcsClient,
+			Webhooks:                 webhooksManager,
 		},
 	)
 	eventParser := &events.EventParser{
@@ -454,44 +565,71 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		BitbucketServerURL: userConfig.BitbucketBaseURL,
 		AzureDevopsUser:    userConfig.AzureDevopsUser,
 		AzureDevopsToken:   userConfig.AzureDevopsToken,
+		GiteaUser:          userConfig.GiteaUser,
+		GiteaToken:         userConfig.GiteaToken,
 	}
 	commentParser := &events.CommentParser{
 		GithubUser:      userConfig.GithubUser,
 		GitlabUser:      userConfig.GitlabUser,
 		BitbucketUser:   userConfig.BitbucketUser,
 		AzureDevopsUser: userConfig.AzureDevopsUser,
+		GiteaUser:       userConfig.GiteaUser,
 		ApplyDisabled:   userConfig.DisableApply,
 	}
 	defaultTfVersion := terraformClient.DefaultVersion()
 	pendingPlanFinder := &events.DefaultPendingPlanFinder{}
+	// Repos that configure a sandbox run their run steps and hooks without
+	// access to the rest of the data dir, the home dir and the files that
+	// may contain secrets.
+	sandboxHidden, err := sandboxHiddenPaths(userConfig.ConfigFile, userConfig.RepoConfig, userConfig.GithubAppKeyFile, userConfig.SSLCertFile, userConfig.SSLKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	sandboxExecutor, err := sandbox.NewNamespaceExecutor(userConfig.DataDir, sandboxHidden, []string{cacheDir}, []string{binDir})
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing sandbox executor")
+	}
 	runStepRunner := &runtime.RunStepRunner{
 		TerraformExecutor: terraformClient,
 		DefaultTFVersion:  defaultTfVersion,
 		TerraformBinDir:   terraformClient.TerraformBinDir(),
+		Executor:          sandboxExecutor,
 	}
 	drainer := &events.Drainer{}
+	var concurrencyLimiter *events.ConcurrencyLimiter
+	if userConfig.ConcurrencyLimit > 0 || userConfig.RepoConcurrencyLimit > 0 {
+		concurrencyLimiter = events.NewConcurrencyLimiter(userConfig.ConcurrencyLimit, userConfig.RepoConcurrencyLimit, statsScope.SubScope("concurrency_limiter"))
+	}
 	statusController := &controllers.StatusController{
-		Logger:  logger,
-		Drainer: drainer,
+		Logger:             logger,
+		Drainer:            drainer,
+		ConcurrencyLimiter: concurrencyLimiter,
 	}
 	preWorkflowHooksCommandRunner := &events.DefaultPreWorkflowHooksCommandRunner{
 		VCSClient:             vcsClient,
 		GlobalCfg:             globalCfg,
 		WorkingDirLocker:      workingDirLocker,
 		WorkingDir:            workingDir,
-		PreWorkflowHookRunner: runtime.DefaultPreWorkflowHookRunner{},
+		PreWorkflowHookRunner: runtime.DefaultPreWorkflowHookRunner{Executor: sandboxExecutor},
 	}
 	postWorkflowHooksCommandRunner := &events.DefaultPostWorkflowHooksCommandRunner{
 		VCSClient:              vcsClient,
 		GlobalCfg:              globalCfg,
 		WorkingDirLocker:       workingDirLocker,
 		WorkingDir:             workingDir,
-		PostWorkflowHookRunner: runtime.DefaultPostWorkflowHookRunner{},
+		PostWorkflowHookRunner: runtime.DefaultPostWorkflowHookRunner{Executor: sandboxExecutor},
+	}
+	defaultProjectFinder := events.DefaultProjectFinder{
+		AutoplanModules: userConfig.AutoplanModules,
+	}
+	var projectFinder events.ProjectFinder = &defaultProjectFinder
+	if userConfig.EnableTerragruntDiscovery {
+		projectFinder = &events.TerragruntProjectFinder{DefaultProjectFinder: defaultProjectFinder}
 	}
 	projectCommandBuilder := events.NewInstrumentedProjectCommandBuilder(
 		policyChecksEnabled,
 		validator,
-		&events.DefaultProjectFinder{},
+		projectFinder,
 		vcsClient,
 		workingDir,
 		workingDirLocker,
@@ -513,7 +651,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 
 	policyCheckRunner, err := runtime.NewPolicyCheckStepRunner(
 		defaultTfVersion,
-		policy.NewConfTestExecutorWorkflow(logger, binDir, &terraform.DefaultDownloader{}),
+		policy.NewConfTestExecutorWorkflow(logger, binDir, policyCacheDir, time.Duration(userConfig.PolicyRefreshInterval)*time.Minute, &terraform.DefaultDownloader{}),
 	)
 
 	if err != nil {
@@ -524,6 +662,8 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		WorkingDir: workingDir,
 	}
 
+	runningCommands := events.NewRunningCommands()
+
 	projectCommandRunner := &events.DefaultProjectCommandRunner{
 		Locker:           projectLocker,
 		LockURLGenerator: router,
@@ -553,14 +693,40 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 			TerraformExecutor: terraformClient,
 			DefaultTFVersion:  defaultTfVersion,
 		},
+		ImportStepRunner: &runtime.ImportStepRunner{
+			TerraformExecutor: terraformClient,
+			DefaultTFVersion:  defaultTfVersion,
+		},
+		StateRmStepRunner: &runtime.StateStepRunner{
+			TerraformExecutor: terraformClient,
+			DefaultTFVersion:  defaultTfVersion,
+			Subcommand:        "rm",
+		},
+		StateMvStepRunner: &runtime.StateStepRunner{
+			TerraformExecutor: terraformClient,
+			DefaultTFVersion:  defaultTfVersion,
+			Subcommand:        "mv",
+		},
 		WorkingDir:                 workingDir,
 		Webhooks:                   webhooksManager,
 		WorkingDirLocker:           workingDirLocker,
 		AggregateApplyRequirements: applyRequirementHandler,
+		ProjectJobURLGenerator:     router,
+		RunningCommands:            runningCommands,
+		ConcurrencyLimiter:         concurrencyLimiter,
+		CommitStatusUpdater:        commitStatusUpdater,
+		JobMessageSender:           projectCmdOutputHandler,
+		EnablePlanSummary:          userConfig.EnablePlanSummary,
+	}
+
+	var workerQueue *workers.Queue
+	if userConfig.EnableRemoteWorkers {
+		workerQueue = workers.NewQueue(time.Duration(userConfig.WorkerLeaseTimeout)*time.Second, projectCmdOutputHandler)
+		projectCommandRunner.StepsRunner = workerQueue
 	}
 
 	dbUpdater := &events.DBUpdater{
-		DB: boltdb,
+		DB: backend,
 	}
 
 	pullUpdater := &events.PullUpdater{
@@ -607,7 +773,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		autoMerger,
 		userConfig.ParallelPoolSize,
 		userConfig.SilenceNoProjects,
-		boltdb,
+		backend,
 	)
 
 	pullReqStatusFetcher := vcs.NewPullReqStatusFetcher(vcsClient)
@@ -621,7 +787,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		autoMerger,
 		pullUpdater,
 		dbUpdater,
-		boltdb,
+		backend,
 		userConfig.ParallelPoolSize,
 		userConfig.SilenceNoProjects,
 		userConfig.SilenceVCSStatusNoProjects,
@@ -634,6 +800,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		instrumentedProjectCmdRunner,
 		pullUpdater,
 		dbUpdater,
+		vcsClient,
 		userConfig.SilenceNoProjects,
 		userConfig.SilenceVCSStatusNoPlans,
 	)
@@ -652,12 +819,38 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		userConfig.SilenceNoProjects,
 	)
 
+	importCommandRunner := events.NewImportCommandRunner(
+		pullUpdater,
+		dbUpdater,
+		projectCommandBuilder,
+		instrumentedProjectCmdRunner,
+		pullReqStatusFetcher,
+	)
+
+	stateCommandRunner := events.NewStateCommandRunner(
+		pullUpdater,
+		dbUpdater,
+		projectCommandBuilder,
+		instrumentedProjectCmdRunner,
+		pullReqStatusFetcher,
+	)
+
+	cancelCommandRunner := events.NewCancelCommandRunner(
+		runningCommands,
+		projectCmdOutputHandler,
+		vcsClient,
+	)
+
 	commentCommandRunnerByCmd := map[command.Name]events.CommentCommandRunner{
 		command.Plan:            planCommandRunner,
 		command.Apply:           applyCommandRunner,
 		command.ApprovePolicies: approvePoliciesCommandRunner,
 		command.Unlock:          unlockCommandRunner,
 		command.Version:         versionCommandRunner,
+		command.Import:          importCommandRunner,
+		command.StateRm:         stateCommandRunner,
+		command.StateMv:         stateCommandRunner,
+		command.Cancel:          cancelCommandRunner,
 	}
 
 	githubTeamAllowlistChecker, err := events.NewTeamAllowlistChecker(userConfig.GithubTeamAllowlist)
@@ -670,6 +863,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		GithubPullGetter:               githubClient,
 		GitlabMergeRequestGetter:       gitlabClient,
 		AzureDevopsPullGetter:          azuredevopsClient,
+		GiteaPullGetter:                giteaClient,
 		CommentCommandRunnerByCmd:      commentCommandRunnerByCmd,
 		EventParser:                    eventParser,
 		Logger:                         logger,
@@ -683,7 +877,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		Drainer:                        drainer,
 		PreWorkflowHooksCommandRunner:  preWorkflowHooksCommandRunner,
 		PostWorkflowHooksCommandRunner: postWorkflowHooksCommandRunner,
-		PullStatusFetcher:              boltdb,
+		PullStatusFetcher:              backend,
 		TeamAllowlistChecker:           githubTeamAllowlistChecker,
 	}
 	repoAllowlist, err := events.NewRepoAllowlistChecker(userConfig.RepoAllowlist)
@@ -700,8 +894,9 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		LockDetailTemplate: templates.LockTemplate,
 		WorkingDir:         workingDir,
 		WorkingDirLocker:   workingDirLocker,
-		DB:                 boltdb,
+		DB:                 backend,
 		DeleteLockCommand:  deleteLockCommand,
+		GlobalCfg:          globalCfg,
 	}
 
 	wsMux := websocket.NewMultiplexor(
@@ -716,10 +911,37 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		Logger:                   logger,
 		ProjectJobsTemplate:      templates.ProjectJobsTemplate,
 		ProjectJobsErrorTemplate: templates.ProjectJobsErrorTemplate,
-		Db:                       boltdb,
+		Db:                       backend,
 		WsMux:                    wsMux,
 		KeyGenerator:             controllers.JobIDKeyGenerator{},
 		StatsScope:               statsScope.SubScope("api"),
+		JobStore:                 jobStore,
+	}
+
+	apiController := &controllers.APIController{
+		APISecret:                 []byte(userConfig.APISecret),
+		DB:                        backend,
+		Locker:                    lockingClient,
+		Logger:                    logger,
+		Parser:                    eventParser,
+		ProjectCmdOutputHandler:   projectCmdOutputHandler,
+		ProjectCommandBuilder:     projectCommandBuilder,
+		ProjectPlanCommandRunner:  instrumentedProjectCmdRunner,
+		ProjectApplyCommandRunner: instrumentedProjectCmdRunner,
+		RepoAllowlistChecker:      repoAllowlist,
+		Scope:                     statsScope,
+		VCSClient:                 vcsClient,
+		Webhooks:                  webhooksManager,
+	}
+
+	var workersController *controllers.WorkersController
+	if workerQueue != nil {
+		workersController = &controllers.WorkersController{
+			Secret:    []byte(userConfig.APISecret),
+			Queue:     workerQueue,
+			Logger:    logger,
+			ClaimWait: workers.ClaimWait,
+		}
 	}
 
 	eventsController := &events_controllers.VCSEventsController{
@@ -742,6 +964,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		AzureDevopsWebhookBasicUser:     []byte(userConfig.AzureDevopsWebhookUser),
 		AzureDevopsWebhookBasicPassword: []byte(userConfig.AzureDevopsWebhookPassword),
 		AzureDevopsRequestValidator:     &events_controllers.DefaultAzureDevopsRequestValidator{},
+		GiteaWebhookSecret:              []byte(userConfig.GiteaWebhookSecret),
 	}
 	githubAppController := &controllers.GithubAppController{
 		AtlantisURL:         parsedURL,
@@ -750,11 +973,38 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		GithubHostname:      userConfig.GithubHostname,
 		GithubOrg:           userConfig.GithubOrg,
 	}
+	driftDetector := &events.DriftDetector{
+		WorkingDir:        workingDir,
+		WorkingDirLocker:  workingDirLocker,
+		ParserValidator:   validator,
+		GlobalCfg:         globalCfg,
+		TerraformExecutor: terraformClient,
+		DefaultTFVersion:  defaultTfVersion,
+		OutputHandler:     projectCmdOutputHandler,
+		JobURLGenerator:   router,
+		DB:                backend,
+		Parser:            eventParser,
+		VCSClient:         vcsClient,
+		VCSHostTypes:      driftHostTypes,
+		IssueCreator:      driftIssueCreator,
+		Webhooks:          webhooksManager,
+		Logger:            logger,
+		Scope:             statsScope,
+	}
 	scheduledExecutorService := scheduled.NewExecutorService(
 		statsScope,
 		logger,
+		jobStore,
+		time.Duration(userConfig.JobStoreRetentionDays)*24*time.Hour,
+		driftDetector,
+		globalCfg.DriftDetectionRepos(),
 	)
 
+	var metricsEndpoint string
+	if globalCfg.Metrics.Prometheus != nil {
+		metricsEndpoint = globalCfg.Metrics.Prometheus.Endpoint
+	}
+
 	return &Server{
 		AtlantisVersion:                config.AtlantisVersion,
 		AtlantisURL:                    parsedURL,
@@ -766,6 +1016,8 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		Logger:                         logger,
 		StatsScope:                     statsScope,
 		StatsCloser:                    closer,
+		MetricsHandler:                 metricsHandler,
+		MetricsEndpoint:                metricsEndpoint,
 		Locker:                         lockingClient,
 		ApplyLocker:                    applyLockingClient,
 		VCSEventsController:            eventsController,
@@ -773,6 +1025,8 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		LocksController:                locksController,
 		JobsController:                 jobsController,
 		StatusController:               statusController,
+		APIController:                  apiController,
+		WorkersController:              workersController,
 		IndexTemplate:                  templates.IndexTemplate,
 		LockDetailTemplate:             templates.LockTemplate,
 		ProjectJobsTemplate:            templates.ProjectJobsTemplate,
@@ -806,6 +1060,23 @@ func (s *Server) Start() error {
 		Queries(LockViewRouteIDQueryParam, fmt.Sprintf("{%s}", LockViewRouteIDQueryParam)).Name(LockViewRouteName)
 	s.Router.HandleFunc("/jobs/{job-id}", s.JobsController.GetProjectJobs).Methods("GET").Name(ProjectJobsViewRouteName)
 	s.Router.HandleFunc("/jobs/{job-id}/ws", s.JobsController.GetProjectJobsWS).Methods("GET")
+	s.Router.HandleFunc("/api/plan", s.APIController.Plan).Methods("POST")
+	s.Router.HandleFunc("/api/apply", s.APIController.Apply).Methods("POST")
+	s.Router.HandleFunc("/api/locks", s.APIController.ListLocks).Methods("GET")
+	s.Router.HandleFunc("/api/pulls/{repo:.+}/{num:[0-9]+}", s.APIController.GetPull).Methods("GET")
+	s.Router.HandleFunc("/api/jobs", s.APIController.ListJobs).Methods("GET")
+	s.Router.HandleFunc("/api/drift", s.APIController.ListDrift).Methods("GET")
+	s.Router.HandleFunc("/api/db/export", s.APIController.ExportDB).Methods("GET")
+	s.Router.HandleFunc("/api/db/import", s.APIController.ImportDB).Methods("POST")
+	if s.WorkersController != nil {
+		s.Router.HandleFunc("/workers/claim", s.WorkersController.Claim).Methods("POST")
+		s.Router.HandleFunc("/workers/leases/{id}/renew", s.WorkersController.Renew).Methods("POST")
+		s.Router.HandleFunc("/workers/leases/{id}/output", s.WorkersController.Output).Methods("POST")
+		s.Router.HandleFunc("/workers/leases/{id}/complete", s.WorkersController.Complete).Methods("POST")
+	}
+	if s.MetricsHandler != nil {
+		s.Router.Handle(s.MetricsEndpoint, s.MetricsHandler).Methods("GET")
+	}
 
 	n := negroni.New(&negroni.Recovery{
 		Logger:     log.New(os.Stdout, "", log.LstdFlags),
@@ -940,6 +1211,32 @@ func mkSubDir(parentDir string, subDir string) (string, error) {
 	return fullDir, nil
 }
 
+// sandboxHiddenPaths returns the paths sandboxed commands can't read besides
+// the data dir: the home dir, ex. for ~/.git-credentials, and files, the
+// non-empty paths given to server flags.
+func sandboxHiddenPaths(files ...string) ([]string, error) {
+	var paths []string
+	home, err := homedir.Dir()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting home dir to hide it from sandboxes")
+	}
+	// Hiding / would hide everything, ex. the shell.
+	if home != "/" {
+		paths = append(paths, home)
+	}
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "making %s absolute to hide it from sandboxes", file)
+		}
+		paths = append(paths, abs)
+	}
+	return paths, nil
+}
+
 // Healthz returns the health check response. It always returns a 200 currently.
 func (s *Server) Healthz(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -10,9 +10,11 @@ import (
 type UserConfig struct {
 	AllowForkPRs               bool   `mapstructure:"allow-fork-prs"`
 	AllowRepoConfig            bool   `mapstructure:"allow-repo-config"`
+	APISecret                  string `mapstructure:"api-secret"`
 	AtlantisURL                string `mapstructure:"atlantis-url"`
 	Automerge                  bool   `mapstructure:"automerge"`
 	AutoplanFileList           string `mapstructure:"autoplan-file-list"`
+	AutoplanModules            bool   `mapstructure:"autoplan-modules"`
 	AzureDevopsToken           string `mapstructure:"azuredevops-token"`
 	AzureDevopsUser            string `mapstructure:"azuredevops-user"`
 	AzureDevopsWebhookPassword string `mapstructure:"azuredevops-webhook-password"`
@@ -23,6 +25,8 @@ type UserConfig struct {
 	BitbucketUser              string `mapstructure:"bitbucket-user"`
 	BitbucketWebhookSecret     string `mapstructure:"bitbucket-webhook-secret"`
 	CheckoutStrategy           string `mapstructure:"checkout-strategy"`
+	ConcurrencyLimit           int    `mapstructure:"concurrency-limit"`
+	ConfigFile                 string `mapstructure:"config"`
 	DataDir                    string `mapstructure:"data-dir"`
 	DisableApplyAll            bool   `mapstructure:"disable-apply-all"`
 	DisableApply               bool   `mapstructure:"disable-apply"`
@@ -32,6 +36,9 @@ type UserConfig struct {
 	EnablePolicyChecksFlag     bool   `mapstructure:"enable-policy-checks"`
 	EnableRegExpCmd            bool   `mapstructure:"enable-regexp-cmd"`
 	EnableDiffMarkdownFormat   bool   `mapstructure:"enable-diff-markdown-format"`
+	EnablePlanSummary          bool   `mapstructure:"enable-plan-summary"`
+	EnableRemoteWorkers        bool   `mapstructure:"enable-remote-workers"`
+	EnableTerragruntDiscovery  bool   `mapstructure:"enable-terragrunt-discovery"`
 	GithubHostname             string `mapstructure:"gh-hostname"`
 	GithubToken                string `mapstructure:"gh-token"`
 	GithubUser                 string `mapstructure:"gh-user"`
@@ -42,19 +49,39 @@ type UserConfig struct {
 	GithubAppKeyFile           string `mapstructure:"gh-app-key-file"`
 	GithubAppSlug              string `mapstructure:"gh-app-slug"`
 	GithubTeamAllowlist        string `mapstructure:"gh-team-allowlist"`
+	GiteaHostname              string `mapstructure:"gitea-hostname"`
+	GiteaToken                 string `mapstructure:"gitea-token"`
+	GiteaUser                  string `mapstructure:"gitea-user"`
+	GiteaWebhookSecret         string `mapstructure:"gitea-webhook-secret"`
 	GitlabHostname             string `mapstructure:"gitlab-hostname"`
 	GitlabToken                string `mapstructure:"gitlab-token"`
 	GitlabUser                 string `mapstructure:"gitlab-user"`
 	GitlabWebhookSecret        string `mapstructure:"gitlab-webhook-secret"`
 	HidePrevPlanComments       bool   `mapstructure:"hide-prev-plan-comments"`
+	JobStore                   string `mapstructure:"job-store"`
+	JobStoreDir                string `mapstructure:"job-store-dir"`
+	JobStoreRetentionDays      int    `mapstructure:"job-store-retention-days"`
+	JobStoreS3Bucket           string `mapstructure:"job-store-s3-bucket"`
+	JobStoreS3Endpoint         string `mapstructure:"job-store-s3-endpoint"`
+	JobStoreS3Prefix           string `mapstructure:"job-store-s3-prefix"`
+	JobStoreS3Region           string `mapstructure:"job-store-s3-region"`
+	LockingDBType              string `mapstructure:"locking-db-type"`
 	LogLevel                   string `mapstructure:"log-level"`
 	ParallelPoolSize           int    `mapstructure:"parallel-pool-size"`
+	PolicyRefreshInterval      int    `mapstructure:"policy-refresh-interval"`
 	StatsNamespace             string `mapstructure:"stats-namespace"`
 	PlanDrafts                 bool   `mapstructure:"allow-draft-prs"`
 	Port                       int    `mapstructure:"port"`
+	RedisHost                  string `mapstructure:"redis-host"`
+	RedisPassword              string `mapstructure:"redis-password"`
+	RedisPort                  int    `mapstructure:"redis-port"`
+	RedisDB                    int    `mapstructure:"redis-db"`
+	RedisTLSEnabled            bool   `mapstructure:"redis-tls-enabled"`
+	RedisInsecureSkipVerify    bool   `mapstructure:"redis-insecure-skip-verify"`
 	RepoConfig                 string `mapstructure:"repo-config"`
 	RepoConfigJSON             string `mapstructure:"repo-config-json"`
 	RepoAllowlist              string `mapstructure:"repo-allowlist"`
+	RepoConcurrencyLimit       int    `mapstructure:"repo-concurrency-limit"`
 	// RepoWhitelist is deprecated in favour of RepoAllowlist.
 	RepoWhitelist string `mapstructure:"repo-whitelist"`
 
@@ -93,6 +120,8 @@ type UserConfig struct {
 	WebUsername            string          `mapstructure:"web-username"`
 	WebPassword            string          `mapstructure:"web-password"`
 	WriteGitCreds          bool            `mapstructure:"write-git-creds"`
+	// WorkerLeaseTimeout is in seconds.
+	WorkerLeaseTimeout int `mapstructure:"worker-lease-timeout"`
 }
 
 // ToLogLevel returns the LogLevel object corresponding to the user-passed
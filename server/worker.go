@@ -0,0 +1,216 @@
+package server
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	"github.com/runatlantis/atlantis/server/core/runtime/common"
+	"github.com/runatlantis/atlantis/server/core/runtime/policy"
+	"github.com/runatlantis/atlantis/server/core/runtime/sandbox"
+	"github.com/runatlantis/atlantis/server/core/terraform"
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/workers"
+	"github.com/uber-go/tally"
+)
+
+// WorkerConfig is the configuration of atlantis worker.
+type WorkerConfig struct {
+	// ServerURL is the URL the worker reaches the Atlantis server at.
+	ServerURL string
+	// APISecret is the server's --api-secret.
+	APISecret string
+	// DataDir must be the same path as the server's --data-dir and be shared
+	// with the server.
+	DataDir          string
+	DefaultTFVersion string
+	// DefaultTFVersionFlag is the name of the flag that sets
+	// DefaultTFVersion, used in errors.
+	DefaultTFVersionFlag string
+	TFDownloadURL        string
+	// Name identifies the worker in the server's logs.
+	Name string
+	// Concurrency is how many jobs the worker runs at a time.
+	Concurrency int
+	// RenewInterval is how often leases are renewed.
+	RenewInterval time.Duration
+	// RetryInterval is how long to wait after a claim fails.
+	RetryInterval time.Duration
+	// OutputInterval is how often output is sent to the server.
+	OutputInterval time.Duration
+	// PolicyRefreshInterval is how often policies are refreshed, in minutes.
+	PolicyRefreshInterval int
+}
+
+// Worker runs the steps of project commands for an Atlantis server with
+// remote workers enabled.
+type Worker struct {
+	Worker *workers.Worker
+	Logger logging.SimpleLogging
+}
+
+// NewWorker returns a worker that runs steps with the binaries and plugin
+// cache in config.DataDir.
+func NewWorker(config WorkerConfig, logger logging.SimpleLogging) (*Worker, error) {
+	// See NewServer.
+	if err := common.SetNotDumpable(); err != nil {
+		logger.Warn("%s, run steps may be able to read the worker's environment", err)
+	}
+	client, err := workers.NewClient(config.ServerURL, config.APISecret)
+	if err != nil {
+		return nil, err
+	}
+	binDir, err := mkSubDir(config.DataDir, BinDirName)
+	if err != nil {
+		return nil, err
+	}
+	cacheDir, err := mkSubDir(config.DataDir, TerraformPluginCacheDirName)
+	if err != nil {
+		return nil, err
+	}
+	policyCacheDir, err := mkSubDir(config.DataDir, PolicyCacheDirName)
+	if err != nil {
+		return nil, err
+	}
+
+	output := workers.NewOutputForwarder(client, config.OutputInterval, logger)
+	terraformClient, err := terraform.NewClient(
+		logger,
+		binDir,
+		cacheDir,
+		"",
+		"",
+		config.DefaultTFVersion,
+		config.DefaultTFVersionFlag,
+		config.TFDownloadURL,
+		&terraform.DefaultDownloader{},
+		true,
+		output)
+	// See NewServer.
+	if err != nil && flag.Lookup("test.v") == nil {
+		return nil, errors.Wrap(err, "initializing terraform")
+	}
+	defaultTfVersion := terraformClient.DefaultVersion()
+
+	sandboxHidden, err := sandboxHiddenPaths()
+	if err != nil {
+		return nil, err
+	}
+	sandboxExecutor, err := sandbox.NewNamespaceExecutor(config.DataDir, sandboxHidden, []string{cacheDir}, []string{binDir})
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing sandbox executor")
+	}
+	runStepRunner := &runtime.RunStepRunner{
+		TerraformExecutor: terraformClient,
+		DefaultTFVersion:  defaultTfVersion,
+		TerraformBinDir:   terraformClient.TerraformBinDir(),
+		Executor:          sandboxExecutor,
+	}
+	showStepRunner, err := runtime.NewShowStepRunner(terraformClient, defaultTfVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing show step runner")
+	}
+	policyCheckRunner, err := runtime.NewPolicyCheckStepRunner(
+		defaultTfVersion,
+		policy.NewConfTestExecutorWorkflow(logger, binDir, policyCacheDir, time.Duration(config.PolicyRefreshInterval)*time.Minute, &terraform.DefaultDownloader{}),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing policy check runner")
+	}
+
+	// Only the step runners are set since the server does everything else.
+	statusUpdater := noopStatusUpdater{}
+	stepsRunner := &events.DefaultProjectCommandRunner{
+		InitStepRunner: &runtime.InitStepRunner{
+			TerraformExecutor: terraformClient,
+			DefaultTFVersion:  defaultTfVersion,
+		},
+		PlanStepRunner: &runtime.PlanStepRunner{
+			TerraformExecutor:   terraformClient,
+			DefaultTFVersion:    defaultTfVersion,
+			CommitStatusUpdater: statusUpdater,
+			AsyncTFExec:         terraformClient,
+		},
+		ShowStepRunner:        showStepRunner,
+		PolicyCheckStepRunner: policyCheckRunner,
+		ApplyStepRunner: &runtime.ApplyStepRunner{
+			TerraformExecutor:   terraformClient,
+			DefaultTFVersion:    defaultTfVersion,
+			CommitStatusUpdater: statusUpdater,
+			AsyncTFExec:         terraformClient,
+		},
+		RunStepRunner: runStepRunner,
+		EnvStepRunner: &runtime.EnvStepRunner{
+			RunStepRunner: runStepRunner,
+		},
+		VersionStepRunner: &runtime.VersionStepRunner{
+			TerraformExecutor: terraformClient,
+			DefaultTFVersion:  defaultTfVersion,
+		},
+		ImportStepRunner: &runtime.ImportStepRunner{
+			TerraformExecutor: terraformClient,
+			DefaultTFVersion:  defaultTfVersion,
+		},
+		StateRmStepRunner: &runtime.StateStepRunner{
+			TerraformExecutor: terraformClient,
+			DefaultTFVersion:  defaultTfVersion,
+			Subcommand:        "rm",
+		},
+		StateMvStepRunner: &runtime.StateStepRunner{
+			TerraformExecutor: terraformClient,
+			DefaultTFVersion:  defaultTfVersion,
+			Subcommand:        "mv",
+		},
+	}
+
+	return &Worker{
+		Worker: &workers.Worker{
+			Name:          config.Name,
+			Client:        client,
+			StepsRunner:   stepsRunner,
+			Output:        output,
+			Concurrency:   config.Concurrency,
+			RenewInterval: config.RenewInterval,
+			RetryInterval: config.RetryInterval,
+			Logger:        logger,
+			Scope:         tally.NoopScope,
+		},
+		Logger: logger,
+	}, nil
+}
+
+// Start runs jobs until the worker is sent SIGINT or SIGTERM. It then waits
+// for the jobs it's running to finish.
+func (w *Worker) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-stop
+		w.Logger.Warn("Received interrupt. Waiting for in-progress jobs to complete")
+		cancel()
+	}()
+
+	defer w.Logger.Flush()
+	w.Logger.Info("Atlantis worker %q started, running up to %d jobs from %s", w.Worker.Name, w.Worker.Concurrency, w.Worker.Client.URL.String())
+	w.Worker.Start(ctx)
+	w.Logger.Info("All in-progress jobs complete, shutting down")
+	return nil
+}
+
+// noopStatusUpdater is used by workers since they can't update commit
+// statuses. It's only called for Terraform Cloud remote operations.
+type noopStatusUpdater struct{}
+
+func (noopStatusUpdater) UpdateProject(_ command.ProjectContext, _ command.Name, _ models.CommitStatus, _ string) error {
+	return nil
+}
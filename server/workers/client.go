@@ -0,0 +1,144 @@
+package workers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SecretHeader is the header that workers set to the server's --api-secret.
+// It's the same header as the API's.
+const SecretHeader = "X-Atlantis-Token"
+
+// ClaimWait is how long the server waits for a job to be queued before
+// responding to a claim without one.
+const ClaimWait = 30 * time.Second
+
+// ClaimRequest is the body of POST /workers/claim.
+type ClaimRequest struct {
+	// Worker is the name of the worker, used in logs.
+	Worker string
+}
+
+// RenewResponse is the body of the response to POST
+// /workers/leases/{id}/renew.
+type RenewResponse struct {
+	// Cancelled is true if the worker should stop the job's steps.
+	Cancelled bool
+}
+
+// OutputRequest is the body of POST /workers/leases/{id}/output.
+type OutputRequest struct {
+	Lines []string
+}
+
+// Client makes the requests of a worker to the Atlantis server.
+type Client struct {
+	// URL is the URL of the Atlantis server.
+	URL *url.URL
+	// Secret is the server's --api-secret.
+	Secret     string
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client for the server at serverURL.
+func NewClient(serverURL string, secret string) (*Client, error) {
+	parsed, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing server url %q", serverURL)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("server url %q must start with http:// or https://", serverURL)
+	}
+	return &Client{
+		URL:    parsed,
+		Secret: secret,
+		// Claims wait on the server so the timeout must be longer.
+		HTTPClient: &http.Client{Timeout: ClaimWait + time.Minute},
+	}, nil
+}
+
+// Claim leases the next job. It returns nil if none was queued within
+// ClaimWait.
+func (c *Client) Claim(worker string) (*Job, error) {
+	var job Job
+	found, err := c.post("/workers/claim", ClaimRequest{Worker: worker}, &job)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Renew extends the lease with leaseID. It returns true if the job was
+// cancelled.
+func (c *Client) Renew(leaseID string) (bool, error) {
+	var resp RenewResponse
+	_, err := c.post(leasePath(leaseID, "renew"), nil, &resp)
+	return resp.Cancelled, err
+}
+
+// Output sends lines of a job's output.
+func (c *Client) Output(leaseID string, lines []string) error {
+	_, err := c.post(leasePath(leaseID, "output"), OutputRequest{Lines: lines}, nil)
+	return err
+}
+
+// Complete reports the result of a job and ends its lease.
+func (c *Client) Complete(leaseID string, result Result) error {
+	_, err := c.post(leasePath(leaseID, "complete"), result, nil)
+	return err
+}
+
+func leasePath(leaseID string, action string) string {
+	return fmt.Sprintf("/workers/leases/%s/%s", url.PathEscape(leaseID), action)
+}
+
+// post sends body as JSON to path and decodes the response into resp if it
+// isn't nil. It returns false if the server responded with no content. A 404
+// is returned as ErrLeaseNotFound.
+func (c *Client) post(path string, body interface{}, resp interface{}) (bool, error) {
+	serialized, err := json.Marshal(body)
+	if err != nil {
+		return false, errors.Wrap(err, "serializing request")
+	}
+	reqURL := *c.URL
+	reqURL.Path = strings.TrimSuffix(reqURL.Path, "/") + path
+	req, err := http.NewRequest(http.MethodPost, reqURL.String(), bytes.NewReader(serialized))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SecretHeader, c.Secret)
+
+	httpResp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return false, errors.Wrapf(err, "making request to %s", path)
+	}
+	defer httpResp.Body.Close() // nolint: errcheck
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return false, errors.Wrapf(err, "reading response from %s", path)
+	}
+
+	switch {
+	case httpResp.StatusCode == http.StatusNoContent:
+		return false, nil
+	case httpResp.StatusCode == http.StatusNotFound:
+		return false, ErrLeaseNotFound
+	case httpResp.StatusCode != http.StatusOK:
+		return false, fmt.Errorf("request to %s failed with status %d: %s", path, httpResp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if resp != nil {
+		if err := json.Unmarshal(respBody, resp); err != nil {
+			return false, errors.Wrapf(err, "parsing response from %s", path)
+		}
+	}
+	return true, nil
+}
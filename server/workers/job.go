@@ -0,0 +1,154 @@
+// Package workers runs the steps of project commands on worker processes
+// instead of the Atlantis server. The server queues the steps of each command
+// and workers started with atlantis worker lease them over HTTP, run them and
+// report their output back.
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/go-version"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/uber-go/tally"
+)
+
+// Job is the steps of a project command that a worker has leased.
+type Job struct {
+	// LeaseID identifies the lease in the worker's requests. It changes if
+	// the job is requeued.
+	LeaseID string
+	// Project is the project the steps run for.
+	Project Project
+	Steps   []valid.Step
+	// AbsPath is the dir the steps run in. Workers share the server's data
+	// dir so it's the same on both.
+	AbsPath string
+}
+
+// Project is the part of a command.ProjectContext that's needed to run steps.
+// Fields that can't be serialized, ex. the logger, are set by the worker.
+type Project struct {
+	CommandName        command.Name
+	BaseRepo           models.Repo
+	HeadRepo           models.Repo
+	Pull               models.PullRequest
+	User               models.User
+	ProjectName        string
+	RepoRelDir         string
+	Workspace          string
+	EscapedCommentArgs []string
+	RePlanCmd          string
+	ApplyCmd           string
+	Verbose            bool
+	Timeout            time.Duration
+	// TerraformVersion is empty if the worker's default version is used.
+	TerraformVersion string
+	PolicySets       PolicySets
+	EnvPassthrough   *valid.EnvPassthrough
+	Sandbox          *valid.Sandbox
+	JobID            string
+}
+
+// PolicySets is the serializable form of valid.PolicySets.
+type PolicySets struct {
+	// Version is the version of conftest or empty for the default version.
+	Version    string
+	Owners     valid.PolicyOwners
+	PolicySets []valid.PolicySet
+}
+
+// Result is the outcome of running a job's steps.
+type Result struct {
+	// Outputs is the output of each step that had any.
+	Outputs []string
+	// Error is the error that stopped the steps or empty if they all
+	// succeeded.
+	Error string
+}
+
+// NewProject returns the Project of ctx. The credentials in the repos' clone
+// urls are removed since workers don't clone.
+func NewProject(ctx command.ProjectContext) Project {
+	p := Project{
+		CommandName:        ctx.CommandName,
+		BaseRepo:           sanitizeRepo(ctx.BaseRepo),
+		HeadRepo:           sanitizeRepo(ctx.HeadRepo),
+		Pull:               ctx.Pull,
+		User:               ctx.User,
+		ProjectName:        ctx.ProjectName,
+		RepoRelDir:         ctx.RepoRelDir,
+		Workspace:          ctx.Workspace,
+		EscapedCommentArgs: ctx.EscapedCommentArgs,
+		RePlanCmd:          ctx.RePlanCmd,
+		ApplyCmd:           ctx.ApplyCmd,
+		Verbose:            ctx.Verbose,
+		Timeout:            ctx.Timeout,
+		PolicySets: PolicySets{
+			Owners:     ctx.PolicySets.Owners,
+			PolicySets: ctx.PolicySets.PolicySets,
+		},
+		EnvPassthrough: ctx.EnvPassthrough,
+		Sandbox:        ctx.Sandbox,
+		JobID:          ctx.JobID,
+	}
+	p.Pull.BaseRepo = sanitizeRepo(ctx.Pull.BaseRepo)
+	if ctx.TerraformVersion != nil {
+		p.TerraformVersion = ctx.TerraformVersion.String()
+	}
+	if ctx.PolicySets.Version != nil {
+		p.PolicySets.Version = ctx.PolicySets.Version.String()
+	}
+	return p
+}
+
+// ProjectContext returns the command.ProjectContext that runs p's steps with
+// log and scope. It's done when runCtx is.
+func (p Project) ProjectContext(runCtx context.Context, log logging.SimpleLogging, scope tally.Scope) (command.ProjectContext, error) {
+	ctx := command.ProjectContext{
+		CommandName:        p.CommandName,
+		BaseRepo:           p.BaseRepo,
+		HeadRepo:           p.HeadRepo,
+		Pull:               p.Pull,
+		User:               p.User,
+		ProjectName:        p.ProjectName,
+		RepoRelDir:         p.RepoRelDir,
+		Workspace:          p.Workspace,
+		EscapedCommentArgs: p.EscapedCommentArgs,
+		RePlanCmd:          p.RePlanCmd,
+		ApplyCmd:           p.ApplyCmd,
+		Verbose:            p.Verbose,
+		Timeout:            p.Timeout,
+		PolicySets: valid.PolicySets{
+			Owners:     p.PolicySets.Owners,
+			PolicySets: p.PolicySets.PolicySets,
+		},
+		EnvPassthrough: p.EnvPassthrough,
+		Sandbox:        p.Sandbox,
+		JobID:          p.JobID,
+		Ctx:            runCtx,
+		Log:            log,
+		Scope:          scope,
+	}
+	var err error
+	if p.TerraformVersion != "" {
+		if ctx.TerraformVersion, err = version.NewVersion(p.TerraformVersion); err != nil {
+			return ctx, errors.Wrapf(err, "parsing terraform version %q", p.TerraformVersion)
+		}
+	}
+	if p.PolicySets.Version != "" {
+		if ctx.PolicySets.Version, err = version.NewVersion(p.PolicySets.Version); err != nil {
+			return ctx, errors.Wrapf(err, "parsing conftest version %q", p.PolicySets.Version)
+		}
+	}
+	return ctx, nil
+}
+
+func sanitizeRepo(repo models.Repo) models.Repo {
+	repo.CloneURL = repo.SanitizedCloneURL
+	return repo
+}
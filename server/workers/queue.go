@@ -0,0 +1,252 @@
+package workers
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/jobs"
+)
+
+// ErrLeaseNotFound is returned for requests about a lease that expired or was
+// never granted, ex. because the server restarted.
+var ErrLeaseNotFound = errors.New("lease not found")
+
+// Queue is the server's queue of jobs. It implements events.StepsRunner by
+// queueing the steps and waiting for a worker to run them. Jobs are handed
+// out in the order they were queued. A job whose worker doesn't renew its
+// lease within LeaseTimeout is queued again at the front of the queue if
+// running it twice is safe, otherwise it fails.
+//
+// The queue is kept in memory. If the server restarts, workers stop the jobs
+// they're running once they fail to renew their leases.
+type Queue struct {
+	// LeaseTimeout is how long a worker can go without renewing its lease
+	// before its job is given to another worker.
+	LeaseTimeout time.Duration
+	// OutputHandler receives the output that workers stream while running
+	// a job.
+	OutputHandler jobs.ProjectCommandOutputHandler
+
+	mu      sync.Mutex
+	pending []*queuedJob
+	leased  map[string]*queuedJob
+	// queued is closed and replaced when a job is queued to wake up the
+	// workers waiting in Claim.
+	queued chan struct{}
+}
+
+type queuedJob struct {
+	ctx     command.ProjectContext
+	steps   []valid.Step
+	absPath string
+	// leaseID is empty while the job is pending.
+	leaseID string
+	worker  string
+	expires time.Time
+	// cancelled is true if the command was cancelled while the job was
+	// leased. The worker is told when it renews its lease.
+	cancelled bool
+	done      chan Result
+}
+
+// NewQueue returns an empty queue.
+func NewQueue(leaseTimeout time.Duration, outputHandler jobs.ProjectCommandOutputHandler) *Queue {
+	return &Queue{
+		LeaseTimeout:  leaseTimeout,
+		OutputHandler: outputHandler,
+		leased:        make(map[string]*queuedJob),
+		queued:        make(chan struct{}),
+	}
+}
+
+// RunSteps queues steps and waits for a worker to run them. If ctx is done
+// before a worker leases the job, the job is dropped. Otherwise the worker is
+// told to stop the steps and RunSteps waits for its result.
+func (q *Queue) RunSteps(ctx command.ProjectContext, steps []valid.Step, absPath string) ([]string, error) {
+	job := &queuedJob{
+		ctx:     ctx,
+		steps:   steps,
+		absPath: absPath,
+		done:    make(chan Result, 1),
+	}
+	q.mu.Lock()
+	q.pending = append(q.pending, job)
+	q.notifyLocked()
+	q.mu.Unlock()
+	ctx.Log.Debug("queued %s steps for a worker", ctx.CommandName.String())
+
+	ticker := time.NewTicker(q.LeaseTimeout / 2)
+	defer ticker.Stop()
+	cmdDone := ctx.Context().Done()
+	for {
+		select {
+		case result := <-job.done:
+			if result.Error != "" {
+				return result.Outputs, errors.New(result.Error)
+			}
+			return result.Outputs, nil
+		case <-cmdDone:
+			if q.cancel(job) {
+				return nil, fmt.Errorf("%s was stopped before a worker ran it: %s", ctx.CommandName.String(), ctx.Context().Err())
+			}
+			// The worker stops the steps and reports what they output.
+			cmdDone = nil
+		case now := <-ticker.C:
+			q.requeueIfExpired(job, now)
+		}
+	}
+}
+
+// Claim leases the next job to worker. If there isn't one it waits up to wait
+// for a job to be queued and returns nil if none is.
+func (q *Queue) Claim(worker string, wait time.Duration) *Job {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		q.mu.Lock()
+		if len(q.pending) > 0 {
+			job := q.pending[0]
+			q.pending = q.pending[1:]
+			job.leaseID = uuid.New().String()
+			job.worker = worker
+			job.expires = time.Now().Add(q.LeaseTimeout)
+			q.leased[job.leaseID] = job
+			// The lease is read before unlocking since it's cleared if it
+			// expires.
+			leased := &Job{
+				LeaseID: job.leaseID,
+				Project: NewProject(job.ctx),
+				Steps:   job.steps,
+				AbsPath: job.absPath,
+			}
+			q.mu.Unlock()
+			job.ctx.Log.Info("worker %q leased the %s steps", worker, job.ctx.CommandName.String())
+			return leased
+		}
+		queued := q.queued
+		q.mu.Unlock()
+
+		select {
+		case <-queued:
+		case <-timer.C:
+			return nil
+		}
+	}
+}
+
+// Renew extends the lease with leaseID. It returns true if the job's command
+// was cancelled and the worker should stop its steps.
+func (q *Queue) Renew(leaseID string) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.leased[leaseID]
+	if !ok {
+		return false, ErrLeaseNotFound
+	}
+	job.expires = time.Now().Add(q.LeaseTimeout)
+	return job.cancelled, nil
+}
+
+// Output sends lines, the output of the job with leaseID, to the output
+// handler.
+func (q *Queue) Output(leaseID string, lines []string) error {
+	q.mu.Lock()
+	job, ok := q.leased[leaseID]
+	q.mu.Unlock()
+	if !ok {
+		return ErrLeaseNotFound
+	}
+	for _, line := range lines {
+		q.OutputHandler.Send(job.ctx, line, false)
+	}
+	return nil
+}
+
+// Complete ends the lease with leaseID and returns result to RunSteps.
+func (q *Queue) Complete(leaseID string, result Result) error {
+	q.mu.Lock()
+	job, ok := q.leased[leaseID]
+	delete(q.leased, leaseID)
+	q.mu.Unlock()
+	if !ok {
+		return ErrLeaseNotFound
+	}
+	job.done <- result
+	return nil
+}
+
+// Len returns the number of jobs waiting for a worker.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// cancel removes job from the queue if it's still pending and returns true.
+// If it's leased, the worker is told to stop it the next time it renews the
+// lease.
+func (q *Queue) cancel(job *queuedJob) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, pending := range q.pending {
+		if pending == job {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			return true
+		}
+	}
+	job.cancelled = true
+	return false
+}
+
+// requeueIfExpired queues job again if its lease expired before now and its
+// steps can safely be run again. Otherwise the job fails.
+func (q *Queue) requeueIfExpired(job *queuedJob, now time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if job.leaseID == "" || q.leased[job.leaseID] != job || now.Before(job.expires) {
+		return
+	}
+	cmdName := job.ctx.CommandName.String()
+	worker := job.worker
+	delete(q.leased, job.leaseID)
+	job.leaseID = ""
+	job.worker = ""
+	if job.cancelled {
+		job.done <- Result{Error: fmt.Sprintf("%s was stopped but its worker didn't report a result", cmdName)}
+		return
+	}
+	if !job.idempotent() {
+		job.ctx.Log.Warn("worker %q didn't renew its lease of the %s steps, failing them since they can't safely be run again", worker, cmdName)
+		job.done <- Result{Error: fmt.Sprintf("worker %q stopped responding while running the %s steps. They weren't run again since they may have partly run, check the project's state before running %s again", worker, cmdName, cmdName)}
+		return
+	}
+	job.ctx.Log.Warn("worker %q didn't renew its lease of the %s steps, queueing them again", worker, cmdName)
+	q.pending = append([]*queuedJob{job}, q.pending...)
+	q.notifyLocked()
+}
+
+// idempotent returns true if running job's steps twice, ex. by the worker
+// whose lease expired and by another worker, is safe. Commands that change
+// infrastructure or state aren't, nor are steps with custom run commands.
+func (job *queuedJob) idempotent() bool {
+	switch job.ctx.CommandName {
+	case command.Apply, command.Import, command.StateRm, command.StateMv:
+		return false
+	}
+	for _, step := range job.steps {
+		if step.StepName == "run" {
+			return false
+		}
+	}
+	return true
+}
+
+func (q *Queue) notifyLocked() {
+	close(q.queued)
+	q.queued = make(chan struct{})
+}
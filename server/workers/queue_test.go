@@ -0,0 +1,186 @@
+package workers_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/jobs"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/workers"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestQueue_ClaimInOrder(t *testing.T) {
+	q := workers.NewQueue(time.Minute, &jobs.NoopProjectOutputHandler{})
+	first := runStepsAsync(q, testProjectContext(t, "first"))
+	waitForPending(t, q)
+	second := runStepsAsync(q, testProjectContext(t, "second"))
+
+	job := q.Claim("worker", time.Second)
+	Assert(t, job != nil, "expected a job")
+	Equals(t, "first", job.Project.JobID)
+	Ok(t, q.Complete(job.LeaseID, workers.Result{Outputs: []string{"one"}}))
+	res := <-first
+	Ok(t, res.err)
+	Equals(t, []string{"one"}, res.outputs)
+
+	job = q.Claim("worker", time.Second)
+	Assert(t, job != nil, "expected a job")
+	Equals(t, "second", job.Project.JobID)
+	Ok(t, q.Complete(job.LeaseID, workers.Result{Error: "failed"}))
+	res = <-second
+	ErrEquals(t, "failed", res.err)
+}
+
+func TestQueue_ClaimWaits(t *testing.T) {
+	q := workers.NewQueue(time.Minute, &jobs.NoopProjectOutputHandler{})
+	Assert(t, q.Claim("worker", 10*time.Millisecond) == nil, "expected no job")
+
+	claimed := make(chan *workers.Job)
+	go func() {
+		claimed <- q.Claim("worker", 5*time.Second)
+	}()
+	runStepsAsync(q, testProjectContext(t, "job"))
+	job := <-claimed
+	Assert(t, job != nil, "expected a job")
+	Equals(t, "job", job.Project.JobID)
+}
+
+func TestQueue_RequeuesExpiredLease(t *testing.T) {
+	q := workers.NewQueue(50*time.Millisecond, &jobs.NoopProjectOutputHandler{})
+	done := runStepsAsync(q, testProjectContext(t, "job"))
+
+	expired := q.Claim("worker-1", time.Second)
+	Assert(t, expired != nil, "expected a job")
+	job := q.Claim("worker-2", 5*time.Second)
+	Assert(t, job != nil, "expected the job to be requeued")
+	Assert(t, job.LeaseID != expired.LeaseID, "expected a new lease")
+
+	_, err := q.Renew(expired.LeaseID)
+	Equals(t, workers.ErrLeaseNotFound, err)
+	Equals(t, workers.ErrLeaseNotFound, q.Complete(expired.LeaseID, workers.Result{}))
+	Ok(t, q.Complete(job.LeaseID, workers.Result{}))
+	Ok(t, (<-done).err)
+}
+
+func TestQueue_FailsExpiredApply(t *testing.T) {
+	q := workers.NewQueue(50*time.Millisecond, &jobs.NoopProjectOutputHandler{})
+	ctx := testProjectContext(t, "job")
+	ctx.CommandName = command.Apply
+	done := runStepsAsync(q, ctx)
+
+	expired := q.Claim("worker-1", time.Second)
+	Assert(t, expired != nil, "expected a job")
+	ErrEquals(t, `worker "worker-1" stopped responding while running the apply steps. They weren't run again since they may have partly run, check the project's state before running apply again`, (<-done).err)
+	Assert(t, q.Claim("worker-2", 10*time.Millisecond) == nil, "expected the job not to be requeued")
+	_, err := q.Renew(expired.LeaseID)
+	Equals(t, workers.ErrLeaseNotFound, err)
+}
+
+func TestQueue_CancelPending(t *testing.T) {
+	q := workers.NewQueue(time.Minute, &jobs.NoopProjectOutputHandler{})
+	runCtx, cancel := context.WithCancel(context.Background())
+	ctx := testProjectContext(t, "job")
+	ctx.Ctx = runCtx
+	done := runStepsAsync(q, ctx)
+	waitForPending(t, q)
+	cancel()
+
+	ErrEquals(t, "plan was stopped before a worker ran it: context canceled", (<-done).err)
+	Assert(t, q.Claim("worker", 10*time.Millisecond) == nil, "expected the job to be removed")
+}
+
+func TestQueue_CancelLeased(t *testing.T) {
+	q := workers.NewQueue(time.Minute, &jobs.NoopProjectOutputHandler{})
+	runCtx, cancel := context.WithCancel(context.Background())
+	ctx := testProjectContext(t, "job")
+	ctx.Ctx = runCtx
+	done := runStepsAsync(q, ctx)
+	job := q.Claim("worker", time.Second)
+	Assert(t, job != nil, "expected a job")
+
+	cancelled, err := q.Renew(job.LeaseID)
+	Ok(t, err)
+	Equals(t, false, cancelled)
+	cancel()
+	// The cancel is noticed by RunSteps asynchronously.
+	for i := 0; !cancelled && i < 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+		cancelled, err = q.Renew(job.LeaseID)
+		Ok(t, err)
+	}
+	Equals(t, true, cancelled)
+
+	Ok(t, q.Complete(job.LeaseID, workers.Result{Error: "interrupted"}))
+	ErrEquals(t, "interrupted", (<-done).err)
+}
+
+func TestQueue_Output(t *testing.T) {
+	handler := &recordingOutputHandler{}
+	q := workers.NewQueue(time.Minute, handler)
+	runStepsAsync(q, testProjectContext(t, "job"))
+	job := q.Claim("worker", time.Second)
+	Assert(t, job != nil, "expected a job")
+
+	Ok(t, q.Output(job.LeaseID, []string{"a", "b"}))
+	Equals(t, []string{"job: a", "job: b"}, handler.Lines())
+	Equals(t, workers.ErrLeaseNotFound, q.Output("unknown", []string{"c"}))
+}
+
+type runStepsResult struct {
+	outputs []string
+	err     error
+}
+
+func runStepsAsync(q *workers.Queue, ctx command.ProjectContext) chan runStepsResult {
+	done := make(chan runStepsResult, 1)
+	go func() {
+		outputs, err := q.RunSteps(ctx, []valid.Step{{StepName: "plan"}}, "/data/repos/owner/repo/1/default")
+		done <- runStepsResult{outputs: outputs, err: err}
+	}()
+	return done
+}
+
+// waitForPending waits for a job to be queued without leasing it.
+func waitForPending(t *testing.T, q *workers.Queue) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		if q.Len() > 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("timed out waiting for a job to be queued")
+}
+
+func testProjectContext(t *testing.T, jobID string) command.ProjectContext {
+	return command.ProjectContext{
+		CommandName: command.Plan,
+		RepoRelDir:  ".",
+		Workspace:   "default",
+		JobID:       jobID,
+		Log:         logging.NewNoopLogger(t),
+	}
+}
+
+type recordingOutputHandler struct {
+	jobs.NoopProjectOutputHandler
+	mu    sync.Mutex
+	lines []string
+}
+
+func (r *recordingOutputHandler) Send(ctx command.ProjectContext, msg string, _ bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, ctx.JobID+": "+msg)
+}
+
+func (r *recordingOutputHandler) Lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lines
+}
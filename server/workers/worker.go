@@ -0,0 +1,226 @@
+package workers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/jobs"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/uber-go/tally"
+)
+
+// StepsRunner runs steps in this process.
+// events.DefaultProjectCommandRunner implements it.
+type StepsRunner interface {
+	RunSteps(ctx command.ProjectContext, steps []valid.Step, absPath string) ([]string, error)
+}
+
+// Worker runs the jobs it leases from the Atlantis server.
+type Worker struct {
+	// Name identifies the worker in the server's logs.
+	Name        string
+	Client      *Client
+	StepsRunner StepsRunner
+	// Output forwards the output of the steps to the server. It must be the
+	// output handler of the worker's Terraform client.
+	Output *OutputForwarder
+	// Concurrency is how many jobs the worker runs at a time.
+	Concurrency int
+	// RenewInterval is how often leases are renewed. It must be shorter than
+	// the server's lease timeout.
+	RenewInterval time.Duration
+	// RetryInterval is how long to wait after a claim fails, ex. because the
+	// server is restarting.
+	RetryInterval time.Duration
+	Logger        logging.SimpleLogging
+	Scope         tally.Scope
+}
+
+// Start claims and runs jobs until ctx is done. It then waits for the jobs
+// that are running to finish.
+func (w *Worker) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < w.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.claimJobs(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (w *Worker) claimJobs(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := w.Client.Claim(w.Name)
+		if err != nil {
+			w.Logger.Warn("unable to claim a job, retrying in %s: %s", w.RetryInterval, err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(w.RetryInterval):
+			}
+			continue
+		}
+		if job != nil {
+			w.Run(job)
+		}
+	}
+}
+
+// Run runs job's steps and reports the result to the server.
+func (w *Worker) Run(job *Job) {
+	log := w.Logger.WithHistory(
+		"repo", job.Project.BaseRepo.FullName,
+		"pull", job.Project.Pull.Num,
+		"dir", job.Project.RepoRelDir,
+		"workspace", job.Project.Workspace,
+		"lease", job.LeaseID,
+	)
+	log.Info("running %s steps", job.Project.CommandName.String())
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var result Result
+	ctx, err := job.Project.ProjectContext(runCtx, log, w.Scope)
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		w.Output.Track(ctx.JobID, job.LeaseID)
+		stopRenewing := w.renew(job.LeaseID, cancel, log)
+		outputs, err := w.StepsRunner.RunSteps(ctx, job.Steps, job.AbsPath)
+		stopRenewing()
+		w.Output.Untrack(ctx.JobID)
+		result.Outputs = outputs
+		if err != nil {
+			result.Error = err.Error()
+		}
+	}
+
+	if err := w.Client.Complete(job.LeaseID, result); err != nil {
+		log.Err("unable to report the result of the %s steps: %s", job.Project.CommandName.String(), err)
+		return
+	}
+	log.Info("finished %s steps", job.Project.CommandName.String())
+}
+
+// renew renews the lease with leaseID every RenewInterval and calls cancel if
+// the server cancelled the job or the lease expired. It returns a function
+// that stops renewing.
+func (w *Worker) renew(leaseID string, cancel context.CancelFunc, log logging.SimpleLogging) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(w.RenewInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			cancelled, err := w.Client.Renew(leaseID)
+			switch {
+			case err == ErrLeaseNotFound:
+				// Another worker may be running the job now, so the
+				// steps are stopped rather than run twice at once.
+				log.Warn("lease expired, stopping the steps since the server may have given them to another worker or failed them")
+				cancel()
+				return
+			case err != nil:
+				log.Warn("unable to renew lease: %s", err)
+			case cancelled:
+				log.Info("stopping steps since the command was cancelled")
+				cancel()
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+// OutputForwarder is a jobs.ProjectCommandOutputHandler that sends the output
+// of the jobs a worker is running to the server in batches.
+type OutputForwarder struct {
+	jobs.NoopProjectOutputHandler
+	Client *Client
+	Logger logging.SimpleLogging
+
+	mu sync.Mutex
+	// leases maps the job ids of the running jobs to their lease ids.
+	leases map[string]string
+	// lines are the lines of each lease that haven't been sent yet.
+	lines map[string][]string
+	// flushMu keeps the lines of a lease in order when flushes overlap.
+	flushMu sync.Mutex
+}
+
+// NewOutputForwarder returns an OutputForwarder that sends output every
+// flushInterval.
+func NewOutputForwarder(client *Client, flushInterval time.Duration, logger logging.SimpleLogging) *OutputForwarder {
+	o := &OutputForwarder{
+		Client: client,
+		Logger: logger,
+		leases: make(map[string]string),
+		lines:  make(map[string][]string),
+	}
+	ticker := time.NewTicker(flushInterval)
+	go func() {
+		for range ticker.C {
+			o.flush()
+		}
+	}()
+	return o
+}
+
+// Track starts forwarding the output of the job with jobID to the lease with
+// leaseID.
+func (o *OutputForwarder) Track(jobID string, leaseID string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.leases[jobID] = leaseID
+}
+
+// Untrack sends the job's remaining output and stops forwarding it.
+func (o *OutputForwarder) Untrack(jobID string) {
+	o.flush()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.lines, o.leases[jobID])
+	delete(o.leases, jobID)
+}
+
+// Send queues msg to be sent to the server. The server marks the job as
+// complete itself.
+func (o *OutputForwarder) Send(ctx command.ProjectContext, msg string, operationComplete bool) {
+	if operationComplete {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	leaseID, ok := o.leases[ctx.JobID]
+	if !ok {
+		return
+	}
+	o.lines[leaseID] = append(o.lines[leaseID], msg)
+}
+
+func (o *OutputForwarder) flush() {
+	o.flushMu.Lock()
+	defer o.flushMu.Unlock()
+	o.mu.Lock()
+	pending := o.lines
+	o.lines = make(map[string][]string)
+	o.mu.Unlock()
+
+	for leaseID, lines := range pending {
+		if err := o.Client.Output(leaseID, lines); err != nil {
+			o.Logger.Warn("unable to send %d lines of output for lease %s: %s", len(lines), leaseID, err)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/runatlantis/atlantis/server/controllers"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/workers"
+	. "github.com/runatlantis/atlantis/testing"
+	"github.com/uber-go/tally"
+)
+
+func TestWorker_RunsJobs(t *testing.T) {
+	handler := &recordingOutputHandler{}
+	q := workers.NewQueue(time.Minute, handler)
+	stop := startWorker(t, q, func(w *workers.Worker, ctx command.ProjectContext, steps []valid.Step, absPath string) ([]string, error) {
+		Equals(t, "/data/repos/owner/repo/1/default", absPath)
+		Equals(t, []valid.Step{{StepName: "plan"}}, steps)
+		w.Output.Send(ctx, "planning", false)
+		return []string{"plan output"}, nil
+	})
+	defer stop()
+
+	outputs, err := q.RunSteps(testProjectContext(t, "job"), []valid.Step{{StepName: "plan"}}, "/data/repos/owner/repo/1/default")
+	Ok(t, err)
+	Equals(t, []string{"plan output"}, outputs)
+	Equals(t, []string{"job: planning"}, handler.Lines())
+	Equals(t, 0, q.Len())
+}
+
+func TestWorker_Cancel(t *testing.T) {
+	q := workers.NewQueue(time.Minute, &recordingOutputHandler{})
+	started := make(chan struct{})
+	stop := startWorker(t, q, func(_ *workers.Worker, ctx command.ProjectContext, _ []valid.Step, _ string) ([]string, error) {
+		close(started)
+		<-ctx.Context().Done()
+		return nil, ctx.Context().Err()
+	})
+	defer stop()
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	ctx := testProjectContext(t, "job")
+	ctx.Ctx = runCtx
+	go func() {
+		<-started
+		cancel()
+	}()
+	_, err := q.RunSteps(ctx, []valid.Step{{StepName: "apply"}}, "/data")
+	ErrEquals(t, "context canceled", err)
+}
+
+func TestWorker_StopsWhenLeaseExpires(t *testing.T) {
+	q := workers.NewQueue(time.Minute, &recordingOutputHandler{})
+	stopped := make(chan error, 1)
+	w, stop := newWorker(t, q, func(_ *workers.Worker, ctx command.ProjectContext, _ []valid.Step, _ string) ([]string, error) {
+		select {
+		case <-ctx.Context().Done():
+			stopped <- ctx.Context().Err()
+		case <-time.After(5 * time.Second):
+			stopped <- errors.New("timed out waiting for the steps to be stopped")
+		}
+		return nil, ctx.Context().Err()
+	})
+	defer stop()
+
+	// The server doesn't know the lease, ex. because it expired.
+	w.Run(&workers.Job{
+		LeaseID: "expired",
+		Project: workers.NewProject(testProjectContext(t, "job")),
+		Steps:   []valid.Step{{StepName: "apply"}},
+		AbsPath: "/data",
+	})
+	Equals(t, context.Canceled, <-stopped)
+}
+
+type stepsRunnerFunc func(ctx command.ProjectContext, steps []valid.Step, absPath string) ([]string, error)
+
+func (f stepsRunnerFunc) RunSteps(ctx command.ProjectContext, steps []valid.Step, absPath string) ([]string, error) {
+	return f(ctx, steps, absPath)
+}
+
+// startWorker starts a worker that runs jobs from q through a test server
+// with run. The returned func stops the worker and the server.
+func startWorker(t *testing.T, q *workers.Queue, run func(w *workers.Worker, ctx command.ProjectContext, steps []valid.Step, absPath string) ([]string, error)) func() {
+	w, stopServer := newWorker(t, q, run)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.Start(ctx)
+	}()
+	return func() {
+		cancel()
+		<-done
+		stopServer()
+	}
+}
+
+// newWorker returns a worker that runs steps with run and talks to q through
+// a test server. The returned func stops the server.
+func newWorker(t *testing.T, q *workers.Queue, run func(w *workers.Worker, ctx command.ProjectContext, steps []valid.Step, absPath string) ([]string, error)) (*workers.Worker, func()) {
+	logger := logging.NewNoopLogger(t)
+	controller := &controllers.WorkersController{
+		Secret:    []byte("secret"),
+		Queue:     q,
+		Logger:    logger,
+		ClaimWait: 100 * time.Millisecond,
+	}
+	router := mux.NewRouter()
+	router.HandleFunc("/workers/claim", controller.Claim).Methods("POST")
+	router.HandleFunc("/workers/leases/{id}/renew", controller.Renew).Methods("POST")
+	router.HandleFunc("/workers/leases/{id}/output", controller.Output).Methods("POST")
+	router.HandleFunc("/workers/leases/{id}/complete", controller.Complete).Methods("POST")
+	server := httptest.NewServer(router)
+
+	client, err := workers.NewClient(server.URL, "secret")
+	Ok(t, err)
+	w := &workers.Worker{
+		Name:          "worker",
+		Client:        client,
+		Output:        workers.NewOutputForwarder(client, 10*time.Millisecond, logger),
+		Concurrency:   1,
+		RenewInterval: 10 * time.Millisecond,
+		RetryInterval: 10 * time.Millisecond,
+		Logger:        logger,
+		Scope:         tally.NoopScope,
+	}
+	w.StepsRunner = stepsRunnerFunc(func(ctx command.ProjectContext, steps []valid.Step, absPath string) ([]string, error) {
+		return run(w, ctx, steps, absPath)
+	})
+	return w, server.Close
+}